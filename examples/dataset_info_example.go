@@ -74,8 +74,7 @@ func main() {
 
 	fmt.Println("\n=== Example Complete ===")
 	fmt.Println("The GetDatasetInfo method provides:")
-	fmt.Println("- Direct API access for dataset metadata")
-	fmt.Println("- Fallback to list API if direct access fails")
+	fmt.Println("- Dataset attributes from the catalog listing (X-IBM-Attributes: base)")
+	fmt.Println("- An error, not an empty result, when the dataset does not exist")
 	fmt.Println("- Comprehensive error handling")
-	fmt.Println("- Compatibility with different z/OSMF versions")
 }
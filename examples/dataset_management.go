@@ -39,7 +39,7 @@ func main() {
 func demonstrateDatasetManagement(dm *datasets.ZOSMFDatasetManager) {
 	// Example 2: List datasets
 	fmt.Println("\n2. Listing datasets:")
-	datasetList, err := dm.ListDatasets(nil)
+	datasetList, err := dm.ListDatasets(&datasets.DatasetFilter{DefaultToUserPrefix: true})
 	if err != nil {
 		fmt.Printf("   Error listing datasets: %v\n", err)
 		fmt.Println("   (This is expected if not connected to a real mainframe)")
@@ -54,8 +54,9 @@ func demonstrateDatasetManagement(dm *datasets.ZOSMFDatasetManager) {
 	// Example 3: List datasets with filter
 	fmt.Println("\n3. Listing datasets with filter:")
 	filter := &datasets.DatasetFilter{
-		Type:  "SEQ",
-		Limit: 10,
+		Type:                "SEQ",
+		Limit:               10,
+		DefaultToUserPrefix: true,
 	}
 	datasetList, err = dm.ListDatasets(filter)
 	if err != nil {
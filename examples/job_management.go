@@ -133,7 +133,7 @@ func demonstrateJobManagement(jm *jobs.ZOSMFJobManager) {
 	fmt.Println("\n9. Getting spool files:")
 	// Use sample job name and ID for demonstration
 	jobName := "TESTJOB"
-	spoolFiles, err := jm.GetSpoolFiles(jobName, jobID)
+	spoolFiles, err := jm.GetSpoolFilesByCorrelator(jobName + ":" + jobID)
 	if err != nil {
 		fmt.Printf("   Error getting spool files: %v\n", err)
 		fmt.Println("   (This is expected if not connected to a real mainframe)")
@@ -148,7 +148,7 @@ func demonstrateJobManagement(jm *jobs.ZOSMFJobManager) {
 	// Example 10: Get spool file content
 	fmt.Println("\n10. Getting spool file content:")
 	if len(spoolFiles) > 0 {
-		content, err := jm.GetSpoolFileContent(jobName, jobID, spoolFiles[0].ID)
+		content, err := jm.GetSpoolFileContentByCorrelator(jobName+":"+jobID, spoolFiles[0].ID)
 		if err != nil {
 			fmt.Printf("   Error getting spool file content: %v\n", err)
 		} else {
@@ -202,8 +202,13 @@ func demonstrateJobManagement(jm *jobs.ZOSMFJobManager) {
 		"//DD2 DD SYSOUT=A",
 		"//DD3 DD DSN=TEST.OUTPUT,DISP=(NEW,CATLG,DELETE)",
 	}
-	complexJCL := jobs.CreateJobWithStep("COMPLEX", "ACCT", "USER", "A", "(1,1)", "STEP1", "IEFBR14", ddStatements)
-	fmt.Printf("   Complex JCL:\n%s\n", complexJCL)
+	card := &jobs.JobCardBuilder{JobName: "COMPLEX", Account: "ACCT", Programmer: "USER", MsgClass: "A", MsgLevel: "(1,1)"}
+	complexJCL, err := card.BuildJob("STEP1", "IEFBR14", ddStatements)
+	if err != nil {
+		fmt.Printf("   Invalid JCL: %v\n", err)
+	} else {
+		fmt.Printf("   Complex JCL:\n%s\n", complexJCL)
+	}
 
 	// Example 16: Wait for job completion (demonstration)
 	fmt.Println("\n16. Waiting for job completion:")
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/zowe/zowe-client-go-sdk/pkg/datasets"
 	"github.com/zowe/zowe-client-go-sdk/pkg/jobs"
@@ -14,29 +13,17 @@ func main() {
 	fmt.Println("=== FINAL COMPLETE SDK TEST (INCLUDING UPLOAD) ===")
 	fmt.Println()
 
-	host := os.Getenv("ZXPLORE_HOST")
-	port := os.Getenv("ZXPLORE_PORT")
-	user := os.Getenv("ZXPLORE_USER")
-	password := os.Getenv("ZXPLORE_PASSWORD")
-
-	if host == "" || port == "" || user == "" || password == "" {
-		log.Fatal(" Missing required environment variables")
-	}
-
 	cfg := &profile.ZOSMFProfile{
 		Name:               "zxplore",
-		Host:               host,
 		Port:               10443,
 		Protocol:           "https",
-		User:               user,
-		Password:           password,
 		RejectUnauthorized: false,
 	}
-
-	if port != "" {
-		if _, err := fmt.Sscanf(port, "%d", &cfg.Port); err != nil {
-			cfg.Port = 10443
-		}
+	if err := profile.ApplyEnvOverrides(cfg, "ZXPLORE"); err != nil {
+		log.Fatalf(" Invalid environment variables: %v", err)
+	}
+	if err := profile.ValidateProfile(cfg); err != nil {
+		log.Fatalf(" Missing required environment variables: %v", err)
 	}
 
 	fmt.Printf("🔗 Connecting to %s://%s:%d%s as %s\n", cfg.Protocol, cfg.Host, cfg.Port, "/zosmf", cfg.User)
@@ -76,7 +63,7 @@ func main() {
 
 	totalTests++
 	fmt.Print("List user datasets... ")
-	dl, err := dm.ListDatasets(&datasets.DatasetFilter{Name: user + ".*", Limit: 5})
+	dl, err := dm.ListDatasets(&datasets.DatasetFilter{Name: cfg.User + ".*", Limit: 5})
 	if err != nil {
 		fmt.Printf(" FAILED: %v\n", err)
 	} else {
@@ -159,7 +146,7 @@ func main() {
 
 	totalTests++
 	fmt.Printf(" GetDatasetsByOwner... ")
-	ownerDS, err := dm.GetDatasetsByOwner(user, 3)
+	ownerDS, err := dm.GetDatasetsByOwner(cfg.User, 3)
 	if err != nil {
 		fmt.Printf(" FAILED: %v\n", err)
 	} else {
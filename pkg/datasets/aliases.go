@@ -0,0 +1,51 @@
+package datasets
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// UnmarshalJSON accepts the alias flag as a boolean or as "Y"/"YES", and treats a
+// member with an aliasof target as an alias
+func (m *DatasetMember) UnmarshalJSON(data []byte) error {
+	type plainDatasetMember DatasetMember
+	var aux struct {
+		plainDatasetMember
+		Alias json.RawMessage `json:"alias"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*m = DatasetMember(aux.plainDatasetMember)
+
+	if len(aux.Alias) > 0 {
+		var flag bool
+		var text string
+		if json.Unmarshal(aux.Alias, &flag) == nil {
+			m.Alias = flag
+		} else if json.Unmarshal(aux.Alias, &text) == nil {
+			text = strings.ToUpper(strings.TrimSpace(text))
+			m.Alias = text == "Y" || text == "YES" || text == "TRUE"
+		}
+	}
+	if m.AliasOf != "" {
+		m.Alias = true
+	}
+	return nil
+}
+
+// ListMemberAliases lists the alias entries of a partitioned dataset, each with the
+// member it is an alias of when z/OSMF reports it. Members not returned are primaries.
+func (dm *ZOSMFDatasetManager) ListMemberAliases(datasetName string) ([]DatasetMember, error) {
+	list, err := dm.listAllMembers(datasetName)
+	if err != nil {
+		return nil, err
+	}
+	var aliases []DatasetMember
+	for _, member := range list.Members {
+		if member.Alias {
+			aliases = append(aliases, member)
+		}
+	}
+	return aliases, nil
+}
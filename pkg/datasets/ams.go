@@ -0,0 +1,162 @@
+package datasets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AMSEndpoint is the z/OSMF access method services (IDCAMS) endpoint
+const AMSEndpoint = "/restfiles/ams"
+
+// MaxAMSStatementLength is the longest input line z/OSMF accepts for AMS statements
+const MaxAMSStatementLength = 255
+
+// maxConditionCodePattern matches the IDCAMS summary message
+var maxConditionCodePattern = regexp.MustCompile(`MAXIMUM CONDITION CODE WAS (\d+)`)
+
+// LISTCAT lines that identify an alias entry and the dataset it relates to
+var (
+	aliasEntryPattern       = regexp.MustCompile(`^\s*ALIAS\s*-+\s*\S+`)
+	aliasAssociationPattern = regexp.MustCompile(`^\s*(?:NONVSAM|CLUSTER)-+(\S+)`)
+)
+
+// InvokeAMS runs IDCAMS control statements. Statements longer than 255 characters
+// are split into continuation lines. The response holds the IDCAMS output and the
+// highest condition code it reported.
+func (dm *ZOSMFDatasetManager) InvokeAMS(statements []string) (*AMSResponse, error) {
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("at least one AMS statement is required")
+	}
+
+	var input []string
+	for _, statement := range statements {
+		input = append(input, splitAMSStatement(statement)...)
+	}
+
+	jsonBody, err := json.Marshal(map[string][]string{"input": input})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	var result AMSResponse
+	err = dm.doJSON("PUT", AMSEndpoint, nil, jsonHeaders(), bytes.NewReader(jsonBody), &result)
+	if amsChangesCatalog(statements) {
+		// Even a failed run may have carried out some of the statements
+		dm.cache.clear()
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range result.Output {
+		if m := maxConditionCodePattern.FindStringSubmatch(line); m != nil {
+			result.ReturnCode, _ = strconv.Atoi(m[1])
+		}
+	}
+
+	return &result, nil
+}
+
+// DeleteVSAMCluster deletes a VSAM cluster, optionally ignoring its retention period
+func (dm *ZOSMFDatasetManager) DeleteVSAMCluster(name string, purge bool) error {
+	if err := ValidateDatasetName(name); err != nil {
+		return fmt.Errorf("invalid cluster name: %w", err)
+	}
+
+	statement := fmt.Sprintf("DELETE '%s' CLUSTER", name)
+	if purge {
+		statement += " PURGE"
+	}
+	return dm.runAMSStatement("DELETE", statement)
+}
+
+// runAMSStatement runs one IDCAMS command that changes the catalog, failing on a
+// nonzero condition code
+func (dm *ZOSMFDatasetManager) runAMSStatement(command, statement string) error {
+	result, err := dm.InvokeAMS([]string{statement})
+	if err != nil {
+		return err
+	}
+	if result.ReturnCode != 0 {
+		return fmt.Errorf("IDCAMS %s failed with condition code %d: %s", command, result.ReturnCode, strings.Join(result.Output, "\n"))
+	}
+	return nil
+}
+
+// amsChangesCatalog reports whether IDCAMS statements may change datasets, which is
+// the case for anything but LISTCAT
+func amsChangesCatalog(statements []string) bool {
+	for _, statement := range statements {
+		command := strings.ToUpper(strings.Fields(statement + " ")[0])
+		if command != "LISTCAT" && command != "LISTC" {
+			return true
+		}
+	}
+	return false
+}
+
+// ListCatalog runs LISTCAT ALL for a catalog entry and returns the IDCAMS output
+func (dm *ZOSMFDatasetManager) ListCatalog(entry string) (*AMSResponse, error) {
+	if entry == "" {
+		return nil, fmt.Errorf("catalog entry cannot be empty")
+	}
+	if strings.Contains(entry, "'") {
+		return nil, fmt.Errorf("catalog entry cannot contain quotes: %s", entry)
+	}
+
+	return dm.InvokeAMS([]string{fmt.Sprintf("LISTCAT ENTRIES('%s') ALL", entry)})
+}
+
+// resolveAlias returns the dataset a catalog alias refers to, or "" when name is not an alias
+func (dm *ZOSMFDatasetManager) resolveAlias(name string) (string, error) {
+	result, err := dm.ListCatalog(name)
+	if err != nil {
+		return "", err
+	}
+	if result.ReturnCode != 0 {
+		return "", nil
+	}
+	return parseAliasTarget(result.Output), nil
+}
+
+// parseAliasTarget finds the related dataset in LISTCAT output for an alias entry:
+//
+//	ALIAS --------- USER.ALIAS
+//	     ASSOCIATIONS
+//	       NONVSAM--USER.REAL.DATA
+func parseAliasTarget(output []string) string {
+	isAlias := false
+	for _, line := range output {
+		if aliasEntryPattern.MatchString(line) {
+			isAlias = true
+			continue
+		}
+		if m := aliasAssociationPattern.FindStringSubmatch(line); isAlias && m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// splitAMSStatement breaks a statement into lines of at most 255 characters,
+// ending every line but the last with the IDCAMS continuation character
+func splitAMSStatement(statement string) []string {
+	var lines []string
+	for len(statement) > MaxAMSStatementLength {
+		// Leave room for " -" and prefer breaking between parameters
+		limit := MaxAMSStatementLength - 2
+		cut := strings.LastIndex(statement[:limit], " ")
+		if cut <= 0 {
+			// No blank to break at, so continue within the token using "+"
+			lines = append(lines, statement[:limit+1]+"+")
+			statement = statement[limit+1:]
+			continue
+		}
+		lines = append(lines, statement[:cut]+" -")
+		statement = strings.TrimLeft(statement[cut:], " ")
+	}
+	return append(lines, statement)
+}
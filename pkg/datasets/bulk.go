@@ -0,0 +1,156 @@
+package datasets
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Bulk downloads run this many requests in parallel by default, and never more than the maximum
+const (
+	defaultDownloadConcurrency = 4
+	MaxDownloadConcurrency     = 16
+)
+
+// DownloadMembers downloads members of a partitioned dataset into targetDir, one file
+// per member named after the member. An empty member list downloads every member.
+// At most concurrency downloads (capped at MaxDownloadConcurrency) run at once.
+// The returned map has an entry for every member, nil on success; the error is only
+// set when the download could not start.
+func (dm *ZOSMFDatasetManager) DownloadMembers(datasetName string, members []string, targetDir string, concurrency int) (map[string]error, error) {
+	if err := ValidateDatasetName(datasetName); err != nil {
+		return nil, err
+	}
+	if targetDir == "" {
+		return nil, fmt.Errorf("target directory cannot be empty")
+	}
+
+	if len(members) == 0 {
+		memberList, err := dm.listAllMembers(datasetName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members: %w", err)
+		}
+		for _, member := range memberList.Members {
+			members = append(members, member.Name)
+		}
+	}
+
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create target directory: %w", err)
+	}
+
+	if concurrency <= 0 {
+		concurrency = defaultDownloadConcurrency
+	}
+	if concurrency > MaxDownloadConcurrency {
+		concurrency = MaxDownloadConcurrency
+	}
+
+	results := make(map[string]error, len(members))
+	var mu sync.Mutex
+	queue := make(chan string)
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency && i < len(members); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for member := range queue {
+				err := dm.downloadMemberToFile(datasetName, member, targetDir)
+				mu.Lock()
+				results[member] = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, member := range members {
+		queue <- member
+	}
+	close(queue)
+	wg.Wait()
+
+	return results, nil
+}
+
+// downloadMemberToFile writes a single member to targetDir
+func (dm *ZOSMFDatasetManager) downloadMemberToFile(datasetName, member, targetDir string) error {
+	// Member names become file names, so reject anything that is not a plain member name
+	if err := ValidateMemberName(member); err != nil {
+		return err
+	}
+
+	content, err := dm.DownloadTextFromMember(datasetName, member)
+	if err != nil {
+		return fmt.Errorf("failed to download member %s: %w", member, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(targetDir, member), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write member %s: %w", member, err)
+	}
+	return nil
+}
+
+// DownloadToFile streams a dataset, or a member when memberName is set, to localPath,
+// creating its parent directories. Text mode converts from the host code page; binary
+// mode copies the bytes as they are on the host. The file is only replaced once the
+// whole download has arrived. It returns the number of bytes written.
+func (dm *ZOSMFDatasetManager) DownloadToFile(datasetName, memberName, localPath string, binary bool) (int64, error) {
+	if err := ValidateDownloadRequest(&DownloadRequest{DatasetName: datasetName, MemberName: memberName}); err != nil {
+		return 0, err
+	}
+	if localPath == "" {
+		return 0, fmt.Errorf("local path cannot be empty")
+	}
+	if err := dm.checkMemberGiven(datasetName, memberName, false); err != nil {
+		return 0, err
+	}
+
+	headers := map[string]string{}
+	if binary {
+		headers["X-IBM-Data-Type"] = "binary"
+	} else {
+		setTextConversionHeaders(headers, dm.sourceEncoding(""), "")
+	}
+
+	resp, err := dm.do("GET", contentPath(datasetName, memberName), nil, headers, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, contentRequestError(resp.StatusCode, body)
+	}
+
+	dir := filepath.Dir(localPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	// Write next to the target so the rename cannot cross file systems
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(localPath)+".*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", localPath, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", localPath, err)
+	}
+	if err := os.Rename(tmp.Name(), localPath); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", localPath, err)
+	}
+	return written, nil
+}
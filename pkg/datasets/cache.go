@@ -0,0 +1,168 @@
+package datasets
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithCache keeps ListDatasets and ListMembers responses in memory for ttl.
+// Mutating calls made through the manager drop the entries they affect; changes
+// made elsewhere are only seen once an entry expires or ForceRefresh is set.
+func WithCache(ttl time.Duration) ManagerOption {
+	return func(dm *ZOSMFDatasetManager) {
+		if ttl <= 0 {
+			dm.cache = nil
+			return
+		}
+		dm.cache = newListCache(ttl)
+	}
+}
+
+// listCache holds dataset and member list responses until they expire
+type listCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	datasets map[string]cachedDatasetList
+	members  map[string]map[int]cachedMemberList // By dataset, then limit
+}
+
+type cachedDatasetList struct {
+	list    *DatasetList
+	expires time.Time
+}
+
+type cachedMemberList struct {
+	list    *MemberList
+	expires time.Time
+}
+
+func newListCache(ttl time.Duration) *listCache {
+	return &listCache{
+		ttl:      ttl,
+		datasets: make(map[string]cachedDatasetList),
+		members:  make(map[string]map[int]cachedMemberList),
+	}
+}
+
+// datasetListKey identifies a ListDatasets request by its filter and the patterns it lists
+func datasetListKey(filter *DatasetFilter, patterns []string) string {
+	// ForceRefresh is not serialized, so a forced listing refreshes the normal entry
+	key, _ := json.Marshal(struct {
+		Filter   *DatasetFilter
+		Patterns []string
+	}{filter, patterns})
+	return string(key)
+}
+
+// memberListKey identifies a ListMembers request; dataset names are case-insensitive
+func memberListKey(datasetName string) string {
+	return strings.ToUpper(strings.TrimSpace(datasetName))
+}
+
+func (c *listCache) getDatasets(key string) (*DatasetList, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.datasets[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.datasets, key)
+		return nil, false
+	}
+	return copyDatasetList(entry.list), true
+}
+
+func (c *listCache) putDatasets(key string, list *DatasetList) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.datasets[key] = cachedDatasetList{list: copyDatasetList(list), expires: time.Now().Add(c.ttl)}
+}
+
+func (c *listCache) getMembers(key string, limit int) (*MemberList, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.members[key][limit]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.members[key], limit)
+		return nil, false
+	}
+	return copyMemberList(entry.list), true
+}
+
+func (c *listCache) putMembers(key string, limit int, list *MemberList) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.members[key] == nil {
+		c.members[key] = make(map[int]cachedMemberList)
+	}
+	c.members[key][limit] = cachedMemberList{list: copyMemberList(list), expires: time.Now().Add(c.ttl)}
+}
+
+// invalidateDatasets drops every cached dataset list, since any pattern may match
+// the dataset that changed
+func (c *listCache) invalidateDatasets() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.datasets = make(map[string]cachedDatasetList)
+}
+
+// invalidateMembers drops the cached member lists of a dataset
+func (c *listCache) invalidateMembers(datasetName string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.members, memberListKey(datasetName))
+}
+
+func (c *listCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.datasets = make(map[string]cachedDatasetList)
+	c.members = make(map[string]map[int]cachedMemberList)
+}
+
+// InvalidateCache drops every cached list response. It does nothing when the
+// manager was created without WithCache.
+func (dm *ZOSMFDatasetManager) InvalidateCache() {
+	dm.cache.clear()
+}
+
+// copyDatasetList copies the list so callers cannot modify cached entries
+func copyDatasetList(list *DatasetList) *DatasetList {
+	copied := *list
+	copied.Datasets = append([]Dataset(nil), list.Datasets...)
+	return &copied
+}
+
+// copyMemberList copies the list so callers cannot modify cached entries
+func copyMemberList(list *MemberList) *MemberList {
+	copied := *list
+	copied.Members = append([]DatasetMember(nil), list.Members...)
+	return &copied
+}
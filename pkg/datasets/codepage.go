@@ -0,0 +1,71 @@
+package datasets
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/encoding"
+)
+
+// recordDataType makes z/OSMF transfer content unconverted, each record preceded by
+// its length as a 4-byte big-endian integer
+const recordDataType = "record"
+
+// checkCodePage rejects a client-side code page that is unknown or combined with
+// server-side conversion
+func checkCodePage(codePage, sourceEncoding, targetEncoding string) error {
+	if codePage == "" {
+		return nil
+	}
+	if sourceEncoding != "" || targetEncoding != "" {
+		return fmt.Errorf("CodePage converts on the client and cannot be combined with SourceEncoding or TargetEncoding")
+	}
+	_, err := encoding.CanonicalCodePage(codePage)
+	return err
+}
+
+// decodeRecords converts records transferred with recordDataType from EBCDIC,
+// ending each with a newline
+func decodeRecords(data []byte, codePage string) (string, error) {
+	var content strings.Builder
+	for offset := 0; offset < len(data); {
+		if len(data)-offset < 4 {
+			return "", fmt.Errorf("truncated record length at offset %d", offset)
+		}
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		offset += 4
+		if length > len(data)-offset {
+			return "", fmt.Errorf("record at offset %d is %d bytes, but only %d remain", offset-4, length, len(data)-offset)
+		}
+		record, err := encoding.ConvertFromEBCDIC(data[offset:offset+length], codePage)
+		if err != nil {
+			return "", err
+		}
+		content.WriteString(record)
+		content.WriteByte('\n')
+		offset += length
+	}
+	return content.String(), nil
+}
+
+// encodeRecords converts each line of content to EBCDIC and frames it for a
+// recordDataType upload. CRLF line endings and a final newline are accepted.
+func encodeRecords(content, codePage string) ([]byte, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return []byte{}, nil
+	}
+
+	var data []byte
+	for i, line := range strings.Split(content, "\n") {
+		record, err := encoding.ConvertToEBCDIC(line, codePage)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		data = binary.BigEndian.AppendUint32(data, uint32(len(record)))
+		data = append(data, record...)
+	}
+	return data, nil
+}
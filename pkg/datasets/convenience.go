@@ -1,11 +1,14 @@
 package datasets
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/zowe/zowe-client-go-sdk/pkg/internal/deprecation"
 	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
 )
 
@@ -21,27 +24,33 @@ func CreateDatasetManager(pm *profile.ZOSMFProfileManager, profileName string) (
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	return NewDatasetManager(session), nil
+	return New(session), nil
 }
 
 // CreateDatasetManagerDirect creates a dataset manager with connection details
+//
+// Deprecated: create the session with profile.CreateSessionDirect and pass it to New.
 func CreateDatasetManagerDirect(host string, port int, user, password string) (*ZOSMFDatasetManager, error) {
+	deprecation.Report("datasets.CreateDatasetManagerDirect", "datasets.New")
 	session, err := profile.CreateSessionDirect(host, port, user, password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	return NewDatasetManager(session), nil
+	return New(session), nil
 }
 
 // CreateDatasetManagerDirectWithOptions creates a dataset manager with extra options
+//
+// Deprecated: create the session with profile.CreateSessionDirectWithOptions and pass it to New.
 func CreateDatasetManagerDirectWithOptions(host string, port int, user, password string, rejectUnauthorized bool, basePath string) (*ZOSMFDatasetManager, error) {
+	deprecation.Report("datasets.CreateDatasetManagerDirectWithOptions", "datasets.New")
 	session, err := profile.CreateSessionDirectWithOptions(host, port, user, password, rejectUnauthorized, basePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	return NewDatasetManager(session), nil
+	return New(session), nil
 }
 
 // CreateSequentialDataset creates a sequential dataset with defaults
@@ -80,6 +89,106 @@ func (dm *ZOSMFDatasetManager) CreatePartitionedDataset(name string) error {
 	return dm.CreateDataset(request)
 }
 
+// CreateDatasetLike creates a dataset with the DCB and space attributes of an
+// existing model dataset, like ISPF "allocate like". Non-zero fields of overrides
+// replace the copied values. z/OSMF does not report the model's primary and
+// secondary quantities, so the primary is its current allocation and the
+// secondary half of that; PDS directory blocks default to 5.
+func (dm *ZOSMFDatasetManager) CreateDatasetLike(modelName, newName string, overrides *CreateDatasetRequest) error {
+	model, err := dm.GetDataset(modelName)
+	if err != nil {
+		return fmt.Errorf("failed to get model dataset %s: %w", modelName, err)
+	}
+
+	request, err := createRequestFromModel(model, newName)
+	if err != nil {
+		return err
+	}
+	applyCreateOverrides(request, overrides)
+
+	return dm.CreateDataset(request)
+}
+
+// createRequestFromModel converts the list attributes of a model dataset into a create request
+func createRequestFromModel(model *Dataset, newName string) (*CreateDatasetRequest, error) {
+	if model.IsMigrated() {
+		return nil, fmt.Errorf("model dataset %s is migrated, recall it first", model.Name)
+	}
+
+	request := &CreateDatasetRequest{
+		Name:         newName,
+		RecordFormat: RecordFormat(model.RecordFormat),
+		RecordLength: RecordLength(parseAttributeInt(model.RecordLength)),
+		BlockSize:    BlockSize(parseAttributeInt(model.BlockSize)),
+	}
+
+	switch kind := model.DatasetKind(); kind {
+	case DatasetTypeSequential, DatasetTypePartitioned, DatasetTypePDSE:
+		request.Type = kind
+	case DatasetTypeVSAM:
+		return nil, fmt.Errorf("model dataset %s is VSAM, which cannot be allocated like", model.Name)
+	default:
+		return nil, fmt.Errorf("model dataset %s has unsupported organization: %q", model.Name, model.Type)
+	}
+
+	// Size the new dataset from the model's current allocation
+	usage := parseDatasetUsage(model)
+	request.Space.Unit = SpaceUnitTracks
+	request.Space.Primary = usage.AllocatedTracks
+	if strings.HasPrefix(strings.ToUpper(usage.SpaceUnit), "CYL") {
+		request.Space.Unit = SpaceUnitCylinders
+		request.Space.Primary = usage.AllocatedCylinders
+	}
+	if request.Space.Primary <= 0 {
+		request.Space.Primary = 1
+	}
+	request.Space.Secondary = (request.Space.Primary + 1) / 2
+
+	if request.Type == DatasetTypePartitioned {
+		request.Space.Directory = 5
+		request.Directory = 5
+	}
+
+	return request, nil
+}
+
+// applyCreateOverrides copies the non-zero fields of overrides onto request
+func applyCreateOverrides(request, overrides *CreateDatasetRequest) {
+	if overrides == nil {
+		return
+	}
+	if overrides.Type != "" {
+		request.Type = overrides.Type
+	}
+	if overrides.Volume != "" {
+		request.Volume = overrides.Volume
+	}
+	if overrides.Space.Primary > 0 {
+		request.Space.Primary = overrides.Space.Primary
+	}
+	if overrides.Space.Secondary > 0 {
+		request.Space.Secondary = overrides.Space.Secondary
+	}
+	if overrides.Space.Unit != "" {
+		request.Space.Unit = overrides.Space.Unit
+	}
+	if overrides.Space.Directory > 0 {
+		request.Space.Directory = overrides.Space.Directory
+	}
+	if overrides.RecordFormat != "" {
+		request.RecordFormat = overrides.RecordFormat
+	}
+	if overrides.RecordLength > 0 {
+		request.RecordLength = overrides.RecordLength
+	}
+	if overrides.BlockSize > 0 {
+		request.BlockSize = overrides.BlockSize
+	}
+	if overrides.Directory > 0 {
+		request.Directory = overrides.Directory
+	}
+}
+
 // CreateDatasetWithOptions creates a dataset with custom settings
 func (dm *ZOSMFDatasetManager) CreateDatasetWithOptions(name string, datasetType DatasetType, space Space, recordFormat RecordFormat, recordLength RecordLength, blockSize BlockSize) error {
 	request := &CreateDatasetRequest{
@@ -98,17 +207,29 @@ func (dm *ZOSMFDatasetManager) CreateDatasetWithOptions(name string, datasetType
 
 // UploadText uploads text content to a dataset
 func (dm *ZOSMFDatasetManager) UploadText(datasetName, content string) error {
+	return dm.UploadTextWithOptions(datasetName, content, nil)
+}
+
+// UploadTextWithOptions uploads text content to a dataset, verifying it when opts.Verify is set
+func (dm *ZOSMFDatasetManager) UploadTextWithOptions(datasetName, content string, opts *TextUploadOptions) error {
 	request := &UploadRequest{
-		DatasetName: datasetName,
-		Content:     content,
-		Encoding:    "UTF-8",
-		Replace:     true,
+		DatasetName:    datasetName,
+		Content:        content,
+		TargetEncoding: "UTF-8",
+		Replace:        true,
 	}
+	opts.apply(request)
 	return dm.UploadContent(request)
 }
 
 // UploadTextToMember uploads text content to a member in a partitioned dataset
 func (dm *ZOSMFDatasetManager) UploadTextToMember(datasetName, memberName, content string) error {
+	return dm.UploadTextToMemberWithOptions(datasetName, memberName, content, nil)
+}
+
+// UploadTextToMemberWithOptions uploads text content to a member in a partitioned
+// dataset, verifying it when opts.Verify is set
+func (dm *ZOSMFDatasetManager) UploadTextToMemberWithOptions(datasetName, memberName, content string, opts *TextUploadOptions) error {
 	// Basic validation
 	if err := ValidateMemberName(memberName); err != nil {
 		return fmt.Errorf("invalid member name: %w", err)
@@ -116,12 +237,13 @@ func (dm *ZOSMFDatasetManager) UploadTextToMember(datasetName, memberName, conte
 
 	// Create the upload request
 	request := &UploadRequest{
-		DatasetName: datasetName,
-		MemberName:  memberName,
-		Content:     content,
-		Encoding:    "UTF-8",
-		Replace:     true,
+		DatasetName:    datasetName,
+		MemberName:     memberName,
+		Content:        content,
+		TargetEncoding: "UTF-8",
+		Replace:        true,
 	}
+	opts.apply(request)
 
 	// Try the upload with enhanced error handling
 	err := dm.UploadContent(request)
@@ -162,12 +284,12 @@ func (dm *ZOSMFDatasetManager) UploadTextToMemberWithValidation(datasetName, mem
 	}
 
 	// Verify it's a partitioned dataset
-	if dsInfo.Type != "PO" && dsInfo.Type != "PO-E" {
+	if !dsInfo.IsPartitioned() {
 		return fmt.Errorf("dataset %s is not a partitioned dataset (type: %s)", datasetName, dsInfo.Type)
 	}
 
 	// Try to list members first to ensure directory is accessible
-	_, err = dm.ListMembers(datasetName)
+	members, err := dm.listAllMembers(datasetName)
 	if err != nil {
 		// If we can't list members, the directory might be corrupted
 		return fmt.Errorf("PDS directory error for %s: %w. This may indicate directory corruption or insufficient directory space. Consider using IEBCOPY or ISPF to repair the PDS directory", datasetName, err)
@@ -175,26 +297,23 @@ func (dm *ZOSMFDatasetManager) UploadTextToMemberWithValidation(datasetName, mem
 
 	// Check if member already exists - if so, we're replacing it
 	memberExists := false
-	members, err := dm.ListMembers(datasetName)
-	if err == nil {
-		for _, member := range members.Members {
-			if member.Name == memberName {
-				memberExists = true
-				break
-			}
+	for _, member := range members.Members {
+		if member.Name == memberName {
+			memberExists = true
+			break
 		}
 	}
 
 	// Create the upload request with enhanced error handling
 	request := &UploadRequest{
-		DatasetName: datasetName,
-		MemberName:  memberName,
-		Content:     content,
-		Encoding:    "UTF-8",
-		Replace:     true,
+		DatasetName:    datasetName,
+		MemberName:     memberName,
+		Content:        content,
+		TargetEncoding: "UTF-8",
+		Replace:        true,
 	}
 
-	// Attempt the upload with retry logic for directory issues
+	// Attempt the upload, retrying while the member is in use
 	err = dm.uploadWithRetry(request, memberExists)
 	if err != nil {
 		// Provide specific guidance for common PDS errors
@@ -213,8 +332,8 @@ func (dm *ZOSMFDatasetManager) UploadTextToMemberWithValidation(datasetName, mem
 // DownloadText downloads text content from a dataset
 func (dm *ZOSMFDatasetManager) DownloadText(datasetName string) (string, error) {
 	request := &DownloadRequest{
-		DatasetName: datasetName,
-		Encoding:    "UTF-8",
+		DatasetName:    datasetName,
+		TargetEncoding: "UTF-8",
 	}
 	return dm.DownloadContent(request)
 }
@@ -222,9 +341,9 @@ func (dm *ZOSMFDatasetManager) DownloadText(datasetName string) (string, error)
 // DownloadTextFromMember downloads text content from a member in a partitioned dataset
 func (dm *ZOSMFDatasetManager) DownloadTextFromMember(datasetName, memberName string) (string, error) {
 	request := &DownloadRequest{
-		DatasetName: datasetName,
-		MemberName:  memberName,
-		Encoding:    "UTF-8",
+		DatasetName:    datasetName,
+		MemberName:     memberName,
+		TargetEncoding: "UTF-8",
 	}
 	return dm.DownloadContent(request)
 }
@@ -243,8 +362,9 @@ func (dm *ZOSMFDatasetManager) GetDatasetsByOwner(owner string, limit int) (*Dat
 // GetDatasetsByType gets datasets of a specific type
 func (dm *ZOSMFDatasetManager) GetDatasetsByType(datasetType string, limit int) (*DatasetList, error) {
 	filter := &DatasetFilter{
-		Type:  datasetType,
-		Limit: limit,
+		Type:                datasetType,
+		Limit:               limit,
+		DefaultToUserPrefix: true,
 	}
 	return dm.ListDatasets(filter)
 }
@@ -258,12 +378,20 @@ func (dm *ZOSMFDatasetManager) GetDatasetsByName(namePattern string, limit int)
 	return dm.ListDatasets(filter)
 }
 
-// ValidateDatasetName validates a dataset name according to z/OS naming conventions
+// ValidateDatasetName validates a dataset name according to z/OS naming conventions.
+// A relative GDG generation, NAME(0), NAME(+n) or NAME(-n), is accepted.
 func ValidateDatasetName(name string) error {
 	if name == "" {
 		return fmt.Errorf("dataset name cannot be empty")
 	}
 
+	if base, generation, ok := ParseRelativeGeneration(name); ok {
+		if err := validateRelativeGeneration(base, generation); err != nil {
+			return err
+		}
+		name = base
+	}
+
 	// Check length (1-44 characters)
 	if len(name) > 44 {
 		return fmt.Errorf("dataset name cannot exceed 44 characters")
@@ -349,11 +477,24 @@ func ValidateCreateDatasetRequest(request *CreateDatasetRequest) error {
 	if request.Space.Secondary < 0 {
 		return fmt.Errorf("secondary space allocation cannot be negative")
 	}
+	if request.Space.AverageBlock < 0 || request.Space.AverageBlock > MaxAverageBlock {
+		return fmt.Errorf("average block size must be between 1 and %d", MaxAverageBlock)
+	}
 
 	// Validate space unit
 	switch request.Space.Unit {
-	case SpaceUnitTracks, SpaceUnitCylinders, SpaceUnitKB, SpaceUnitMB, SpaceUnitGB:
+	case SpaceUnitTracks, SpaceUnitCylinders:
 		// Valid units
+	case SpaceUnitKB, SpaceUnitMB, SpaceUnitGB:
+		// Byte-based units are sized by the average block length
+		if request.Space.AverageBlock <= 0 {
+			return fmt.Errorf("average block size must be greater than 0 when allocating in %s", request.Space.Unit)
+		}
+	case SpaceUnitBlocks:
+		// Block allocation is sized by the average block length
+		if request.Space.AverageBlock <= 0 && request.BlockSize <= 0 {
+			return fmt.Errorf("block size is required when allocating in blocks")
+		}
 	default:
 		return fmt.Errorf("invalid space unit: %s", request.Space.Unit)
 	}
@@ -382,6 +523,28 @@ func ValidateCreateDatasetRequest(request *CreateDatasetRequest) error {
 		}
 	}
 
+	// Validate SMS class names
+	smsClasses := []struct{ label, name string }{
+		{"data class", request.DataClass},
+		{"management class", request.ManagementClass},
+		{"storage class", request.StorageClass},
+	}
+	for _, class := range smsClasses {
+		if class.name != "" && !isValidSMSClassName(class.name) {
+			return fmt.Errorf("invalid %s: %q", class.label, class.name)
+		}
+	}
+
+	// Validate the device unit; SMS picks the device for datasets with a storage class
+	if request.Unit != "" {
+		if !isValidUnitName(request.Unit) {
+			return fmt.Errorf("invalid unit: %q", request.Unit)
+		}
+		if request.StorageClass != "" {
+			return fmt.Errorf("unit %s cannot be combined with storage class %s", request.Unit, request.StorageClass)
+		}
+	}
+
 	// Validate directory blocks for partitioned datasets
 	if request.Type == DatasetTypePartitioned && request.Directory > 0 {
 		if request.Directory < 1 || request.Directory > 9999 {
@@ -408,6 +571,9 @@ func ValidateUploadRequest(request *UploadRequest) error {
 		if err := ValidateMemberName(request.MemberName); err != nil {
 			return fmt.Errorf("invalid member name: %w", err)
 		}
+		if _, _, ok := ParseRelativeGeneration(request.DatasetName); ok {
+			return fmt.Errorf("member name cannot be used with a relative generation")
+		}
 	}
 
 	// Validate content
@@ -434,38 +600,86 @@ func ValidateDownloadRequest(request *DownloadRequest) error {
 		if err := ValidateMemberName(request.MemberName); err != nil {
 			return fmt.Errorf("invalid member name: %w", err)
 		}
+		if _, _, ok := ParseRelativeGeneration(request.DatasetName); ok {
+			return fmt.Errorf("member name cannot be used with a relative generation")
+		}
 	}
 
 	return nil
 }
 
+// isValidSMSClassName checks an SMS class name: 1-8 characters A-Z, 0-9, @, # or $,
+// not starting with a digit
+func isValidSMSClassName(name string) bool {
+	if len(name) == 0 || len(name) > 8 || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, char := range name {
+		if !(char >= 'A' && char <= 'Z') && !(char >= '0' && char <= '9') && char != '@' && char != '#' && char != '$' {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidUnitName checks a device type or esoteric unit name (SYSDA, 3390, VIO):
+// 1-8 characters A-Z, 0-9, @, # or $
+func isValidUnitName(name string) bool {
+	if len(name) == 0 || len(name) > 8 {
+		return false
+	}
+	for _, char := range name {
+		if !(char >= 'A' && char <= 'Z') && !(char >= '0' && char <= '9') && char != '@' && char != '#' && char != '$' {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxAverageBlock is the largest average block length z/OS accepts
+const MaxAverageBlock = 65535
+
+// defaultAverageBlock returns the average block length the space helpers use:
+// 1024 bytes for KB, MB and GB units, none otherwise
+func defaultAverageBlock(unit SpaceUnit) int {
+	switch unit {
+	case SpaceUnitKB, SpaceUnitMB, SpaceUnitGB:
+		return 1024
+	default:
+		return 0
+	}
+}
+
 // CreateDefaultSpace creates a default space allocation
 func CreateDefaultSpace(unit SpaceUnit) Space {
 	return Space{
-		Primary:   10,
-		Secondary: 5,
-		Unit:      unit,
-		Directory: 5, // For partitioned datasets
+		Primary:      10,
+		Secondary:    5,
+		Unit:         unit,
+		Directory:    5, // For partitioned datasets
+		AverageBlock: defaultAverageBlock(unit),
 	}
 }
 
 // CreateLargeSpace creates a large space allocation
 func CreateLargeSpace(unit SpaceUnit) Space {
 	return Space{
-		Primary:   100,
-		Secondary: 50,
-		Unit:      unit,
-		Directory: 20, // For partitioned datasets
+		Primary:      100,
+		Secondary:    50,
+		Unit:         unit,
+		Directory:    20, // For partitioned datasets
+		AverageBlock: defaultAverageBlock(unit),
 	}
 }
 
 // CreateSmallSpace creates a small space allocation
 func CreateSmallSpace(unit SpaceUnit) Space {
 	return Space{
-		Primary:   5,
-		Secondary: 2,
-		Unit:      unit,
-		Directory: 2, // For partitioned datasets
+		Primary:      5,
+		Secondary:    2,
+		Unit:         unit,
+		Directory:    2, // For partitioned datasets
+		AverageBlock: defaultAverageBlock(unit),
 	}
 }
 
@@ -479,7 +693,8 @@ func (dm *ZOSMFDatasetManager) CopyMemberWithSameName(sourceDataset, targetDatas
 	return dm.CopyMember(sourceDataset, memberName, targetDataset, memberName)
 }
 
-// uploadWithRetry attempts to upload content with retry logic for PDS directory issues
+// uploadWithRetry attempts to upload content, retrying while the dataset is in use
+// (for example open in ISPF edit) or the request fails to reach z/OSMF
 func (dm *ZOSMFDatasetManager) uploadWithRetry(request *UploadRequest, memberExists bool) error {
 	const maxRetries = 3
 	const retryDelay = time.Second * 2
@@ -495,13 +710,8 @@ func (dm *ZOSMFDatasetManager) uploadWithRetry(request *UploadRequest, memberExi
 		lastError = err
 
 		// Check if this is a retryable error
-		errorStr := strings.ToLower(err.Error())
-		isRetryable := strings.Contains(errorStr, "isrz002") ||
-			strings.Contains(errorStr, "i/o error") ||
-			strings.Contains(errorStr, "lmfind error") ||
-			strings.Contains(errorStr, "directory") ||
-			strings.Contains(errorStr, "timeout") ||
-			strings.Contains(errorStr, "connection")
+		var transportErr *url.Error
+		isRetryable := errors.Is(err, ErrDatasetInUse) || errors.As(err, &transportErr)
 
 		if !isRetryable {
 			// Non-retryable error, fail immediately
@@ -559,12 +769,12 @@ func (dm *ZOSMFDatasetManager) CheckPDSDirectoryHealth(datasetName string) error
 	}
 
 	// Verify it's a PDS
-	if dsInfo.Type != "PO" && dsInfo.Type != "PO-E" {
+	if !dsInfo.IsPartitioned() {
 		return fmt.Errorf("dataset %s is not a partitioned dataset (type: %s)", datasetName, dsInfo.Type)
 	}
 
-	// Try to list members to test directory accessibility
-	_, err = dm.ListMembers(datasetName)
+	// Try to list members to test directory accessibility; one is enough
+	_, err = dm.ListMembersWithOptions(datasetName, &MemberListOptions{Limit: 1})
 	if err != nil {
 		return fmt.Errorf("PDS directory is not accessible: %w", err)
 	}
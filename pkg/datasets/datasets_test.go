@@ -1,16 +1,31 @@
 package datasets
 
 import (
+	"context"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zowe/zowe-client-go-sdk/pkg/encoding"
 	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+	"github.com/zowe/zowe-client-go-sdk/pkg/zosmftest"
 )
 
 // createTestProfile creates a profile for testing with the given server URL
@@ -50,6 +65,7 @@ func TestNewDatasetManager(t *testing.T) {
 	dm := NewDatasetManager(session)
 	assert.NotNil(t, dm)
 	assert.Equal(t, session, dm.session)
+	assert.Same(t, session, dm.Session())
 }
 
 func TestNewDatasetManagerFromProfile(t *testing.T) {
@@ -125,7 +141,7 @@ func TestListDatasets(t *testing.T) {
 	dm := NewDatasetManager(session)
 
 	// Test list datasets
-	datasetList, err := dm.ListDatasets(nil)
+	datasetList, err := dm.ListDatasets(&DatasetFilter{DefaultToUserPrefix: true})
 	require.NoError(t, err)
 	assert.Len(t, datasetList.Datasets, 1)
 	assert.Equal(t, "TEST.DATA", datasetList.Datasets[0].Name)
@@ -212,34 +228,32 @@ func TestCreateDataset(t *testing.T) {
 }
 
 func TestDeleteDataset(t *testing.T) {
-	// Create test server
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "DELETE", r.Method)
-		assert.Equal(t, "/api/v1/restfiles/ds/TEST.DATA", r.URL.Path)
-
-		w.WriteHeader(http.StatusNoContent)
-	}))
-	defer server.Close()
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnDeleteDataset("TEST.DATA")
 
 	// Create dataset manager
-	profile := createTestProfile(server.URL)
-	session, err := profile.NewSession()
+	session, err := mock.NewSession()
 	require.NoError(t, err)
 	dm := NewDatasetManager(session)
 
 	// Test delete dataset
 	err = dm.DeleteDataset("TEST.DATA")
 	assert.NoError(t, err)
+	assert.Len(t, mock.RequestsTo("DELETE", "/restfiles/ds/TEST.DATA"), 1)
 }
 
 func TestUploadContent(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if serveDsorgLookup(w, r, "TEST.DATA", "PS") {
+			return
+		}
 		assert.Equal(t, "PUT", r.Method)
 		assert.Equal(t, "/api/v1/restfiles/ds/TEST.DATA", r.URL.Path)
 
 		// Verify content type
-		assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+		assert.Equal(t, "text/plain;charset=UTF-8", r.Header.Get("Content-Type"))
 
 		// Read and verify request body
 		body, err := io.ReadAll(r.Body)
@@ -271,6 +285,9 @@ func TestUploadContent(t *testing.T) {
 func TestDownloadContent(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if serveDsorgLookup(w, r, "TEST.DATA", "PS") {
+			return
+		}
 		assert.Equal(t, "GET", r.Method)
 		assert.Equal(t, "/api/v1/restfiles/ds/TEST.DATA", r.URL.Path)
 
@@ -411,6 +428,78 @@ func TestExists(t *testing.T) {
 	assert.True(t, exists)
 }
 
+func TestExistsDirect(t *testing.T) {
+	var listed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == "/api/v1/restfiles/ds" {
+			listed = true
+			w.Header().Set("Content-Type", "application/json")
+			var items []Dataset
+			switch r.URL.Query().Get("dslevel") {
+			case "OLD.SERVER.DATA":
+				items = []Dataset{{Name: "OLD.SERVER.DATA"}}
+			case "USER.PDS":
+				items = []Dataset{{Name: "USER.PDS", Type: "PO"}, {Name: "USER.PDS.BACKUP", Type: "PO"}}
+			case "USER.MIGRATED":
+				items = []Dataset{{Name: "USER.MIGRATED", Volume: "MIGRAT"}}
+			case "USER.GONE":
+				items = []Dataset{{Name: "USER.GONE.SOON"}}
+			}
+			json.NewEncoder(w).Encode(DatasetList{Datasets: items})
+			return
+		}
+		assert.Equal(t, "HEAD", r.Method)
+		assert.Equal(t, "error", r.Header.Get("X-IBM-Migrated-Recall"))
+		assert.Equal(t, "0,1", r.Header.Get("X-IBM-Record-Range"))
+		switch r.URL.Path {
+		case "/api/v1/restfiles/ds/TEST.$DATA":
+			w.WriteHeader(http.StatusOK)
+		case "/api/v1/restfiles/ds/MISSING.DATA":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/v1/restfiles/ds/OLD.SERVER.DATA":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case "/api/v1/restfiles/ds/USER.PDS", "/api/v1/restfiles/ds/USER.GONE":
+			w.WriteHeader(http.StatusBadRequest)
+		case "/api/v1/restfiles/ds/USER.MIGRATED":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	exists, err := dm.ExistsDirect("test.$data")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = dm.ExistsDirect("MISSING.DATA")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.False(t, listed)
+
+	// Servers without HEAD support fall back to the list API
+	exists, err = dm.ExistsDirect("OLD.SERVER.DATA")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.True(t, listed)
+
+	// Partitioned and migrated datasets are confirmed by an exact-name listing
+	for name, want := range map[string]bool{"USER.PDS": true, "USER.MIGRATED": true, "USER.GONE": false} {
+		exists, err = dm.ExistsDirect(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, exists, name)
+	}
+
+	_, err = dm.ExistsDirect("BROKEN.DATA")
+	var apiErr *profile.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+}
+
 func TestCopySequentialDataset(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -601,11 +690,14 @@ func TestCreatePartitionedDataset(t *testing.T) {
 func TestUploadText(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if serveDsorgLookup(w, r, "TEST.DATA", "PS") {
+			return
+		}
 		assert.Equal(t, "PUT", r.Method)
 		assert.Equal(t, "/api/v1/restfiles/ds/TEST.DATA", r.URL.Path)
 
 		// Verify content type
-		assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+		assert.Equal(t, "text/plain;charset=UTF-8", r.Header.Get("Content-Type"))
 
 		// Read and verify request body
 		body, err := io.ReadAll(r.Body)
@@ -632,7 +724,7 @@ func TestUploadTextToMember(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "PUT", r.Method) // Changed from POST to PUT for members
 		assert.Equal(t, "/api/v1/restfiles/ds/TEST.PDS(MEMBER1)", r.URL.Path)
-		assert.Equal(t, "text/plain", r.Header.Get("Content-Type")) // Changed from JSON to plain text
+		assert.Equal(t, "text/plain;charset=UTF-8", r.Header.Get("Content-Type")) // Changed from JSON to plain text
 
 		// Read request body as plain text
 		body, err := io.ReadAll(r.Body)
@@ -659,9 +751,13 @@ func TestUploadTextToMember(t *testing.T) {
 func TestDownloadText(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if serveDsorgLookup(w, r, "TEST.DATA", "PS") {
+			return
+		}
 		assert.Equal(t, "GET", r.Method)
 		assert.Equal(t, "/api/v1/restfiles/ds/TEST.DATA", r.URL.Path)
-		assert.Equal(t, "UTF-8", r.URL.Query().Get("encoding"))
+		assert.Empty(t, r.URL.Query().Get("encoding"))
+		assert.Equal(t, "text/plain;charset=UTF-8", r.Header.Get("Content-Type"))
 
 		w.Header().Set("Content-Type", "text/plain")
 		w.Write([]byte("Hello, World!"))
@@ -685,7 +781,8 @@ func TestDownloadTextFromMember(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
 		assert.Equal(t, "/api/v1/restfiles/ds/TEST.PDS(MEMBER1)", r.URL.Path)
-		assert.Equal(t, "UTF-8", r.URL.Query().Get("encoding"))
+		assert.Empty(t, r.URL.Query().Get("encoding"))
+		assert.Equal(t, "text/plain;charset=UTF-8", r.Header.Get("Content-Type"))
 
 		w.Header().Set("Content-Type", "text/plain")
 		w.Write([]byte("Hello, World!"))
@@ -715,6 +812,10 @@ func TestValidateDatasetName(t *testing.T) {
 		"TEST#FILE",
 		"DATA$SET",
 		"A.B.C",
+		"MY.GDG(0)",
+		"MY.GDG(+1)",
+		"MY.GDG(-12)",
+		"MY.GDG(+255)",
 	}
 
 	for _, name := range validNames {
@@ -726,13 +827,18 @@ func TestValidateDatasetName(t *testing.T) {
 	invalidNames := []string{
 		"", // Empty
 		"toolongdatasetnamewithwaytoomanycharacters", // Too long
-		"test.data", // Lowercase
-		"123.DATA",  // Starts with number
-		".DATA",     // Starts with period
-		"DATA.",     // Ends with period
-		"DATA..SET", // Consecutive periods
-		"DATA--SET", // Consecutive hyphens
-		"DATA SET",  // Contains space
+		"test.data",    // Lowercase
+		"123.DATA",     // Starts with number
+		".DATA",        // Starts with period
+		"DATA.",        // Ends with period
+		"DATA..SET",    // Consecutive periods
+		"DATA--SET",    // Consecutive hyphens
+		"DATA SET",     // Contains space
+		"MY.GDG(1)",    // Generation without sign
+		"MY.GDG(+0)",   // Signed zero
+		"MY.GDG(+256)", // Beyond the generation limit
+		"MY.GDG(+1",    // Unclosed
+		"ABCDEFGH.ABCDEFGH.ABCDEFGH.ABCDEFGH.GDG(0)", // Base over 35 characters
 	}
 
 	for _, name := range invalidNames {
@@ -741,6 +847,192 @@ func TestValidateDatasetName(t *testing.T) {
 	}
 }
 
+func TestParseRelativeGeneration(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       string
+		generation int
+		ok         bool
+	}{
+		{"MY.GDG(0)", "MY.GDG", 0, true},
+		{"MY.GDG(+1)", "MY.GDG", 1, true},
+		{"MY.GDG(-3)", "MY.GDG", -3, true},
+		{"MY.PDS(MEMBER)", "MY.PDS(MEMBER)", 0, false},
+		{"MY.GDG.G0001V00", "MY.GDG.G0001V00", 0, false},
+	}
+	for _, tt := range tests {
+		base, generation, ok := ParseRelativeGeneration(tt.name)
+		assert.Equal(t, tt.base, base, tt.name)
+		assert.Equal(t, tt.generation, generation, tt.name)
+		assert.Equal(t, tt.ok, ok, tt.name)
+	}
+}
+
+func TestRelativeGenerationContent(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == "PUT" {
+			// + is escaped so it is not read as a space
+			assert.Contains(t, r.RequestURI, "%2B1")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte("LATEST"))
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	require.NoError(t, dm.UploadText("MY.GDG(+1)", "NEW"))
+	content, err := dm.DownloadText("MY.GDG(0)")
+	require.NoError(t, err)
+	assert.Equal(t, "LATEST", content)
+	_, err = dm.DownloadText("MY.GDG(-1)")
+	require.NoError(t, err)
+
+	// No listing to check the organization of a relative generation
+	assert.Equal(t, []string{
+		"PUT /api/v1/restfiles/ds/MY.GDG(+1)",
+		"GET /api/v1/restfiles/ds/MY.GDG(0)",
+		"GET /api/v1/restfiles/ds/MY.GDG(-1)",
+	}, requests)
+
+	err = ValidateUploadRequest(&UploadRequest{DatasetName: "MY.GDG(+1)", MemberName: "MEMBER", Content: "X"})
+	assert.EqualError(t, err, "member name cannot be used with a relative generation")
+	err = ValidateDownloadRequest(&DownloadRequest{DatasetName: "MY.GDG(0)"})
+	assert.NoError(t, err)
+}
+
+func TestListGDGGenerations(t *testing.T) {
+	var dslevels []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dslevels = append(dslevels, r.URL.Query().Get("dslevel"))
+		list := DatasetList{Datasets: []Dataset{
+			{Name: "MY.GDG.G0003V00"},
+			{Name: "MY.GDG.G0001V00", Volume: "MIGRAT"},
+			{Name: "MY.GDG.GOLDEN"},
+			{Name: "MY.GDG.G0002V01"},
+			{Name: "MY.GDG.G0004V00.BACKUP"},
+		}, JSONVersion: 1}
+		list.ReturnedRows = len(list.Datasets)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(list)
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	generations, err := dm.ListGDGGenerations("my.gdg")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MY.GDG.G0001V00", "MY.GDG.G0002V01", "MY.GDG.G0003V00"}, generations)
+	assert.Equal(t, []string{"MY.GDG.G*"}, dslevels)
+
+	_, err = dm.ListGDGGenerations("MY.GDG(0)")
+	assert.EqualError(t, err, "GDG base name cannot include a generation: MY.GDG(0)")
+	_, err = dm.ListGDGGenerations("")
+	assert.Error(t, err)
+	assert.Len(t, dslevels, 1)
+}
+
+func TestGDGDefineStatement(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		options *GDGOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "defaults",
+			base:    "USER.GDG",
+			options: &GDGOptions{Limit: 5},
+			want:    "DEFINE GENERATIONDATAGROUP (NAME('USER.GDG') LIMIT(5) NOEMPTY NOSCRATCH NOPURGE)",
+		},
+		{
+			name:    "all options",
+			base:    "USER.BACKUP.GDG",
+			options: &GDGOptions{Limit: 255, Empty: true, Scratch: true, Purge: true},
+			want:    "DEFINE GENERATIONDATAGROUP (NAME('USER.BACKUP.GDG') LIMIT(255) EMPTY SCRATCH PURGE)",
+		},
+		{
+			name:    "extended",
+			base:    "USER.GDG",
+			options: &GDGOptions{Limit: 999, Scratch: true, Extended: true},
+			want:    "DEFINE GENERATIONDATAGROUP (NAME('USER.GDG') LIMIT(999) NOEMPTY SCRATCH NOPURGE EXTENDED)",
+		},
+		{name: "zero limit", base: "USER.GDG", options: &GDGOptions{}, wantErr: "GDG limit must be between 1 and 255"},
+		{name: "limit over 255", base: "USER.GDG", options: &GDGOptions{Limit: 256}, wantErr: "GDG limit must be between 1 and 255"},
+		{name: "extended limit over 999", base: "USER.GDG", options: &GDGOptions{Limit: 1000, Extended: true}, wantErr: "GDG limit must be between 1 and 999"},
+		{name: "purge without scratch", base: "USER.GDG", options: &GDGOptions{Limit: 5, Purge: true}, wantErr: "GDG purge requires scratch"},
+		{name: "no options", base: "USER.GDG", wantErr: "GDG options are required"},
+		{name: "relative generation", base: "USER.GDG(0)", options: &GDGOptions{Limit: 5}, wantErr: "GDG base name cannot include a generation: USER.GDG(0)"},
+		{name: "base too long", base: "ABCDEFGH.ABCDEFGH.ABCDEFGH.ABCDEFGH.GDG", options: &GDGOptions{Limit: 5}, wantErr: "GDG base name cannot exceed 35 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement, err := gdgDefineStatement(tt.base, tt.options)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, statement)
+		})
+	}
+
+	assert.Equal(t, "DELETE 'USER.GDG' GENERATIONDATAGROUP", gdgDeleteStatement("USER.GDG", false))
+	assert.Equal(t, "DELETE 'USER.GDG' GENERATIONDATAGROUP FORCE", gdgDeleteStatement("USER.GDG", true))
+}
+
+func TestCreateAndDeleteGDGBase(t *testing.T) {
+	var inputs [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/api/v1/restfiles/ams", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var body struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		inputs = append(inputs, body.Input)
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(body.Input[0], "USER.FULL.GDG") {
+			w.Write([]byte(`{"output":["IDC3009I ** VSAM CATALOG RETURN CODE IS 50","IDC0002I IDCAMS PROCESSING COMPLETE. MAXIMUM CONDITION CODE WAS 8"]}`))
+			return
+		}
+		w.Write([]byte(`{"output":["IDC0001I FUNCTION COMPLETED, HIGHEST CONDITION CODE WAS 0","IDC0002I IDCAMS PROCESSING COMPLETE. MAXIMUM CONDITION CODE WAS 0"]}`))
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	require.NoError(t, dm.CreateGDGBase("user.gdg", &GDGOptions{Limit: 7, Scratch: true}))
+	require.NoError(t, dm.DeleteGDGBase("USER.GDG", true))
+	err = dm.DeleteGDGBase("USER.FULL.GDG", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "IDCAMS DELETE failed with condition code 8")
+
+	assert.Equal(t, [][]string{
+		{"DEFINE GENERATIONDATAGROUP (NAME('USER.GDG') LIMIT(7) NOEMPTY SCRATCH NOPURGE)"},
+		{"DELETE 'USER.GDG' GENERATIONDATAGROUP FORCE"},
+		{"DELETE 'USER.FULL.GDG' GENERATIONDATAGROUP"},
+	}, inputs)
+
+	// Invalid options fail before a request is made
+	assert.Error(t, dm.CreateGDGBase("USER.GDG", &GDGOptions{Limit: 300}))
+	assert.Error(t, dm.DeleteGDGBase("USER.GDG(0)", false))
+	assert.Len(t, inputs, 3)
+}
+
 func TestValidateMemberName(t *testing.T) {
 	// Test valid names
 	validNames := []string{
@@ -775,6 +1067,149 @@ func TestValidateMemberName(t *testing.T) {
 	}
 }
 
+func TestCreateDatasetInBlocks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&requestBody))
+		assert.Equal(t, "BLK", requestBody["alcunit"])
+		assert.Equal(t, float64(100), requestBody["primary"])
+		assert.Equal(t, float64(27920), requestBody["blksize"])
+		assert.Equal(t, float64(27920), requestBody["avgblk"])
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	request := &CreateDatasetRequest{
+		Name:         "TEST.BLOCKS",
+		Type:         DatasetTypeSequential,
+		Space:        Space{Primary: 100, Secondary: 50, Unit: SpaceUnitBlocks},
+		RecordFormat: RecordFormatFixed,
+		RecordLength: RecordLength80,
+		BlockSize:    BlockSize27920,
+	}
+	require.NoError(t, ValidateCreateDatasetRequest(request))
+	require.NoError(t, dm.CreateDataset(request))
+
+	// Blocks need a block size to be meaningful
+	request.BlockSize = 0
+	assert.Error(t, ValidateCreateDatasetRequest(request))
+}
+
+func TestCreateDatasetAverageBlock(t *testing.T) {
+	var requestBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestBody = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&requestBody))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	request := &CreateDatasetRequest{
+		Name:  "TEST.SMS.DATA",
+		Type:  DatasetTypeSequential,
+		Space: Space{Primary: 500, Secondary: 100, Unit: SpaceUnitKB, AverageBlock: 800},
+	}
+	require.NoError(t, ValidateCreateDatasetRequest(request))
+	require.NoError(t, dm.CreateDataset(request))
+	assert.Equal(t, "KB", requestBody["alcunit"])
+	assert.Equal(t, float64(800), requestBody["avgblk"])
+
+	// Track and cylinder allocations never send avgblk
+	request.Space = Space{Primary: 10, Secondary: 5, Unit: SpaceUnitTracks, AverageBlock: 800}
+	require.NoError(t, dm.CreateDataset(request))
+	assert.NotContains(t, requestBody, "avgblk")
+
+	// Byte units need an average block size
+	for _, unit := range []SpaceUnit{SpaceUnitKB, SpaceUnitMB, SpaceUnitGB} {
+		request.Space = Space{Primary: 1, Unit: unit}
+		assert.Error(t, ValidateCreateDatasetRequest(request), unit)
+		request.Space = CreateDefaultSpace(unit)
+		assert.NoError(t, ValidateCreateDatasetRequest(request), unit)
+	}
+	request.Space = Space{Primary: 1, Unit: SpaceUnitMB, AverageBlock: 70000}
+	assert.Error(t, ValidateCreateDatasetRequest(request))
+}
+
+func TestCreateDatasetSMSClasses(t *testing.T) {
+	var requestBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestBody = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&requestBody))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	request := &CreateDatasetRequest{
+		Name:            "TEST.SMS.DATA",
+		Type:            DatasetTypeSequential,
+		Space:           Space{Primary: 10, Secondary: 5, Unit: SpaceUnitCylinders},
+		DataClass:       "DCLARGE",
+		ManagementClass: "MCNOMIG",
+		StorageClass:    "SCPROD",
+	}
+	require.NoError(t, ValidateCreateDatasetRequest(request))
+	require.NoError(t, dm.CreateDataset(request))
+	assert.Equal(t, "DCLARGE", requestBody["dataclass"])
+	assert.Equal(t, "MCNOMIG", requestBody["mgntclass"])
+	assert.Equal(t, "SCPROD", requestBody["storclass"])
+
+	// Unset classes are left to the ACS routines
+	request.DataClass, request.ManagementClass, request.StorageClass = "", "", ""
+	require.NoError(t, dm.CreateDataset(request))
+	assert.NotContains(t, requestBody, "dataclass")
+	assert.NotContains(t, requestBody, "mgntclass")
+	assert.NotContains(t, requestBody, "storclass")
+
+	request.StorageClass = "TOOLONGNAME"
+	assert.ErrorContains(t, ValidateCreateDatasetRequest(request), "storage class")
+}
+
+func TestCreateDatasetUnit(t *testing.T) {
+	var requestBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestBody = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&requestBody))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	request := &CreateDatasetRequest{
+		Name:  "TEST.WORK.DATA",
+		Type:  DatasetTypeSequential,
+		Unit:  "SYSDA",
+		Space: Space{Primary: 10, Secondary: 5, Unit: SpaceUnitTracks},
+	}
+	require.NoError(t, ValidateCreateDatasetRequest(request))
+	require.NoError(t, dm.CreateDataset(request))
+	assert.Equal(t, "SYSDA", requestBody["unit"])
+
+	request.Unit = "3390"
+	assert.NoError(t, ValidateCreateDatasetRequest(request))
+	request.Unit = "sysda"
+	assert.Error(t, ValidateCreateDatasetRequest(request))
+
+	// SMS chooses the device for datasets with a storage class
+	request.Unit = "SYSDA"
+	request.StorageClass = "SCPROD"
+	assert.ErrorContains(t, ValidateCreateDatasetRequest(request), "storage class")
+}
+
 func TestValidateCreateDatasetRequest(t *testing.T) {
 	// Test valid request
 	validRequest := &CreateDatasetRequest{
@@ -912,7 +1347,7 @@ func TestListDatasetsError(t *testing.T) {
 	dm := NewDatasetManager(session)
 
 	// Test list datasets error
-	_, err = dm.ListDatasets(nil)
+	_, err = dm.ListDatasets(&DatasetFilter{DefaultToUserPrefix: true})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "API request failed with status 500")
 }
@@ -1130,3 +1565,2293 @@ func TestRenameDatasetError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "API request failed with status 400")
 }
+
+func TestDatasetManagerRequestsAreLogged(t *testing.T) {
+	// Create test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// Create dataset manager with a logging session
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+
+	var logged []string
+	session.SetLogger(profile.LoggerFunc(func(entry *profile.RequestLog) {
+		logged = append(logged, entry.String())
+	}))
+	dm := NewDatasetManager(session)
+
+	err = dm.DeleteDataset("TEST.DATA")
+	require.NoError(t, err)
+
+	require.Len(t, logged, 1)
+	assert.Contains(t, logged[0], "DELETE "+server.URL+"/api/v1/restfiles/ds/TEST.DATA")
+	assert.Contains(t, logged[0], "status=204")
+	assert.NotContains(t, logged[0], "testpass")
+}
+
+func TestParseDatasetUsage(t *testing.T) {
+	tests := []struct {
+		name    string
+		dataset Dataset
+		want    DatasetUsage
+	}{
+		{
+			name: "fully populated",
+			dataset: Dataset{
+				Name: "TEST.DATA", SizeX: "45", Used: "37", Extents: "2",
+				BlockSize: "27920", RecordLength: "80", SpaceUnit: "CYLINDERS",
+			},
+			want: DatasetUsage{
+				Name: "TEST.DATA", SpaceUnit: "CYLINDERS", AllocatedTracks: 45, AllocatedCylinders: 3,
+				UsedPercent: 37, Extents: 2, BlockSize: 27920, RecordLength: 80,
+			},
+		},
+		{
+			name:    "partial cylinder rounds up",
+			dataset: Dataset{Name: "TEST.DATA", SizeX: "16", SpaceUnit: "TRACKS"},
+			want:    DatasetUsage{Name: "TEST.DATA", SpaceUnit: "TRACKS", AllocatedTracks: 16, AllocatedCylinders: 2},
+		},
+		{
+			name: "unauthorized fields",
+			dataset: Dataset{
+				Name: "SYS1.SECRET", SizeX: "?", Used: "?", Extents: "?",
+				BlockSize: "?", RecordLength: "?", SpaceUnit: "?",
+			},
+			want: DatasetUsage{Name: "SYS1.SECRET"},
+		},
+		{
+			name:    "empty fields",
+			dataset: Dataset{Name: "MIGRATED.DATA"},
+			want:    DatasetUsage{Name: "MIGRATED.DATA"},
+		},
+		{
+			name:    "whitespace and malformed values",
+			dataset: Dataset{Name: "TEST.DATA", Used: " 5 ", Extents: "n/a"},
+			want:    DatasetUsage{Name: "TEST.DATA", UsedPercent: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, *parseDatasetUsage(&tt.dataset))
+		})
+	}
+}
+
+func TestGetDatasetUsage(t *testing.T) {
+	// Create test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[{"dsname":"TEST.DATA","dsorg":"PS","sizex":"30","used":"50","extx":"1","blksz":"6160","lrecl":"80","spacu":"TRACKS"}],"returnedRows":1}`))
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	usage, err := dm.GetDatasetUsage("TEST.DATA")
+	require.NoError(t, err)
+	assert.Equal(t, 30, usage.AllocatedTracks)
+	assert.Equal(t, 2, usage.AllocatedCylinders)
+	assert.Equal(t, 50, usage.UsedPercent)
+	assert.Equal(t, 1, usage.Extents)
+	assert.Equal(t, 6160, usage.BlockSize)
+	assert.Equal(t, 80, usage.RecordLength)
+
+	_, err = dm.GetDatasetUsage("OTHER.DATA")
+	assert.Error(t, err)
+}
+
+func TestSearchMembers(t *testing.T) {
+	members := map[string]string{
+		"JOBA":  "//JOBA JOB\n//STEP1 EXEC PGM=IEFBR14\n//DD1 DD DSN=PROD.DATA,DISP=SHR\n",
+		"JOBB":  "//JOBB JOB\n//DD1 DD DSN=test.data,DISP=SHR\n//DD2 DD DSN=PROD.DATA.BKP,DISP=SHR\n",
+		"PROCA": "//PROCA PROC\n//DD1 DD DSN=PROD.DATA,DISP=SHR\n",
+	}
+
+	// Create test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/restfiles/ds/LIB.JCL/member":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"items":[{"member":"JOBA"},{"member":"JOBB"},{"member":"PROCA"}],"returnedRows":3}`))
+		default:
+			name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/restfiles/ds/LIB.JCL("), ")")
+			content, ok := members[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(content))
+		}
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	// Literal search across all members
+	matches, err := dm.SearchMembers("LIB.JCL", "PROD.DATA", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []SearchMatch{
+		{Member: "JOBA", Line: 3, Text: "//DD1 DD DSN=PROD.DATA,DISP=SHR"},
+		{Member: "JOBB", Line: 3, Text: "//DD2 DD DSN=PROD.DATA.BKP,DISP=SHR"},
+		{Member: "PROCA", Line: 2, Text: "//DD1 DD DSN=PROD.DATA,DISP=SHR"},
+	}, matches)
+
+	// Member filter and case-insensitive literal
+	matches, err = dm.SearchMembers("LIB.JCL", "TEST.DATA", &SearchOptions{MemberPattern: "JOB*", CaseInsensitive: true})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "JOBB", matches[0].Member)
+	assert.Equal(t, 2, matches[0].Line)
+
+	// Regexp with a per-member limit
+	matches, err = dm.SearchMembers("LIB.JCL", `DSN=PROD\.DATA(,|\.)`, &SearchOptions{Regexp: true, MaxMatchesPerMember: 1, Concurrency: 1})
+	require.NoError(t, err)
+	assert.Len(t, matches, 3)
+}
+
+func TestSearchMembersInvalidRegexp(t *testing.T) {
+	// Create test server that counts requests
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	_, err = dm.SearchMembers("LIB.JCL", "DSN=(", &SearchOptions{Regexp: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid search pattern")
+	assert.Equal(t, 0, requests)
+}
+
+func TestListDatasetsPatterns(t *testing.T) {
+	var levels []string
+	var rawQueries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		level := r.URL.Query().Get("dslevel")
+		levels = append(levels, level)
+		rawQueries = append(rawQueries, r.URL.RawQuery)
+
+		// Both patterns return the shared dataset
+		items := `{"dsname":"SHARED.DATA"}`
+		if level == "USER1.JCL*" {
+			items += `,{"dsname":"USER1.JCL"}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[` + items + `],"returnedRows":1,"moreRows":false}`))
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	// Multiple patterns are listed separately and merged without duplicates
+	datasetList, err := dm.ListDatasets(&DatasetFilter{Name: "USER1.JCL*", Names: []string{" ", "USER1.$#@.**"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"USER1.JCL*", "USER1.$#@.**"}, levels)
+	assert.Len(t, datasetList.Datasets, 2)
+	assert.Equal(t, 2, datasetList.ReturnedRows)
+
+	// Special characters are escaped in the query string
+	assert.Contains(t, rawQueries[1], "dslevel=USER1.%24%23%40.%2A%2A")
+
+	// Volume-only listing sends no dslevel
+	levels = nil
+	_, err = dm.ListDatasets(&DatasetFilter{Volume: "VOL001"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, levels)
+}
+
+func TestListDatasetsRequiresPattern(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	// No pattern and no opt-in
+	_, err = dm.ListDatasets(nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "needs a name pattern or volume")
+
+	_, err = dm.ListDatasets(&DatasetFilter{Names: []string{"", "  "}})
+	assert.Error(t, err)
+
+	// Opt-in with an empty user must not send dslevel=.*
+	session.User = ""
+	_, err = dm.ListDatasets(&DatasetFilter{DefaultToUserPrefix: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "session has no user")
+
+	assert.Equal(t, 0, requests)
+}
+
+func TestCreateDatasetLike(t *testing.T) {
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/restfiles/ds":
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Query().Get("dslevel") {
+			case "USER1.MODEL.JCL":
+				w.Write([]byte(`{"items":[{"dsname":"USER1.MODEL.JCL","dsorg":"PO","dsntp":"PDS","recfm":"FB","lrecl":"80","blksz":"27920","sizex":"45","spacu":"CYLINDERS","vol":"VOL001"}]}`))
+			case "USER1.OLD.DATA":
+				w.Write([]byte(`{"items":[{"dsname":"USER1.OLD.DATA","dsorg":"PS","migr":"YES","vol":"MIGRAT"}]}`))
+			case "USER1.KSDS":
+				w.Write([]byte(`{"items":[{"dsname":"USER1.KSDS","dsorg":"VS"}]}`))
+			default:
+				w.Write([]byte(`{"items":[]}`))
+			}
+		case r.Method == "POST":
+			assert.Equal(t, "/api/v1/restfiles/ds/USER1.NEW.JCL", r.URL.Path)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	// Attributes are copied from the model
+	err = dm.CreateDatasetLike("USER1.MODEL.JCL", "USER1.NEW.JCL", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "USER1.NEW.JCL", created["dsname"])
+	assert.Equal(t, "PO", created["dsorg"])
+	assert.Equal(t, "FB", created["recfm"])
+	assert.Equal(t, float64(80), created["lrecl"])
+	assert.Equal(t, float64(27920), created["blksize"])
+	assert.Equal(t, "CYL", created["alcunit"])
+	assert.Equal(t, float64(3), created["primary"])
+	assert.Equal(t, float64(2), created["secondary"])
+	assert.Equal(t, float64(5), created["dirblk"])
+	assert.Nil(t, created["vol"])
+
+	// Overrides replace copied values
+	err = dm.CreateDatasetLike("USER1.MODEL.JCL", "USER1.NEW.JCL", &CreateDatasetRequest{
+		Space:        Space{Primary: 10, Unit: SpaceUnitTracks},
+		RecordLength: RecordLength132,
+		Directory:    20,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "TRK", created["alcunit"])
+	assert.Equal(t, float64(10), created["primary"])
+	assert.Equal(t, float64(132), created["lrecl"])
+	assert.Equal(t, float64(20), created["dirblk"])
+
+	// Migrated and VSAM models are rejected
+	err = dm.CreateDatasetLike("USER1.OLD.DATA", "USER1.NEW.JCL", nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "migrated")
+
+	err = dm.CreateDatasetLike("USER1.KSDS", "USER1.NEW.JCL", nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "VSAM")
+
+	err = dm.CreateDatasetLike("USER1.MISSING", "USER1.NEW.JCL", nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "dataset not found")
+}
+
+func TestDownloadRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/api/v1/restfiles/ds/USER1.BIG.LOG", r.URL.Path)
+		switch r.Header.Get("X-IBM-Record-Range") {
+		case "100,3":
+			w.Write([]byte("REC100\nREC101\nREC102\n"))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"category":1,"message":"Invalid X-IBM-Record-Range header"}`))
+		}
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	content, err := dm.DownloadRecords("USER1.BIG.LOG", 100, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "REC100\nREC101\nREC102\n", content)
+
+	_, err = dm.DownloadRecords("USER1.BIG.LOG", 0, 1)
+	assert.ErrorIs(t, err, ErrRecordRangeUnsupported)
+
+	_, err = dm.DownloadRecords("USER1.BIG.LOG", -1, 1)
+	assert.Error(t, err)
+	_, err = dm.DownloadRecords("USER1.BIG.LOG", 0, 0)
+	assert.Error(t, err)
+}
+
+func TestAppendContent(t *testing.T) {
+	var written string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/restfiles/ds/USER1.APP.LOG", r.URL.Path)
+		switch r.Method {
+		case "GET":
+			assert.Equal(t, "EXCL", r.Header.Get("X-IBM-Obtain-ENQ"))
+			w.Header().Set("X-IBM-Session-Ref", "ENQ-REF-1")
+			w.Write([]byte("LINE1\nLINE2"))
+		case "PUT":
+			assert.Equal(t, "ENQ-REF-1", r.Header.Get("X-IBM-Session-Ref"))
+			assert.Equal(t, "true", r.Header.Get("X-IBM-Release-ENQ"))
+			assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			written = string(body)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	err = dm.AppendContent("USER1.APP.LOG", "LINE3\n")
+	require.NoError(t, err)
+	assert.Equal(t, "LINE1\nLINE2\nLINE3\n", written)
+}
+
+func TestAppendContentReleasesENQ(t *testing.T) {
+	var puts, releases int
+	grantENQ := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.Header.Get("X-IBM-Release-ENQ") == "true":
+			assert.Equal(t, "ENQ-REF-1", r.Header.Get("X-IBM-Session-Ref"))
+			releases++
+		case r.Method == "GET":
+			if grantENQ {
+				w.Header().Set("X-IBM-Session-Ref", "ENQ-REF-1")
+			}
+			w.Write([]byte("LINE1\n"))
+		case r.Method == "PUT":
+			puts++
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	// A failed write releases the ENQ
+	err = dm.AppendContent("USER1.APP.LOG", "LINE2\n")
+	require.Error(t, err)
+	assert.Equal(t, 1, puts)
+	assert.Equal(t, 1, releases)
+
+	// Without an ENQ session nothing is written
+	grantENQ = false
+	err = dm.AppendContent("USER1.APP.LOG", "LINE2\n")
+	assert.ErrorIs(t, err, ErrAppendUnsupported)
+	assert.Equal(t, 1, puts)
+}
+
+func TestUploadDownloadProgress(t *testing.T) {
+	payload := strings.Repeat("RECORD DATA 0123456789\n", 20000)
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			body, _ := io.ReadAll(r.Body)
+			received = string(body)
+			w.WriteHeader(http.StatusNoContent)
+		case "GET":
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(payload)))
+			w.Write([]byte(payload))
+		}
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	checkProgress := func(t *testing.T, calls [][2]int64, expectedTotal int64) {
+		require.NotEmpty(t, calls)
+		for i := 1; i < len(calls); i++ {
+			assert.Greater(t, calls[i][0], calls[i-1][0])
+		}
+		last := calls[len(calls)-1]
+		assert.Equal(t, int64(len(payload)), last[0])
+		assert.Equal(t, expectedTotal, last[1])
+	}
+
+	var uploadCalls [][2]int64
+	err = dm.UploadContent(&UploadRequest{
+		DatasetName: "USER1.BIG.DATA",
+		Content:     payload,
+		Progress: func(transferred, total int64) {
+			uploadCalls = append(uploadCalls, [2]int64{transferred, total})
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, payload, received)
+	checkProgress(t, uploadCalls, int64(len(payload)))
+
+	var downloadCalls [][2]int64
+	content, err := dm.DownloadContent(&DownloadRequest{
+		DatasetName: "USER1.BIG.DATA",
+		Progress: func(transferred, total int64) {
+			downloadCalls = append(downloadCalls, [2]int64{transferred, total})
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, payload, content)
+	checkProgress(t, downloadCalls, int64(len(payload)))
+}
+
+func TestInvokeAMS(t *testing.T) {
+	var input []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/api/v1/restfiles/ams", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var body struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		input = body.Input
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(input[0], "MISSING") {
+			w.Write([]byte(`{"output":["IDC3012I ENTRY USER1.MISSING.KSDS NOT FOUND","IDC0002I IDCAMS PROCESSING COMPLETE. MAXIMUM CONDITION CODE WAS 8"]}`))
+			return
+		}
+		w.Write([]byte(`{"output":["CLUSTER ------- USER1.TEST.KSDS","     IN-CAT --- CATALOG.USER","IDC0001I FUNCTION COMPLETED, HIGHEST CONDITION CODE WAS 0","IDC0002I IDCAMS PROCESSING COMPLETE. MAXIMUM CONDITION CODE WAS 0"]}`))
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	result, err := dm.ListCatalog("USER1.TEST.KSDS")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"LISTCAT ENTRIES('USER1.TEST.KSDS') ALL"}, input)
+	assert.Equal(t, 0, result.ReturnCode)
+	assert.Len(t, result.Output, 4)
+	assert.Contains(t, result.Output[0], "USER1.TEST.KSDS")
+
+	err = dm.DeleteVSAMCluster("USER1.TEST.KSDS", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DELETE 'USER1.TEST.KSDS' CLUSTER PURGE"}, input)
+
+	err = dm.DeleteVSAMCluster("USER1.MISSING.KSDS", false)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "condition code 8")
+
+	// Long statements are continued across lines of at most 255 characters
+	long := "DEFINE CLUSTER (NAME(USER1.TEST.KSDS) " + strings.Repeat("VOLUMES(VOL001) ", 30) + "INDEXED)"
+	_, err = dm.InvokeAMS([]string{long})
+	require.NoError(t, err)
+	require.Greater(t, len(input), 1)
+	for i, line := range input {
+		assert.LessOrEqual(t, len(line), MaxAMSStatementLength)
+		if i < len(input)-1 {
+			assert.True(t, strings.HasSuffix(line, " -"), line)
+		}
+	}
+	assert.Equal(t, strings.Fields(long), strings.Fields(strings.ReplaceAll(strings.Join(input, " "), " - ", " ")))
+
+	_, err = dm.InvokeAMS(nil)
+	assert.Error(t, err)
+}
+
+func TestTransformDownloadedText(t *testing.T) {
+	// Two FB 80 records of a COBOL member with sequence numbers in 73-80
+	record1 := fmt.Sprintf("%-72s%s", "       IDENTIFICATION DIVISION.", "00000100")
+	record2 := fmt.Sprintf("%-72s%s", "       PROGRAM-ID. HELLO.", "00000200")
+	padded := fmt.Sprintf("%-80s", "       PROCEDURE DIVISION.")
+	fixed := record1 + "\n" + record2 + "\n" + padded + "\n"
+
+	tests := []struct {
+		name     string
+		content  string
+		request  DownloadRequest
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "no options leaves content untouched",
+			content:  fixed,
+			expected: fixed,
+		},
+		{
+			name:     "trim trailing spaces",
+			content:  padded + "\n" + padded,
+			request:  DownloadRequest{TrimTrailingSpaces: true},
+			expected: "       PROCEDURE DIVISION.\n       PROCEDURE DIVISION.",
+		},
+		{
+			name:     "strip sequence numbers and trim",
+			content:  fixed,
+			request:  DownloadRequest{StripSequenceNumbers: true, TrimTrailingSpaces: true},
+			expected: "       IDENTIFICATION DIVISION.\n       PROGRAM-ID. HELLO.\n       PROCEDURE DIVISION.\n",
+		},
+		{
+			name:    "insert sequence numbers",
+			content: "       IDENTIFICATION DIVISION.\n       PROGRAM-ID. HELLO.\n",
+			request: DownloadRequest{InsertSequenceNumbers: true},
+			expected: fmt.Sprintf("%-72s00000100\n%-72s00000200\n",
+				"       IDENTIFICATION DIVISION.", "       PROGRAM-ID. HELLO."),
+		},
+		{
+			name:     "renumber by stripping then inserting",
+			content:  strings.Replace(fixed, "00000200", "00000150", 1),
+			request:  DownloadRequest{StripSequenceNumbers: true, InsertSequenceNumbers: true},
+			expected: strings.Replace(fixed, padded, fmt.Sprintf("%-72s00000300", "       PROCEDURE DIVISION."), 1),
+		},
+		{
+			name:     "CRLF output",
+			content:  "LINE1   \nLINE2\n",
+			request:  DownloadRequest{TrimTrailingSpaces: true, NormalizeLineEndings: LineEndingCRLF},
+			expected: "LINE1\r\nLINE2\r\n",
+		},
+		{
+			name:     "LF output from CRLF input",
+			content:  "LINE1\r\nLINE2",
+			request:  DownloadRequest{NormalizeLineEndings: LineEndingLF},
+			expected: "LINE1\nLINE2",
+		},
+		{
+			name:     "columns are characters, not bytes",
+			content:  fmt.Sprintf("%-72s00000100\n", "* PRÜFUNG Ä Ö Ü €"),
+			request:  DownloadRequest{StripSequenceNumbers: true},
+			expected: fmt.Sprintf("%-72s\n", "* PRÜFUNG Ä Ö Ü €"),
+		},
+		{
+			name:     "numbering multibyte records",
+			content:  strings.Repeat("€", 72) + "\n",
+			request:  DownloadRequest{InsertSequenceNumbers: true},
+			expected: strings.Repeat("€", 72) + "00000100\n",
+		},
+		{
+			name:    "record too long to number",
+			content: strings.Repeat("X", 80),
+			request: DownloadRequest{InsertSequenceNumbers: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := transformDownloadedText(tt.content, &tt.request)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestDownloadContentTextOptions(t *testing.T) {
+	member := fmt.Sprintf("%-72s%s\n%-72s%s\n", "//COMPILE JOB", "00000100", "//STEP1   EXEC PGM=IGYCRCTL", "00000200")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/restfiles/ds/USER1.JCL(COMPILE)", r.URL.Path)
+		w.Write([]byte(member))
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	content, err := dm.DownloadContent(&DownloadRequest{
+		DatasetName:          "USER1.JCL",
+		MemberName:           "COMPILE",
+		StripSequenceNumbers: true,
+		TrimTrailingSpaces:   true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "//COMPILE JOB\n//STEP1   EXEC PGM=IGYCRCTL\n", content)
+
+	_, err = dm.DownloadContent(&DownloadRequest{DatasetName: "USER1.JCL", MemberName: "COMPILE", NormalizeLineEndings: "CR"})
+	assert.Error(t, err)
+}
+
+func TestDownloadMembers(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/restfiles/ds/USER1.COBOL/member" {
+			json.NewEncoder(w).Encode(MemberList{Members: []DatasetMember{{Name: "PAYROLL"}, {Name: "TAXES"}}})
+			return
+		}
+
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		member := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/restfiles/ds/USER1.COBOL("), ")")
+		if member == "BROKEN" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("SOURCE OF " + member + "\n"))
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	targetDir := filepath.Join(t.TempDir(), "cobol")
+	members := []string{"MEM1", "MEM2", "MEM3", "MEM4", "MEM5", "MEM6", "BROKEN", "bad/name"}
+	results, err := dm.DownloadMembers("USER1.COBOL", members, targetDir, 2)
+	require.NoError(t, err)
+
+	require.Len(t, results, len(members))
+	for _, member := range members[:6] {
+		assert.NoError(t, results[member], member)
+		data, err := os.ReadFile(filepath.Join(targetDir, member))
+		require.NoError(t, err)
+		assert.Equal(t, "SOURCE OF "+member+"\n", string(data))
+	}
+	assert.Error(t, results["BROKEN"])
+	assert.Error(t, results["bad/name"])
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+
+	// No member list downloads every member
+	results, err = dm.DownloadMembers("USER1.COBOL", nil, targetDir, 0)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.NoError(t, results["PAYROLL"])
+	assert.FileExists(t, filepath.Join(targetDir, "TAXES"))
+
+	_, err = dm.DownloadMembers("USER1.COBOL", members, "", 2)
+	assert.Error(t, err)
+}
+
+func TestDownloadToFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/restfiles/ds/USER1.COBOL(PAYROLL)":
+			assert.Equal(t, "", r.Header.Get("X-IBM-Data-Type"))
+			w.Write([]byte("IDENTIFICATION DIVISION.\n"))
+		case "/api/v1/restfiles/ds/USER1.LOADLIB(PROG)":
+			assert.Equal(t, "binary", r.Header.Get("X-IBM-Data-Type"))
+			w.Write([]byte{0x00, 0xC1, 0xFF})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"category":6,"rc":4,"reason":8,"message":"Data set not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	testProfile := createTestProfile(server.URL)
+	session, err := testProfile.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+	dir := t.TempDir()
+
+	// Parent directories are created
+	textPath := filepath.Join(dir, "src", "cobol", "PAYROLL.cbl")
+	written, err := dm.DownloadToFile("USER1.COBOL", "PAYROLL", textPath, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), written)
+	data, err := os.ReadFile(textPath)
+	require.NoError(t, err)
+	assert.Equal(t, "IDENTIFICATION DIVISION.\n", string(data))
+
+	binaryPath := filepath.Join(dir, "PROG.bin")
+	written, err = dm.DownloadToFile("USER1.LOADLIB", "PROG", binaryPath, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), written)
+	data, err = os.ReadFile(binaryPath)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0xC1, 0xFF}, data)
+
+	// A failed download leaves no file behind
+	missingPath := filepath.Join(dir, "MISSING.txt")
+	_, err = dm.DownloadToFile("USER1.MISSING", "MEM", missingPath, false)
+	var apiErr *profile.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.NoFileExists(t, missingPath)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "no temporary files are left")
+
+	_, err = dm.DownloadToFile("USER1.COBOL", "PAYROLL", "", false)
+	assert.Error(t, err)
+	_, err = dm.DownloadToFile("bad name", "", textPath, false)
+	assert.Error(t, err)
+}
+
+func TestDatasetKind(t *testing.T) {
+	tests := []struct {
+		dataset     Dataset
+		kind        DatasetType
+		partitioned bool
+		sequential  bool
+		vsam        bool
+	}{
+		{Dataset{Type: "PS"}, DatasetTypeSequential, false, true, false},
+		{Dataset{Type: "PS-L"}, DatasetTypeSequential, false, true, false},
+		{Dataset{Type: "PO"}, DatasetTypePartitioned, true, false, false},
+		{Dataset{Type: "PO", DatasetType: "LIBRARY"}, DatasetTypePDSE, true, false, false},
+		{Dataset{Type: "PO-E"}, DatasetTypePDSE, true, false, false},
+		{Dataset{Type: "po-e"}, DatasetTypePDSE, true, false, false},
+		{Dataset{Type: "VS"}, DatasetTypeVSAM, false, false, true},
+		{Dataset{Type: "?"}, "", false, false, false},
+		{Dataset{}, "", false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dataset.Type+"/"+tt.dataset.DatasetType, func(t *testing.T) {
+			assert.Equal(t, tt.kind, tt.dataset.DatasetKind())
+			assert.Equal(t, tt.partitioned, tt.dataset.IsPartitioned())
+			assert.Equal(t, tt.sequential, tt.dataset.IsSequential())
+			assert.Equal(t, tt.vsam, tt.dataset.IsVSAM())
+		})
+	}
+}
+
+func TestReleaseSpace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/api/v1/restfiles/ds/USER.LOADLIB", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "release", body["request"])
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	require.NoError(t, dm.ReleaseSpace("USER.LOADLIB"))
+	assert.Error(t, dm.ReleaseSpace("not a dataset"))
+}
+
+// fakeJobRunner records the JCL it is asked to run
+type fakeJobRunner struct {
+	jcl     string
+	retCode string
+}
+
+func (r *fakeJobRunner) RunJCL(jcl string, timeout time.Duration) (string, error) {
+	r.jcl = jcl
+	return r.retCode, nil
+}
+
+func TestCompressPDS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/restfiles/ds", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DatasetList{Datasets: []Dataset{
+			{Name: "USER.LOADLIB", Type: "PO"},
+			{Name: "USER.PDSE", Type: "PO-E"},
+			{Name: "USER.SEQ", Type: "PS"},
+		}})
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+	runner := &fakeJobRunner{retCode: "CC 0000"}
+	dm.SetJobRunner(runner)
+
+	retCode, err := dm.CompressPDS("USER.LOADLIB", "//COMPRESS JOB (ACCT),'USER',CLASS=A,MSGCLASS=X\n")
+	require.NoError(t, err)
+	assert.Equal(t, "CC 0000", retCode)
+	assert.Equal(t, "//COMPRESS JOB (ACCT),'USER',CLASS=A,MSGCLASS=X\n"+
+		"//COMPRESS EXEC PGM=IEBCOPY\n"+
+		"//SYSPRINT DD SYSOUT=*\n"+
+		"//PDS      DD DISP=OLD,DSN=USER.LOADLIB\n"+
+		"//SYSIN    DD *\n"+
+		"  COPY OUTDD=PDS,INDD=PDS\n"+
+		"/*\n", runner.jcl)
+
+	runner.jcl = ""
+	_, err = dm.CompressPDS("USER.PDSE", "//COMPRESS JOB (ACCT)")
+	assert.ErrorContains(t, err, "PDSE")
+	_, err = dm.CompressPDS("USER.SEQ", "//COMPRESS JOB (ACCT)")
+	assert.Error(t, err)
+	_, err = dm.CompressPDS("USER.LOADLIB", "COMPRESS JOB")
+	assert.Error(t, err)
+	assert.Empty(t, runner.jcl)
+}
+
+func TestListDatasetTree(t *testing.T) {
+	var memberCalls int32
+	var maxItems atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/restfiles/ds":
+			assert.Equal(t, "USER.*", r.URL.Query().Get("dslevel"))
+			json.NewEncoder(w).Encode(DatasetList{Datasets: []Dataset{
+				{Name: "USER.JCL", Type: "PO"},
+				{Name: "USER.LOAD", Type: "PO-E"},
+				{Name: "USER.SEQ", Type: "PS"},
+				{Name: "USER.OLD", Type: "PO", Migrated: "YES"},
+			}})
+		case "/api/v1/restfiles/ds/USER.JCL/member":
+			atomic.AddInt32(&memberCalls, 1)
+			members := []DatasetMember{{Name: "A"}, {Name: "B"}, {Name: "C"}}
+			maxItems.Store(r.Header.Get("X-IBM-Max-Items"))
+			limit, err := strconv.Atoi(r.Header.Get("X-IBM-Max-Items"))
+			require.NoError(t, err)
+			more := limit > 0 && limit < len(members)
+			if more {
+				members = members[:limit]
+			}
+			json.NewEncoder(w).Encode(MemberList{Members: members, MoreRows: more})
+		case "/api/v1/restfiles/ds/USER.LOAD/member":
+			atomic.AddInt32(&memberCalls, 1)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"not authorized"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	tree, err := dm.ListDatasetTree("USER.*", &TreeOptions{IncludeMembers: true, Concurrency: 2, MemberLimit: 2})
+	require.NoError(t, err)
+	require.Len(t, tree.Nodes, 4)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&memberCalls))
+
+	pds := tree.Nodes[0]
+	assert.Equal(t, "USER.JCL", pds.Dataset.Name)
+	assert.Equal(t, []DatasetMember{{Name: "A"}, {Name: "B"}}, pds.Members)
+	assert.True(t, pds.MembersTruncated)
+	assert.NoError(t, pds.Err)
+	assert.Equal(t, "2", maxItems.Load())
+
+	// A failing member list is recorded on its node only, and serialized as text
+	pdse := tree.Nodes[1]
+	require.Error(t, pdse.Err)
+	var apiErr *profile.APIError
+	require.ErrorAs(t, pdse.Err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	assert.Empty(t, pdse.Members)
+	encoded, err := json.Marshal(pdse)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"error":"failed to list members of USER.LOAD: API request failed with status 403`)
+	encoded, err = json.Marshal(pds)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), `"error"`)
+
+	assert.Empty(t, tree.Nodes[2].Members)
+	assert.False(t, tree.Nodes[2].Skipped)
+	assert.True(t, tree.Nodes[3].Skipped)
+
+	// Without a limit every member is listed
+	tree, err = dm.ListDatasetTree("USER.*", nil)
+	require.NoError(t, err)
+	assert.Len(t, tree.Nodes[0].Members, 3)
+	assert.False(t, tree.Nodes[0].MembersTruncated)
+	assert.Equal(t, "0", maxItems.Load())
+
+	// Members can be left out entirely
+	atomic.StoreInt32(&memberCalls, 0)
+	tree, err = dm.ListDatasetTree("USER.*", &TreeOptions{})
+	require.NoError(t, err)
+	assert.Len(t, tree.Nodes, 4)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&memberCalls))
+
+	_, err = dm.ListDatasetTree("", nil)
+	assert.Error(t, err)
+}
+
+func TestDatasetManagerCache(t *testing.T) {
+	var listCalls, memberCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/restfiles/ds":
+			atomic.AddInt32(&listCalls, 1)
+			json.NewEncoder(w).Encode(DatasetList{Datasets: []Dataset{{Name: "USER.JCL", Type: "PO"}}})
+		case r.Method == "GET" && r.URL.Path == "/api/v1/restfiles/ds/USER.JCL/member":
+			atomic.AddInt32(&memberCalls, 1)
+			json.NewEncoder(w).Encode(MemberList{Members: []DatasetMember{{Name: "A"}}})
+		case r.Method == "PUT" && r.URL.Path == "/api/v1/restfiles/ds/USER.JCL(B)":
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "POST" && r.URL.Path == "/api/v1/restfiles/ds/USER.NEW":
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session, WithCache(time.Minute))
+
+	// Repeated listings are served from the cache
+	for i := 0; i < 3; i++ {
+		list, err := dm.ListDatasets(&DatasetFilter{Name: "USER.*"})
+		require.NoError(t, err)
+		require.Len(t, list.Datasets, 1)
+		members, err := dm.ListMembers("USER.JCL")
+		require.NoError(t, err)
+		require.Len(t, members.Members, 1)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&listCalls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&memberCalls))
+
+	// Callers cannot change cached entries
+	list, err := dm.ListDatasets(&DatasetFilter{Name: "USER.*"})
+	require.NoError(t, err)
+	list.Datasets[0].Name = "CHANGED"
+	list, err = dm.ListDatasets(&DatasetFilter{Name: "USER.*"})
+	require.NoError(t, err)
+	assert.Equal(t, "USER.JCL", list.Datasets[0].Name)
+
+	// A different filter is a different entry, and ForceRefresh bypasses the cache
+	_, err = dm.ListDatasets(&DatasetFilter{Name: "USER.*", Limit: 10})
+	require.NoError(t, err)
+	_, err = dm.ListDatasets(&DatasetFilter{Name: "USER.*", ForceRefresh: true})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&listCalls))
+
+	// Uploading a member invalidates the member list
+	require.NoError(t, dm.UploadContent(&UploadRequest{DatasetName: "USER.JCL", MemberName: "B", Content: "DATA"}))
+	_, err = dm.ListMembers("USER.JCL")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&memberCalls))
+
+	// Creating a dataset invalidates dataset lists
+	require.NoError(t, dm.CreateDataset(&CreateDatasetRequest{Name: "USER.NEW", Type: DatasetTypeSequential}))
+	_, err = dm.ListDatasets(&DatasetFilter{Name: "USER.*"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&listCalls))
+
+	dm.InvalidateCache()
+	_, err = dm.ListMembers("USER.JCL")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&memberCalls))
+
+	// Entries expire after the TTL
+	dm = NewDatasetManager(session, WithCache(10*time.Millisecond))
+	_, err = dm.ListMembers("USER.JCL")
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	_, err = dm.ListMembers("USER.JCL")
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&memberCalls))
+}
+
+func TestDatasetManagerCacheInvalidation(t *testing.T) {
+	var listCalls, memberCalls int32
+	var prefixes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/restfiles/ds":
+			atomic.AddInt32(&listCalls, 1)
+			prefixes = append(prefixes, r.URL.Query().Get("dslevel"))
+			json.NewEncoder(w).Encode(DatasetList{Datasets: []Dataset{{Name: "USER.JCL", Type: "PO"}}})
+		case r.Method == "GET" && r.URL.Path == "/api/v1/restfiles/ds/USER.JCL/member":
+			atomic.AddInt32(&memberCalls, 1)
+			json.NewEncoder(w).Encode(MemberList{Members: []DatasetMember{{Name: "A"}}})
+		case r.Method == "PUT" && r.URL.Path == "/api/v1/restfiles/ams":
+			json.NewEncoder(w).Encode(AMSResponse{Output: []string{"IDC0001I FUNCTION COMPLETED"}})
+		case r.Method == "PUT" && r.URL.Path == "/api/v1/restfiles/ds/USER.JCL":
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session, WithCache(time.Minute))
+	list := func() {
+		_, err := dm.ListDatasets(&DatasetFilter{Name: "USER.*"})
+		require.NoError(t, err)
+		_, err = dm.ListMembers("USER.JCL")
+		require.NoError(t, err)
+	}
+
+	// ForceRefresh bypasses the member cache
+	list()
+	_, err = dm.ListMembersWithOptions("USER.JCL", &MemberListOptions{ForceRefresh: true})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&memberCalls))
+
+	// LISTCAT leaves the cache alone, other IDCAMS statements clear it
+	_, err = dm.InvokeAMS([]string{"LISTCAT ENTRIES(USER.JCL)"})
+	require.NoError(t, err)
+	list()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&listCalls))
+	_, err = dm.InvokeAMS([]string{"DELETE USER.OLD"})
+	require.NoError(t, err)
+	list()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&listCalls))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&memberCalls))
+
+	// Copying into a dataset invalidates dataset lists and the target's members
+	require.NoError(t, dm.CopySequentialDataset("USER.SRC", "USER.JCL"))
+	list()
+	assert.Equal(t, int32(3), atomic.LoadInt32(&listCalls))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&memberCalls))
+
+	// Listings under the user prefix follow the session user
+	for _, user := range []string{"ALICE", "ALICE", "BOB"} {
+		session.UpdateCredentials(user, "secret")
+		_, err = dm.ListDatasets(&DatasetFilter{DefaultToUserPrefix: true})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"USER.*", "USER.*", "USER.*", "ALICE.*", "BOB.*"}, prefixes)
+}
+
+// mockSession serves requests from a handler function without a server
+type mockSession struct {
+	handler func(r *http.Request) *http.Response
+}
+
+func (m *mockSession) GetBaseURL() string {
+	return "https://mock/zosmf"
+}
+
+func (m *mockSession) GetHeaders() map[string]string {
+	return map[string]string{"X-CSRF-ZOSMF-HEADER": ""}
+}
+
+func (m *mockSession) GetUser() string {
+	return "MOCKUSER"
+}
+
+func (m *mockSession) GetHTTPClient() *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return m.handler(r), nil
+	})}
+}
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestDatasetManagerWithMockSession(t *testing.T) {
+	var requested *http.Request
+	session := &mockSession{handler: func(r *http.Request) *http.Response {
+		requested = r
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"items":[{"dsname":"MOCKUSER.DATA"}],"returnedRows":1}`)),
+		}
+	}}
+
+	dm := NewDatasetManager(session)
+	assert.Same(t, session, dm.Session())
+
+	list, err := dm.ListDatasets(&DatasetFilter{DefaultToUserPrefix: true})
+	require.NoError(t, err)
+	require.Len(t, list.Datasets, 1)
+	assert.Equal(t, "MOCKUSER.DATA", list.Datasets[0].Name)
+
+	require.NotNil(t, requested)
+	assert.Equal(t, "/zosmf/restfiles/ds", requested.URL.Path)
+	assert.Equal(t, "MOCKUSER.*", requested.URL.Query().Get("dslevel"))
+}
+
+func TestDatasetMigrated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/restfiles/ds":
+			ds := Dataset{Name: r.URL.Query().Get("dslevel"), Migrated: "NO"}
+			if ds.Name == "USER.OLD" {
+				ds.Migrated = "YES"
+			}
+			json.NewEncoder(w).Encode(DatasetList{Datasets: []Dataset{ds}})
+		case "/api/v1/restfiles/ds/USER.OLD":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"category":4,"rc":8,"reason":0,"message":"Data set USER.OLD is migrated"}`))
+		case "/api/v1/restfiles/ds/USER.BAD":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"category":4,"rc":8,"reason":0,"message":"I/O error"}`))
+		case "/api/v1/restfiles/ds/USER.MIGRATION.JCL", "/api/v1/restfiles/ds/USER.MIGRAT":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"category":4,"rc":8,"reason":0,"message":"Data set ` + strings.TrimPrefix(r.URL.Path, "/api/v1/restfiles/ds/") + ` not found"}`))
+		case "/api/v1/restfiles/ds/USER.ARCHIVE":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"category":4,"rc":8,"reason":0,"message":"dynamic allocation error","details":["IKJ56221I DATA SET USER.ARCHIVE NOT ALLOCATED, VOLUME MIGRAT NOT AVAILABLE"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	migrated, err := dm.IsMigrated("user.old")
+	require.NoError(t, err)
+	assert.True(t, migrated)
+
+	migrated, err = dm.IsMigrated("USER.NEW")
+	require.NoError(t, err)
+	assert.False(t, migrated)
+
+	_, err = dm.IsMigrated("BAD..NAME")
+	assert.Error(t, err)
+
+	// Reads and writes report migration with a dedicated error
+	_, err = dm.DownloadText("USER.OLD")
+	assert.ErrorIs(t, err, ErrDatasetMigrated)
+	err = dm.UploadText("USER.OLD", "DATA")
+	assert.ErrorIs(t, err, ErrDatasetMigrated)
+	err = dm.AppendContent("USER.OLD", "DATA")
+	assert.ErrorIs(t, err, ErrDatasetMigrated)
+
+	_, err = dm.DownloadText("USER.ARCHIVE")
+	assert.ErrorIs(t, err, ErrDatasetMigrated, "the MIGRAT volser")
+
+	_, err = dm.DownloadText("USER.BAD")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrDatasetMigrated)
+
+	// Dataset names that contain MIGRAT are not migration
+	for _, name := range []string{"USER.MIGRATION.JCL", "USER.MIGRAT"} {
+		_, err = dm.DownloadText(name)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrDatasetMigrated, name)
+	}
+}
+
+func TestGetDatasetExactAndAlias(t *testing.T) {
+	amsCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/restfiles/ds":
+			assert.Equal(t, "5", r.Header.Get("X-IBM-Max-Items"))
+			var items []Dataset
+			switch r.URL.Query().Get("dslevel") {
+			case "USER.DATA":
+				items = []Dataset{{Name: "USER.DATA", Type: "PS"}, {Name: "USER.DATA.BACKUP", Type: "PS"}}
+			case "USER.REAL.DATA":
+				items = []Dataset{{Name: "USER.REAL.DATA", Type: "PO", Catalog: "CATALOG.USER"}}
+			case "USER.TWICE":
+				items = []Dataset{{Name: "USER.TWICE", Type: "PS", Volume: "VOL001"}, {Name: "USER.TWICE", Type: "PS", Volume: "VOL002"}}
+			case "USER.ALIAS":
+				items = []Dataset{{Name: "USER.ALIAS", Volume: "*ALIAS"}}
+			}
+			json.NewEncoder(w).Encode(DatasetList{Datasets: items, ReturnedRows: len(items)})
+		case "/api/v1/restfiles/ams":
+			amsCalls++
+			var body map[string][]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if strings.Contains(body["input"][0], "'USER.BROKEN'") {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"message": "IDCAMS unavailable"}`))
+				return
+			}
+			if strings.Contains(body["input"][0], "'USER.ALIAS'") || strings.Contains(body["input"][0], "'USER.UNLISTED'") {
+				json.NewEncoder(w).Encode(AMSResponse{Output: []string{
+					"ALIAS --------- USER.ALIAS",
+					"     IN-CAT --- CATALOG.USER",
+					"     ASSOCIATIONS",
+					"       NONVSAM--USER.REAL.DATA",
+					"IDC0001I FUNCTION COMPLETED, HIGHEST CONDITION CODE WAS 0",
+					"IDC0002I IDCAMS PROCESSING COMPLETE. MAXIMUM CONDITION CODE WAS 0",
+				}})
+				return
+			}
+			json.NewEncoder(w).Encode(AMSResponse{Output: []string{
+				"IDC3012I ENTRY USER.MISSING NOT FOUND",
+				"IDC0002I IDCAMS PROCESSING COMPLETE. MAXIMUM CONDITION CODE WAS 4",
+			}})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	// Siblings under the name are ignored
+	dataset, err := dm.GetDataset("user.data")
+	require.NoError(t, err)
+	assert.Equal(t, "USER.DATA", dataset.Name)
+	assert.Empty(t, dataset.Alias)
+
+	assert.Equal(t, 0, amsCalls)
+
+	// Names listed as aliases resolve to the dataset they refer to
+	dataset, err = dm.GetDataset("USER.ALIAS")
+	require.NoError(t, err)
+	assert.Equal(t, "USER.REAL.DATA", dataset.Name)
+	assert.Equal(t, "USER.ALIAS", dataset.Alias)
+	assert.Equal(t, "CATALOG.USER", dataset.Catalog)
+	assert.Equal(t, 1, amsCalls)
+
+	// A miss costs no LISTCAT unless the caller asks for alias resolution
+	_, err = dm.GetDataset("USER.MISSING")
+	assert.ErrorContains(t, err, "dataset not found: USER.MISSING")
+	_, err = dm.GetDataset("USER.UNLISTED")
+	assert.ErrorContains(t, err, "dataset not found: USER.UNLISTED")
+	assert.Equal(t, 1, amsCalls)
+
+	dataset, err = dm.GetDatasetWithOptions("USER.UNLISTED", &GetDatasetOptions{ResolveAlias: true})
+	require.NoError(t, err)
+	assert.Equal(t, "USER.REAL.DATA", dataset.Name)
+	assert.Equal(t, "USER.UNLISTED", dataset.Alias)
+	_, err = dm.GetDatasetWithOptions("USER.MISSING", &GetDatasetOptions{ResolveAlias: true})
+	assert.ErrorContains(t, err, "dataset not found: USER.MISSING")
+
+	// Catalog failures are reported, not turned into "not found"
+	_, err = dm.GetDatasetWithOptions("USER.BROKEN", &GetDatasetOptions{ResolveAlias: true})
+	var apiErr *profile.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	assert.NotContains(t, err.Error(), "not found")
+
+	// Wildcards are rejected before any request
+	for _, name := range []string{"USER.*", "USER.DAT%", "USER.**"} {
+		_, err = dm.GetDataset(name)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "use ListDatasets")
+	}
+
+	// Only the strict variant fails on duplicate entries
+	dataset, err = dm.GetDataset("USER.TWICE")
+	require.NoError(t, err)
+	assert.Equal(t, "VOL001", dataset.Volume)
+	_, err = dm.GetDatasetStrict("USER.TWICE")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "matched 2 catalog entries")
+
+	dataset, err = dm.GetDatasetStrict("USER.DATA")
+	require.NoError(t, err)
+	assert.Equal(t, "USER.DATA", dataset.Name)
+}
+
+func TestOpenMember(t *testing.T) {
+	var mu sync.Mutex
+	content := "//JOB1 JOB\n"
+	version := 1
+	var ifMatch []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "/api/v1/restfiles/ds/USER.JCL(JOB1)", r.URL.Path)
+		etag := fmt.Sprintf(`"V%d"`, version)
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "true", r.Header.Get("X-IBM-Return-Etag"))
+			w.Header().Set("ETag", etag)
+			w.Write([]byte(content))
+		case http.MethodPut:
+			ifMatch = append(ifMatch, r.Header.Get("If-Match"))
+			if r.Header.Get("If-Match") != etag {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				w.Write([]byte(`{"category":6,"rc":4,"reason":8,"message":"Etag mismatch"}`))
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			content = string(body)
+			version++
+			w.Header().Set("ETag", fmt.Sprintf(`"V%d"`, version))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	// Read, edit and write back through one handle
+	f, err := dm.OpenMember("USER.JCL", "JOB1")
+	require.NoError(t, err)
+	assert.Equal(t, "USER.JCL(JOB1)", f.Name())
+	assert.Equal(t, `"V1"`, f.ETag())
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "//JOB1 JOB\n", string(data))
+
+	_, err = io.WriteString(f, strings.ToUpper(string(data))+"//STEP1 EXEC PGM=IEFBR14\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Commit())
+	assert.Equal(t, `"V2"`, f.ETag())
+
+	// A second commit sends the new ETag
+	_, err = io.WriteString(f, "//*\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, "//JOB1 JOB\n//STEP1 EXEC PGM=IEFBR14\n//*\n", content)
+	assert.Equal(t, []string{`"V1"`, `"V2"`}, ifMatch)
+
+	_, err = f.Write([]byte("x"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+	assert.ErrorIs(t, f.Close(), os.ErrClosed)
+
+	// A change made by someone else after opening is detected on close
+	f, err = dm.OpenMember("USER.JCL", "JOB1")
+	require.NoError(t, err)
+	mu.Lock()
+	version++
+	mu.Unlock()
+	_, err = f.Write([]byte("//LOST JOB\n"))
+	require.NoError(t, err)
+	err = f.Close()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrETagMismatch)
+	assert.Contains(t, err.Error(), "USER.JCL(JOB1)")
+	var apiErr *profile.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusPreconditionFailed, apiErr.StatusCode)
+
+	// Closing without writing uploads nothing
+	ifMatch = nil
+	f, err = dm.OpenMember("USER.JCL", "JOB1")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Empty(t, ifMatch)
+
+	_, err = dm.OpenMember("USER.JCL", "TOOLONGNAME")
+	assert.Error(t, err)
+}
+
+func TestDatasetInUse(t *testing.T) {
+	// Response bodies as z/OSMF returns them
+	const (
+		ispfEditBody  = `{"rc":4,"reason":0,"category":4,"message":"LMFIND error","details":["ISRZ002 Member in use - Member JOB1 in data set IBMUSER.JCL is being updated by you or another user. Enter HELP for a list of users using the data set."]}`
+		allocatedBody = `{"rc":8,"reason":0,"category":4,"message":"dynamic allocation error","details":["IKJ56225I DATA SET IBMUSER.TEST.DATA ALREADY IN USE, TRY LATER+","IKJ56225I DATA SET IS ALLOCATED TO ANOTHER JOB OR USER"]}`
+		enqBody       = `{"rc":8,"reason":0,"category":4,"message":"dynamic allocation error, data set held by job PAYROLL1","details":["IKJ56225I DATA SET IBMUSER.TEST.DATA ALREADY IN USE, TRY LATER+"]}`
+		otherBody     = `{"rc":8,"reason":0,"category":4,"message":"Data set not found"}`
+		enqLogBody    = `{"rc":8,"reason":0,"category":4,"message":"Data set USER.ENQLOG not found, check the name IN USE by the request"}`
+	)
+
+	var enqHeld atomic.Int32
+	var released atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v1/restfiles/ds/IBMUSER.JCL(JOB1)":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(ispfEditBody))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/restfiles/ds/IBMUSER.TEST.DATA":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(allocatedBody))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/restfiles/ds/IBMUSER.JCL(OLD)":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(enqBody))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/restfiles/ds/USER.ENQLOG":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(enqLogBody))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(otherBody))
+		case r.Method == http.MethodGet && r.Header.Get("X-IBM-Release-ENQ") == "true":
+			assert.Equal(t, "SESSION1", r.Header.Get("X-IBM-Session-Ref"))
+			released.Add(1)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/restfiles/ds/IBMUSER.TEST.DATA":
+			assert.Equal(t, "EXCL", r.Header.Get("X-IBM-Obtain-ENQ"))
+			// Held for the first two probes
+			if enqHeld.Add(1) <= 2 {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(enqBody))
+				return
+			}
+			w.Header().Set("X-IBM-Session-Ref", "SESSION1")
+			w.Write([]byte("RECORD 1\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(otherBody))
+		}
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	err = dm.UploadContent(&UploadRequest{DatasetName: "IBMUSER.JCL", MemberName: "JOB1", Content: "X"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrDatasetInUse)
+	var inUseErr *DatasetInUseError
+	require.ErrorAs(t, err, &inUseErr)
+	assert.Equal(t, http.StatusInternalServerError, inUseErr.StatusCode)
+	assert.Empty(t, inUseErr.Holder)
+	assert.Contains(t, apiErrorMessage(inUseErr.APIError), "ISRZ002 Member in use")
+
+	// The response is also available as an API error
+	var apiErr *profile.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	assert.Equal(t, 4, apiErr.Category)
+
+	err = dm.DeleteDataset("IBMUSER.TEST.DATA")
+	require.ErrorAs(t, err, &inUseErr)
+	assert.Empty(t, inUseErr.Holder)
+	assert.Contains(t, err.Error(), "IKJ56225I")
+
+	err = dm.DeleteMember("IBMUSER.JCL", "OLD")
+	require.ErrorAs(t, err, &inUseErr)
+	assert.Equal(t, "PAYROLL1", inUseErr.Holder)
+	assert.Contains(t, err.Error(), "dataset is in use by PAYROLL1")
+
+	err = dm.DeleteDataset("IBMUSER.GONE")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrDatasetInUse)
+
+	// Only message IDs count, not words in dataset names or messages
+	err = dm.DeleteDataset("USER.ENQLOG")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrDatasetInUse)
+
+	// WaitForExclusive polls until the ENQ can be obtained
+	defer func(interval time.Duration) { exclusivePollInterval = interval }(exclusivePollInterval)
+	exclusivePollInterval = 10 * time.Millisecond
+	require.NoError(t, dm.WaitForExclusive(context.Background(), "IBMUSER.TEST.DATA", time.Second))
+	assert.Equal(t, int32(3), enqHeld.Load())
+	assert.Equal(t, int32(1), released.Load())
+
+	// and gives up when the timeout passes
+	enqHeld.Store(-1000)
+	err = dm.WaitForExclusive(context.Background(), "IBMUSER.TEST.DATA", 50*time.Millisecond)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrDatasetInUse)
+	assert.Contains(t, err.Error(), "gave up waiting for IBMUSER.TEST.DATA")
+
+	// Other errors are returned at once
+	err = dm.WaitForExclusive(context.Background(), "IBMUSER.GONE", time.Second)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrDatasetInUse)
+}
+
+func TestGetDatasetInfo(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	// A listing with X-IBM-Attributes: base, the only form in which z/OSMF returns attributes
+	mock.OnListDatasets("IBMUSER.CNTL").Return(`{"items": [
+    {"dsname": "IBMUSER.CNTL", "blksz": "3200", "catnm": "CATALOG.USER", "cdate": "2024/01/15", "dev": "3390", "dsntp": "PDS", "dsorg": "PO", "edate": "***None***", "extx": "1", "lrecl": "80", "migr": "NO", "mvol": "N", "ovf": "NO", "rdate": "2024/06/03", "recfm": "FB", "sizex": "15", "spacu": "TRACKS", "used": "20", "vol": "USR001", "vols": "USR001"}
+  ], "returnedRows": 1, "totalRows": 1, "JSONversion": 1}`)
+	mock.OnListDatasets("IBMUSER.NONE").Return(`{"items": [], "returnedRows": 0, "totalRows": 0, "JSONversion": 1}`)
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	info, err := dm.GetDatasetInfo("IBMUSER.CNTL")
+	require.NoError(t, err)
+	assert.Equal(t, "IBMUSER.CNTL", info.Name)
+	assert.Equal(t, "PO", info.Type)
+	assert.Equal(t, "FB", info.RecordFormat)
+	assert.Equal(t, "80", info.RecordLength)
+	assert.Equal(t, "3200", info.BlockSize)
+	assert.Equal(t, "USR001", info.Volume)
+	assert.Equal(t, "2024/01/15", info.CreatedDate)
+	assert.True(t, info.IsPartitioned())
+
+	requests := mock.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/restfiles/ds", requests[0].Path)
+	assert.Equal(t, "base", requests[0].Header.Get("X-IBM-Attributes"))
+
+	// A missing dataset is an error rather than an empty Dataset
+	info, err = dm.GetDatasetInfo("IBMUSER.NONE")
+	require.Error(t, err)
+	assert.Nil(t, info)
+}
+
+func TestTextEncoding(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnReadDataset("IBMUSER.CNTL", "ALLOC")
+	mock.OnWriteDataset("IBMUSER.CNTL", "ALLOC")
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	// No encoding anywhere leaves conversion to the z/OSMF default
+	_, err = dm.DownloadTextFromMember("IBMUSER.CNTL", "ALLOC")
+	require.NoError(t, err)
+	last := mock.LastRequest()
+	assert.Empty(t, last.Header.Get("X-IBM-Data-Type"))
+
+	// The profile's encoding applies to every content request; bare numbers get IBM-
+	session.Profile.Encoding = "1140"
+	_, err = dm.DownloadTextFromMember("IBMUSER.CNTL", "ALLOC")
+	require.NoError(t, err)
+	assert.Equal(t, "text;fileEncoding=IBM-1140", mock.LastRequest().Header.Get("X-IBM-Data-Type"))
+
+	// Wrapping the session with retries keeps the profile's encoding
+	_, err = New(session, WithRetries(1)).DownloadTextFromMember("IBMUSER.CNTL", "ALLOC")
+	require.NoError(t, err)
+	assert.Equal(t, "text;fileEncoding=IBM-1140", mock.LastRequest().Header.Get("X-IBM-Data-Type"))
+
+	require.NoError(t, dm.UploadTextToMember("IBMUSER.CNTL", "ALLOC", "HELLO"))
+	last = mock.LastRequest()
+	assert.Equal(t, "text;fileEncoding=IBM-1140", last.Header.Get("X-IBM-Data-Type"))
+	assert.Equal(t, "text/plain;charset=UTF-8", last.Header.Get("Content-Type"))
+
+	// Explicit encodings override the profile
+	_, err = dm.DownloadContent(&DownloadRequest{
+		DatasetName:    "IBMUSER.CNTL",
+		MemberName:     "ALLOC",
+		SourceEncoding: "IBM-037",
+		TargetEncoding: "UTF-8",
+	})
+	require.NoError(t, err)
+	last = mock.LastRequest()
+	assert.Equal(t, "text;fileEncoding=IBM-037", last.Header.Get("X-IBM-Data-Type"))
+	assert.Equal(t, "text/plain;charset=UTF-8", last.Header.Get("Content-Type"))
+
+	require.NoError(t, dm.UploadContent(&UploadRequest{
+		DatasetName:    "IBMUSER.CNTL",
+		MemberName:     "ALLOC",
+		Content:        "HELLO",
+		SourceEncoding: "IBM-037",
+		TargetEncoding: "ISO8859-1",
+	}))
+	last = mock.LastRequest()
+	assert.Equal(t, "text;fileEncoding=IBM-037", last.Header.Get("X-IBM-Data-Type"))
+	assert.Equal(t, "text/plain;charset=ISO8859-1", last.Header.Get("Content-Type"))
+
+	// Encoding is the older name for TargetEncoding and is not sent as a query parameter
+	_, err = dm.DownloadContent(&DownloadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "ALLOC", Encoding: "ISO8859-1"})
+	require.NoError(t, err)
+	last = mock.LastRequest()
+	assert.Empty(t, last.Query.Get("encoding"))
+	assert.Equal(t, "text/plain;charset=ISO8859-1", last.Header.Get("Content-Type"))
+
+	// The two may agree but not conflict
+	_, err = dm.DownloadContent(&DownloadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "ALLOC", Encoding: "utf-8", TargetEncoding: "UTF-8"})
+	require.NoError(t, err)
+	count := len(mock.Requests())
+	_, err = dm.DownloadContent(&DownloadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "ALLOC", Encoding: "UTF-8", TargetEncoding: "ISO8859-1"})
+	assert.ErrorContains(t, err, "conflicts with TargetEncoding")
+	err = dm.UploadContent(&UploadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "ALLOC", Content: "HELLO", Encoding: "UTF-8", TargetEncoding: "ISO8859-1"})
+	assert.ErrorContains(t, err, "conflicts with TargetEncoding")
+	assert.Len(t, mock.Requests(), count)
+}
+
+func TestPDSFS(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListMembers("IBMUSER.CNTL")
+	for _, member := range []string{"ALLOC", "COMPILE", "IEFBR14"} {
+		mock.OnReadDataset("IBMUSER.CNTL", member)
+	}
+	mock.OnListMembers("IBMUSER.LOADLIB").Return(`{"items": [{"member": "PAYROLL", "size": "0000A0", "ac": "00"}], "returnedRows": 1, "JSONversion": 1}`)
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	fsys := NewPDSFS(dm, "IBMUSER.CNTL", WithMemberExtension(".jcl"))
+	require.NoError(t, fstest.TestFS(fsys, "alloc.jcl", "compile.jcl", "iefbr14.jcl"))
+
+	content, err := fs.ReadFile(fsys, "alloc.jcl")
+	require.NoError(t, err)
+	assert.Equal(t, zosmftest.DatasetContent, string(content))
+
+	// The member list is fetched once within the TTL, with member statistics
+	listings := mock.RequestsTo(http.MethodGet, "/restfiles/ds/IBMUSER.CNTL/member")
+	require.Len(t, listings, 1)
+	assert.Equal(t, "base", listings[0].Header.Get("X-IBM-Attributes"))
+
+	info, err := fs.Stat(fsys, "alloc.jcl")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 30, 9, 14, 22, 0, time.UTC), info.ModTime())
+	assert.Equal(t, fs.FileMode(0444), info.Mode())
+	member, ok := info.Sys().(*DatasetMember)
+	require.True(t, ok)
+	assert.Equal(t, "ALLOC", member.Name)
+	assert.Equal(t, 12, member.CurrentRecords)
+
+	_, err = fsys.Open("ALLOC")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = fsys.Open("alloc")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// Content is only downloaded when a file is read
+	before := len(mock.Requests())
+	file, err := fsys.Open("compile.jcl")
+	require.NoError(t, err)
+	_, err = file.Stat()
+	require.NoError(t, err)
+	assert.Len(t, mock.Requests(), before)
+	_, err = io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Len(t, mock.Requests(), before+1)
+	assert.Equal(t, "/restfiles/ds/IBMUSER.CNTL(COMPILE)", mock.LastRequest().Path)
+
+	// Without a TTL every call lists the members again
+	uncached := NewPDSFS(dm, "IBMUSER.CNTL", WithListTTL(0))
+	_, err = fs.ReadDir(uncached, ".")
+	require.NoError(t, err)
+	_, err = fs.Stat(uncached, "alloc")
+	require.NoError(t, err)
+	assert.Len(t, mock.RequestsTo(http.MethodGet, "/restfiles/ds/IBMUSER.CNTL/member"), 3)
+
+	// Load modules report their size
+	loadlib := NewPDSFS(dm, "IBMUSER.LOADLIB")
+	info, err = fs.Stat(loadlib, "payroll")
+	require.NoError(t, err)
+	assert.Equal(t, int64(160), info.Size())
+}
+
+func TestSetDefaultLimit(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListDatasets("IBMUSER.*")
+	mock.OnListMembers("IBMUSER.CNTL")
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session, WithCache(time.Minute))
+
+	maxItems := func() string {
+		return mock.LastRequest().Header.Get("X-IBM-Max-Items")
+	}
+
+	// No default: datasets and members are unlimited
+	_, err = dm.ListDatasets(&DatasetFilter{Name: "IBMUSER.*"})
+	require.NoError(t, err)
+	assert.Equal(t, "0", maxItems())
+	_, err = dm.ListMembers("IBMUSER.CNTL")
+	require.NoError(t, err)
+	assert.Equal(t, "0", maxItems())
+
+	// The default applies when the filter sets no limit, and drops cached lists
+	dm.SetDefaultLimit(500)
+	_, err = dm.ListDatasets(&DatasetFilter{Name: "IBMUSER.*"})
+	require.NoError(t, err)
+	assert.Equal(t, "500", maxItems())
+	_, err = dm.ListMembers("IBMUSER.CNTL")
+	require.NoError(t, err)
+	assert.Equal(t, "500", maxItems())
+	assert.Len(t, mock.Requests(), 4)
+
+	// A per-call limit wins; a negative one lists everything
+	_, err = dm.ListDatasets(&DatasetFilter{Name: "IBMUSER.*", Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, "20", maxItems())
+	_, err = dm.ListDatasets(&DatasetFilter{Name: "IBMUSER.*", Limit: -1})
+	require.NoError(t, err)
+	assert.Equal(t, "0", maxItems())
+	_, err = dm.ListMembersWithOptions("IBMUSER.CNTL", &MemberListOptions{Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, "20", maxItems())
+	_, err = dm.ListMembersWithOptions("IBMUSER.CNTL", &MemberListOptions{Limit: -1})
+	require.NoError(t, err)
+	assert.Equal(t, "0", maxItems())
+
+	// Each limit is cached separately
+	requests := len(mock.Requests())
+	_, err = dm.ListMembers("IBMUSER.CNTL")
+	require.NoError(t, err)
+	_, err = dm.ListMembersWithOptions("IBMUSER.CNTL", &MemberListOptions{Limit: 20})
+	require.NoError(t, err)
+	assert.Len(t, mock.Requests(), requests)
+
+	// Listings that need every member ignore the default
+	dm.InvalidateCache()
+	_, err = dm.ListMemberAliases("IBMUSER.CNTL")
+	require.NoError(t, err)
+	assert.Equal(t, "0", maxItems())
+
+	dm.SetDefaultLimit(0)
+	_, err = dm.ListDatasets(&DatasetFilter{Name: "IBMUSER.*"})
+	require.NoError(t, err)
+	assert.Equal(t, "0", maxItems())
+}
+
+func TestVerifyUpload(t *testing.T) {
+	pad := func(line string) string { return line + strings.Repeat(" ", 80-len(line)) }
+
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	// A fixed 80-byte dataset reads back with every record padded to full length
+	mock.OnReadDataset("IBMUSER.FB80", "").Return(pad("//IBMUSERA JOB") + "\n" + pad("//STEP1 EXEC PGM=IEFBR14") + "\n")
+	mock.OnReadDataset("IBMUSER.CODEPAGE", "").Return("X = A(Ý1¨);\nNAME = '\x1a'\n")
+	mock.OnWriteDataset("IBMUSER.CNTL", "JOB1")
+	mock.OnReadDataset("IBMUSER.CNTL", "JOB1").Return(pad("//IBMUSERA JOB") + "\n" + pad("//STEP1 EXEC PGM=IEFBR15") + "\n")
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	local := "//IBMUSERA JOB\r\n//STEP1 EXEC PGM=IEFBR14"
+
+	// Padding and line endings are ignored by default
+	ok, diff, err := dm.VerifyUpload("IBMUSER.FB80", "", local, nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, diff)
+
+	// Compared strictly, every line differs, including the empty one after the final newline
+	ok, diff, err = dm.VerifyUpload("IBMUSER.FB80", "", local, &VerifyOptions{MaxDiffs: 1})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	require.NotNil(t, diff)
+	assert.Equal(t, 3, diff.TotalMismatches)
+	assert.Equal(t, 3, diff.RemoteLines)
+	require.Len(t, diff.Mismatches, 1)
+	assert.Equal(t, 1, diff.Mismatches[0].Line)
+	assert.Equal(t, "//IBMUSERA JOB\r", diff.Mismatches[0].Local)
+	assert.Equal(t, pad("//IBMUSERA JOB"), diff.Mismatches[0].Remote)
+	assert.Contains(t, diff.String(), "... and 2 more")
+
+	// The content is read back as UTF-8 unless the options name another character set
+	assert.Equal(t, "text/plain;charset=UTF-8", mock.LastRequest().Header.Get("Content-Type"))
+	opts := DefaultVerifyOptions()
+	opts.SourceEncoding = "IBM-037"
+	opts.TargetEncoding = "ISO8859-1"
+	ok, _, err = dm.VerifyUpload("IBMUSER.FB80", "", local, opts)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	last := mock.LastRequest()
+	assert.Equal(t, "text;fileEncoding=IBM-037", last.Header.Get("X-IBM-Data-Type"))
+	assert.Equal(t, "text/plain;charset=ISO8859-1", last.Header.Get("Content-Type"))
+
+	// Code page variant characters and unconvertible ones need EBCDICTolerant
+	codePage := "X = A([1]);\nNAME = 'é'"
+	ok, _, err = dm.VerifyUpload("IBMUSER.CODEPAGE", "", codePage, nil)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	tolerant := DefaultVerifyOptions()
+	tolerant.EBCDICTolerant = true
+	ok, _, err = dm.VerifyUpload("IBMUSER.CODEPAGE", "", codePage, tolerant)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, _, err = dm.VerifyUpload("IBMUSER.CODEPAGE", "", "X = B([1]);\nNAME = 'é'", tolerant)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Verified uploads fail with the diff when the content reads back differently
+	err = dm.UploadContent(&UploadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "JOB1", Content: "//IBMUSERA JOB\n", Verify: true})
+	require.Error(t, err)
+
+	dm.SetVerifyUploads(DefaultVerifyOptions())
+	err = dm.UploadTextToMember("IBMUSER.CNTL", "JOB1", local)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrUploadMismatch)
+	var verifyErr *UploadVerificationError
+	require.ErrorAs(t, err, &verifyErr)
+	assert.Equal(t, "JOB1", verifyErr.MemberName)
+	assert.Equal(t, 2, verifyErr.Diff.Mismatches[0].Line)
+	assert.Contains(t, err.Error(), "IBMUSER.CNTL(JOB1)")
+	assert.Contains(t, err.Error(), "PGM=IEFBR15")
+
+	dm.SetVerifyUploads(nil)
+	require.NoError(t, dm.UploadTextToMember("IBMUSER.CNTL", "JOB1", local))
+
+	// Verification can be asked for per call
+	err = dm.UploadTextToMemberWithOptions("IBMUSER.CNTL", "JOB1", local, &TextUploadOptions{Verify: true})
+	assert.ErrorIs(t, err, ErrUploadMismatch)
+	mock.OnWriteDataset("IBMUSER.FB80", "")
+	require.NoError(t, dm.UploadTextWithOptions("IBMUSER.FB80", local, &TextUploadOptions{Verify: true}))
+
+	// The content is read back with the code pages it was written with
+	err = dm.UploadContent(&UploadRequest{DatasetName: "IBMUSER.FB80", Content: local, SourceEncoding: "IBM-037", TargetEncoding: "UTF-8", Verify: true})
+	require.NoError(t, err)
+	readBack := mock.LastRequest()
+	assert.Equal(t, http.MethodGet, readBack.Method)
+	assert.Equal(t, "text;fileEncoding=IBM-037", readBack.Header.Get("X-IBM-Data-Type"))
+	assert.Equal(t, "text/plain;charset=UTF-8", readBack.Header.Get("Content-Type"))
+}
+
+func TestListMemberAliases(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListMembers("IBMUSER.LOADLIB").Return(`{"items": [
+    {"member": "PAYROLL", "size": "0000A0", "ac": "00", "amode": "31", "rmode": "ANY"},
+    {"member": "PAYR", "size": "0000A0", "ac": "00", "alias": true, "aliasof": "PAYROLL"},
+    {"member": "PAYRPT", "aliasof": "PAYROLL"},
+    {"member": "OLDPAY", "alias": "Y"},
+    {"member": "TAX", "alias": "N"}
+  ], "returnedRows": 5, "JSONversion": 1}`)
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	list, err := dm.ListMembers("IBMUSER.LOADLIB")
+	require.NoError(t, err)
+	require.Len(t, list.Members, 5)
+	assert.False(t, list.Members[0].Alias)
+	assert.True(t, list.Members[1].Alias)
+	assert.False(t, list.Members[4].Alias)
+	assert.Equal(t, "base", mock.LastRequest().Header.Get("X-IBM-Attributes"))
+
+	aliases, err := dm.ListMemberAliases("IBMUSER.LOADLIB")
+	require.NoError(t, err)
+	require.Len(t, aliases, 3)
+	assert.Equal(t, "PAYR", aliases[0].Name)
+	assert.Equal(t, "PAYROLL", aliases[0].AliasOf)
+	assert.Equal(t, "PAYRPT", aliases[1].Name)
+	assert.Equal(t, "OLDPAY", aliases[2].Name)
+	assert.Empty(t, aliases[2].AliasOf)
+
+	_, err = dm.ListMemberAliases("IBMUSER.MISSING")
+	assert.Error(t, err)
+}
+
+func TestDatasetManagerAPIError(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListMembers("USER.MISSING").ReturnError(http.StatusNotFound, "Data set not found")
+	mock.OnDeleteDataset("USER.MIGRATED").ReturnError(http.StatusInternalServerError, "Data set USER.MIGRATED is migrated (MIGRAT)")
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	_, err = dm.ListMembers("USER.MISSING")
+	var apiErr *profile.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "Data set not found", apiErr.Message)
+
+	// Dataset-specific errors still wrap the API error
+	err = dm.DeleteDataset("USER.MIGRATED")
+	assert.ErrorIs(t, err, ErrDatasetMigrated)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+}
+
+// serveDsorgLookup answers the listing the content guard makes to find a dataset's
+// organization, and reports whether the request was that listing
+func serveDsorgLookup(w http.ResponseWriter, r *http.Request, datasetName, dsorg string) bool {
+	if r.Method != "GET" || !strings.HasSuffix(r.URL.Path, "/restfiles/ds") {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"items": [{"dsname": %q, "dsorg": %q}], "returnedRows": 1, "JSONversion": 1}`, datasetName, dsorg)
+	return true
+}
+
+func TestContentDsorgGuard(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListDatasets("IBMUSER.SEQ").Return(`{"items": [{"dsname": "IBMUSER.SEQ", "dsorg": "PS"}], "returnedRows": 1, "JSONversion": 1}`)
+	mock.OnListDatasets("IBMUSER.CNTL").Return(`{"items": [{"dsname": "IBMUSER.CNTL", "dsorg": "PO", "dsntp": "PDS"}], "returnedRows": 1, "JSONversion": 1}`)
+	mock.OnReadDataset("IBMUSER.SEQ", "")
+	mock.OnWriteDataset("IBMUSER.SEQ", "")
+	mock.OnReadDataset("IBMUSER.CNTL", "")
+	mock.OnWriteDataset("IBMUSER.CNTL", "")
+	mock.OnReadDataset("IBMUSER.CNTL", "ALLOC")
+	mock.OnWriteDataset("IBMUSER.CNTL", "ALLOC")
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	// A sequential dataset is read and written as it is; its organization is listed once
+	require.NoError(t, dm.UploadText("IBMUSER.SEQ", "HELLO"))
+	_, err = dm.DownloadText("IBMUSER.SEQ")
+	require.NoError(t, err)
+	assert.Len(t, mock.RequestsTo("GET", "/restfiles/ds"), 1)
+	assert.Len(t, mock.RequestsTo("PUT", "/restfiles/ds/IBMUSER.SEQ"), 1)
+
+	// Members of a PDS need no lookup
+	require.NoError(t, dm.UploadTextToMember("IBMUSER.CNTL", "ALLOC", "HELLO"))
+	_, err = dm.DownloadTextFromMember("IBMUSER.CNTL", "ALLOC")
+	require.NoError(t, err)
+	assert.Len(t, mock.RequestsTo("GET", "/restfiles/ds"), 1)
+
+	// The PDS itself is refused without touching its directory
+	err = dm.UploadText("IBMUSER.CNTL", "HELLO")
+	require.ErrorIs(t, err, ErrMemberRequired)
+	assert.Contains(t, err.Error(), "IBMUSER.CNTL")
+	_, err = dm.DownloadText("IBMUSER.CNTL")
+	require.ErrorIs(t, err, ErrMemberRequired)
+	assert.Empty(t, mock.RequestsTo("PUT", "/restfiles/ds/IBMUSER.CNTL"))
+	assert.Empty(t, mock.RequestsTo("GET", "/restfiles/ds/IBMUSER.CNTL"))
+	assert.Len(t, mock.RequestsTo("GET", "/restfiles/ds"), 2)
+
+	// Force bypasses the check
+	require.NoError(t, dm.UploadContent(&UploadRequest{DatasetName: "IBMUSER.CNTL", Content: "HELLO", Force: true}))
+	_, err = dm.DownloadContent(&DownloadRequest{DatasetName: "IBMUSER.CNTL", Force: true})
+	require.NoError(t, err)
+	assert.Len(t, mock.RequestsTo("PUT", "/restfiles/ds/IBMUSER.CNTL"), 1)
+	assert.Len(t, mock.RequestsTo("GET", "/restfiles/ds/IBMUSER.CNTL"), 1)
+}
+
+// ebcdicRecords frames EBCDIC records as z/OSMF sends them with X-IBM-Data-Type: record
+func ebcdicRecords(t *testing.T, codePage string, lines ...string) []byte {
+	var data []byte
+	for _, line := range lines {
+		record, err := encoding.ConvertToEBCDIC(line, codePage)
+		require.NoError(t, err)
+		data = binary.BigEndian.AppendUint32(data, uint32(len(record)))
+		data = append(data, record...)
+	}
+	return data
+}
+
+func TestContentCodePage(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	records := ebcdicRecords(t, "IBM-037", "  A(1) = B[2] ^ C   ", "//* £ ¢ $")
+	mock.OnReadDataset("IBMUSER.CNTL", "ALLOC").Return(records)
+	mock.OnWriteDataset("IBMUSER.CNTL", "ALLOC")
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	session.Profile.Encoding = "IBM-1047"
+	dm := NewDatasetManager(session)
+
+	// Records are fetched unconverted and decoded locally, whatever the profile says
+	content, err := dm.DownloadContent(&DownloadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "ALLOC", CodePage: "IBM-037", TrimTrailingSpaces: true})
+	require.NoError(t, err)
+	assert.Equal(t, "  A(1) = B[2] ^ C\n//* £ ¢ $\n", content)
+	last := mock.LastRequest()
+	assert.Equal(t, "record", last.Header.Get("X-IBM-Data-Type"))
+	assert.Empty(t, last.Query.Get("encoding"))
+
+	// Read as another code page, the same bytes give other characters
+	content, err = dm.DownloadContent(&DownloadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "ALLOC", CodePage: "285"})
+	require.NoError(t, err)
+	assert.NotContains(t, content, "B[2]")
+
+	// Uploads are framed the same way, and read back in the code page to verify them
+	require.NoError(t, dm.UploadContent(&UploadRequest{
+		DatasetName: "IBMUSER.CNTL",
+		MemberName:  "ALLOC",
+		Content:     "  A(1) = B[2] ^ C   \r\n//* £ ¢ $\r\n",
+		CodePage:    "IBM-037",
+		Verify:      true,
+	}))
+	writes := mock.RequestsTo("PUT", "/restfiles/ds/IBMUSER.CNTL(ALLOC)")
+	require.Len(t, writes, 1)
+	assert.Equal(t, records, writes[0].Body)
+	assert.Equal(t, "record", writes[0].Header.Get("X-IBM-Data-Type"))
+	assert.Equal(t, "application/octet-stream", writes[0].Header.Get("Content-Type"))
+	assert.Equal(t, "record", mock.LastRequest().Header.Get("X-IBM-Data-Type"))
+
+	// Characters the code page lacks, unknown code pages and server-side conversion are refused
+	mock.Reset()
+	err = dm.UploadContent(&UploadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "ALLOC", Content: "OK\nCOST 10€", CodePage: "IBM-037"})
+	assert.ErrorContains(t, err, "line 2")
+	_, err = dm.DownloadContent(&DownloadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "ALLOC", CodePage: "IBM-500"})
+	assert.ErrorContains(t, err, "unsupported code page")
+	_, err = dm.DownloadContent(&DownloadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "ALLOC", CodePage: "IBM-037", SourceEncoding: "IBM-037"})
+	assert.Error(t, err)
+	assert.Empty(t, mock.Requests())
+
+	// A truncated record is reported rather than decoded
+	mock.OnReadDataset("IBMUSER.CNTL", "ALLOC").Return(records[:len(records)-2])
+	_, err = dm.DownloadContent(&DownloadRequest{DatasetName: "IBMUSER.CNTL", MemberName: "ALLOC", CodePage: "IBM-037"})
+	assert.ErrorContains(t, err, "only")
+}
+
+func TestDatasetIsMigratedValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		dataset Dataset
+		want    bool
+	}{
+		{"migr YES", Dataset{Migrated: "YES", Volume: "MIGRAT"}, true},
+		{"migr lower case", Dataset{Migrated: "yes"}, true},
+		{"migr padded", Dataset{Migrated: " YES "}, true},
+		{"migr NO", Dataset{Migrated: "NO", Volume: "USR001"}, false},
+		{"migr empty", Dataset{Volume: "USR001"}, false},
+		{"no attributes", Dataset{}, false},
+		{"MIGRAT volume without migr", Dataset{Volume: "MIGRAT"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.dataset.IsMigrated())
+		})
+	}
+}
+
+func TestDatasetAttributeAccessors(t *testing.T) {
+	dataset := Dataset{Used: "45", SizeX: "150", Extents: "3", BlockSize: "27920", RecordLength: "80"}
+	for name, get := range map[string]func() (int, error){
+		"used":  dataset.UsedPercent,
+		"sizex": dataset.SizeTracks,
+		"extx":  dataset.ExtentCount,
+		"blksz": dataset.BlockSizeBytes,
+		"lrecl": dataset.RecordLengthBytes,
+	} {
+		_, err := get()
+		assert.NoError(t, err, name)
+	}
+
+	tests := []struct {
+		name    string
+		used    string
+		want    int
+		wantErr string
+	}{
+		{"number", "45", 45, ""},
+		{"padded", " 7 ", 7, ""},
+		{"percent sign", "100%", 100, ""},
+		{"zero", "0", 0, ""},
+		{"empty", "", 0, "attribute not available: used"},
+		{"question mark", "?", 0, "attribute not available: used"},
+		{"malformed", "n/a", 0, `invalid used value "n/a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&Dataset{Used: tt.used}).UsedPercent()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Equal(t, tt.used == "" || tt.used == "?", errors.Is(err, ErrAttributeUnavailable))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	tracks, err := dataset.SizeTracks()
+	require.NoError(t, err)
+	assert.Equal(t, 150, tracks)
+	_, err = (&Dataset{SizeX: "?"}).SizeTracks()
+	assert.EqualError(t, err, "attribute not available: sizex")
+}
+
+func TestListDatasetsMigrationStatus(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListDatasets("IBMUSER.*")
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	names := func(list *DatasetList) []string {
+		var result []string
+		for _, ds := range list.Datasets {
+			result = append(result, ds.Name)
+		}
+		return result
+	}
+
+	all, err := dm.ListDatasets(&DatasetFilter{Name: "IBMUSER.*"})
+	require.NoError(t, err)
+	assert.Len(t, all.Datasets, 4)
+
+	active, err := dm.ListDatasets(&DatasetFilter{Name: "IBMUSER.*", MigrationStatus: MigrationStatusOnlyActive})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"IBMUSER.CNTL", "IBMUSER.DATA", "IBMUSER.LOADLIB"}, names(active))
+	assert.Equal(t, 3, active.ReturnedRows)
+	assert.Equal(t, "base", mock.LastRequest().Header.Get("X-IBM-Attributes"))
+
+	migrated, err := dm.ListDatasets(&DatasetFilter{Name: "IBMUSER.*", MigrationStatus: MigrationStatusOnlyMigrated})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"IBMUSER.OLD.DATA"}, names(migrated))
+
+	// Volume listings are filtered too
+	mock.On("GET", "/restfiles/ds").WithQuery("volser", "MIGRAT").Return(`{"items": [{"dsname": "IBMUSER.OLD.DATA", "migr": "YES", "vol": "MIGRAT"}], "returnedRows": 1}`)
+	active, err = dm.ListDatasets(&DatasetFilter{Volume: "MIGRAT", MigrationStatus: MigrationStatusOnlyActive})
+	require.NoError(t, err)
+	assert.Empty(t, active.Datasets)
+
+	// An unknown status is rejected without a request
+	requests := len(mock.Requests())
+	_, err = dm.ListDatasets(&DatasetFilter{Name: "IBMUSER.*", MigrationStatus: "recalled"})
+	assert.ErrorContains(t, err, `invalid migration status "recalled"`)
+	assert.Len(t, mock.Requests(), requests)
+}
+
+// newDatasetPagingServer serves a sorted dataset listing a page at a time, starting at
+// the start parameter as z/OSMF does
+func newDatasetPagingServer(t *testing.T, names []string, starts *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		maxItems, err := strconv.Atoi(r.Header.Get("X-IBM-Max-Items"))
+		require.NoError(t, err)
+		start := r.URL.Query().Get("start")
+		*starts = append(*starts, start)
+
+		list := DatasetList{Datasets: []Dataset{}, JSONVersion: 1}
+		for i, name := range names {
+			if name < start {
+				continue
+			}
+			if len(list.Datasets) == maxItems {
+				list.MoreRows = true
+				break
+			}
+			list.Datasets = append(list.Datasets, Dataset{Name: name, Volume: fmt.Sprintf("VOL%03d", i)})
+		}
+		list.ReturnedRows = len(list.Datasets)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(list)
+	}))
+}
+
+func TestListAllDatasets(t *testing.T) {
+	var names []string
+	for i := 1; i <= 7; i++ {
+		names = append(names, fmt.Sprintf("USER.DATA%d", i))
+	}
+	var starts []string
+	server := newDatasetPagingServer(t, names, &starts)
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	// ListDatasets stops at the first page
+	first, err := dm.ListDatasets(&DatasetFilter{Name: "USER.*", Limit: 3})
+	require.NoError(t, err)
+	assert.Len(t, first.Datasets, 3)
+	assert.True(t, first.MoreRows)
+
+	starts = nil
+	all, err := dm.ListAllDatasets(&DatasetFilter{Name: "USER.*", Limit: 3})
+	require.NoError(t, err)
+	require.Len(t, all.Datasets, 7)
+	for i, ds := range all.Datasets {
+		assert.Equal(t, names[i], ds.Name)
+	}
+	assert.False(t, all.MoreRows)
+	assert.Equal(t, 7, all.ReturnedRows)
+	assert.Equal(t, []string{"", "USER.DATA3", "USER.DATA5"}, starts)
+
+	// Owner is not used as a start, and the caller's filter is left alone
+	starts = nil
+	filter := &DatasetFilter{Name: "USER.*", Limit: 3, Owner: "USER.DATA6"}
+	all, err = dm.ListAllDatasets(filter)
+	require.NoError(t, err)
+	assert.Len(t, all.Datasets, 7)
+	assert.Equal(t, []string{"", "USER.DATA3", "USER.DATA5"}, starts)
+	assert.Equal(t, "USER.DATA6", filter.Owner)
+
+	// A page size of 1 could never advance past an inclusive start
+	starts = nil
+	all, err = dm.ListAllDatasets(&DatasetFilter{Name: "USER.*", Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, all.Datasets, 7)
+	assert.Len(t, starts, 6)
+}
+
+func TestListAllDatasetsNoProgress(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": [{"dsname": "USER.A"}, {"dsname": "USER.B"}], "returnedRows": 2, "moreRows": true}`))
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	dm := NewDatasetManager(session)
+
+	_, err = dm.ListAllDatasets(&DatasetFilter{Name: "USER.*", Limit: 2})
+	assert.ErrorContains(t, err, `listing USER.* did not advance past "USER.B"`)
+	assert.Equal(t, 2, requests)
+}
+
+func TestNewDatasetManagerOptions(t *testing.T) {
+	var paths []string
+	failed := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if !failed && r.URL.Path == "/ibmzosmf/api/v1/restfiles/ds" {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[{"dsname":"USER.DATA"}],"returnedRows":1}`))
+	}))
+	defer server.Close()
+
+	var deprecations []profile.Deprecation
+	profile.SetDeprecationLogger(func(d profile.Deprecation) {
+		deprecations = append(deprecations, d)
+	})
+	defer profile.SetDeprecationLogger(nil)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	require.NoError(t, err)
+
+	// The old constructors delegate to New
+	session, err := profile.CreateSessionDirectWithOptions(serverURL.Hostname(), port, "testuser", "testpass", false, "/api/v1")
+	require.NoError(t, err)
+	assert.Equal(t, New(session, WithCache(time.Minute)), NewDatasetManager(session, WithCache(time.Minute)))
+
+	dm, err := CreateDatasetManagerDirectWithOptions(serverURL.Hostname(), port, "testuser", "testpass", false, "/api/v1")
+	require.NoError(t, err)
+	_, err = dm.ListDatasets(&DatasetFilter{Name: "USER.*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/api/v1/restfiles/ds"}, paths)
+
+	_, err = CreateDatasetManagerDirect(serverURL.Hostname(), port, "testuser", "testpass")
+	require.NoError(t, err)
+	assert.Equal(t, []profile.Deprecation{
+		{Function: "datasets.CreateDatasetManagerDirectWithOptions", Replacement: "datasets.New"},
+		{Function: "datasets.CreateDatasetManagerDirect", Replacement: "datasets.New"},
+	}, deprecations)
+
+	// A 503 is retried under the overriding base path
+	paths = nil
+	dm = New(session, WithBasePath("/ibmzosmf/api/v1"), WithRetries(1))
+	list, err := dm.ListDatasets(&DatasetFilter{Name: "USER.*"})
+	require.NoError(t, err)
+	require.Len(t, list.Datasets, 1)
+	assert.Equal(t, []string{"/ibmzosmf/api/v1/restfiles/ds", "/ibmzosmf/api/v1/restfiles/ds"}, paths)
+}
@@ -0,0 +1,63 @@
+package datasets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMemberRequired is returned when content is read or written without a member name
+// on a partitioned dataset, which would return or overwrite its directory
+var ErrMemberRequired = errors.New("partitioned dataset requires a member name")
+
+// checkMemberGiven fails with ErrMemberRequired when no member is named and the
+// dataset is partitioned. If the organization cannot be found the check is skipped,
+// and the content request reports whatever is wrong.
+func (dm *ZOSMFDatasetManager) checkMemberGiven(datasetName, memberName string, force bool) error {
+	if memberName != "" || force {
+		return nil
+	}
+	if _, _, ok := ParseRelativeGeneration(datasetName); ok {
+		return nil // a relative generation cannot be listed by name
+	}
+	kind, ok := dm.datasetKind(datasetName)
+	if !ok || (kind != DatasetTypePartitioned && kind != DatasetTypePDSE) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is partitioned (%s); specify a member name, or set Force to bypass this check", ErrMemberRequired, strings.ToUpper(datasetName), kind)
+}
+
+// datasetKind returns the organization of a dataset, listing it the first time it is
+// asked for and remembering the answer for the life of the manager
+func (dm *ZOSMFDatasetManager) datasetKind(datasetName string) (DatasetType, bool) {
+	key := strings.ToUpper(strings.TrimSpace(datasetName))
+
+	dm.dsorgMu.Lock()
+	kind, ok := dm.dsorgs[key]
+	dm.dsorgMu.Unlock()
+	if ok {
+		return kind, true
+	}
+
+	matches, err := dm.listExact(key)
+	if err != nil || len(matches) == 0 {
+		return "", false
+	}
+	kind = matches[0].DatasetKind()
+
+	dm.dsorgMu.Lock()
+	if dm.dsorgs == nil {
+		dm.dsorgs = make(map[string]DatasetType)
+	}
+	dm.dsorgs[key] = kind
+	dm.dsorgMu.Unlock()
+	return kind, true
+}
+
+// forgetDatasetKind drops the remembered organization of a dataset that was created,
+// deleted or renamed through the manager
+func (dm *ZOSMFDatasetManager) forgetDatasetKind(datasetName string) {
+	dm.dsorgMu.Lock()
+	delete(dm.dsorgs, strings.ToUpper(strings.TrimSpace(datasetName)))
+	dm.dsorgMu.Unlock()
+}
@@ -0,0 +1,41 @@
+package datasets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+)
+
+// sourceEncoding returns the host code page to convert dataset text from: the
+// request's value, else the profile's encoding, else "" for the z/OSMF default
+func (dm *ZOSMFDatasetManager) sourceEncoding(requested string) string {
+	if requested != "" {
+		return profile.NormalizeEncoding(requested)
+	}
+	return profile.SessionEncoding(dm.session)
+}
+
+// targetEncoding returns the local character set to convert text to: target, or the
+// older Encoding field when only it is set. The two may not name different sets.
+func targetEncoding(encoding, target string) (string, error) {
+	encoding, target = strings.TrimSpace(encoding), strings.TrimSpace(target)
+	if encoding == "" {
+		return target, nil
+	}
+	if target != "" && !strings.EqualFold(encoding, target) {
+		return "", fmt.Errorf("Encoding %q conflicts with TargetEncoding %q", encoding, target)
+	}
+	return encoding, nil
+}
+
+// setTextConversionHeaders asks z/OSMF to convert text between the host code page
+// and the local character set. Without them z/OSMF assumes IBM-1047 on the host.
+func setTextConversionHeaders(headers map[string]string, source, target string) {
+	if source != "" {
+		headers["X-IBM-Data-Type"] = "text;fileEncoding=" + source
+	}
+	if target != "" {
+		headers["Content-Type"] = "text/plain;charset=" + target
+	}
+}
@@ -0,0 +1,151 @@
+package datasets
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/internal/gdg"
+)
+
+// Generation data group limits
+const (
+	// MaxGDGBaseLength is the longest GDG base name; the .GnnnnVnn suffix takes the rest of 44
+	MaxGDGBaseLength = 35
+	// MaxGDGGenerations is the most generations a GDG can hold, and so the largest relative generation
+	MaxGDGGenerations = gdg.MaxGenerations
+	// MaxExtendedGDGGenerations is the most generations an extended GDG can hold
+	MaxExtendedGDGGenerations = 999
+)
+
+var absoluteGenerationPattern = regexp.MustCompile(`^G[0-9]{4}V[0-9]{2}$`)
+
+// ParseRelativeGeneration splits a relative GDG reference such as "MY.GDG(0)", "MY.GDG(+1)"
+// or "MY.GDG(-2)" into its base name and generation. ok is false when name has no
+// relative generation.
+func ParseRelativeGeneration(name string) (base string, generation int, ok bool) {
+	return gdg.ParseReference(name)
+}
+
+// validateRelativeGeneration validates the base and generation of a relative GDG reference
+func validateRelativeGeneration(base string, generation int) error {
+	if len(base) > MaxGDGBaseLength {
+		return fmt.Errorf("GDG base name cannot exceed %d characters", MaxGDGBaseLength)
+	}
+	if generation < -MaxGDGGenerations || generation > MaxGDGGenerations {
+		return fmt.Errorf("relative generation must be between -%d and +%d", MaxGDGGenerations, MaxGDGGenerations)
+	}
+	return nil
+}
+
+// escapeDatasetName escapes a dataset name for a URL path. PathEscape leaves "+" as is,
+// which some proxies decode as a space, so the + of a relative generation is escaped too.
+func escapeDatasetName(name string) string {
+	return strings.ReplaceAll(url.PathEscape(name), "+", "%2B")
+}
+
+// ListGDGGenerations lists the absolute generation names (BASE.GnnnnVnn) of a GDG,
+// oldest first. Other datasets under the base, and the base itself, are left out.
+func (dm *ZOSMFDatasetManager) ListGDGGenerations(baseName string) ([]string, error) {
+	baseName = strings.ToUpper(strings.TrimSpace(baseName))
+	if err := validateGDGBaseName(baseName); err != nil {
+		return nil, err
+	}
+
+	list, err := dm.ListAllDatasets(&DatasetFilter{Name: baseName + ".G*"})
+	if err != nil {
+		return nil, err
+	}
+
+	generations := []string{}
+	prefix := baseName + "."
+	for _, ds := range list.Datasets {
+		if strings.HasPrefix(ds.Name, prefix) && absoluteGenerationPattern.MatchString(ds.Name[len(prefix):]) {
+			generations = append(generations, ds.Name)
+		}
+	}
+	sort.Strings(generations)
+	return generations, nil
+}
+
+// CreateGDGBase defines a generation data group base with IDCAMS DEFINE GENERATIONDATAGROUP
+func (dm *ZOSMFDatasetManager) CreateGDGBase(name string, options *GDGOptions) error {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	statement, err := gdgDefineStatement(name, options)
+	if err != nil {
+		return err
+	}
+	return dm.runAMSStatement("DEFINE", statement)
+}
+
+// DeleteGDGBase deletes a generation data group base. Without force the base must have
+// no generations; with force its generations are deleted along with it.
+func (dm *ZOSMFDatasetManager) DeleteGDGBase(name string, force bool) error {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if err := validateGDGBaseName(name); err != nil {
+		return err
+	}
+	return dm.runAMSStatement("DELETE", gdgDeleteStatement(name, force))
+}
+
+// validateGDGBaseName validates an uppercased GDG base name
+func validateGDGBaseName(name string) error {
+	if err := ValidateDatasetName(name); err != nil {
+		return fmt.Errorf("invalid GDG base name: %w", err)
+	}
+	if _, _, ok := ParseRelativeGeneration(name); ok {
+		return fmt.Errorf("GDG base name cannot include a generation: %s", name)
+	}
+	if len(name) > MaxGDGBaseLength {
+		return fmt.Errorf("GDG base name cannot exceed %d characters", MaxGDGBaseLength)
+	}
+	return nil
+}
+
+// gdgDefineStatement builds the IDCAMS statement defining a GDG base, e.g.
+//
+//	DEFINE GENERATIONDATAGROUP (NAME('USER.GDG') LIMIT(5) NOEMPTY SCRATCH NOPURGE)
+func gdgDefineStatement(name string, options *GDGOptions) (string, error) {
+	if err := validateGDGBaseName(name); err != nil {
+		return "", err
+	}
+	if options == nil {
+		return "", fmt.Errorf("GDG options are required")
+	}
+	maxLimit := MaxGDGGenerations
+	if options.Extended {
+		maxLimit = MaxExtendedGDGGenerations
+	}
+	if options.Limit < 1 || options.Limit > maxLimit {
+		return "", fmt.Errorf("GDG limit must be between 1 and %d", maxLimit)
+	}
+	if options.Purge && !options.Scratch {
+		return "", fmt.Errorf("GDG purge requires scratch")
+	}
+
+	params := []string{fmt.Sprintf("NAME('%s')", name), fmt.Sprintf("LIMIT(%d)", options.Limit)}
+	params = append(params, gdgKeyword(options.Empty, "EMPTY"), gdgKeyword(options.Scratch, "SCRATCH"), gdgKeyword(options.Purge, "PURGE"))
+	if options.Extended {
+		params = append(params, "EXTENDED")
+	}
+	return "DEFINE GENERATIONDATAGROUP (" + strings.Join(params, " ") + ")", nil
+}
+
+// gdgDeleteStatement builds the IDCAMS statement deleting a GDG base
+func gdgDeleteStatement(name string, force bool) string {
+	statement := fmt.Sprintf("DELETE '%s' GENERATIONDATAGROUP", name)
+	if force {
+		statement += " FORCE"
+	}
+	return statement
+}
+
+// gdgKeyword returns keyword, or its NO form when off
+func gdgKeyword(on bool, keyword string) string {
+	if on {
+		return keyword
+	}
+	return "NO" + keyword
+}
@@ -0,0 +1,162 @@
+package datasets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+)
+
+// ErrDatasetInUse is returned when a dataset or member cannot be changed because
+// another job or user holds it, for example while it is open in ISPF edit.
+// The error is a *DatasetInUseError, which names the holder when z/OSMF does.
+var ErrDatasetInUse = errors.New("dataset is in use")
+
+// DatasetInUseError reports a request that failed because the dataset was in use.
+// It wraps the *profile.APIError of the failed response, so errors.As finds either.
+type DatasetInUseError struct {
+	*profile.APIError
+	Holder string // Job or user holding the dataset, when the message names one
+}
+
+func (e *DatasetInUseError) Error() string {
+	msg := ErrDatasetInUse.Error()
+	if e.Holder != "" {
+		msg += " by " + e.Holder
+	}
+	return msg + ": " + e.APIError.Error()
+}
+
+// Is makes errors.Is(err, ErrDatasetInUse) report true
+func (e *DatasetInUseError) Is(target error) bool {
+	return target == ErrDatasetInUse
+}
+
+// Unwrap returns the API error of the failed response
+func (e *DatasetInUseError) Unwrap() error {
+	return e.APIError
+}
+
+// errorMessage returns the message and details of a z/OSMF error document, or the
+// body as it is when it is not one
+func errorMessage(body []byte) string {
+	return apiErrorMessage(profile.NewAPIError(0, body))
+}
+
+// apiErrorMessage returns the z/OSMF message and details of an API error, or its body
+// when the response was not a z/OSMF error document
+func apiErrorMessage(apiErr *profile.APIError) string {
+	if apiErr.Message != "" || len(apiErr.Details) > 0 {
+		return strings.TrimSpace(strings.Join(append([]string{apiErr.Message}, apiErr.Details...), " "))
+	}
+	return apiErr.Body
+}
+
+// inUseMessagePattern finds the IDs of the messages z/OSMF passes on when it cannot
+// serialize a dataset: ISPF's member-in-use message and the dynamic allocation failure.
+// Only whole message IDs match, so dataset names such as USER.ENQLOG do not.
+var inUseMessagePattern = regexp.MustCompile(`\b(?:ISRZ002|IKJ56225I)\b`)
+
+// inUseHolderPattern finds the job or user named as holding a dataset
+var inUseHolderPattern = regexp.MustCompile(`(?i)\b(?:held by|in use by|allocated to|used by)\s+(?:job\s+|user\s+|tso user\s+)?'?([A-Z0-9$#@]{1,8})'?`)
+
+// notHolders are words the holder pattern matches that do not name a holder
+var notHolders = map[string]bool{"ANOTHER": true, "YOU": true, "JOB": true, "USER": true, "THE": true}
+
+// inUseError returns a *DatasetInUseError when an error response says the dataset
+// is in use, and nil otherwise
+func inUseError(statusCode int, body []byte) *DatasetInUseError {
+	apiErr := profile.NewAPIError(statusCode, body)
+	message := apiErrorMessage(apiErr)
+	if !inUseMessagePattern.MatchString(message) {
+		return nil
+	}
+
+	inUseErr := &DatasetInUseError{APIError: apiErr}
+	for _, match := range inUseHolderPattern.FindAllStringSubmatch(message, -1) {
+		holder := strings.ToUpper(match[1])
+		if !notHolders[holder] {
+			inUseErr.Holder = holder
+			break
+		}
+	}
+	return inUseErr
+}
+
+// exclusivePollInterval is how often WaitForExclusive retries
+var exclusivePollInterval = 2 * time.Second
+
+// WaitForExclusive waits until the dataset can be held exclusively, polling with an
+// exclusive ENQ that is released straight away, until timeout passes or ctx ends.
+// It returns nil once no other job or user holds the dataset, an error wrapping
+// ErrDatasetInUse if it is still held, or any other error at once.
+func (dm *ZOSMFDatasetManager) WaitForExclusive(ctx context.Context, datasetName string, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(exclusivePollInterval)
+	defer ticker.Stop()
+	var inUseErr error
+	for {
+		err := dm.probeExclusive(ctx, datasetName)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, ErrDatasetInUse) {
+			inUseErr = err
+		} else if ctx.Err() == nil || inUseErr == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up waiting for %s: %w", datasetName, inUseErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeExclusive obtains and releases an exclusive ENQ on a dataset
+func (dm *ZOSMFDatasetManager) probeExclusive(ctx context.Context, datasetName string) error {
+	path := fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(datasetName))
+
+	// Read a single record; only the ENQ matters
+	headers := map[string]string{"X-IBM-Record-Range": "0,1", "X-IBM-Obtain-ENQ": "EXCL"}
+	resp, err := profile.DoRequest(ctx, dm.session, "GET", path, nil, headers, nil)
+	if err != nil {
+		return err
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return contentRequestError(resp.StatusCode, body)
+	}
+
+	return dm.releaseENQ(ctx, path, resp.Header.Get("X-IBM-Session-Ref"))
+}
+
+// releaseENQ releases the ENQ held by an ENQ session; an empty sessionRef holds none.
+// The retrieve service takes X-IBM-Release-ENQ along with X-IBM-Obtain-ENQ and
+// X-IBM-Session-Ref, so a one-record read releases it without writing the dataset.
+func (dm *ZOSMFDatasetManager) releaseENQ(ctx context.Context, path, sessionRef string) error {
+	if sessionRef == "" {
+		return nil
+	}
+	headers := map[string]string{"X-IBM-Record-Range": "0,1", "X-IBM-Session-Ref": sessionRef, "X-IBM-Release-ENQ": "true"}
+	resp, err := profile.DoRequest(ctx, dm.session, "GET", path, nil, headers, nil)
+	if err != nil {
+		return fmt.Errorf("failed to release ENQ: %w", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return nil
+}
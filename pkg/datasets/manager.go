@@ -2,12 +2,15 @@ package datasets
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
 )
@@ -32,11 +35,23 @@ const (
 	MemberContentEndpoint  = "/content/%s"
 )
 
-// NewDatasetManager creates a dataset manager with the given session
-func NewDatasetManager(session *profile.Session) *ZOSMFDatasetManager {
-	return &ZOSMFDatasetManager{
+// Ensure ZOSMFDatasetManager implements DatasetManager
+var _ DatasetManager = (*ZOSMFDatasetManager)(nil)
+
+// New creates a dataset manager with the given session, usually a *profile.Session
+func New(session profile.HTTPSession, opts ...ManagerOption) *ZOSMFDatasetManager {
+	dm := &ZOSMFDatasetManager{
 		session: session,
 	}
+	for _, opt := range opts {
+		opt(dm)
+	}
+	return dm
+}
+
+// NewDatasetManager creates a dataset manager with the given session; it is the same as New
+func NewDatasetManager(session profile.HTTPSession, opts ...ManagerOption) *ZOSMFDatasetManager {
+	return New(session, opts...)
 }
 
 // NewDatasetManagerFromProfile creates a dataset manager from a profile
@@ -45,184 +60,341 @@ func NewDatasetManagerFromProfile(profile *profile.ZOSMFProfile) (*ZOSMFDatasetM
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
-	return NewDatasetManager(session), nil
+	return New(session), nil
 }
 
-// ListDatasets gets datasets matching the filter
-func (dm *ZOSMFDatasetManager) ListDatasets(filter *DatasetFilter) (*DatasetList, error) {
-	session := dm.session.(*profile.Session)
+// Session returns the session the manager sends requests with, wrapped when
+// WithBasePath or WithRetries was given
+func (dm *ZOSMFDatasetManager) Session() profile.HTTPSession {
+	return dm.session
+}
 
-	// Build query parameters
-	params := url.Values{}
+// do sends a request to a z/OSMF path through the manager's session; the caller
+// must close the response body
+func (dm *ZOSMFDatasetManager) do(method, path string, query url.Values, headers map[string]string, body io.Reader) (*http.Response, error) {
+	return profile.DoRequest(context.Background(), dm.session, method, path, query, headers, body)
+}
 
-	// Need either dslevel or volser parameter
-	hasRequiredParam := false
+// doJSON sends a request and decodes its JSON response into out, which may be nil
+func (dm *ZOSMFDatasetManager) doJSON(method, path string, query url.Values, headers map[string]string, body io.Reader, out interface{}) error {
+	return profile.DoJSONRequest(context.Background(), dm.session, method, path, query, headers, body, out)
+}
 
-	if filter != nil {
-		if filter.Name != "" {
-			// Dataset name pattern (wildcards supported)
-			params.Set("dslevel", filter.Name)
-			hasRequiredParam = true
-		}
-		if filter.Volume != "" {
-			// Volume serial number
-			params.Set("volser", filter.Volume)
-			hasRequiredParam = true
-		}
-		if filter.Owner != "" {
-			// Starting dataset name for pagination
-			params.Set("start", filter.Owner)
-		}
-		// Limit is handled via header, not query param
+// SetDefaultLimit caps the results of ListDatasets calls whose filter sets no Limit,
+// and of ListMembers, at n entries. n <= 0 removes the cap, which is the default.
+// Cached lists are dropped, as they may have been fetched with another limit.
+func (dm *ZOSMFDatasetManager) SetDefaultLimit(n int) {
+	if n < 0 {
+		n = 0
 	}
+	dm.defaultLimit = n
+	dm.cache.clear()
+}
 
-	// Default to user's datasets if no filter specified
-	if !hasRequiredParam {
-		// Use user ID to avoid listing everything
-		params.Set("dslevel", session.User+".*")
+// ListDatasets gets datasets matching the filter.
+// z/OSMF accepts a single dslevel pattern per request, so each entry of
+// filter.Names is listed separately and the results are merged.
+// With WithCache, results are served from the cache unless filter.ForceRefresh is set.
+func (dm *ZOSMFDatasetManager) ListDatasets(filter *DatasetFilter) (*DatasetList, error) {
+	if filter == nil {
+		filter = &DatasetFilter{}
+	}
+	if dm.cache == nil {
+		return dm.listDatasets(filter)
 	}
 
-	// Build URL
-	apiURL := session.GetBaseURL() + DatasetsEndpoint
-	if len(params) > 0 {
-		apiURL += "?" + params.Encode()
+	// Key on the patterns listed, which DefaultToUserPrefix takes from the session user
+	patterns, err := dm.listPatterns(filter)
+	if err != nil {
+		return nil, err
 	}
+	key := datasetListKey(filter, patterns)
+	if !filter.ForceRefresh {
+		if list, ok := dm.cache.getDatasets(key); ok {
+			return list, nil
+		}
+	}
+	list, err := dm.listDatasets(filter)
+	if err != nil {
+		return nil, err
+	}
+	dm.cache.putDatasets(key, list)
+	return list, nil
+}
 
-	// Create request
-	req, err := http.NewRequest("GET", apiURL, nil)
+// listDatasets queries z/OSMF for the datasets matching the filter
+func (dm *ZOSMFDatasetManager) listDatasets(filter *DatasetFilter) (*DatasetList, error) {
+	session := dm.session
+	if err := filter.MigrationStatus.validate(); err != nil {
+		return nil, err
+	}
+	patterns, err := dm.listPatterns(filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
+	// Volume-only listing
+	if len(patterns) == 0 {
+		datasetList, err := dm.listDatasetsPage(session, "", filter.Owner, filter)
+		if err != nil {
+			return nil, err
+		}
+		return filterMigrationStatus(datasetList, filter.MigrationStatus), nil
 	}
 
-	// Set result limit
-	if filter != nil && filter.Limit > 0 {
-		req.Header.Set("X-IBM-Max-Items", strconv.Itoa(filter.Limit))
-	} else {
-		req.Header.Set("X-IBM-Max-Items", "0") // 0 = no limit
+	merged := &DatasetList{}
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		datasetList, err := dm.listDatasetsPage(session, pattern, filter.Owner, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, ds := range datasetList.Datasets {
+			// The same name on different volumes is a different dataset
+			key := ds.Name + " " + ds.Volume
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged.Datasets = append(merged.Datasets, ds)
+		}
+		merged.MoreRows = merged.MoreRows || datasetList.MoreRows
+		merged.JSONVersion = datasetList.JSONVersion
 	}
+	merged.ReturnedRows = len(merged.Datasets)
 
-	// Get basic attributes only
-	req.Header.Set("X-IBM-Attributes", "base")
+	return filterMigrationStatus(merged, filter.MigrationStatus), nil
+}
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+// listPatterns returns the dslevel patterns a filter lists; it is empty for a
+// volume-only listing
+func (dm *ZOSMFDatasetManager) listPatterns(filter *DatasetFilter) ([]string, error) {
+	var patterns []string
+	for _, pattern := range append([]string{filter.Name}, filter.Names...) {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	// Need either dslevel or volser parameter
+	if len(patterns) == 0 && filter.Volume == "" {
+		if !filter.DefaultToUserPrefix {
+			return nil, fmt.Errorf("dataset filter needs a name pattern or volume (set DefaultToUserPrefix to list the session user's datasets)")
+		}
+		user := dm.session.GetUser()
+		if user == "" {
+			return nil, fmt.Errorf("cannot default to the user prefix: session has no user")
+		}
+		patterns = append(patterns, user+".*")
 	}
+	return patterns, nil
+}
 
-	// Parse response
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+// listDatasetsPage runs a single z/OSMF dataset list request for one dslevel pattern,
+// starting from the dataset named by start when it is set
+func (dm *ZOSMFDatasetManager) listDatasetsPage(session profile.HTTPSession, pattern, start string, filter *DatasetFilter) (*DatasetList, error) {
+	// Build query parameters
+	params := url.Values{}
+	if pattern != "" {
+		// Dataset name pattern (wildcards supported)
+		params.Set("dslevel", pattern)
+	}
+	if filter.Volume != "" {
+		// Volume serial number
+		params.Set("volser", filter.Volume)
+	}
+	if start != "" {
+		// Starting dataset name for pagination
+		params.Set("start", start)
+	}
+
+	// Set result limit; 0 means no limit
+	limit := filter.Limit
+	if limit == 0 {
+		limit = dm.defaultLimit
+	}
+	// The base attributes include migr, which IsMigrated and MigrationStatus rely on
+	headers := map[string]string{
+		"X-IBM-Max-Items":  "0",
+		"X-IBM-Attributes": "base", // Get basic attributes only
+	}
+	if limit > 0 {
+		headers["X-IBM-Max-Items"] = strconv.Itoa(limit)
 	}
 
 	var datasetList DatasetList
-	if err := json.Unmarshal(bodyBytes, &datasetList); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := profile.DoJSONRequest(context.Background(), session, "GET", DatasetsEndpoint, params, headers, nil, &datasetList); err != nil {
+		return nil, err
 	}
 
 	return &datasetList, nil
 }
 
-// GetDataset gets info for a specific dataset
+// getDatasetMaxItems caps the listing made by GetDataset. A dataset name sorts before
+// every dataset qualified under it, so the exact match is always among the first entries.
+const getDatasetMaxItems = 5
+
+// aliasVolume is the volume z/OSMF lists for a catalog alias entry
+const aliasVolume = "*ALIAS"
+
+// GetDataset gets info for a specific dataset. The name must not contain wildcards;
+// use ListDatasets for patterns. When the listing marks the name as a catalog alias,
+// the dataset it refers to is returned with Alias set.
 func (dm *ZOSMFDatasetManager) GetDataset(name string) (*Dataset, error) {
-	// Use list API to get metadata
-	dl, err := dm.ListDatasets(&DatasetFilter{Name: name})
+	return dm.GetDatasetWithOptions(name, nil)
+}
+
+// GetDatasetWithOptions gets info for a specific dataset like GetDataset. With
+// opts.ResolveAlias, a name that is not listed is looked up in the catalog in case
+// it is an alias, at the cost of an IDCAMS LISTCAT.
+func (dm *ZOSMFDatasetManager) GetDatasetWithOptions(name string, opts *GetDatasetOptions) (*Dataset, error) {
+	matches, err := dm.findDataset(name, opts != nil && opts.ResolveAlias)
 	if err != nil {
 		return nil, err
 	}
-
-	// Find the dataset in results
-	for _, ds := range dl.Datasets {
-		if ds.Name == name {
-			return &ds, nil
-		}
-	}
-
-	return nil, fmt.Errorf("dataset not found: %s", name)
+	return &matches[0], nil
 }
 
-// GetDatasetInfo gets detailed dataset info, trying direct API first
-func (dm *ZOSMFDatasetManager) GetDatasetInfo(name string) (*Dataset, error) {
-	// Try direct API first
-	dataset, err := dm.getDatasetInfoDirect(name)
-	if err == nil {
-		return dataset, nil
+// GetDatasetStrict gets info for a specific dataset like GetDataset, but fails when
+// more than one catalog entry has the name
+func (dm *ZOSMFDatasetManager) GetDatasetStrict(name string) (*Dataset, error) {
+	matches, err := dm.findDataset(name, false)
+	if err != nil {
+		return nil, err
 	}
-
-	// Fall back to list API
-	return dm.GetDataset(name)
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("dataset name %s matched %d catalog entries", strings.ToUpper(name), len(matches))
+	}
+	return &matches[0], nil
 }
 
-// getDatasetInfoDirect tries to get dataset info via direct API
-func (dm *ZOSMFDatasetManager) getDatasetInfoDirect(name string) (*Dataset, error) {
-	session := dm.session.(*profile.Session)
-
-	// Build URL for direct dataset access
-	apiURL := session.GetBaseURL() + fmt.Sprintf(DatasetByNameEndpoint, url.PathEscape(name))
-
-	// Request metadata, not content
-	params := url.Values{}
-	params.Set("metadata", "true")
-	apiURL += "?" + params.Encode()
+// findDataset returns the listed entries named exactly name, following an alias when
+// the listing marks the name as one, or when resolveAlias is set and it is not listed
+func (dm *ZOSMFDatasetManager) findDataset(name string, resolveAlias bool) ([]Dataset, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if strings.ContainsAny(name, "*%") {
+		return nil, fmt.Errorf("dataset name %s contains wildcards, use ListDatasets for patterns", name)
+	}
+	if err := ValidateDatasetName(name); err != nil {
+		return nil, err
+	}
 
-	// Create request
-	req, err := http.NewRequest("GET", apiURL, nil)
+	matches, err := dm.listExact(name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
+	}
+	isAlias := len(matches) > 0 && matches[0].Volume == aliasVolume
+	if len(matches) > 0 && !isAlias {
+		return matches, nil
+	}
+	if len(matches) == 0 && !resolveAlias {
+		return nil, fmt.Errorf("dataset not found: %s", name)
 	}
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
+	// The listing does not name the dataset an alias refers to, so ask the catalog
+	target, err := dm.resolveAlias(name)
+	if err != nil {
+		return nil, err
+	}
+	if target == "" {
+		if isAlias {
+			return nil, fmt.Errorf("cannot resolve alias %s: LISTCAT names no related dataset", name)
+		}
+		return nil, fmt.Errorf("dataset not found: %s", name)
+	}
+	matches, err = dm.listExact(target)
+	if err != nil {
+		return nil, err
 	}
-	req.Header.Set("Accept", "application/json")
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("dataset not found: %s (alias of %s)", name, target)
+	}
+	for i := range matches {
+		matches[i].Alias = name
+	}
+	return matches, nil
+}
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+// listExact lists the entries named exactly name
+func (dm *ZOSMFDatasetManager) listExact(name string) ([]Dataset, error) {
+	dl, err := dm.ListDatasets(&DatasetFilter{Name: name, Limit: getDatasetMaxItems})
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("dataset not found: %s", name)
+	var matches []Dataset
+	for _, ds := range dl.Datasets {
+		if strings.EqualFold(ds.Name, name) {
+			matches = append(matches, ds)
+		}
 	}
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	return matches, nil
+}
+
+// GetDatasetInfo gets the attributes of a dataset (organization, record format, space,
+// dates, ...). z/OSMF has no metadata request for a single dataset, since a GET of
+// /restfiles/ds/<name> returns its content, so the attributes come from the catalog
+// listing made with X-IBM-Attributes: base.
+func (dm *ZOSMFDatasetManager) GetDatasetInfo(name string) (*Dataset, error) {
+	return dm.GetDataset(name)
+}
+
+// GetDatasetUsage gets parsed space usage and attributes for a dataset
+func (dm *ZOSMFDatasetManager) GetDatasetUsage(name string) (*DatasetUsage, error) {
+	// The list API returns the base attributes (sizex, used, extx, ...)
+	dataset, err := dm.GetDataset(name)
+	if err != nil {
+		return nil, err
 	}
+	return parseDatasetUsage(dataset), nil
+}
 
-	// Try to parse response body as JSON
-	var dataset Dataset
-	if err := json.NewDecoder(resp.Body).Decode(&dataset); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// parseDatasetUsage converts the raw list attributes of a dataset into numbers
+func parseDatasetUsage(dataset *Dataset) *DatasetUsage {
+	usage := &DatasetUsage{
+		Name:            dataset.Name,
+		AllocatedTracks: parseAttributeInt(dataset.SizeX),
+		UsedPercent:     parseAttributeInt(dataset.Used),
+		Extents:         parseAttributeInt(dataset.Extents),
+		BlockSize:       parseAttributeInt(dataset.BlockSize),
+		RecordLength:    parseAttributeInt(dataset.RecordLength),
+	}
+	if unit := strings.TrimSpace(dataset.SpaceUnit); unit != "?" {
+		usage.SpaceUnit = unit
+	}
+	usage.AllocatedCylinders = (usage.AllocatedTracks + tracksPerCylinder - 1) / tracksPerCylinder
+	return usage
+}
+
+// tracksPerCylinder is the 3390 track count per cylinder
+const tracksPerCylinder = 15
+
+// ErrAttributeUnavailable is returned by the Dataset accessors for an attribute that
+// z/OSMF left empty or reported as "?", e.g. without READ access to the catalog entry
+var ErrAttributeUnavailable = errors.New("attribute not available")
+
+// attributeInt parses the numeric list attribute name
+func attributeInt(name, value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "?" {
+		return 0, fmt.Errorf("%w: %s", ErrAttributeUnavailable, name)
 	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", name, value)
+	}
+	return n, nil
+}
 
-	return &dataset, nil
+// parseAttributeInt parses a numeric list attribute, returning 0 for "?", empty or malformed values
+func parseAttributeInt(value string) int {
+	n, _ := attributeInt("", value)
+	return n
 }
 
 // CreateDataset creates a new dataset using the correct z/OSMF REST API format
 // Based on IBM documentation: POST /zosmf/restfiles/ds/<data-set-name>
 func (dm *ZOSMFDatasetManager) CreateDataset(request *CreateDatasetRequest) error {
-	session := dm.session.(*profile.Session)
-
-	// Build URL using the correct format from IBM documentation
-	apiURL := session.GetBaseURL() + fmt.Sprintf(DatasetByNameEndpoint, url.PathEscape(request.Name))
-
 	// Prepare request body
 	requestBody := map[string]interface{}{
 		"dsname": request.Name,
@@ -233,6 +405,9 @@ func (dm *ZOSMFDatasetManager) CreateDataset(request *CreateDatasetRequest) erro
 	if request.Volume != "" {
 		requestBody["vol"] = request.Volume
 	}
+	if request.Unit != "" {
+		requestBody["unit"] = request.Unit
+	}
 	if request.Space.Primary > 0 {
 		requestBody["alcunit"] = string(request.Space.Unit)
 		requestBody["primary"] = request.Space.Primary
@@ -240,6 +415,12 @@ func (dm *ZOSMFDatasetManager) CreateDataset(request *CreateDatasetRequest) erro
 		if request.Space.Directory > 0 {
 			requestBody["dirblk"] = request.Space.Directory
 		}
+		switch request.Space.Unit {
+		case SpaceUnitKB, SpaceUnitMB, SpaceUnitGB, SpaceUnitBlocks:
+			if request.Space.AverageBlock > 0 {
+				requestBody["avgblk"] = request.Space.AverageBlock
+			}
+		}
 	}
 	if request.RecordFormat != "" {
 		requestBody["recfm"] = string(request.RecordFormat)
@@ -249,216 +430,246 @@ func (dm *ZOSMFDatasetManager) CreateDataset(request *CreateDatasetRequest) erro
 	}
 	if request.BlockSize > 0 {
 		requestBody["blksize"] = int(request.BlockSize)
+		if request.Space.Unit == SpaceUnitBlocks && request.Space.AverageBlock == 0 {
+			requestBody["avgblk"] = int(request.BlockSize)
+		}
 	}
 	if request.Directory > 0 {
 		requestBody["dirblk"] = request.Directory
 	}
-
-	// Serialize request body
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
+	if request.DataClass != "" {
+		requestBody["dataclass"] = request.DataClass
 	}
-
-	// Create request
-	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	if request.ManagementClass != "" {
+		requestBody["mgntclass"] = request.ManagementClass
 	}
-
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
+	if request.StorageClass != "" {
+		requestBody["storclass"] = request.StorageClass
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+	// Serialize request body
+	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return fmt.Errorf("failed to marshal request body: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	// Path format from IBM documentation
+	path := fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(request.Name))
+	if err := dm.doJSON("POST", path, nil, jsonHeaders(), bytes.NewReader(jsonBody), nil); err != nil {
+		return err
 	}
 
+	dm.cache.invalidateDatasets()
+	dm.forgetDatasetKind(request.Name)
 	return nil
 }
 
 // DeleteDataset deletes a dataset
 func (dm *ZOSMFDatasetManager) DeleteDataset(name string) error {
-	session := dm.session.(*profile.Session)
-
-	// Build URL using template
-	apiURL := session.GetBaseURL() + fmt.Sprintf(DatasetByNameEndpoint, url.PathEscape(name))
-
-	// Create request
-	req, err := http.NewRequest("DELETE", apiURL, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+	resp, err := dm.do("DELETE", fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(name)), nil, nil, nil)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return contentRequestError(resp.StatusCode, body)
 	}
 
+	dm.cache.invalidateDatasets()
+	dm.cache.invalidateMembers(name)
+	dm.forgetDatasetKind(name)
 	return nil
 }
 
-// UploadContent uploads content to a dataset
+// UploadContent uploads content to a dataset. With request.Verify or SetVerifyUploads
+// the content is read back afterwards and compared. Without a member name it fails
+// with ErrMemberRequired if the dataset is partitioned, unless request.Force is set.
 func (dm *ZOSMFDatasetManager) UploadContent(request *UploadRequest) error {
-	session := dm.session.(*profile.Session)
-
-	// Build URL using correct z/OSMF format
-	var apiURL string
-	if request.MemberName != "" {
-		// For members, use dataset(member) format
-		apiURL = session.GetBaseURL() + fmt.Sprintf("/restfiles/ds/%s(%s)", url.PathEscape(request.DatasetName), url.PathEscape(request.MemberName))
-	} else {
-		// For datasets, use the dataset endpoint directly (no /content suffix)
-		apiURL = session.GetBaseURL() + fmt.Sprintf(DatasetByNameEndpoint, url.PathEscape(request.DatasetName))
+	if err := dm.checkMemberGiven(request.DatasetName, request.MemberName, request.Force); err != nil {
+		return err
 	}
+	if _, err := dm.uploadContent(request); err != nil {
+		return err
+	}
+	return dm.verifyUploaded(request)
+}
 
-	var req *http.Request
-	var err error
-
-	if request.MemberName != "" {
-		// For members, use PUT with plain text content
-		req, err = http.NewRequest("PUT", apiURL, bytes.NewBufferString(request.Content))
+// uploadContent uploads content to a dataset and returns the ETag z/OSMF reports
+// for the new content, which is empty unless request.ETag was set
+func (dm *ZOSMFDatasetManager) uploadContent(request *UploadRequest) (string, error) {
+	if err := checkCodePage(request.CodePage, request.SourceEncoding, request.TargetEncoding); err != nil {
+		return "", err
+	}
+	// For both datasets and members, use PUT with plain text content (per z/OSMF API
+	// specification), or with EBCDIC records when converting on the client
+	content := []byte(request.Content)
+	headers := map[string]string{"Content-Type": "text/plain"}
+	if request.CodePage != "" {
+		records, err := encodeRecords(request.Content, request.CodePage)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert content to %s: %w", request.CodePage, err)
+		}
+		content = records
+		headers["Content-Type"] = "application/octet-stream"
+		headers["X-IBM-Data-Type"] = recordDataType
 	} else {
-		// For datasets, use PUT with plain text content (per z/OSMF API specification)
-		req, err = http.NewRequest("PUT", apiURL, bytes.NewBufferString(request.Content))
-	}
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		target, err := targetEncoding(request.Encoding, request.TargetEncoding)
+		if err != nil {
+			return "", err
+		}
+		setTextConversionHeaders(headers, dm.sourceEncoding(request.SourceEncoding), target)
 	}
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
+	if request.ETag != "" {
+		headers["If-Match"] = request.ETag
+		headers["X-IBM-Return-Etag"] = "true"
 	}
 
-	// For both datasets and members, use plain text content type (per z/OSMF API specification)
-	req.Header.Set("Content-Type", "text/plain")
-
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+	// Let redirects and body logging replay the content without reporting progress twice
+	body := profile.NewProgressBody(content, request.Progress)
+	resp, err := dm.do("PUT", contentPath(request.DatasetName, request.MemberName), nil, headers, body)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	// Check response status
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("%w: %w", ErrETagMismatch, profile.NewAPIError(resp.StatusCode, body))
+	}
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return "", contentRequestError(resp.StatusCode, body)
 	}
 
-	return nil
+	dm.cache.invalidateMembers(request.DatasetName)
+	return resp.Header.Get("ETag"), nil
 }
 
-// DownloadContent downloads content from a dataset
+// DownloadContent downloads content from a dataset. Without a member name it fails
+// with ErrMemberRequired if the dataset is partitioned, unless request.Force is set.
+// With request.CodePage the records are transferred unconverted and decoded locally.
 func (dm *ZOSMFDatasetManager) DownloadContent(request *DownloadRequest) (string, error) {
-	session := dm.session.(*profile.Session)
-
-	// Build URL using correct z/OSMF format
-	var apiURL string
-	if request.MemberName != "" {
-		// For members, use dataset(member) format
-		apiURL = session.GetBaseURL() + fmt.Sprintf("/restfiles/ds/%s(%s)", url.PathEscape(request.DatasetName), url.PathEscape(request.MemberName))
-	} else {
-		// For datasets, use the dataset endpoint directly (no /content suffix)
-		apiURL = session.GetBaseURL() + fmt.Sprintf(DatasetByNameEndpoint, url.PathEscape(request.DatasetName))
+	if err := validateTextOptions(request); err != nil {
+		return "", err
 	}
-
-	// Add query parameters
-	params := url.Values{}
-	if request.Encoding != "" {
-		params.Set("encoding", request.Encoding)
-	}
-	if len(params) > 0 {
-		apiURL += "?" + params.Encode()
+	if err := dm.checkMemberGiven(request.DatasetName, request.MemberName, request.Force); err != nil {
+		return "", err
 	}
 
-	// Create request
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+	if err := checkCodePage(request.CodePage, request.SourceEncoding, request.TargetEncoding); err != nil {
+		return "", err
 	}
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
+	headers := map[string]string{}
+	if request.CodePage != "" {
+		headers["X-IBM-Data-Type"] = recordDataType
+	} else {
+		target, err := targetEncoding(request.Encoding, request.TargetEncoding)
+		if err != nil {
+			return "", err
+		}
+		setTextConversionHeaders(headers, dm.sourceEncoding(request.SourceEncoding), target)
 	}
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+	resp, err := dm.do("GET", contentPath(request.DatasetName, request.MemberName), nil, headers, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to make request: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return "", contentRequestError(resp.StatusCode, body)
 	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Read response body; ContentLength is -1 for chunked responses
+	body, err := io.ReadAll(profile.NewProgressReader(resp.Body, resp.ContentLength, request.Progress))
 	if err != nil {
 		return "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	return string(body), nil
+	content := string(body)
+	if request.CodePage != "" {
+		if content, err = decodeRecords(body, request.CodePage); err != nil {
+			return "", fmt.Errorf("failed to convert content from %s: %w", request.CodePage, err)
+		}
+	}
+	return transformDownloadedText(content, request)
+}
+
+// contentPath returns the path of a dataset, or of a member in dataset(member) format.
+// Content is read and written at the dataset path itself, with no /content suffix.
+func contentPath(datasetName, memberName string) string {
+	if memberName != "" {
+		return fmt.Sprintf("/restfiles/ds/%s(%s)", escapeDatasetName(datasetName), url.PathEscape(memberName))
+	}
+	return fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(datasetName))
 }
 
-// ListMembers retrieves a list of members in a partitioned dataset
+// ListMembers retrieves a list of members in a partitioned dataset, up to the
+// manager's default limit (see SetDefaultLimit).
+// With WithCache, results are served from the cache until they expire.
 func (dm *ZOSMFDatasetManager) ListMembers(datasetName string) (*MemberList, error) {
-	session := dm.session.(*profile.Session)
+	return dm.ListMembersWithOptions(datasetName, nil)
+}
 
-	// Build URL using template
-	apiURL := session.GetBaseURL() + fmt.Sprintf(DatasetByNameEndpoint, url.PathEscape(datasetName)) + MembersEndpoint
+// ListMembersWithOptions retrieves a list of members in a partitioned dataset with a
+// per-call limit. MoreRows is set when the limit left members out. With WithCache,
+// results are served from the cache unless opts.ForceRefresh is set.
+func (dm *ZOSMFDatasetManager) ListMembersWithOptions(datasetName string, opts *MemberListOptions) (*MemberList, error) {
+	limit := dm.defaultLimit
+	if opts != nil && opts.Limit != 0 {
+		limit = opts.Limit
+	}
+	if limit < 0 {
+		limit = 0
+	}
 
-	// Create request
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if dm.cache == nil {
+		return dm.listMembers(datasetName, limit)
 	}
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
+	key := memberListKey(datasetName)
+	if opts == nil || !opts.ForceRefresh {
+		if list, ok := dm.cache.getMembers(key, limit); ok {
+			return list, nil
+		}
+	}
+	list, err := dm.listMembers(datasetName, limit)
+	if err != nil {
+		return nil, err
 	}
+	dm.cache.putMembers(key, limit, list)
+	return list, nil
+}
+
+// listAllMembers lists every member of a partitioned dataset, whatever the default limit
+func (dm *ZOSMFDatasetManager) listAllMembers(datasetName string) (*MemberList, error) {
+	return dm.ListMembersWithOptions(datasetName, &MemberListOptions{Limit: -1})
+}
+
+// listMembers queries z/OSMF for up to limit members of a partitioned dataset; 0 lists them all
+func (dm *ZOSMFDatasetManager) listMembers(datasetName string, limit int) (*MemberList, error) {
+	// Ask for member statistics as well as names. Without a limit z/OSMF stops at 1000
+	headers := map[string]string{"X-IBM-Attributes": "base", "X-IBM-Max-Items": strconv.Itoa(limit)}
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+	path := fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(datasetName)) + MembersEndpoint
+	resp, err := dm.do("GET", path, nil, headers, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, contentRequestError(resp.StatusCode, body)
 	}
 
 	// Parse response
@@ -472,33 +683,16 @@ func (dm *ZOSMFDatasetManager) ListMembers(datasetName string) (*MemberList, err
 
 // GetMember retrieves information about a specific member
 func (dm *ZOSMFDatasetManager) GetMember(datasetName, memberName string) (*DatasetMember, error) {
-	session := dm.session.(*profile.Session)
-
-	// Build URL using correct z/OSMF format: /zosmf/restfiles/ds/<dataset-name>(<member-name>)
-	apiURL := session.GetBaseURL() + fmt.Sprintf("/restfiles/ds/%s(%s)", url.PathEscape(datasetName), url.PathEscape(memberName))
-
-	// Create request
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+	resp, err := dm.do("GET", contentPath(datasetName, memberName), nil, nil, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, profile.NewAPIError(resp.StatusCode, body)
 	}
 
 	// For member access, z/OSMF returns the member content as text, not JSON
@@ -513,35 +707,19 @@ func (dm *ZOSMFDatasetManager) GetMember(datasetName, memberName string) (*Datas
 
 // DeleteMember deletes a member from a partitioned dataset
 func (dm *ZOSMFDatasetManager) DeleteMember(datasetName, memberName string) error {
-	session := dm.session.(*profile.Session)
-
-	// Build URL using correct z/OSMF format: /zosmf/restfiles/ds/<dataset-name>(<member-name>)
-	apiURL := session.GetBaseURL() + fmt.Sprintf("/restfiles/ds/%s(%s)", url.PathEscape(datasetName), url.PathEscape(memberName))
-
-	// Create request
-	req, err := http.NewRequest("DELETE", apiURL, nil)
+	resp, err := dm.do("DELETE", contentPath(datasetName, memberName), nil, nil, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return contentRequestError(resp.StatusCode, body)
 	}
 
+	dm.cache.invalidateMembers(datasetName)
 	return nil
 }
 
@@ -563,14 +741,50 @@ func (dm *ZOSMFDatasetManager) Exists(name string) (bool, error) {
 	return false, nil
 }
 
+// existsDirectHeaders make the ExistsDirect HEAD request a metadata check: a migrated
+// dataset is reported rather than recalled, and at most one record is read
+var existsDirectHeaders = map[string]string{
+	"X-IBM-Migrated-Recall": "error",
+	"X-IBM-Record-Range":    "0,1",
+}
+
+// ExistsDirect checks if a dataset exists with a HEAD request for the exact name,
+// avoiding a catalog list scan. Partitioned datasets (which have no content without a
+// member name), migrated datasets and servers without HEAD support fail the request,
+// so for those it falls back to an exact-name catalog listing.
+func (dm *ZOSMFDatasetManager) ExistsDirect(name string) (bool, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" {
+		return false, fmt.Errorf("dataset name cannot be empty")
+	}
+
+	resp, err := dm.do("HEAD", fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(name)), nil, existsDirectHeaders, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	case http.StatusBadRequest, // partitioned dataset read without a member
+		http.StatusInternalServerError, // migrated dataset not recalled
+		http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		matches, err := dm.listExact(name)
+		if err != nil {
+			return false, err
+		}
+		return len(matches) > 0, nil
+	default:
+		return false, profile.NewAPIError(resp.StatusCode, nil)
+	}
+}
+
 // CopySequentialDataset copies a sequential dataset using the z/OSMF REST API
 // This function handles copying entire datasets (not members)
 func (dm *ZOSMFDatasetManager) CopySequentialDataset(sourceName, targetName string) error {
-	session := dm.session.(*profile.Session)
-
-	// Build URL to the target dataset (z/OSMF format: PUT to target with source in body)
-	apiURL := session.GetBaseURL() + fmt.Sprintf(DatasetByNameEndpoint, url.PathEscape(targetName))
-
 	// Prepare request body according to z/OSMF API specification for dataset copy
 	requestBody := map[string]interface{}{
 		"request": "copy",
@@ -579,37 +793,13 @@ func (dm *ZOSMFDatasetManager) CopySequentialDataset(sourceName, targetName stri
 		},
 	}
 
-	// Serialize request body
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
-	// Create request (PUT to target dataset, not POST to source/copy)
-	req, err := http.NewRequest("PUT", apiURL, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	// PUT to the target dataset with the source in the body
+	if err := dm.putDatasetRequest(fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(targetName)), requestBody); err != nil {
+		return err
 	}
 
+	dm.cache.invalidateDatasets()
+	dm.cache.invalidateMembers(targetName)
 	return nil
 }
 
@@ -617,11 +807,6 @@ func (dm *ZOSMFDatasetManager) CopySequentialDataset(sourceName, targetName stri
 // sourceName should be in format "DATASET.NAME" and sourceMember is the member name
 // targetName should be in format "DATASET.NAME" and targetMember is the member name
 func (dm *ZOSMFDatasetManager) CopyMember(sourceName, sourceMember, targetName, targetMember string) error {
-	session := dm.session.(*profile.Session)
-
-	// Build URL to the target member using correct z/OSMF format: /zosmf/restfiles/ds/<target-dataset>(<target-member>)
-	apiURL := session.GetBaseURL() + fmt.Sprintf("/restfiles/ds/%s(%s)", url.PathEscape(targetName), url.PathEscape(targetMember))
-
 	// Prepare request body according to z/OSMF API specification for member copy
 	requestBody := map[string]interface{}{
 		"request": "copy",
@@ -631,47 +816,17 @@ func (dm *ZOSMFDatasetManager) CopyMember(sourceName, sourceMember, targetName,
 		},
 	}
 
-	// Serialize request body
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
-	// Create request (PUT to target member)
-	req, err := http.NewRequest("PUT", apiURL, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	// PUT to the target member
+	if err := dm.putDatasetRequest(contentPath(targetName, targetMember), requestBody); err != nil {
+		return err
 	}
 
+	dm.cache.invalidateMembers(targetName)
 	return nil
 }
 
 // RenameDataset renames a dataset using the z/OSMF REST API
 func (dm *ZOSMFDatasetManager) RenameDataset(oldName, newName string) error {
-	session := dm.session.(*profile.Session)
-
-	// Build URL to the new dataset name (z/OSMF format: PUT to target with source in body)
-	apiURL := session.GetBaseURL() + fmt.Sprintf(DatasetByNameEndpoint, url.PathEscape(newName))
-
 	// Prepare request body according to z/OSMF API specification
 	requestBody := map[string]interface{}{
 		"request": "rename",
@@ -680,43 +835,36 @@ func (dm *ZOSMFDatasetManager) RenameDataset(oldName, newName string) error {
 		},
 	}
 
-	// Serialize request body
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
-	// Create request (PUT to target dataset, not PUT to source/rename)
-	req, err := http.NewRequest("PUT", apiURL, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	// PUT to the new dataset name with the old one in the body
+	if err := dm.putDatasetRequest(fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(newName)), requestBody); err != nil {
+		return err
 	}
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-	req.Header.Set("Content-Type", "application/json")
+	dm.cache.invalidateDatasets()
+	dm.cache.invalidateMembers(oldName)
+	dm.cache.invalidateMembers(newName)
+	dm.forgetDatasetKind(oldName)
+	dm.forgetDatasetKind(newName)
+	return nil
+}
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+// putDatasetRequest sends a JSON utility request, such as copy or rename, to a dataset path
+func (dm *ZOSMFDatasetManager) putDatasetRequest(path string, requestBody map[string]interface{}) error {
+	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("failed to marshal request body: %w", err)
 	}
+	return dm.doJSON("PUT", path, nil, jsonHeaders(), bytes.NewReader(jsonBody), nil)
+}
 
-	return nil
+// jsonHeaders returns the headers of a request with a JSON body
+func jsonHeaders() map[string]string {
+	return map[string]string{"Content-Type": "application/json"}
 }
 
 // CloseDatasetManager closes the dataset manager and its underlying HTTP connections
 func (dm *ZOSMFDatasetManager) CloseDatasetManager() error {
-	session := dm.session.(*profile.Session)
+	session := dm.session
 
 	// Close idle connections in the HTTP client
 	if client := session.GetHTTPClient(); client != nil {
@@ -0,0 +1,98 @@
+package datasets
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+)
+
+// ErrDatasetMigrated is returned when z/OSMF reports that a dataset has been migrated
+// by HSM. Callers can recall the dataset and retry.
+var ErrDatasetMigrated = errors.New("dataset is migrated")
+
+// MigrationStatus selects datasets in a listing by whether HSM has migrated them
+type MigrationStatus string
+
+const (
+	MigrationStatusAll          MigrationStatus = ""         // Every dataset
+	MigrationStatusOnlyActive   MigrationStatus = "active"   // Datasets that are not migrated
+	MigrationStatusOnlyMigrated MigrationStatus = "migrated" // Migrated datasets only
+)
+
+// validate rejects an unknown migration status
+func (s MigrationStatus) validate() error {
+	switch s {
+	case MigrationStatusAll, MigrationStatusOnlyActive, MigrationStatusOnlyMigrated:
+		return nil
+	}
+	return fmt.Errorf("invalid migration status %q: must be %q, %q or empty", s, MigrationStatusOnlyActive, MigrationStatusOnlyMigrated)
+}
+
+// filterMigrationStatus keeps the listed datasets with the given migration status.
+// z/OSMF cannot filter on migr, so this is applied to each listing.
+func filterMigrationStatus(list *DatasetList, status MigrationStatus) *DatasetList {
+	if status == MigrationStatusAll {
+		return list
+	}
+
+	wantMigrated := status == MigrationStatusOnlyMigrated
+	filtered := *list
+	filtered.Datasets = []Dataset{}
+	for _, ds := range list.Datasets {
+		if ds.IsMigrated() == wantMigrated {
+			filtered.Datasets = append(filtered.Datasets, ds)
+		}
+	}
+	filtered.ReturnedRows = len(filtered.Datasets)
+	return &filtered
+}
+
+// IsMigrated reports whether a dataset has been migrated, from the migr attribute of
+// its catalog entry. The listing always bypasses the cache, since HSM migrates and
+// recalls datasets independently of this client.
+func (dm *ZOSMFDatasetManager) IsMigrated(name string) (bool, error) {
+	name = strings.ToUpper(name)
+	if err := ValidateDatasetName(name); err != nil {
+		return false, err
+	}
+
+	dl, err := dm.ListDatasets(&DatasetFilter{Name: name, ForceRefresh: true})
+	if err != nil {
+		return false, err
+	}
+	for _, ds := range dl.Datasets {
+		if ds.Name == name {
+			return ds.IsMigrated(), nil
+		}
+	}
+	return false, fmt.Errorf("dataset not found: %s", name)
+}
+
+// contentRequestError builds the error for a failed request on a dataset or its content.
+// It returns a *DatasetInUseError when the response says the dataset is in use, and
+// wraps ErrDatasetMigrated when it says the dataset is migrated.
+func contentRequestError(statusCode int, body []byte) error {
+	if inUseErr := inUseError(statusCode, body); inUseErr != nil {
+		return inUseErr
+	}
+	apiErr := profile.NewAPIError(statusCode, body)
+	if isMigratedResponse(body) {
+		return fmt.Errorf("%w: %w", ErrDatasetMigrated, apiErr)
+	}
+	return apiErr
+}
+
+// migratedPattern finds MIGRATED or the MIGRAT volser as a word of its own. Characters
+// that may appear in a dataset name do not delimit it, so names such as
+// USER.MIGRATION.JCL or USER.MIGRAT do not match.
+var migratedPattern = regexp.MustCompile(`(?:^|[^A-Z0-9.$#@-])MIGRAT(?:ED)?(?:$|[^A-Z0-9.$#@-])`)
+
+// isMigratedResponse reports whether an error response refers to a migrated dataset.
+// z/OSMF passes on the allocation failure, which says the dataset is migrated or
+// names its volume as MIGRAT.
+func isMigratedResponse(body []byte) bool {
+	return migratedPattern.MatchString(strings.ToUpper(errorMessage(body)))
+}
@@ -0,0 +1,23 @@
+package datasets
+
+import "github.com/zowe/zowe-client-go-sdk/pkg/internal/request"
+
+// ManagerOption customizes a dataset manager created by New or NewDatasetManager
+type ManagerOption func(*ZOSMFDatasetManager)
+
+// WithBasePath sends requests under basePath instead of the session's base path, e.g.
+// "/ibmzosmf/api/v1" through the API Mediation Layer
+func WithBasePath(basePath string) ManagerOption {
+	return func(dm *ZOSMFDatasetManager) {
+		dm.session = request.WithBasePath(dm.session, basePath)
+	}
+}
+
+// WithRetries retries GET and HEAD requests up to n times when they fail with a
+// transport error or a 502, 503 or 504 status, waiting 250ms and then twice as long
+// before each further retry. Requests that change data are never retried.
+func WithRetries(n int) ManagerOption {
+	return func(dm *ZOSMFDatasetManager) {
+		dm.session = request.WithRetries(dm.session, n, request.DefaultRetryBackoff)
+	}
+}
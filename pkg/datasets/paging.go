@@ -0,0 +1,88 @@
+package datasets
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultDatasetPageSize is the page size ListAllDatasets uses when the filter sets no Limit
+const DefaultDatasetPageSize = 1000
+
+// ListAllDatasets lists every dataset matching the filter. Each pattern is listed a page
+// at a time: while z/OSMF reports moreRows, the listing is repeated from the last dataset
+// returned with the start parameter. filter.Limit sets the page size (default
+// DefaultDatasetPageSize, at least 2 since start is inclusive) and filter.Owner is
+// ignored. Listings are not cached. A listing whose start does not advance fails rather
+// than looping.
+func (dm *ZOSMFDatasetManager) ListAllDatasets(filter *DatasetFilter) (*DatasetList, error) {
+	base := DatasetFilter{}
+	if filter != nil {
+		base = *filter
+	}
+	if err := base.MigrationStatus.validate(); err != nil {
+		return nil, err
+	}
+	patterns, err := dm.listPatterns(&base)
+	if err != nil {
+		return nil, err
+	}
+	if len(patterns) == 0 {
+		patterns = []string{""} // volume-only listing
+	}
+
+	switch {
+	case base.Limit <= 0:
+		base.Limit = DefaultDatasetPageSize
+	case base.Limit < 2:
+		base.Limit = 2
+	}
+
+	result := &DatasetList{Datasets: []Dataset{}}
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		if err := dm.listAllPages(pattern, &base, result, seen); err != nil {
+			return nil, err
+		}
+	}
+	result.ReturnedRows = len(result.Datasets)
+	return filterMigrationStatus(result, base.MigrationStatus), nil
+}
+
+// listAllPages appends every page of one pattern's listing to result
+func (dm *ZOSMFDatasetManager) listAllPages(pattern string, filter *DatasetFilter, result *DatasetList, seen map[string]bool) error {
+	start := ""
+	for {
+		page, err := dm.listDatasetsPage(dm.session, pattern, start, filter)
+		if err != nil {
+			return err
+		}
+		result.JSONVersion = page.JSONVersion
+
+		for _, ds := range page.Datasets {
+			// The same name on different volumes is a different dataset; the
+			// first entry of a continued page repeats the last of the previous one
+			key := ds.Name + " " + ds.Volume
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			result.Datasets = append(result.Datasets, ds)
+		}
+		if !page.MoreRows {
+			return nil
+		}
+
+		next := ""
+		if len(page.Datasets) > 0 {
+			next = page.Datasets[len(page.Datasets)-1].Name
+		}
+		if next == "" || strings.EqualFold(next, start) {
+			what := "volume " + filter.Volume
+			if pattern != "" {
+				what = pattern
+			}
+			return fmt.Errorf("listing %s did not advance past %q; %d datasets listed so far", what, start, len(result.Datasets))
+		}
+		start = next
+	}
+}
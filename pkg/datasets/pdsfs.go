@@ -0,0 +1,328 @@
+package datasets
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultPDSFSListTTL is how long NewPDSFS reuses a member list by default
+const defaultPDSFSListTTL = 30 * time.Second
+
+// PDSFSOption customizes a file system created by NewPDSFS
+type PDSFSOption func(*pdsFS)
+
+// WithMemberExtension adds ext, such as ".jcl", to the file name of every member
+func WithMemberExtension(ext string) PDSFSOption {
+	return func(fsys *pdsFS) {
+		fsys.ext = ext
+	}
+}
+
+// WithListTTL sets how long the member list is reused before it is fetched again.
+// Zero or less fetches it for every call that needs it.
+func WithListTTL(ttl time.Duration) PDSFSOption {
+	return func(fsys *pdsFS) {
+		fsys.ttl = ttl
+	}
+}
+
+// pdsFS is a read-only fs.FS over the members of a partitioned dataset
+type pdsFS struct {
+	dm          *ZOSMFDatasetManager
+	datasetName string
+	ext         string
+	ttl         time.Duration
+
+	mu      sync.Mutex
+	entries []*pdsMemberInfo // sorted by file name
+	byName  map[string]*pdsMemberInfo
+	expires time.Time
+}
+
+// NewPDSFS returns a read-only file system over a partitioned dataset. Each member is a
+// file in the root directory, named after the member in lower case plus the extension
+// set with WithMemberExtension. The member list is fetched with ListMembers and reused
+// for 30 seconds unless WithListTTL says otherwise; a file's content is downloaded when
+// it is first read. The file system also implements fs.StatFS and fs.ReadDirFS, with
+// sizes and modification times taken from the member statistics when there are any.
+func NewPDSFS(dm *ZOSMFDatasetManager, datasetName string, opts ...PDSFSOption) fs.FS {
+	fsys := &pdsFS{
+		dm:          dm,
+		datasetName: strings.ToUpper(datasetName),
+		ttl:         defaultPDSFSListTTL,
+	}
+	for _, opt := range opts {
+		opt(fsys)
+	}
+	return fsys
+}
+
+// Open opens the root directory "." or a member file
+func (fsys *pdsFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		entries, err := fsys.members()
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &pdsDir{entries: entries}, nil
+	}
+
+	info, err := fsys.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &pdsFile{fsys: fsys, info: info}, nil
+}
+
+// Stat describes the root directory or a member file without downloading anything
+func (fsys *pdsFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return pdsRootInfo{}, nil
+	}
+	info, err := fsys.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return info, nil
+}
+
+// ReadDir lists the members of the dataset; "." is the only directory
+func (fsys *pdsFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if name != "." {
+		if _, err := fsys.lookup(name); err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+		}
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	entries, err := fsys.members()
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	list := make([]fs.DirEntry, len(entries))
+	for i, entry := range entries {
+		list[i] = entry
+	}
+	return list, nil
+}
+
+// members returns the member files, fetching the member list when the cached one
+// has expired
+func (fsys *pdsFS) members() ([]*pdsMemberInfo, error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	if fsys.byName != nil && time.Now().Before(fsys.expires) {
+		return fsys.entries, nil
+	}
+
+	list, err := fsys.dm.listAllMembers(fsys.datasetName)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*pdsMemberInfo, 0, len(list.Members))
+	byName := make(map[string]*pdsMemberInfo, len(list.Members))
+	for _, member := range list.Members {
+		info := newPDSMemberInfo(strings.ToLower(member.Name)+fsys.ext, member)
+		entries = append(entries, info)
+		byName[info.name] = info
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	fsys.entries = entries
+	fsys.byName = byName
+	fsys.expires = time.Now().Add(fsys.ttl)
+	return entries, nil
+}
+
+// lookup finds the member file with the given name
+func (fsys *pdsFS) lookup(name string) (*pdsMemberInfo, error) {
+	if _, err := fsys.members(); err != nil {
+		return nil, err
+	}
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	info, ok := fsys.byName[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return info, nil
+}
+
+// pdsMemberInfo describes a member file; it is both its fs.FileInfo and fs.DirEntry
+type pdsMemberInfo struct {
+	name    string
+	member  DatasetMember
+	size    int64
+	modTime time.Time
+}
+
+func newPDSMemberInfo(name string, member DatasetMember) *pdsMemberInfo {
+	info := &pdsMemberInfo{name: name, member: member}
+	if member.Size != "" {
+		if size, err := strconv.ParseInt(member.Size, 16, 64); err == nil {
+			info.size = size
+		}
+	}
+	if member.Changed != "" {
+		stamp := member.Changed
+		layout := "2006/01/02"
+		if member.ChangedTime != "" {
+			stamp += " " + member.ChangedTime
+			layout += " 15:04"
+			if member.ChangedSeconds != "" {
+				stamp += ":" + member.ChangedSeconds
+				layout += ":05"
+			}
+		}
+		if modTime, err := time.Parse(layout, stamp); err == nil {
+			info.modTime = modTime
+		}
+	}
+	return info
+}
+
+// Name returns the file name of the member
+func (i *pdsMemberInfo) Name() string { return i.name }
+
+// Size returns the size of a load module; other members report 0, as their
+// statistics only count records
+func (i *pdsMemberInfo) Size() int64 { return i.size }
+
+// Mode reports a read-only regular file
+func (i *pdsMemberInfo) Mode() fs.FileMode { return 0444 }
+
+// ModTime returns when the member was last changed according to its statistics
+func (i *pdsMemberInfo) ModTime() time.Time { return i.modTime }
+
+// IsDir reports false; members are always files
+func (i *pdsMemberInfo) IsDir() bool { return false }
+
+// Sys returns a copy of the member's *DatasetMember
+func (i *pdsMemberInfo) Sys() interface{} {
+	member := i.member
+	return &member
+}
+
+// Type returns the type bits of a regular file
+func (i *pdsMemberInfo) Type() fs.FileMode { return 0 }
+
+// Info returns the member's file info
+func (i *pdsMemberInfo) Info() (fs.FileInfo, error) { return i, nil }
+
+// pdsRootInfo describes the root directory of a PDS file system
+type pdsRootInfo struct{}
+
+func (pdsRootInfo) Name() string       { return "." }
+func (pdsRootInfo) Size() int64        { return 0 }
+func (pdsRootInfo) Mode() fs.FileMode  { return fs.ModeDir | 0555 }
+func (pdsRootInfo) ModTime() time.Time { return time.Time{} }
+func (pdsRootInfo) IsDir() bool        { return true }
+func (pdsRootInfo) Sys() interface{}   { return nil }
+
+// pdsFile is an open member whose content is downloaded on the first read
+type pdsFile struct {
+	fsys    *pdsFS
+	info    *pdsMemberInfo
+	content *strings.Reader
+	closed  bool
+}
+
+// Stat returns the member's file info
+func (f *pdsFile) Stat() (fs.FileInfo, error) {
+	if f.closed {
+		return nil, &fs.PathError{Op: "stat", Path: f.info.name, Err: fs.ErrClosed}
+	}
+	return f.info, nil
+}
+
+// Read reads the member's content, downloading it first if needed
+func (f *pdsFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.info.name, Err: fs.ErrClosed}
+	}
+	if f.content == nil {
+		content, err := f.fsys.dm.DownloadTextFromMember(f.fsys.datasetName, f.info.member.Name)
+		if err != nil {
+			return 0, &fs.PathError{Op: "read", Path: f.info.name, Err: err}
+		}
+		f.content = strings.NewReader(content)
+	}
+	return f.content.Read(p)
+}
+
+// Close releases the file
+func (f *pdsFile) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.info.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	f.content = nil
+	return nil
+}
+
+// pdsDir is the open root directory
+type pdsDir struct {
+	entries []*pdsMemberInfo
+	offset  int
+	closed  bool
+}
+
+// Stat returns the root directory's file info
+func (d *pdsDir) Stat() (fs.FileInfo, error) {
+	if d.closed {
+		return nil, &fs.PathError{Op: "stat", Path: ".", Err: fs.ErrClosed}
+	}
+	return pdsRootInfo{}, nil
+}
+
+// Read fails; directories have no content
+func (d *pdsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: ".", Err: errors.New("is a directory")}
+}
+
+// ReadDir returns the next n members, or all remaining ones when n <= 0
+func (d *pdsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: ".", Err: fs.ErrClosed}
+	}
+	remaining := d.entries[d.offset:]
+	if n > 0 {
+		if len(remaining) == 0 {
+			return nil, io.EOF
+		}
+		if n < len(remaining) {
+			remaining = remaining[:n]
+		}
+	}
+	d.offset += len(remaining)
+
+	list := make([]fs.DirEntry, len(remaining))
+	for i, entry := range remaining {
+		list[i] = entry
+	}
+	return list, nil
+}
+
+// Close releases the directory
+func (d *pdsDir) Close() error {
+	if d.closed {
+		return &fs.PathError{Op: "close", Path: ".", Err: fs.ErrClosed}
+	}
+	d.closed = true
+	return nil
+}
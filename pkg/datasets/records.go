@@ -0,0 +1,126 @@
+package datasets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrRecordRangeUnsupported is returned when the z/OSMF server rejects X-IBM-Record-Range requests
+var ErrRecordRangeUnsupported = errors.New("record range requests are not supported by this z/OSMF server")
+
+// ErrAppendUnsupported is returned by AppendContent when the server cannot hold an ENQ
+// across requests, which appending safely depends on
+var ErrAppendUnsupported = errors.New("append is not supported by this z/OSMF server")
+
+// DownloadRecords downloads count records of a dataset starting at the zero-based startRecord
+func (dm *ZOSMFDatasetManager) DownloadRecords(datasetName string, startRecord, count int) (string, error) {
+	if startRecord < 0 {
+		return "", fmt.Errorf("start record must not be negative")
+	}
+	if count <= 0 {
+		return "", fmt.Errorf("record count must be positive")
+	}
+
+	headers := map[string]string{"X-IBM-Record-Range": fmt.Sprintf("%d,%d", startRecord, count)}
+	setTextConversionHeaders(headers, dm.sourceEncoding(""), "")
+
+	resp, err := dm.do("GET", fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(datasetName)), nil, headers, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if isRecordRangeRejection(resp.StatusCode, body) {
+			return "", fmt.Errorf("%w: %s", ErrRecordRangeUnsupported, string(body))
+		}
+		return "", contentRequestError(resp.StatusCode, body)
+	}
+
+	return string(body), nil
+}
+
+// AppendContent appends records to the end of a sequential dataset.
+// z/OSMF has no append mode for dataset writes, so the dataset is read and rewritten
+// while holding an exclusive ENQ, which keeps other writers out between the two requests.
+// The ENQ is released on every path; a failed release is ignored, as z/OSMF also
+// releases it when the session times out. Servers that do not return an ENQ session
+// reference cannot hold the dataset between the requests, so AppendContent fails with
+// ErrAppendUnsupported rather than risk losing another writer's records.
+func (dm *ZOSMFDatasetManager) AppendContent(datasetName string, content string) error {
+	if content == "" {
+		return nil
+	}
+
+	path := fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(datasetName))
+
+	// Read the current content and obtain the ENQ
+	headers := map[string]string{"X-IBM-Obtain-ENQ": "EXCL"}
+	setTextConversionHeaders(headers, dm.sourceEncoding(""), "")
+	resp, err := dm.do("GET", path, nil, headers, nil)
+	if err != nil {
+		return err
+	}
+	sessionRef := resp.Header.Get("X-IBM-Session-Ref")
+	existing, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		dm.releaseENQ(context.Background(), path, sessionRef)
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		dm.releaseENQ(context.Background(), path, sessionRef)
+		return contentRequestError(resp.StatusCode, existing)
+	}
+	if sessionRef == "" {
+		return fmt.Errorf("%w: no ENQ session reference was returned for %s", ErrAppendUnsupported, datasetName)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(existing)
+	if buf.Len() > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(content)
+
+	// Write it back and release the ENQ
+	headers = map[string]string{
+		"Content-Type":      "text/plain",
+		"X-IBM-Session-Ref": sessionRef,
+		"X-IBM-Release-ENQ": "true",
+	}
+	setTextConversionHeaders(headers, dm.sourceEncoding(""), "")
+	resp, err = dm.do("PUT", path, nil, headers, &buf)
+	if err != nil {
+		dm.releaseENQ(context.Background(), path, sessionRef)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		dm.releaseENQ(context.Background(), path, sessionRef)
+		return contentRequestError(resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// isRecordRangeRejection reports whether an error response means the server does not accept X-IBM-Record-Range
+func isRecordRangeRejection(statusCode int, body []byte) bool {
+	if statusCode != http.StatusBadRequest {
+		return false
+	}
+	text := strings.ToLower(string(body))
+	return strings.Contains(text, "record-range") || strings.Contains(text, "record range")
+}
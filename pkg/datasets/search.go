@@ -0,0 +1,128 @@
+package datasets
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// defaultSearchConcurrency is the number of members downloaded in parallel
+const defaultSearchConcurrency = 4
+
+// SearchMembers searches the content of the members of a partitioned dataset.
+// The search runs client side: every selected member is downloaded in full,
+// so narrow large libraries with opts.MemberPattern where possible.
+// Matches are returned in member list order, then line order.
+func (dm *ZOSMFDatasetManager) SearchMembers(datasetName, pattern string, opts *SearchOptions) ([]SearchMatch, error) {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("search pattern cannot be empty")
+	}
+
+	// Build the matcher before any network calls so bad patterns fail fast
+	match, err := newLineMatcher(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	memberList, err := dm.listAllMembers(datasetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	var members []string
+	for _, member := range memberList.Members {
+		if opts.MemberPattern != "" && !matchMemberName(opts.MemberPattern, member.Name) {
+			continue
+		}
+		members = append(members, member.Name)
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultSearchConcurrency
+	}
+
+	results := make([][]SearchMatch, len(members))
+	errs := make([]error, len(members))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, member := range members {
+		wg.Add(1)
+		go func(i int, member string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			content, err := dm.DownloadTextFromMember(datasetName, member)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to download member %s: %w", member, err)
+				return
+			}
+			results[i] = searchLines(member, content, match, opts.MaxMatchesPerMember)
+		}(i, member)
+	}
+	wg.Wait()
+
+	var matches []SearchMatch
+	for i := range members {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		matches = append(matches, results[i]...)
+	}
+
+	return matches, nil
+}
+
+// newLineMatcher builds the line predicate for a search pattern
+func newLineMatcher(pattern string, opts *SearchOptions) (func(string) bool, error) {
+	if opts.Regexp {
+		if opts.CaseInsensitive {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search pattern: %w", err)
+		}
+		return re.MatchString, nil
+	}
+
+	if opts.CaseInsensitive {
+		lower := strings.ToLower(pattern)
+		return func(line string) bool {
+			return strings.Contains(strings.ToLower(line), lower)
+		}, nil
+	}
+	return func(line string) bool {
+		return strings.Contains(line, pattern)
+	}, nil
+}
+
+// searchLines returns the matching lines of a member, up to max matches when max > 0
+func searchLines(member, content string, match func(string) bool, max int) []SearchMatch {
+	var matches []SearchMatch
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if !match(line) {
+			continue
+		}
+		matches = append(matches, SearchMatch{Member: member, Line: i + 1, Text: line})
+		if max > 0 && len(matches) >= max {
+			break
+		}
+	}
+	return matches
+}
+
+// matchMemberName matches a member name against a z/OS style pattern (* and %)
+func matchMemberName(pattern, name string) bool {
+	pattern = strings.ReplaceAll(strings.ToUpper(pattern), "%", "?")
+	matched, err := path.Match(pattern, strings.ToUpper(name))
+	return err == nil && matched
+}
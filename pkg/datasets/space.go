@@ -0,0 +1,88 @@
+package datasets
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/jobs"
+)
+
+// CompressTimeout is how long CompressPDS waits for the IEBCOPY job to finish
+const CompressTimeout = 10 * time.Minute
+
+// JobRunner submits JCL, waits for the job to finish and returns its return code.
+// *jobs.ZOSMFJobManager implements it.
+type JobRunner interface {
+	RunJCL(jcl string, timeout time.Duration) (string, error)
+}
+
+// SetJobRunner sets the runner used for operations that need a batch job, such as
+// CompressPDS. By default a job manager on the same session is used.
+func (dm *ZOSMFDatasetManager) SetJobRunner(runner JobRunner) {
+	dm.jobRunner = runner
+}
+
+// ReleaseSpace releases the unused space allocated to a dataset
+func (dm *ZOSMFDatasetManager) ReleaseSpace(datasetName string) error {
+	if err := ValidateDatasetName(datasetName); err != nil {
+		return err
+	}
+
+	return dm.putDatasetRequest(fmt.Sprintf(DatasetByNameEndpoint, escapeDatasetName(datasetName)), map[string]interface{}{"request": "release"})
+}
+
+// CompressPDS compresses a PDS in place by running IEBCOPY under the given job card,
+// and returns the job's return code (e.g. "CC 0000"). PDSEs do not need compressing
+// and are rejected. The dataset is allocated DISP=OLD, so the job waits for other users.
+func (dm *ZOSMFDatasetManager) CompressPDS(datasetName string, jobCard string) (string, error) {
+	jcl, err := buildCompressJCL(datasetName, jobCard)
+	if err != nil {
+		return "", err
+	}
+
+	dataset, err := dm.GetDataset(datasetName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get dataset information: %w", err)
+	}
+	switch dataset.DatasetKind() {
+	case DatasetTypePartitioned:
+	case DatasetTypePDSE:
+		return "", fmt.Errorf("dataset %s is a PDSE, which does not need compressing", datasetName)
+	default:
+		return "", fmt.Errorf("dataset %s is not a partitioned dataset (type: %s)", datasetName, dataset.Type)
+	}
+
+	runner := dm.jobRunner
+	if runner == nil {
+		runner = jobs.New(dm.session)
+	}
+
+	retCode, err := runner.RunJCL(jcl, CompressTimeout)
+	if err != nil {
+		return "", fmt.Errorf("compress job for %s failed: %w", datasetName, err)
+	}
+	return retCode, nil
+}
+
+// buildCompressJCL generates an IEBCOPY job that compresses a PDS in place
+func buildCompressJCL(datasetName, jobCard string) (string, error) {
+	if err := ValidateDatasetName(datasetName); err != nil {
+		return "", err
+	}
+	jobCard = strings.TrimRight(jobCard, "\r\n")
+	if !strings.HasPrefix(jobCard, "//") || !strings.Contains(strings.SplitN(jobCard, "\n", 2)[0], " JOB") {
+		return "", fmt.Errorf("job card must be a JCL JOB statement")
+	}
+
+	lines := []string{
+		jobCard,
+		"//COMPRESS EXEC PGM=IEBCOPY",
+		"//SYSPRINT DD SYSOUT=*",
+		"//PDS      DD DISP=OLD,DSN=" + datasetName,
+		"//SYSIN    DD *",
+		"  COPY OUTDD=PDS,INDD=PDS",
+		"/*",
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
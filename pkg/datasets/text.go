@@ -0,0 +1,84 @@
+package datasets
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Classic source members carry sequence numbers in columns 73-80, counted in characters
+// since downloaded text is UTF-8
+const (
+	sequenceColumn    = 72
+	sequenceWidth     = 8
+	sequenceIncrement = 100
+)
+
+// validateTextOptions rejects unknown download text options
+func validateTextOptions(request *DownloadRequest) error {
+	switch request.NormalizeLineEndings {
+	case "", LineEndingLF, LineEndingCRLF:
+		return nil
+	default:
+		return fmt.Errorf("invalid line ending %q (expected LF or CRLF)", request.NormalizeLineEndings)
+	}
+}
+
+// transformDownloadedText applies the client-side text options of a download request
+func transformDownloadedText(content string, request *DownloadRequest) (string, error) {
+	if !request.StripSequenceNumbers && !request.TrimTrailingSpaces && !request.InsertSequenceNumbers && request.NormalizeLineEndings == "" {
+		return content, nil
+	}
+
+	// Work on records, remembering the original terminator and final newline
+	lineEnding := "\n"
+	if strings.Contains(content, "\r\n") {
+		lineEnding = "\r\n"
+	}
+	switch request.NormalizeLineEndings {
+	case LineEndingLF:
+		lineEnding = "\n"
+	case LineEndingCRLF:
+		lineEnding = "\r\n"
+	}
+	hasFinalNewline := strings.HasSuffix(content, "\n")
+	content = strings.TrimSuffix(strings.TrimSuffix(content, "\n"), "\r")
+	if content == "" {
+		return content, nil
+	}
+
+	records := strings.Split(content, "\n")
+	for i, record := range records {
+		record = strings.TrimSuffix(record, "\r")
+		if request.StripSequenceNumbers {
+			record = leadingColumns(record, sequenceColumn)
+		}
+		if request.TrimTrailingSpaces {
+			record = strings.TrimRight(record, " ")
+		}
+		if request.InsertSequenceNumbers {
+			if utf8.RuneCountInString(record) > sequenceColumn {
+				return "", fmt.Errorf("record %d is longer than %d columns, cannot insert sequence numbers", i+1, sequenceColumn)
+			}
+			record = fmt.Sprintf("%-*s%0*d", sequenceColumn, record, sequenceWidth, (i+1)*sequenceIncrement)
+		}
+		records[i] = record
+	}
+
+	result := strings.Join(records, lineEnding)
+	if hasFinalNewline {
+		result += lineEnding
+	}
+	return result, nil
+}
+
+// leadingColumns returns the first n characters of record
+func leadingColumns(record string, n int) string {
+	for i := range record {
+		if n == 0 {
+			return record[:i]
+		}
+		n--
+	}
+	return record
+}
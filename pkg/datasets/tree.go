@@ -0,0 +1,100 @@
+package datasets
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// defaultTreeConcurrency is the number of member lists fetched in parallel
+const defaultTreeConcurrency = 4
+
+// ListDatasetTree lists the datasets matching hlqPattern and, for each PDS or PDSE,
+// its members. Member lists are fetched concurrently; migrated datasets are skipped
+// rather than recalled. A failure listing one dataset's members is recorded on its
+// node and does not fail the tree. nil opts includes members with default settings.
+func (dm *ZOSMFDatasetManager) ListDatasetTree(hlqPattern string, opts *TreeOptions) (*DatasetTree, error) {
+	if hlqPattern == "" {
+		return nil, fmt.Errorf("dataset pattern cannot be empty")
+	}
+	if opts == nil {
+		opts = &TreeOptions{IncludeMembers: true}
+	}
+	if opts.MemberLimit < 0 {
+		return nil, fmt.Errorf("member limit cannot be negative")
+	}
+	// The limit is sent to z/OSMF, which reports moreRows when members were left out
+	memberOpts := &MemberListOptions{Limit: opts.MemberLimit}
+	if memberOpts.Limit == 0 {
+		memberOpts.Limit = -1
+	}
+
+	list, err := dm.ListDatasets(&DatasetFilter{Name: hlqPattern})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list datasets: %w", err)
+	}
+
+	tree := &DatasetTree{Pattern: hlqPattern, Nodes: make([]DatasetNode, len(list.Datasets))}
+	for i, dataset := range list.Datasets {
+		tree.Nodes[i].Dataset = dataset
+	}
+	if !opts.IncludeMembers {
+		return tree, nil
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultTreeConcurrency
+	}
+	if concurrency > MaxDownloadConcurrency {
+		concurrency = MaxDownloadConcurrency
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := range tree.Nodes {
+		node := &tree.Nodes[i]
+		if !node.Dataset.IsPartitioned() {
+			continue
+		}
+		if node.Dataset.IsMigrated() {
+			node.Skipped = true
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			members, err := dm.ListMembersWithOptions(node.Dataset.Name, memberOpts)
+			if err != nil {
+				node.Err = fmt.Errorf("failed to list members of %s: %w", node.Dataset.Name, err)
+				return
+			}
+			node.Members = members.Members
+			node.MembersTruncated = members.MoreRows
+			if opts.MemberLimit > 0 && len(node.Members) > opts.MemberLimit {
+				node.Members = node.Members[:opts.MemberLimit]
+				node.MembersTruncated = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	return tree, nil
+}
+
+// MarshalJSON writes Err as its message under "error"
+func (n DatasetNode) MarshalJSON() ([]byte, error) {
+	type node DatasetNode
+	var message string
+	if n.Err != nil {
+		message = n.Err.Error()
+	}
+	return json.Marshal(struct {
+		node
+		Error string `json:"error,omitempty"`
+	}{node(n), message})
+}
@@ -1,5 +1,12 @@
 package datasets
 
+import (
+	"strings"
+	"sync"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+)
+
 // DatasetType represents the type of dataset
 type DatasetType string
 
@@ -19,6 +26,7 @@ const (
 	SpaceUnitKB       SpaceUnit = "KB"
 	SpaceUnitMB       SpaceUnit = "MB"
 	SpaceUnitGB       SpaceUnit = "GB"
+	SpaceUnitBlocks   SpaceUnit = "BLK" // Blocks of BlockSize bytes
 )
 
 // RecordFormat represents the record format
@@ -30,6 +38,14 @@ const (
 	RecordFormatUndefined RecordFormat = "U"
 )
 
+// LineEnding selects the line terminator of downloaded text
+type LineEnding string
+
+const (
+	LineEndingLF   LineEnding = "LF"
+	LineEndingCRLF LineEnding = "CRLF"
+)
+
 // RecordLength represents the record length
 type RecordLength int
 
@@ -72,6 +88,89 @@ type Dataset struct {
 	SpaceUnit    string `json:"spacu,omitempty"`  // Space unit
 	Used         string `json:"used,omitempty"`   // Used percentage
 	VolumeList   string `json:"vols,omitempty"`   // Volume list
+	Alias        string `json:"alias,omitempty"`  // Alias the dataset was looked up by, set by GetDataset
+}
+
+// DatasetKind normalizes the dataset organization reported by z/OSMF into a DatasetType.
+// PO-E, and PO with a LIBRARY or PDSE dataset type, are PDSEs; VS is VSAM. It returns ""
+// when the organization is missing or not recognized.
+func (d *Dataset) DatasetKind() DatasetType {
+	switch dsorg := strings.ToUpper(strings.TrimSpace(d.Type)); {
+	case dsorg == "PS" || strings.HasPrefix(dsorg, "PS-"):
+		return DatasetTypeSequential
+	case dsorg == "PO-E" || dsorg == "PDSE":
+		return DatasetTypePDSE
+	case dsorg == "PO":
+		if strings.EqualFold(d.DatasetType, "LIBRARY") || strings.EqualFold(d.DatasetType, "PDSE") {
+			return DatasetTypePDSE
+		}
+		return DatasetTypePartitioned
+	case dsorg == "VS" || dsorg == "VSAM":
+		return DatasetTypeVSAM
+	default:
+		return ""
+	}
+}
+
+// IsPartitioned reports whether the dataset is a PDS or PDSE
+func (d *Dataset) IsPartitioned() bool {
+	kind := d.DatasetKind()
+	return kind == DatasetTypePartitioned || kind == DatasetTypePDSE
+}
+
+// IsSequential reports whether the dataset is a sequential dataset
+func (d *Dataset) IsSequential() bool {
+	return d.DatasetKind() == DatasetTypeSequential
+}
+
+// IsVSAM reports whether the dataset is a VSAM cluster or component
+func (d *Dataset) IsVSAM() bool {
+	return d.DatasetKind() == DatasetTypeVSAM
+}
+
+// IsMigrated reports whether the dataset has been migrated by HSM and needs a recall
+func (d *Dataset) IsMigrated() bool {
+	return strings.EqualFold(strings.TrimSpace(d.Migrated), "YES") || strings.EqualFold(strings.TrimSpace(d.Volume), "MIGRAT")
+}
+
+// UsedPercent returns the percentage of the allocated space that is used (used)
+func (d *Dataset) UsedPercent() (int, error) {
+	return attributeInt("used", strings.TrimSuffix(strings.TrimSpace(d.Used), "%"))
+}
+
+// SizeTracks returns the allocated size in tracks (sizex)
+func (d *Dataset) SizeTracks() (int, error) {
+	return attributeInt("sizex", d.SizeX)
+}
+
+// ExtentCount returns the number of extents (extx)
+func (d *Dataset) ExtentCount() (int, error) {
+	return attributeInt("extx", d.Extents)
+}
+
+// BlockSizeBytes returns the block size (blksz)
+func (d *Dataset) BlockSizeBytes() (int, error) {
+	return attributeInt("blksz", d.BlockSize)
+}
+
+// RecordLengthBytes returns the logical record length (lrecl)
+func (d *Dataset) RecordLengthBytes() (int, error) {
+	return attributeInt("lrecl", d.RecordLength)
+}
+
+// DatasetUsage holds parsed space and attribute values for a dataset.
+// Values z/OSMF reports as "?" or leaves empty (e.g. without READ access
+// to the catalog entry) are left at zero. The list API does not return the
+// primary and secondary quantities, only the unit they were allocated in.
+type DatasetUsage struct {
+	Name               string `json:"name"`
+	SpaceUnit          string `json:"spaceUnit,omitempty"`          // Allocation unit (CYLINDERS, TRACKS, BLOCKS, ...)
+	AllocatedTracks    int    `json:"allocatedTracks"`              // Allocated size in tracks
+	AllocatedCylinders int    `json:"allocatedCylinders"`           // Allocated tracks on 3390 geometry (15 per cylinder), rounded up
+	UsedPercent        int    `json:"usedPercent"`                  // Percentage of allocated space used
+	Extents            int    `json:"extents"`                      // Number of extents
+	BlockSize          int    `json:"blockSize"`                    // Block size
+	RecordLength       int    `json:"recordLength"`                 // Logical record length
 }
 
 // Space represents space allocation parameters
@@ -80,11 +179,33 @@ type Space struct {
 	Secondary int       `json:"secondary"`
 	Unit      SpaceUnit `json:"unit"`
 	Directory int       `json:"directory,omitempty"` // For PDS
+	// AverageBlock is the average block length in bytes, required for KB, MB and GB
+	// units; BLK allocations fall back to the request's BlockSize
+	AverageBlock int `json:"averageBlock,omitempty"`
 }
 
-// DatasetMember represents a member in a partitioned dataset
+// DatasetMember represents a member in a partitioned dataset. The statistics are
+// filled in from the member listing when the member has them: ISPF statistics for
+// source members and the size for load modules.
 type DatasetMember struct {
 	Name string `json:"member"` // Member name
+	// ISPF statistics
+	Version         int    `json:"vers,omitempty"`
+	Modification    int    `json:"mod,omitempty"`
+	Created         string `json:"c4date,omitempty"` // yyyy/mm/dd
+	Changed         string `json:"m4date,omitempty"` // yyyy/mm/dd
+	ChangedTime     string `json:"mtime,omitempty"`  // hh:mm
+	ChangedSeconds  string `json:"msec,omitempty"`   // ss
+	CurrentRecords  int    `json:"cnorc,omitempty"`
+	InitialRecords  int    `json:"inorc,omitempty"`
+	ModifiedRecords int    `json:"mnorc,omitempty"`
+	User            string `json:"user,omitempty"` // User who last changed the member
+	// Size is the hexadecimal size in bytes of a load module
+	Size string `json:"size,omitempty"`
+	// Alias is set for an alias entry, and AliasOf names the member it points to
+	// when z/OSMF reports it
+	Alias   bool   `json:"alias,omitempty"`
+	AliasOf string `json:"aliasof,omitempty"`
 }
 
 // DatasetList represents a list of datasets
@@ -99,6 +220,7 @@ type DatasetList struct {
 type MemberList struct {
 	Members      []DatasetMember `json:"items"`           // Member array
 	ReturnedRows int             `json:"returnedRows"`    // Rows returned
+	MoreRows     bool            `json:"moreRows"`        // More members than the limit
 	JSONVersion  int             `json:"JSONversion"`     // API version
 }
 
@@ -107,11 +229,16 @@ type CreateDatasetRequest struct {
 	Name         string      `json:"name"`
 	Type         DatasetType `json:"type"`
 	Volume       string      `json:"volume,omitempty"`
+	Unit         string      `json:"unit,omitempty"` // Device type or esoteric group, e.g. SYSDA or 3390
 	Space        Space       `json:"space,omitempty"`
 	RecordFormat RecordFormat `json:"recordFormat,omitempty"`
 	RecordLength RecordLength `json:"recordLength,omitempty"`
 	BlockSize    BlockSize   `json:"blockSize,omitempty"`
 	Directory    int         `json:"directory,omitempty"`
+	// SMS classes, for sites where allocation goes through SMS rather than volume or unit
+	DataClass       string `json:"dataClass,omitempty"`
+	ManagementClass string `json:"managementClass,omitempty"`
+	StorageClass    string `json:"storageClass,omitempty"`
 }
 
 // UploadRequest represents a request to upload content
@@ -119,31 +246,169 @@ type UploadRequest struct {
 	DatasetName string `json:"datasetName"`
 	MemberName  string `json:"memberName,omitempty"` // For PDS members
 	Content     string `json:"content"`
-	Encoding    string `json:"encoding,omitempty"`
+	Encoding    string `json:"encoding,omitempty"` // Older name for TargetEncoding
 	Replace     bool   `json:"replace,omitempty"`
+	// SourceEncoding is the host code page the dataset is stored in, e.g. IBM-037.
+	// Defaults to the profile's encoding, then to the z/OSMF default of IBM-1047.
+	SourceEncoding string `json:"sourceEncoding,omitempty"`
+	// TargetEncoding is the character set of Content, e.g. UTF-8
+	TargetEncoding string `json:"targetEncoding,omitempty"`
+	// CodePage converts Content to this EBCDIC code page on the client, e.g. IBM-037,
+	// and writes each line as a record with no conversion by z/OSMF. It cannot be
+	// combined with SourceEncoding or TargetEncoding.
+	CodePage string `json:"codePage,omitempty"`
+	Progress    profile.ProgressFunc `json:"-"` // Optional, called as content is sent
+	// ETag, when set, is sent as If-Match so the upload fails with ErrETagMismatch
+	// if the content changed since it was read
+	ETag string `json:"etag,omitempty"`
+	// Verify reads the content back after uploading and fails with an
+	// *UploadVerificationError if it differs; VerifyOptions defaults to DefaultVerifyOptions
+	Verify        bool           `json:"verify,omitempty"`
+	VerifyOptions *VerifyOptions `json:"-"`
+	// Force skips the check that a dataset written without MemberName is not a PDS
+	// or PDSE, which would overwrite its directory
+	Force bool `json:"force,omitempty"`
+}
+
+// TextUploadOptions controls UploadTextWithOptions and UploadTextToMemberWithOptions
+type TextUploadOptions struct {
+	// Verify reads the content back after uploading and fails with an
+	// *UploadVerificationError if it differs; VerifyOptions defaults to DefaultVerifyOptions
+	Verify        bool           `json:"verify,omitempty"`
+	VerifyOptions *VerifyOptions `json:"-"`
+}
+
+// apply sets the options on an upload request; nil options change nothing
+func (o *TextUploadOptions) apply(request *UploadRequest) {
+	if o == nil {
+		return
+	}
+	request.Verify = o.Verify
+	request.VerifyOptions = o.VerifyOptions
 }
 
 // DownloadRequest represents a request to download content
 type DownloadRequest struct {
 	DatasetName string `json:"datasetName"`
 	MemberName  string `json:"memberName,omitempty"` // For PDS members
-	Encoding    string `json:"encoding,omitempty"`
+	Encoding    string `json:"encoding,omitempty"` // Older name for TargetEncoding
+	Progress    profile.ProgressFunc `json:"-"` // Optional, called as content is received
+	// SourceEncoding is the host code page the dataset is stored in, e.g. IBM-037.
+	// Defaults to the profile's encoding, then to the z/OSMF default of IBM-1047.
+	SourceEncoding string `json:"sourceEncoding,omitempty"`
+	// TargetEncoding is the character set to return the content in, e.g. UTF-8
+	TargetEncoding string `json:"targetEncoding,omitempty"`
+	// CodePage reads the records with no conversion by z/OSMF and decodes them on the
+	// client from this EBCDIC code page, e.g. IBM-037. It cannot be combined with
+	// SourceEncoding or TargetEncoding.
+	CodePage string `json:"codePage,omitempty"`
+	// Client-side text transformations, applied in this order after download
+	StripSequenceNumbers  bool       `json:"stripSequenceNumbers,omitempty"`  // Drop columns 73-80
+	TrimTrailingSpaces    bool       `json:"trimTrailingSpaces,omitempty"`    // Remove record padding
+	InsertSequenceNumbers bool       `json:"insertSequenceNumbers,omitempty"` // Number columns 73-80 in steps of 100
+	NormalizeLineEndings  LineEnding `json:"normalizeLineEndings,omitempty"`  // LF or CRLF
+	// Force skips the check that a dataset read without MemberName is not a PDS or
+	// PDSE, whose content is its directory
+	Force bool `json:"force,omitempty"`
+}
+
+// AMSResponse represents the result of running IDCAMS statements
+type AMSResponse struct {
+	Output     []string `json:"output"`         // IDCAMS listing lines
+	ReturnCode int      `json:"rc,omitempty"`   // Highest condition code reported by IDCAMS
+	Message    string   `json:"message,omitempty"`
 }
 
 // DatasetFilter represents filters for dataset queries
 type DatasetFilter struct {
-	Name   string `json:"name,omitempty"`
-	Type   string `json:"type,omitempty"`
-	Volume string `json:"volume,omitempty"`
-	Owner  string `json:"owner,omitempty"`
-	Limit  int    `json:"limit,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	Names  []string `json:"names,omitempty"` // Additional patterns, each listed separately and merged
+	Type   string   `json:"type,omitempty"`
+	Volume string   `json:"volume,omitempty"`
+	Owner  string   `json:"owner,omitempty"`
+	// Limit caps the datasets returned; 0 uses the manager's default limit (see
+	// SetDefaultLimit) and a negative value lists without a limit
+	Limit int `json:"limit,omitempty"`
+	// DefaultToUserPrefix lists "<session user>.*" when no pattern or volume is given
+	DefaultToUserPrefix bool `json:"defaultToUserPrefix,omitempty"`
+	// MigrationStatus keeps only active or only migrated datasets; it is applied to the
+	// listed datasets, after Limit
+	MigrationStatus MigrationStatus `json:"migrationStatus,omitempty"`
+	// ForceRefresh skips the response cache and refreshes it (see WithCache)
+	ForceRefresh bool `json:"-"`
+}
+
+// GetDatasetOptions controls a single dataset lookup
+type GetDatasetOptions struct {
+	// ResolveAlias asks the catalog (LISTCAT) whether a name that is not listed is an
+	// alias. Names the listing marks as aliases are always resolved.
+	ResolveAlias bool `json:"resolveAlias,omitempty"`
+}
+
+// MemberListOptions controls a member listing
+type MemberListOptions struct {
+	// Limit caps the members returned; 0 uses the manager's default limit (see
+	// SetDefaultLimit) and a negative value lists without a limit
+	Limit int `json:"limit,omitempty"`
+	// ForceRefresh skips the response cache and refreshes it (see WithCache)
+	ForceRefresh bool `json:"-"`
+}
+
+// SearchOptions controls a client-side member content search
+type SearchOptions struct {
+	MemberPattern       string `json:"memberPattern,omitempty"`       // Member name filter, * and % wildcards
+	Regexp              bool   `json:"regexp,omitempty"`              // Treat the pattern as a regular expression
+	CaseInsensitive     bool   `json:"caseInsensitive,omitempty"`     // Ignore case when matching
+	MaxMatchesPerMember int    `json:"maxMatchesPerMember,omitempty"` // 0 = no limit
+	Concurrency         int    `json:"concurrency,omitempty"`         // Parallel downloads, defaults to 4
+}
+
+// SearchMatch is a line that matched a member content search
+type SearchMatch struct {
+	Member string `json:"member"`
+	Line   int    `json:"line"` // 1-based line number
+	Text   string `json:"text"`
+}
+
+// GDGOptions are the attributes of a new generation data group base
+type GDGOptions struct {
+	Limit    int  // Generations kept: 1-255, or 1-999 when Extended
+	Empty    bool // At the limit, uncatalog every generation rather than only the oldest
+	Scratch  bool // Delete generations from their volume when they are uncataloged
+	Purge    bool // Scratch generations even before their expiration date; requires Scratch
+	Extended bool // Extended GDG, allowing up to 999 generations
+}
+
+// TreeOptions controls ListDatasetTree
+type TreeOptions struct {
+	IncludeMembers bool `json:"includeMembers,omitempty"` // List members of partitioned datasets
+	Concurrency    int  `json:"concurrency,omitempty"`    // Parallel member lists, defaults to 4
+	MemberLimit    int  `json:"memberLimit,omitempty"`    // Members kept per dataset, 0 = no limit
+}
+
+// DatasetTree is a set of datasets with the members of each partitioned dataset
+type DatasetTree struct {
+	Pattern string        `json:"pattern"`
+	Nodes   []DatasetNode `json:"nodes"`
+}
+
+// DatasetNode is a dataset in a DatasetTree
+type DatasetNode struct {
+	Dataset          Dataset         `json:"dataset"`
+	Members          []DatasetMember `json:"members,omitempty"`
+	MembersTruncated bool            `json:"membersTruncated,omitempty"` // More members than TreeOptions.MemberLimit
+	Skipped          bool            `json:"skipped,omitempty"`          // Migrated, so members were not listed
+	Err              error           `json:"-"`                          // Failure listing members, serialized as "error"
 }
 
 // DatasetManager interface for dataset operations
 type DatasetManager interface {
 	// Basic operations
 	ListDatasets(filter *DatasetFilter) (*DatasetList, error)
+	ListAllDatasets(filter *DatasetFilter) (*DatasetList, error)
+	ListGDGGenerations(baseName string) ([]string, error)
 	GetDataset(name string) (*Dataset, error)
+	GetDatasetStrict(name string) (*Dataset, error)
 	GetDatasetInfo(name string) (*Dataset, error)
 	CreateDataset(request *CreateDatasetRequest) error
 	DeleteDataset(name string) error
@@ -151,6 +416,9 @@ type DatasetManager interface {
 	// Content operations
 	UploadContent(request *UploadRequest) error
 	DownloadContent(request *DownloadRequest) (string, error)
+	OpenMember(datasetName, memberName string) (*MemberFile, error)
+	DownloadRecords(datasetName string, startRecord, count int) (string, error)
+	AppendContent(datasetName string, content string) error
 	
 	// Member operations (for PDS)
 	ListMembers(datasetName string) (*MemberList, error)
@@ -159,12 +427,24 @@ type DatasetManager interface {
 	
 	// Utility operations
 	Exists(name string) (bool, error)
+	ExistsDirect(name string) (bool, error)
+	IsMigrated(name string) (bool, error)
 	CopySequentialDataset(sourceName, targetName string) error
 	CopyMember(sourceName, sourceMember, targetName, targetMember string) error
 	RenameDataset(oldName, newName string) error
+	InvokeAMS(statements []string) (*AMSResponse, error)
+	CreateGDGBase(name string, options *GDGOptions) error
+	DeleteGDGBase(name string, force bool) error
+	CloseDatasetManager() error
 }
 
 // ZOSMFDatasetManager implements DatasetManager for ZOSMF
 type ZOSMFDatasetManager struct {
-	session interface{} // Will be *profile.Session
+	session       profile.HTTPSession
+	jobRunner     JobRunner
+	cache         *listCache
+	defaultLimit  int            // X-IBM-Max-Items for list calls that set no limit, 0 = none
+	verifyUploads *VerifyOptions // Verify every upload with these options, nil = off
+	dsorgMu       sync.Mutex
+	dsorgs        map[string]DatasetType // Organization of datasets content was read or written without a member
 }
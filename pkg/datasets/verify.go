@@ -0,0 +1,246 @@
+package datasets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUploadMismatch is returned when content read back after an upload differs from
+// what was uploaded. The error is an *UploadVerificationError carrying the differences.
+var ErrUploadMismatch = errors.New("uploaded content does not match")
+
+// defaultVerifyMaxDiffs is how many mismatched lines a Diff keeps by default
+const defaultVerifyMaxDiffs = 10
+
+// ebcdicVariantChars are characters stored at different code points in common EBCDIC
+// code pages, so they can come back as another character when the dataset's code page
+// is not the one the upload was converted with
+const ebcdicVariantChars = "[]^|!\\`~{}¢¬¦"
+
+// ebcdicSubstitutes are what characters with no EBCDIC equivalent come back as
+const ebcdicSubstitutes = "\x1a?\ufffd"
+
+// VerifyOptions controls how VerifyUpload compares content
+type VerifyOptions struct {
+	// TrimTrailingSpaces ignores blanks at the end of lines, which fixed-length
+	// records are padded with
+	TrimTrailingSpaces bool
+	// NormalizeLineEndings treats CRLF, CR and LF as the same and ignores a
+	// missing final newline
+	NormalizeLineEndings bool
+	// EBCDICTolerant accepts characters that do not survive an EBCDIC round trip
+	// unchanged: code page variant characters such as [ ] and ^ may come back as any
+	// character, and characters EBCDIC cannot hold as a substitute character
+	EBCDICTolerant bool
+	// MaxDiffs is how many mismatched lines the Diff keeps (default 10)
+	MaxDiffs int
+	// SourceEncoding and TargetEncoding are the code pages VerifyUpload reads the
+	// dataset back with, as in DownloadRequest; TargetEncoding defaults to UTF-8.
+	// Uploads verified through UploadRequest.Verify or SetVerifyUploads use the
+	// upload's own encodings.
+	SourceEncoding string
+	TargetEncoding string
+}
+
+// DefaultVerifyOptions returns the options VerifyUpload uses when none are given:
+// trailing blanks and line endings are ignored, and characters must match exactly
+func DefaultVerifyOptions() *VerifyOptions {
+	return &VerifyOptions{TrimTrailingSpaces: true, NormalizeLineEndings: true}
+}
+
+// Diff describes how content read back from a dataset differs from the local content
+type Diff struct {
+	LocalLines      int            `json:"localLines"`
+	RemoteLines     int            `json:"remoteLines"`
+	TotalMismatches int            `json:"totalMismatches"` // All mismatched lines
+	Mismatches      []LineMismatch `json:"mismatches"`      // The first MaxDiffs of them
+}
+
+// LineMismatch is a line that differs; a line missing on one side is empty
+type LineMismatch struct {
+	Line   int    `json:"line"` // 1-based
+	Local  string `json:"local"`
+	Remote string `json:"remote"`
+}
+
+// String lists the mismatched lines
+func (d *Diff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d lines differ (%d local, %d remote)", d.TotalMismatches, max(d.LocalLines, d.RemoteLines), d.LocalLines, d.RemoteLines)
+	for _, m := range d.Mismatches {
+		fmt.Fprintf(&b, "\nline %d:\n  local:  %q\n  remote: %q", m.Line, m.Local, m.Remote)
+	}
+	if more := d.TotalMismatches - len(d.Mismatches); more > 0 {
+		fmt.Fprintf(&b, "\n... and %d more", more)
+	}
+	return b.String()
+}
+
+// UploadVerificationError reports an upload whose content did not read back the same
+type UploadVerificationError struct {
+	DatasetName string
+	MemberName  string
+	Diff        *Diff
+}
+
+func (e *UploadVerificationError) Error() string {
+	name := e.DatasetName
+	if e.MemberName != "" {
+		name = fmt.Sprintf("%s(%s)", e.DatasetName, e.MemberName)
+	}
+	return fmt.Sprintf("%s in %s: %s", ErrUploadMismatch.Error(), name, e.Diff)
+}
+
+// Is makes errors.Is(err, ErrUploadMismatch) report true
+func (e *UploadVerificationError) Is(target error) bool {
+	return target == ErrUploadMismatch
+}
+
+// SetVerifyUploads makes every upload through the manager, including UploadText and
+// UploadTextToMember, read the content back and compare it using opts. An upload that
+// does not match fails with an *UploadVerificationError. nil turns verification off.
+func (dm *ZOSMFDatasetManager) SetVerifyUploads(opts *VerifyOptions) {
+	dm.verifyUploads = opts
+}
+
+// VerifyUpload downloads a dataset, or a member when memberName is set, and compares
+// it line by line with localContent. opts may be nil for DefaultVerifyOptions. It
+// returns whether the content matches and, when it does not, the differences.
+func (dm *ZOSMFDatasetManager) VerifyUpload(datasetName, memberName, localContent string, opts *VerifyOptions) (bool, *Diff, error) {
+	download := &DownloadRequest{DatasetName: datasetName, MemberName: memberName, TargetEncoding: "UTF-8"}
+	if opts != nil {
+		download.SourceEncoding = opts.SourceEncoding
+		if opts.TargetEncoding != "" {
+			download.TargetEncoding = opts.TargetEncoding
+		}
+	}
+	return dm.verifyContent(download, localContent, opts)
+}
+
+// verifyContent downloads content as download says and compares it with localContent
+func (dm *ZOSMFDatasetManager) verifyContent(download *DownloadRequest, localContent string, opts *VerifyOptions) (bool, *Diff, error) {
+	if opts == nil {
+		opts = DefaultVerifyOptions()
+	}
+
+	remote, err := dm.DownloadContent(download)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to read back content: %w", err)
+	}
+
+	diff := compareContent(localContent, remote, opts)
+	if diff.TotalMismatches == 0 {
+		return true, nil, nil
+	}
+	return false, diff, nil
+}
+
+// verifyUploaded checks an upload when the request or the manager asks for it
+func (dm *ZOSMFDatasetManager) verifyUploaded(request *UploadRequest) error {
+	opts := dm.verifyUploads
+	if request.Verify {
+		opts = request.VerifyOptions
+		if opts == nil {
+			opts = DefaultVerifyOptions()
+		}
+	}
+	if opts == nil {
+		return nil
+	}
+
+	// Content is read back with the code pages it was written with
+	download := &DownloadRequest{
+		DatasetName:    request.DatasetName,
+		MemberName:     request.MemberName,
+		Force:          request.Force,
+		Encoding:       request.Encoding,
+		SourceEncoding: request.SourceEncoding,
+		TargetEncoding: request.TargetEncoding,
+		CodePage:       request.CodePage,
+	}
+	ok, diff, err := dm.verifyContent(download, request.Content, opts)
+	if err != nil {
+		return fmt.Errorf("failed to verify upload: %w", err)
+	}
+	if !ok {
+		return &UploadVerificationError{DatasetName: request.DatasetName, MemberName: request.MemberName, Diff: diff}
+	}
+	return nil
+}
+
+// compareContent compares local and remote content line by line
+func compareContent(local, remote string, opts *VerifyOptions) *Diff {
+	localLines := verifyLines(local, opts)
+	remoteLines := verifyLines(remote, opts)
+	maxDiffs := opts.MaxDiffs
+	if maxDiffs <= 0 {
+		maxDiffs = defaultVerifyMaxDiffs
+	}
+
+	diff := &Diff{LocalLines: len(localLines), RemoteLines: len(remoteLines)}
+	for i := 0; i < max(len(localLines), len(remoteLines)); i++ {
+		var l, r string
+		if i < len(localLines) {
+			l = localLines[i]
+		}
+		if i < len(remoteLines) {
+			r = remoteLines[i]
+		}
+		if i < len(localLines) && i < len(remoteLines) && linesMatch(l, r, opts.EBCDICTolerant) {
+			continue
+		}
+		diff.TotalMismatches++
+		if len(diff.Mismatches) < maxDiffs {
+			diff.Mismatches = append(diff.Mismatches, LineMismatch{Line: i + 1, Local: l, Remote: r})
+		}
+	}
+	return diff
+}
+
+// verifyLines splits content into lines normalized as opts says
+func verifyLines(content string, opts *VerifyOptions) []string {
+	if opts.NormalizeLineEndings {
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+		content = strings.ReplaceAll(content, "\r", "\n")
+		content = strings.TrimSuffix(content, "\n")
+	}
+	if content == "" {
+		return nil
+	}
+	lines := strings.Split(content, "\n")
+	if opts.TrimTrailingSpaces {
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " ")
+		}
+	}
+	return lines
+}
+
+// linesMatch compares two lines, allowing for EBCDIC conversion when tolerant is set
+func linesMatch(local, remote string, tolerant bool) bool {
+	if local == remote {
+		return true
+	}
+	if !tolerant {
+		return false
+	}
+
+	l, r := []rune(local), []rune(remote)
+	if len(l) != len(r) {
+		return false
+	}
+	for i := range l {
+		if l[i] == r[i] {
+			continue
+		}
+		if strings.ContainsRune(ebcdicVariantChars, l[i]) {
+			continue
+		}
+		if l[i] > '~' && strings.ContainsRune(ebcdicSubstitutes, r[i]) {
+			continue
+		}
+		return false
+	}
+	return true
+}
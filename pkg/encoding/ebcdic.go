@@ -0,0 +1,146 @@
+// Package encoding converts text between Unicode and the single-byte EBCDIC code
+// pages z/OS datasets are commonly stored in.
+package encoding
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// codePage maps the 256 bytes of an EBCDIC code page to Unicode and back
+type codePage struct {
+	name        string
+	toUnicode   [256]rune
+	fromUnicode map[rune]byte
+}
+
+// ibm037 is IBM-037 (US and Canada), the code page the others are described against.
+// Byte 0x15 is NEL and 0x25 is LF, as in the z/OS Unicode Services tables.
+var ibm037 = [256]rune{
+	0x0000, 0x0001, 0x0002, 0x0003, 0x009C, 0x0009, 0x0086, 0x007F, 0x0097, 0x008D, 0x008E, 0x000B, 0x000C, 0x000D, 0x000E, 0x000F, // 00
+	0x0010, 0x0011, 0x0012, 0x0013, 0x009D, 0x0085, 0x0008, 0x0087, 0x0018, 0x0019, 0x0092, 0x008F, 0x001C, 0x001D, 0x001E, 0x001F, // 10
+	0x0080, 0x0081, 0x0082, 0x0083, 0x0084, 0x000A, 0x0017, 0x001B, 0x0088, 0x0089, 0x008A, 0x008B, 0x008C, 0x0005, 0x0006, 0x0007, // 20
+	0x0090, 0x0091, 0x0016, 0x0093, 0x0094, 0x0095, 0x0096, 0x0004, 0x0098, 0x0099, 0x009A, 0x009B, 0x0014, 0x0015, 0x009E, 0x001A, // 30
+	0x0020, 0x00A0, 0x00E2, 0x00E4, 0x00E0, 0x00E1, 0x00E3, 0x00E5, 0x00E7, 0x00F1, 0x00A2, 0x002E, 0x003C, 0x0028, 0x002B, 0x007C, // 40
+	0x0026, 0x00E9, 0x00EA, 0x00EB, 0x00E8, 0x00ED, 0x00EE, 0x00EF, 0x00EC, 0x00DF, 0x0021, 0x0024, 0x002A, 0x0029, 0x003B, 0x00AC, // 50
+	0x002D, 0x002F, 0x00C2, 0x00C4, 0x00C0, 0x00C1, 0x00C3, 0x00C5, 0x00C7, 0x00D1, 0x00A6, 0x002C, 0x0025, 0x005F, 0x003E, 0x003F, // 60
+	0x00F8, 0x00C9, 0x00CA, 0x00CB, 0x00C8, 0x00CD, 0x00CE, 0x00CF, 0x00CC, 0x0060, 0x003A, 0x0023, 0x0040, 0x0027, 0x003D, 0x0022, // 70
+	0x00D8, 0x0061, 0x0062, 0x0063, 0x0064, 0x0065, 0x0066, 0x0067, 0x0068, 0x0069, 0x00AB, 0x00BB, 0x00F0, 0x00FD, 0x00FE, 0x00B1, // 80
+	0x00B0, 0x006A, 0x006B, 0x006C, 0x006D, 0x006E, 0x006F, 0x0070, 0x0071, 0x0072, 0x00AA, 0x00BA, 0x00E6, 0x00B8, 0x00C6, 0x00A4, // 90
+	0x00B5, 0x007E, 0x0073, 0x0074, 0x0075, 0x0076, 0x0077, 0x0078, 0x0079, 0x007A, 0x00A1, 0x00BF, 0x00D0, 0x00DD, 0x00DE, 0x00AE, // A0
+	0x005E, 0x00A3, 0x00A5, 0x00B7, 0x00A9, 0x00A7, 0x00B6, 0x00BC, 0x00BD, 0x00BE, 0x005B, 0x005D, 0x00AF, 0x00A8, 0x00B4, 0x00D7, // B0
+	0x007B, 0x0041, 0x0042, 0x0043, 0x0044, 0x0045, 0x0046, 0x0047, 0x0048, 0x0049, 0x00AD, 0x00F4, 0x00F6, 0x00F2, 0x00F3, 0x00F5, // C0
+	0x007D, 0x004A, 0x004B, 0x004C, 0x004D, 0x004E, 0x004F, 0x0050, 0x0051, 0x0052, 0x00B9, 0x00FB, 0x00FC, 0x00F9, 0x00FA, 0x00FF, // D0
+	0x005C, 0x00F7, 0x0053, 0x0054, 0x0055, 0x0056, 0x0057, 0x0058, 0x0059, 0x005A, 0x00B2, 0x00D4, 0x00D6, 0x00D2, 0x00D3, 0x00D5, // E0
+	0x0030, 0x0031, 0x0032, 0x0033, 0x0034, 0x0035, 0x0036, 0x0037, 0x0038, 0x0039, 0x00B3, 0x00DB, 0x00DC, 0x00D9, 0x00DA, 0x009F, // F0
+}
+
+// codePageChanges lists where each supported code page differs from IBM-037
+var codePageChanges = map[string]map[byte]rune{
+	"IBM-037": {},
+	// Open Systems Latin-1, the default for z/OS UNIX and z/OSMF
+	"IBM-1047": {0x5F: '^', 0xAD: '[', 0xB0: '¬', 0xBA: 'Ý', 0xBB: '¨', 0xBD: ']'},
+	// United Kingdom
+	"IBM-285": {0x4A: '$', 0x5B: '£', 0xA1: '‾', 0xB0: '¢', 0xB1: '[', 0xBA: '^', 0xBC: '~'},
+	// IBM-037 with the euro sign
+	"IBM-1140": {0x9F: '€'},
+}
+
+// codePages holds the built tables by canonical name
+var codePages = buildCodePages()
+
+func buildCodePages() map[string]*codePage {
+	pages := make(map[string]*codePage, len(codePageChanges))
+	for name, changes := range codePageChanges {
+		page := &codePage{name: name, toUnicode: ibm037, fromUnicode: make(map[rune]byte, 256)}
+		for b, r := range changes {
+			page.toUnicode[b] = r
+		}
+		for b, r := range page.toUnicode {
+			page.fromUnicode[r] = byte(b)
+		}
+		pages[name] = page
+	}
+	return pages
+}
+
+// CanonicalCodePage returns the name a code page is known by, e.g. "IBM-037" for
+// "037", "IBM037", "cp37" or "CCSID 37", or an error if it is not supported
+func CanonicalCodePage(codepage string) (string, error) {
+	page, err := lookup(codepage)
+	if err != nil {
+		return "", err
+	}
+	return page.name, nil
+}
+
+// SupportedCodePages returns the canonical names of the supported code pages, sorted
+func SupportedCodePages() []string {
+	names := make([]string, 0, len(codePages))
+	for name := range codePages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup finds a code page by any of the names CanonicalCodePage accepts
+func lookup(codepage string) (*codePage, error) {
+	name := strings.ToUpper(strings.TrimSpace(codepage))
+	for _, prefix := range []string{"IBM-", "IBM", "CP", "CCSID"} {
+		if strings.HasPrefix(name, prefix) {
+			name = strings.TrimSpace(strings.TrimPrefix(name, prefix))
+			break
+		}
+	}
+	number, err := strconv.Atoi(name)
+	if err != nil || number <= 0 {
+		return nil, fmt.Errorf("unsupported code page %q (supported: %s)", codepage, strings.Join(SupportedCodePages(), ", "))
+	}
+	page, ok := codePages[fmt.Sprintf("IBM-%03d", number)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported code page %q (supported: %s)", codepage, strings.Join(SupportedCodePages(), ", "))
+	}
+	return page, nil
+}
+
+// ConvertFromEBCDIC decodes EBCDIC bytes in the given code page into a string.
+// Every byte has a Unicode equivalent, so only an unknown code page is an error.
+func ConvertFromEBCDIC(b []byte, codepage string) (string, error) {
+	page, err := lookup(codepage)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	out.Grow(len(b))
+	for _, c := range b {
+		out.WriteRune(page.toUnicode[c])
+	}
+	return out.String(), nil
+}
+
+// ConvertToEBCDIC encodes a string into EBCDIC bytes in the given code page. It fails
+// on the first character the code page cannot represent, or on invalid UTF-8.
+func ConvertToEBCDIC(s string, codepage string) ([]byte, error) {
+	page, err := lookup(codepage)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(s))
+	for i, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size <= 1 {
+				return nil, fmt.Errorf("invalid UTF-8 at offset %d", i)
+			}
+		}
+		c, ok := page.fromUnicode[r]
+		if !ok {
+			return nil, fmt.Errorf("character %q at offset %d cannot be represented in %s", r, i, page.name)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
@@ -0,0 +1,133 @@
+package encoding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodePageMappings(t *testing.T) {
+	tests := []struct {
+		codepage string
+		char     rune
+		ebcdic   byte
+	}{
+		// Invariant characters are the same in every code page
+		{"IBM-037", ' ', 0x40},
+		{"IBM-037", 'A', 0xC1},
+		{"IBM-037", 'a', 0x81},
+		{"IBM-037", '0', 0xF0},
+		{"IBM-037", '\n', 0x25},
+		{"IBM-1047", 'A', 0xC1},
+		{"IBM-285", 'Z', 0xE9},
+
+		// IBM-037
+		{"IBM-037", '[', 0xBA},
+		{"IBM-037", ']', 0xBB},
+		{"IBM-037", '^', 0xB0},
+		{"IBM-037", '¬', 0x5F},
+		{"IBM-037", '$', 0x5B},
+		{"IBM-037", '¢', 0x4A},
+		{"IBM-037", '£', 0xB1},
+		{"IBM-037", 'Ý', 0xAD},
+		{"IBM-037", '¤', 0x9F},
+
+		// IBM-1047
+		{"IBM-1047", '[', 0xAD},
+		{"IBM-1047", ']', 0xBD},
+		{"IBM-1047", '^', 0x5F},
+		{"IBM-1047", '¬', 0xB0},
+		{"IBM-1047", 'Ý', 0xBA},
+		{"IBM-1047", '¨', 0xBB},
+		{"IBM-1047", '$', 0x5B},
+
+		// IBM-285
+		{"IBM-285", '$', 0x4A},
+		{"IBM-285", '£', 0x5B},
+		{"IBM-285", '¢', 0xB0},
+		{"IBM-285", '[', 0xB1},
+		{"IBM-285", ']', 0xBB},
+		{"IBM-285", '^', 0xBA},
+		{"IBM-285", '~', 0xBC},
+		{"IBM-285", '‾', 0xA1},
+
+		// IBM-1140
+		{"IBM-1140", '€', 0x9F},
+		{"IBM-1140", '[', 0xBA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.codepage+" "+string(tt.char), func(t *testing.T) {
+			encoded, err := ConvertToEBCDIC(string(tt.char), tt.codepage)
+			require.NoError(t, err)
+			assert.Equal(t, []byte{tt.ebcdic}, encoded)
+
+			decoded, err := ConvertFromEBCDIC([]byte{tt.ebcdic}, tt.codepage)
+			require.NoError(t, err)
+			assert.Equal(t, string(tt.char), decoded)
+		})
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+
+	for _, codepage := range SupportedCodePages() {
+		t.Run(codepage, func(t *testing.T) {
+			decoded, err := ConvertFromEBCDIC(all, codepage)
+			require.NoError(t, err)
+			assert.Len(t, []rune(decoded), 256)
+
+			encoded, err := ConvertToEBCDIC(decoded, codepage)
+			require.NoError(t, err)
+			assert.Equal(t, all, encoded)
+		})
+	}
+
+	// The same bytes read as the wrong code page give different brackets
+	jcl := "//SYSIN DD *\n  A[1] = B^C\n"
+	encoded, err := ConvertToEBCDIC(jcl, "IBM-037")
+	require.NoError(t, err)
+	wrong, err := ConvertFromEBCDIC(encoded, "IBM-1047")
+	require.NoError(t, err)
+	assert.NotEqual(t, jcl, wrong)
+	right, err := ConvertFromEBCDIC(encoded, "IBM-037")
+	require.NoError(t, err)
+	assert.Equal(t, jcl, right)
+}
+
+func TestCodePageNames(t *testing.T) {
+	for _, name := range []string{"IBM-037", "ibm-037", "IBM037", "037", "37", "cp037", "CCSID 37"} {
+		canonical, err := CanonicalCodePage(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, "IBM-037", canonical)
+	}
+	canonical, err := CanonicalCodePage("1047")
+	require.NoError(t, err)
+	assert.Equal(t, "IBM-1047", canonical)
+
+	assert.Equal(t, []string{"IBM-037", "IBM-1047", "IBM-1140", "IBM-285"}, SupportedCodePages())
+
+	for _, name := range []string{"", "IBM-500", "UTF-8", "IBM-"} {
+		_, err := CanonicalCodePage(name)
+		assert.ErrorContains(t, err, "unsupported code page", name)
+		_, err = ConvertFromEBCDIC([]byte{0xC1}, name)
+		assert.Error(t, err)
+	}
+}
+
+func TestConvertToEBCDICErrors(t *testing.T) {
+	_, err := ConvertToEBCDIC("PRICE 10€", "IBM-037")
+	assert.ErrorContains(t, err, `character '€' at offset 8 cannot be represented in IBM-037`)
+
+	_, err = ConvertToEBCDIC("AB\xffC", "IBM-1047")
+	assert.ErrorContains(t, err, "invalid UTF-8 at offset 2")
+
+	// A literal U+FFFD is valid UTF-8 but still has no EBCDIC equivalent
+	_, err = ConvertToEBCDIC("�", "IBM-1047")
+	assert.ErrorContains(t, err, "cannot be represented")
+}
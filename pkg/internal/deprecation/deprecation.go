@@ -0,0 +1,35 @@
+// Package deprecation reports calls to deprecated SDK functions to the handler set
+// with profile.SetDeprecationLogger
+package deprecation
+
+import "sync"
+
+var (
+	mu       sync.Mutex
+	handler  func(function, replacement string)
+	reported = make(map[string]bool)
+)
+
+// SetHandler sets the function deprecations are reported to; nil stops reporting.
+// Every function is reported again, once, to the new handler.
+func SetHandler(h func(function, replacement string)) {
+	mu.Lock()
+	defer mu.Unlock()
+	handler = h
+	reported = make(map[string]bool)
+}
+
+// Report reports a call to a deprecated function, e.g. "jobs.CreateJobManagerDirect",
+// and what to use instead. Each function is reported only on its first call.
+func Report(function, replacement string) {
+	mu.Lock()
+	h := handler
+	if h == nil || reported[function] {
+		mu.Unlock()
+		return
+	}
+	reported[function] = true
+	mu.Unlock()
+
+	h(function, replacement)
+}
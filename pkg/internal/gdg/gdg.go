@@ -0,0 +1,50 @@
+// Package gdg parses relative generation data group references for the datasets
+// and jobs packages
+package gdg
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// MaxGenerations is the most generations a GDG can hold, and so the largest relative generation
+const MaxGenerations = 255
+
+var (
+	generationPattern = regexp.MustCompile(`^(?:0|[+-][1-9][0-9]{0,2})$`)
+	referencePattern  = regexp.MustCompile(`^(.+)\(([^()]*)\)$`)
+)
+
+// ParseGeneration parses a relative generation: "0", or "+n" or "-n" with up to three
+// digits and no leading zero. The range is left to the caller to check.
+func ParseGeneration(generation string) (int, bool) {
+	if !generationPattern.MatchString(generation) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(generation)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// IsRelativeGeneration reports whether generation is a relative generation within
+// MaxGenerations of the current one
+func IsRelativeGeneration(generation string) bool {
+	n, ok := ParseGeneration(generation)
+	return ok && n >= -MaxGenerations && n <= MaxGenerations
+}
+
+// ParseReference splits a reference such as "MY.GDG(+1)" into its base name and
+// relative generation. ok is false when the reference has no relative generation.
+func ParseReference(reference string) (base string, generation int, ok bool) {
+	match := referencePattern.FindStringSubmatch(reference)
+	if match == nil {
+		return reference, 0, false
+	}
+	generation, ok = ParseGeneration(match[2])
+	if !ok {
+		return reference, 0, false
+	}
+	return match[1], generation, true
+}
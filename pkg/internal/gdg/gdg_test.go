@@ -0,0 +1,47 @@
+package gdg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGeneration(t *testing.T) {
+	tests := []struct {
+		generation string
+		want       int
+		ok         bool
+		relative   bool
+	}{
+		{"0", 0, true, true},
+		{"+1", 1, true, true},
+		{"-255", -255, true, true},
+		{"+256", 256, true, false},
+		{"+999", 999, true, false},
+		{"+1000", 0, false, false},
+		{"1", 0, false, false},
+		{"+01", 0, false, false},
+		{"-0", 0, false, false},
+		{"", 0, false, false},
+		{"MEMBER", 0, false, false},
+	}
+	for _, tt := range tests {
+		n, ok := ParseGeneration(tt.generation)
+		assert.Equal(t, tt.want, n, tt.generation)
+		assert.Equal(t, tt.ok, ok, tt.generation)
+		assert.Equal(t, tt.relative, IsRelativeGeneration(tt.generation), tt.generation)
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	base, generation, ok := ParseReference("MY.GDG(-2)")
+	assert.True(t, ok)
+	assert.Equal(t, "MY.GDG", base)
+	assert.Equal(t, -2, generation)
+
+	for _, reference := range []string{"MY.PDS(MEMBER)", "MY.GDG", "MY.GDG(+)", "(0)"} {
+		base, _, ok = ParseReference(reference)
+		assert.False(t, ok, reference)
+		assert.Equal(t, reference, base)
+	}
+}
@@ -0,0 +1,106 @@
+// Package request holds the HTTP plumbing shared by the SDK packages: building z/OSMF
+// URLs, sending requests with a session's headers, reading z/OSMF error documents and
+// wrapping sessions with another base path or with retries.
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Session is what requests are sent with. It has the methods of profile.HTTPSession,
+// which cannot be named here as the profile package imports this one.
+type Session interface {
+	GetBaseURL() string
+	GetHTTPClient() *http.Client
+	GetHeaders() map[string]string
+	GetUser() string
+}
+
+// URL returns the URL of path under baseURL, e.g. "/restfiles/ds" under
+// https://host/zosmf, with query appended
+func URL(baseURL, path string, query url.Values) string {
+	apiURL := strings.TrimRight(baseURL, "/")
+	if path != "" && !strings.HasPrefix(path, "/") {
+		apiURL += "/"
+	}
+	apiURL += path
+	if len(query) > 0 {
+		separator := "?"
+		if strings.Contains(path, "?") {
+			separator = "&"
+		}
+		apiURL += separator + query.Encode()
+	}
+	return apiURL
+}
+
+// ReplayableBody is a request body whose content is known up front but is sent
+// through another reader, such as one reporting upload progress. New gives the request
+// its length and lets redirects and body logging read Content again.
+type ReplayableBody struct {
+	io.Reader
+	Content []byte
+}
+
+// New builds a request for path under the session's base URL. The session's headers
+// are set first, so headers take precedence over them.
+func New(ctx context.Context, session Session, method, path string, query url.Values, headers map[string]string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, URL(session.GetBaseURL(), path, query), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if replayable, ok := body.(*ReplayableBody); ok {
+		req.ContentLength = int64(len(replayable.Content))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(replayable.Content)), nil
+		}
+		if req.ContentLength == 0 {
+			req.Body = http.NoBody
+		}
+	}
+	for key, value := range session.GetHeaders() {
+		req.Header.Set(key, value)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
+
+// Do builds a request like New and sends it with the session's client. The response is
+// returned whatever its status, and the caller must close its body.
+func Do(ctx context.Context, session Session, method, path string, query url.Values, headers map[string]string, body io.Reader) (*http.Response, error) {
+	req, err := New(ctx, session, method, path, query, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := session.GetHTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	return resp, nil
+}
+
+// ErrorDocument is the JSON body z/OSMF returns with a failed request
+type ErrorDocument struct {
+	Category int      `json:"category"`
+	RC       int      `json:"rc"`
+	Reason   int      `json:"reason"`
+	Message  string   `json:"message"`
+	Details  []string `json:"details"`
+}
+
+// ParseErrorDocument reads a z/OSMF error document; ok is false when body is not a JSON object
+func ParseErrorDocument(body []byte) (doc ErrorDocument, ok bool) {
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return ErrorDocument{}, false
+	}
+	return doc, true
+}
@@ -0,0 +1,185 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSession sends requests to a server with a plain client
+type testSession struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (s *testSession) GetBaseURL() string          { return s.baseURL }
+func (s *testSession) GetHTTPClient() *http.Client { return s.client }
+func (s *testSession) GetHeaders() map[string]string {
+	return map[string]string{"X-CSRF-ZOSMF-HEADER": "", "Accept": "*/*"}
+}
+func (s *testSession) GetUser() string { return "IBMUSER" }
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		path    string
+		query   url.Values
+		want    string
+	}{
+		{"path", "https://host/zosmf", "/restfiles/ds", nil, "https://host/zosmf/restfiles/ds"},
+		{"trailing slash", "https://host/zosmf/", "/restfiles/ds", nil, "https://host/zosmf/restfiles/ds"},
+		{"relative path", "https://host/zosmf", "info", nil, "https://host/zosmf/info"},
+		{"query", "https://host/zosmf", "/restfiles/ds", url.Values{"dslevel": {"USER.*"}}, "https://host/zosmf/restfiles/ds?dslevel=USER.%2A"},
+		{"query after query", "https://host/zosmf", "/restjobs/jobs?owner=*", url.Values{"prefix": {"A"}}, "https://host/zosmf/restjobs/jobs?owner=*&prefix=A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, URL(tt.baseURL, tt.path, tt.query))
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/zosmf/restfiles/ds", r.URL.Path)
+		assert.Equal(t, "", r.Header.Get("X-CSRF-ZOSMF-HEADER"))
+		assert.Equal(t, "application/json", r.Header.Get("Accept"), "headers override session headers")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	session := &testSession{baseURL: server.URL + "/zosmf", client: server.Client()}
+	resp, err := Do(context.Background(), session, "GET", "/restfiles/ds", nil, map[string]string{"Accept": "application/json"}, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	_, err = Do(context.Background(), session, "BAD METHOD", "/", nil, nil, nil)
+	assert.ErrorContains(t, err, "failed to create request")
+}
+
+func TestNewReplayableBody(t *testing.T) {
+	session := &testSession{baseURL: "https://host/zosmf"}
+	content := []byte("HELLO")
+	body := &ReplayableBody{Reader: io.LimitReader(bytes.NewReader(content), 5), Content: content}
+
+	// The length and a fresh copy of the content come from Content, not the reader
+	req, err := New(context.Background(), session, "PUT", "/restfiles/ds/A.B", nil, nil, body)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), req.ContentLength)
+	replay, err := req.GetBody()
+	require.NoError(t, err)
+	data, err := io.ReadAll(replay)
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO", string(data))
+
+	// Empty content is sent with no body rather than chunked
+	req, err = New(context.Background(), session, "PUT", "/restfiles/ds/A.B", nil, nil, &ReplayableBody{Reader: bytes.NewReader(nil)})
+	require.NoError(t, err)
+	assert.Equal(t, http.NoBody, req.Body)
+	assert.Zero(t, req.ContentLength)
+}
+
+func TestParseErrorDocument(t *testing.T) {
+	doc, ok := ParseErrorDocument([]byte(`{"category":4,"rc":8,"reason":0,"message":"No job found","details":["IZUG001E"]}`))
+	require.True(t, ok)
+	assert.Equal(t, ErrorDocument{Category: 4, RC: 8, Message: "No job found", Details: []string{"IZUG001E"}}, doc)
+
+	_, ok = ParseErrorDocument([]byte("<html>Bad Gateway</html>"))
+	assert.False(t, ok)
+}
+
+func TestWithBasePath(t *testing.T) {
+	session := WithBasePath(&testSession{baseURL: "https://host:443/zosmf"}, "/ibmzosmf/api/v1/")
+	assert.Equal(t, "https://host:443/ibmzosmf/api/v1", session.GetBaseURL())
+	assert.Equal(t, "IBMUSER", session.GetUser())
+
+	session = WithBasePath(&testSession{baseURL: "https://host/zosmf"}, "")
+	assert.Equal(t, "https://host", session.GetBaseURL())
+}
+
+// encodedSession carries a profile encoding, as *profile.Session does
+type encodedSession struct {
+	testSession
+	encoding string
+}
+
+func (s *encodedSession) GetEncoding() string { return s.encoding }
+
+func TestWrappersKeepEncoding(t *testing.T) {
+	type encoder interface{ GetEncoding() string }
+
+	encoded := &encodedSession{testSession: testSession{baseURL: "https://host/zosmf", client: http.DefaultClient}, encoding: "IBM-1140"}
+	session := WithRetries(WithBasePath(encoded, "/ibmzosmf/api/v1"), 2, time.Millisecond)
+	require.Implements(t, (*encoder)(nil), session)
+	assert.Equal(t, "IBM-1140", session.(encoder).GetEncoding())
+
+	plain := WithBasePath(&testSession{baseURL: "https://host/zosmf"}, "/api/v1")
+	assert.Equal(t, "", plain.(encoder).GetEncoding())
+}
+
+func TestWithRetries(t *testing.T) {
+	var calls int32
+	failures := int32(2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= atomic.LoadInt32(&failures) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	base := &testSession{baseURL: server.URL, client: server.Client()}
+	session := WithRetries(base, 2, time.Millisecond)
+
+	// Two 503s, then success on the last retry
+	resp, err := Do(context.Background(), session, "GET", "/info", nil, nil, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// Out of retries, the last response is returned
+	atomic.StoreInt32(&calls, 0)
+	atomic.StoreInt32(&failures, 10)
+	resp, err = Do(context.Background(), session, "HEAD", "/info", nil, nil, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// Requests that change data are sent once
+	atomic.StoreInt32(&calls, 0)
+	resp, err = Do(context.Background(), session, "PUT", "/restjobs/jobs", nil, nil, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// A canceled context stops retrying
+	var canceledCalls int32
+	ctx, cancel := context.WithCancel(context.Background())
+	canceling := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&canceledCalls, 1)
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer canceling.Close()
+	slow := WithRetries(&testSession{baseURL: canceling.URL, client: canceling.Client()}, 5, time.Hour)
+	_, err = Do(ctx, slow, "GET", "/info", nil, nil, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&canceledCalls))
+
+	// No retries leaves the session as it is
+	assert.Same(t, base, WithRetries(base, 0, time.Millisecond))
+}
@@ -0,0 +1,140 @@
+package request
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultRetryBackoff is the wait before the first retry; each later retry waits twice as long
+const DefaultRetryBackoff = 250 * time.Millisecond
+
+// WithBasePath returns a session whose base URL keeps the scheme and host of the
+// session's but has basePath as its path, e.g. "/ibmzosmf/api/v1" through the API
+// Mediation Layer
+func WithBasePath(session Session, basePath string) Session {
+	return &basePathSession{Session: session, basePath: "/" + strings.Trim(basePath, "/")}
+}
+
+type basePathSession struct {
+	Session
+	basePath string
+}
+
+// GetEncoding returns the wrapped session's encoding, if it has one
+func (s *basePathSession) GetEncoding() string {
+	return sessionEncoding(s.Session)
+}
+
+// GetBaseURL returns the wrapped session's base URL with its path replaced
+func (s *basePathSession) GetBaseURL() string {
+	baseURL := s.Session.GetBaseURL()
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return baseURL
+	}
+	u.Path = s.basePath
+	u.RawPath = ""
+	return strings.TrimRight(u.String(), "/")
+}
+
+// sessionEncoding returns the encoding of a session that carries its profile's, such as
+// *profile.Session, so that wrapping a session does not hide it
+func sessionEncoding(session Session) string {
+	if encoded, ok := session.(interface{ GetEncoding() string }); ok {
+		return encoded.GetEncoding()
+	}
+	return ""
+}
+
+// WithRetries returns a session that retries GET and HEAD requests up to retries more
+// times when they fail with a transport error or a 502, 503 or 504 status. It waits
+// backoff before the first retry and twice as long before each later one. Other
+// methods are sent once, as repeating them may not be safe.
+func WithRetries(session Session, retries int, backoff time.Duration) Session {
+	if retries <= 0 {
+		return session
+	}
+	return &retrySession{Session: session, retries: retries, backoff: backoff}
+}
+
+type retrySession struct {
+	Session
+	retries int
+	backoff time.Duration
+}
+
+// GetEncoding returns the wrapped session's encoding, if it has one
+func (s *retrySession) GetEncoding() string {
+	return sessionEncoding(s.Session)
+}
+
+// GetHTTPClient returns a copy of the wrapped session's client that retries requests
+func (s *retrySession) GetHTTPClient() *http.Client {
+	client := s.Session.GetHTTPClient()
+	if client == nil {
+		return nil
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	retrying := *client
+	retrying.Transport = &retryTransport{base: base, retries: s.retries, backoff: s.backoff}
+	return &retrying
+}
+
+// retryTransport repeats idempotent requests that failed in a way worth retrying
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	wait := t.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt == t.retries || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport
+func (t *retryTransport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// retryable reports whether a request that ended with resp or err may succeed if repeated
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
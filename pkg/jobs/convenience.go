@@ -1,13 +1,44 @@
 package jobs
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/zowe/zowe-client-go-sdk/pkg/internal/deprecation"
+	"github.com/zowe/zowe-client-go-sdk/pkg/internal/gdg"
+	"github.com/zowe/zowe-client-go-sdk/pkg/internal/request"
 	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
 )
 
+// nativeCorrelatorPattern matches a z/OSMF job correlator: a 31-character system portion
+// (J, S or T, the 7-digit job number, then the system name and a time token padded with
+// dots), a colon and a user portion of up to 32 characters
+var nativeCorrelatorPattern = regexp.MustCompile(`^[JST][0-9]{7}[A-Z0-9$#@.]{23}:.{0,32}$`)
+
+// IsNativeCorrelator reports whether s is a z/OSMF job correlator, such as the
+// Job.JobCorrelator of a listed job, rather than a "jobname:jobid" correlator
+func IsNativeCorrelator(s string) bool {
+	return nativeCorrelatorPattern.MatchString(s)
+}
+
+// MakeCorrelator returns the "jobname:jobid" correlator the job methods accept
+func MakeCorrelator(jobName, jobID string) string {
+	return jobName + ":" + jobID
+}
+
 // parseCorrelator parses "jobname:jobid" into separate parts
 func parseCorrelator(correlator string) (jobName, jobID string, err error) {
 	parts := strings.Split(correlator, ":")
@@ -17,6 +48,81 @@ func parseCorrelator(correlator string) (jobName, jobID string, err error) {
 	return parts[0], parts[1], nil
 }
 
+// buildCorrelator joins a job name and ID into the "jobname:jobid" correlator format
+func buildCorrelator(jobName, jobID string) (string, error) {
+	if jobName == "" || jobID == "" {
+		return "", fmt.Errorf("job name and job ID are required, got: %q, %q", jobName, jobID)
+	}
+	return MakeCorrelator(jobName, jobID), nil
+}
+
+// Correlator returns the z/OSMF job correlator of a submitted job when the response has
+// one, otherwise its "jobname:jobid" correlator
+func (r *SubmitJobResponse) Correlator() string {
+	if IsNativeCorrelator(r.JobCorrelator) {
+		return r.JobCorrelator
+	}
+	return MakeCorrelator(r.JobName, r.JobID)
+}
+
+// submittedCorrelator returns the correlator follow-up calls use for a submitted job
+func submittedCorrelator(resp *SubmitJobResponse) (string, error) {
+	if resp == nil {
+		return "", fmt.Errorf("submit response is required")
+	}
+	if IsNativeCorrelator(resp.JobCorrelator) {
+		return resp.JobCorrelator, nil
+	}
+	return buildCorrelator(resp.JobName, resp.JobID)
+}
+
+// Correlator returns the "jobname:jobid" correlator of a job
+func (j *Job) Correlator() string {
+	return MakeCorrelator(j.JobName, j.JobID)
+}
+
+// DeleteSubmittedJob deletes the job described by a SubmitJob response
+func (jm *ZOSMFJobManager) DeleteSubmittedJob(resp *SubmitJobResponse) error {
+	correlator, err := submittedCorrelator(resp)
+	if err != nil {
+		return err
+	}
+	return jm.DeleteJob(correlator)
+}
+
+// PurgeSubmittedJob purges the job described by a SubmitJob response
+func (jm *ZOSMFJobManager) PurgeSubmittedJob(resp *SubmitJobResponse) error {
+	correlator, err := submittedCorrelator(resp)
+	if err != nil {
+		return err
+	}
+	return jm.PurgeJob(correlator)
+}
+
+// DeleteListedJob deletes a job returned by ListJobs or GetJob
+func (jm *ZOSMFJobManager) DeleteListedJob(job *Job) error {
+	if job == nil {
+		return fmt.Errorf("job is required")
+	}
+	correlator, err := buildCorrelator(job.JobName, job.JobID)
+	if err != nil {
+		return err
+	}
+	return jm.DeleteJob(correlator)
+}
+
+// PurgeListedJob purges a job returned by ListJobs or GetJob
+func (jm *ZOSMFJobManager) PurgeListedJob(job *Job) error {
+	if job == nil {
+		return fmt.Errorf("job is required")
+	}
+	correlator, err := buildCorrelator(job.JobName, job.JobID)
+	if err != nil {
+		return err
+	}
+	return jm.PurgeJob(correlator)
+}
+
 // CreateJobManager creates a job manager from a profile manager
 func CreateJobManager(pm *profile.ZOSMFProfileManager, profileName string) (*ZOSMFJobManager, error) {
 	zosmfProfile, err := pm.GetZOSMFProfile(profileName)
@@ -29,27 +135,33 @@ func CreateJobManager(pm *profile.ZOSMFProfileManager, profileName string) (*ZOS
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	return NewJobManager(session), nil
+	return New(session), nil
 }
 
 // CreateJobManagerDirect creates a job manager with connection details
+//
+// Deprecated: create the session with profile.CreateSessionDirect and pass it to New.
 func CreateJobManagerDirect(host string, port int, user, password string) (*ZOSMFJobManager, error) {
+	deprecation.Report("jobs.CreateJobManagerDirect", "jobs.New")
 	session, err := profile.CreateSessionDirect(host, port, user, password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	return NewJobManager(session), nil
+	return New(session), nil
 }
 
 // CreateJobManagerDirectWithOptions creates a job manager with extra options
+//
+// Deprecated: create the session with profile.CreateSessionDirectWithOptions and pass it to New.
 func CreateJobManagerDirectWithOptions(host string, port int, user, password string, rejectUnauthorized bool, basePath string) (*ZOSMFJobManager, error) {
+	deprecation.Report("jobs.CreateJobManagerDirectWithOptions", "jobs.New")
 	session, err := profile.CreateSessionDirectWithOptions(host, port, user, password, rejectUnauthorized, basePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	return NewJobManager(session), nil
+	return New(session), nil
 }
 
 // SubmitJobStatement submits a job using a JCL statement
@@ -60,21 +172,20 @@ func (jm *ZOSMFJobManager) SubmitJobStatement(jclStatement string) (*SubmitJobRe
 	return jm.SubmitJob(request)
 }
 
-// SubmitJobFromDataset submits a job from a dataset
-func (jm *ZOSMFJobManager) SubmitJobFromDataset(dataset string, volume string) (*SubmitJobResponse, error) {
-	// Ensure dataset name is properly formatted for z/OSMF
-	// Remove any existing "//" prefix to ensure consistency
-	dataset = strings.TrimPrefix(dataset, "//")
-
-	// z/OSMF automatically adds the user prefix, so we should use relative dataset names
-	// If the dataset name starts with the user ID, remove it to avoid duplication
-	// This is a common pattern in z/OSMF APIs
-	session := jm.session.(*profile.Session)
-	userID := session.User
-	if strings.HasPrefix(dataset, userID+".") {
-		dataset = strings.TrimPrefix(dataset, userID+".")
+// SubmitJobStatementWithSymbols submits a job using a JCL statement and JCL symbol values
+func (jm *ZOSMFJobManager) SubmitJobStatementWithSymbols(jclStatement string, symbols map[string]string) (*SubmitJobResponse, error) {
+	request := &SubmitJobRequest{
+		JobStatement: jclStatement,
+		Symbols:      symbols,
 	}
+	return jm.SubmitJob(request)
+}
 
+// SubmitJobFromDataset submits a job from JCL in a sequential dataset, a member given
+// as "DSN(MEMBER)" or a relative GDG generation such as "DSN(0)". The name is always
+// fully qualified: it is sent as "//'DSN'" and never made relative to the user's
+// prefix. volume is needed only for uncataloged datasets.
+func (jm *ZOSMFJobManager) SubmitJobFromDataset(dataset string, volume string) (*SubmitJobResponse, error) {
 	request := &SubmitJobRequest{
 		JobDataSet: dataset,
 		Volume:     volume,
@@ -82,7 +193,23 @@ func (jm *ZOSMFJobManager) SubmitJobFromDataset(dataset string, volume string) (
 	return jm.SubmitJob(request)
 }
 
-// SubmitJobFromLocalFile submits a job from a local file
+// SubmitJobFromDatasetMember submits a job from JCL in a member of a partitioned dataset
+func (jm *ZOSMFJobManager) SubmitJobFromDatasetMember(dataset, member, volume string) (*SubmitJobResponse, error) {
+	if member == "" {
+		return nil, fmt.Errorf("member name is required")
+	}
+	return jm.SubmitJobFromDataset(fmt.Sprintf("%s(%s)", dataset, member), volume)
+}
+
+// SubmitJobFromUSSFile submits a job from JCL in a z/OS UNIX file, given by its absolute path
+func (jm *ZOSMFJobManager) SubmitJobFromUSSFile(path string) (*SubmitJobResponse, error) {
+	return jm.SubmitJob(&SubmitJobRequest{JobUSSFile: path})
+}
+
+// SubmitJobFromLocalFile reads JCL from a file on this machine and submits it.
+// A relative localFile is resolved against directory, and extension is added when
+// the file name has none; both may be empty. Use SubmitJobFromUSSFile for JCL that
+// is already on the host.
 func (jm *ZOSMFJobManager) SubmitJobFromLocalFile(localFile, directory, extension string) (*SubmitJobResponse, error) {
 	request := &SubmitJobRequest{
 		JobLocalFile: localFile,
@@ -92,43 +219,256 @@ func (jm *ZOSMFJobManager) SubmitJobFromLocalFile(localFile, directory, extensio
 	return jm.SubmitJob(request)
 }
 
-// WaitForJobCompletion waits for a job to complete and returns the final status
-func (jm *ZOSMFJobManager) WaitForJobCompletion(correlator string, timeout time.Duration, pollInterval time.Duration) (string, error) {
-	startTime := time.Now()
+// readLocalJCL reads the JCL of a local file for submission
+func readLocalJCL(localFile, directory, extension string) ([]byte, error) {
+	path := localFile
+	if directory != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(directory, path)
+	}
+	if extension != "" && filepath.Ext(path) == "" {
+		path += "." + strings.TrimPrefix(extension, ".")
+	}
+
+	jcl, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local JCL file: %w", err)
+	}
+	if len(bytes.TrimSpace(jcl)) == 0 {
+		return nil, fmt.Errorf("local JCL file %s is empty", path)
+	}
+	return jcl, nil
+}
+
+// SubmitJobFromReader reads JCL from r and submits it with the given options
+func (jm *ZOSMFJobManager) SubmitJobFromReader(r io.Reader, opts *SubmitOptions) (*SubmitJobResponse, error) {
+	jcl, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JCL: %w", err)
+	}
+
+	request := &SubmitJobRequest{
+		JobStatement: string(jcl),
+	}
+	if opts != nil {
+		request.InternalReaderClass = opts.InternalReaderClass
+		request.InternalReaderRecfm = opts.InternalReaderRecfm
+		request.InternalReaderLrecl = opts.InternalReaderLrecl
+		request.UserCorrelator = opts.UserCorrelator
+		request.Symbols = opts.Symbols
+	}
+
+	if err := ValidateJobRequest(request); err != nil {
+		return nil, err
+	}
+	return jm.SubmitJob(request)
+}
+
+// RunJCLPollInterval is how often RunJCL checks whether its job has finished
+const RunJCLPollInterval = 2 * time.Second
+
+// RunJCL submits JCL, waits up to timeout for the job to finish and returns its
+// return code (e.g. "CC 0000" or "ABEND S0C4")
+func (jm *ZOSMFJobManager) RunJCL(jcl string, timeout time.Duration) (string, error) {
+	resp, err := jm.SubmitJobStatement(jcl)
+	if err != nil {
+		return "", fmt.Errorf("failed to submit job: %w", err)
+	}
+
+	correlator := resp.Correlator()
+	if _, err := jm.WaitForJobCompletion(correlator, timeout, RunJCLPollInterval); err != nil {
+		return "", err
+	}
+
+	job, err := jm.GetJob(correlator)
+	if err != nil {
+		return "", fmt.Errorf("failed to get job status: %w", err)
+	}
+	return job.RetCode, nil
+}
+
+// isJobComplete checks if a job status indicates completion.
+// A return code in place of a status also means the job has finished.
+func isJobComplete(status string) bool {
+	if ParseJobStatus(status) == JobStatusOutput {
+		return true
+	}
+	_, err := ParseReturnCode(status)
+	return err == nil
+}
+
+// ParseJobStatus normalizes a z/OSMF job status, returning "" for unknown values
+func ParseJobStatus(status string) JobStatus {
+	switch JobStatus(strings.ToUpper(strings.TrimSpace(status))) {
+	case JobStatusInput:
+		return JobStatusInput
+	case JobStatusActive:
+		return JobStatusActive
+	case JobStatusOutput:
+		return JobStatusOutput
+	}
+	return ""
+}
 
-	for {
-		// Check if timeout exceeded
-		if time.Since(startTime) > timeout {
-			return "", fmt.Errorf("timeout waiting for job %s to complete", correlator)
+// ParseReturnCode parses a z/OSMF retcode such as "CC 0004", "ABEND S0C4",
+// "ABEND U4038", "JCL ERROR" or "CANCELED"
+func ParseReturnCode(retcode string) (*ReturnCode, error) {
+	raw := retcode
+	retcode = strings.ToUpper(strings.TrimSpace(retcode))
+	if retcode == "" {
+		return nil, fmt.Errorf("empty return code")
+	}
+
+	fields := strings.Fields(retcode)
+	switch {
+	case fields[0] == "CC":
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid return code: %s", raw)
 		}
+		code, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition code in return code: %s", raw)
+		}
+		return &ReturnCode{Kind: ReturnCodeCC, Code: code, Raw: raw}, nil
 
-		// Get job status
-		status, err := jm.GetJobStatus(correlator)
+	case fields[0] == "ABEND":
+		rc := &ReturnCode{Kind: ReturnCodeAbend, Raw: raw}
+		if len(fields) == 1 {
+			return rc, nil
+		}
+		abend := fields[1]
+		var code int64
+		var err error
+		switch abend[0] {
+		case 'S':
+			code, err = strconv.ParseInt(abend[1:], 16, 32)
+		case 'U':
+			code, err = strconv.ParseInt(abend[1:], 10, 32)
+		default:
+			err = fmt.Errorf("unknown abend type")
+		}
 		if err != nil {
-			return "", fmt.Errorf("failed to get job status: %w", err)
+			return nil, fmt.Errorf("invalid abend code in return code: %s", raw)
 		}
+		rc.Code = int(code)
+		rc.Abend = abend
+		return rc, nil
+
+	case retcode == "CANCELED" || retcode == "CANCELLED":
+		return &ReturnCode{Kind: ReturnCodeCanceled, Raw: raw}, nil
+	}
 
-		// Check if job is complete
-		if isJobComplete(status) {
-			return status, nil
+	for _, kind := range []ReturnCodeKind{ReturnCodeJCLError, ReturnCodeSecError, ReturnCodeConvError, ReturnCodeSystemFail} {
+		if retcode == string(kind) {
+			return &ReturnCode{Kind: kind, Raw: raw}, nil
 		}
+	}
+
+	return nil, fmt.Errorf("unrecognized return code: %s", raw)
+}
 
-		// Wait before next poll
-		time.Sleep(pollInterval)
+// IsComplete reports whether the job has finished executing
+func (j *Job) IsComplete() bool {
+	return ParseJobStatus(j.Status) == JobStatusOutput
+}
+
+// Succeeded reports whether the job finished with a condition code of at most maxCC
+func (j *Job) Succeeded(maxCC int) bool {
+	if !j.IsComplete() {
+		return false
+	}
+	rc, err := ParseReturnCode(j.RetCode)
+	if err != nil {
+		return false
 	}
+	return rc.Kind == ReturnCodeCC && rc.Code <= maxCC
 }
 
-// isJobComplete checks if a job status indicates completion
-func isJobComplete(status string) bool {
-	completedStatuses := []string{"OUTPUT", "CC 0000", "CC 0001", "CC 0002", "CC 0003", "CC 0004", "ABEND"}
-	status = strings.ToUpper(status)
+// execTimeLayouts are the timestamp formats z/OSMF uses for exec-* fields
+var execTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000",
+	"2006-01-02T15:04:05",
+}
+
+// ParseExecTime parses an exec-submitted/exec-started/exec-ended value.
+// An empty value (a job that has not reached that point yet) returns the zero time.
+func ParseExecTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range execTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid exec time: %s", value)
+}
+
+// SubmittedTime returns the parsed exec-submitted time, or the zero time when it is not set
+func (j *Job) SubmittedTime() (time.Time, error) {
+	return ParseExecTime(j.ExecSubmitted)
+}
+
+// StartedTime returns the parsed exec-started time, or the zero time when it is not set
+func (j *Job) StartedTime() (time.Time, error) {
+	return ParseExecTime(j.ExecStarted)
+}
+
+// EndedTime returns the parsed exec-ended time, or the zero time when it is not set
+func (j *Job) EndedTime() (time.Time, error) {
+	return ParseExecTime(j.ExecEnded)
+}
 
-	for _, completedStatus := range completedStatuses {
-		if strings.Contains(status, completedStatus) {
-			return true
+// GetJobsSince retrieves jobs of an owner that were submitted at or after since
+func (jm *ZOSMFJobManager) GetJobsSince(owner string, since time.Time, maxJobs int) (*JobList, error) {
+	jobList, err := jm.ListJobs(&JobFilter{
+		Owner:    owner,
+		MaxJobs:  maxJobs,
+		ExecData: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &JobList{Jobs: []Job{}, Truncated: jobList.Truncated}
+	for _, job := range jobList.Jobs {
+		submitted, err := job.SubmittedTime()
+		if err != nil {
+			return nil, fmt.Errorf("job %s(%s): %w", job.JobName, job.JobID, err)
+		}
+		if submitted.IsZero() || submitted.Before(since) {
+			continue
 		}
+		result.Jobs = append(result.Jobs, job)
 	}
-	return false
+	return result, nil
+}
+
+// GetJobsByCorrelatorPrefix retrieves the session user's jobs whose user correlator starts with prefix.
+// z/OSMF only matches full user correlators, so the prefix is applied to the listed jobs.
+func (jm *ZOSMFJobManager) GetJobsByCorrelatorPrefix(prefix string, maxJobs int) (*JobList, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("correlator prefix is required")
+	}
+
+	jobList, err := jm.ListJobs(&JobFilter{MaxJobs: maxJobs})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &JobList{Jobs: []Job{}, Truncated: jobList.Truncated}
+	for _, job := range jobList.Jobs {
+		// The user portion follows the last ':' of the job correlator
+		sep := strings.LastIndex(job.JobCorrelator, ":")
+		if sep < 0 {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(job.JobCorrelator[sep+1:]), prefix) {
+			result.Jobs = append(result.Jobs, job)
+		}
+	}
+	return result, nil
 }
 
 // GetJobsByOwner retrieves jobs owned by a specific user
@@ -160,42 +500,9 @@ func (jm *ZOSMFJobManager) GetJobsByStatus(status string, maxJobs int) (*JobList
 
 // GetJobOutput retrieves the output of a completed job
 func (jm *ZOSMFJobManager) GetJobOutput(correlator string) (map[string]string, error) {
-	var jobName, jobID string
-	var err error
-
-	// Check if it's already in correlator format (jobname:jobid)
-	if strings.Contains(correlator, ":") {
-		// Parse correlator to get jobname and jobid
-		jobName, jobID, err = parseCorrelator(correlator)
-		if err != nil {
-			return nil, fmt.Errorf("invalid correlator format: %w", err)
-		}
-	} else {
-		// If it's just a job ID, we need to find the job first
-		jobFilter := &JobFilter{
-			JobID:   correlator,
-			MaxJobs: 100,
-		}
-
-		jobList, err := jm.ListJobs(jobFilter)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find job with ID %s: %w", correlator, err)
-		}
-
-		// Find the job with the specified job ID
-		found := false
-		for _, job := range jobList.Jobs {
-			if job.JobID == correlator {
-				jobName = job.JobName
-				jobID = job.JobID
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			return nil, fmt.Errorf("job with ID %s not found", correlator)
-		}
+	jobName, jobID, err := jm.resolveJobNameID(correlator)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get spool files
@@ -207,7 +514,7 @@ func (jm *ZOSMFJobManager) GetJobOutput(correlator string) (map[string]string, e
 	// Get content for each spool file
 	output := make(map[string]string)
 	for _, spoolFile := range spoolFiles {
-		content, err := jm.GetSpoolFileContent(jobName, jobID, spoolFile.ID)
+		content, err := jm.GetSpoolFileContentWithOptions(jobName, jobID, spoolFile.ID, nil)
 		if err != nil {
 			// Log error but continue with other files
 			continue
@@ -220,62 +527,42 @@ func (jm *ZOSMFJobManager) GetJobOutput(correlator string) (map[string]string, e
 
 // GetJobOutputByDDName retrieves the output of a specific DD name for a job
 func (jm *ZOSMFJobManager) GetJobOutputByDDName(correlator, ddName string) (string, error) {
-	var jobName, jobID string
-	var err error
-
-	// Check if it's already in correlator format (jobname:jobid)
-	if strings.Contains(correlator, ":") {
-		// Parse correlator to get jobname and jobid
-		jobName, jobID, err = parseCorrelator(correlator)
-		if err != nil {
-			return "", fmt.Errorf("invalid correlator format: %w", err)
-		}
-	} else {
-		// If it's just a job ID, we need to find the job first
-		jobFilter := &JobFilter{
-			JobID:   correlator,
-			MaxJobs: 100,
-		}
-
-		jobList, err := jm.ListJobs(jobFilter)
-		if err != nil {
-			return "", fmt.Errorf("failed to find job with ID %s: %w", correlator, err)
-		}
-
-		// Find the job with the specified job ID
-		found := false
-		for _, job := range jobList.Jobs {
-			if job.JobID == correlator {
-				jobName = job.JobName
-				jobID = job.JobID
-				found = true
-				break
-			}
-		}
+	jobName, jobID, err := jm.resolveJobNameID(correlator)
+	if err != nil {
+		return "", err
+	}
 
-		if !found {
-			return "", fmt.Errorf("job with ID %s not found", correlator)
-		}
+	spoolFile, err := jm.GetSpoolFileByDDName(jobName, jobID, ddName)
+	if err != nil {
+		return "", fmt.Errorf("failed to find DD %s for job %s: %w", ddName, correlator, err)
 	}
 
-	// Get spool files
-	spoolFiles, err := jm.GetSpoolFiles(jobName, jobID)
+	content, err := jm.GetSpoolFileContentWithOptions(jobName, jobID, spoolFile.ID, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to get spool files: %w", err)
+		return "", fmt.Errorf("failed to get content for DD %s: %w", ddName, err)
 	}
+	return content, nil
+}
 
-	// Find the spool file with the specified DD name
-	for _, spoolFile := range spoolFiles {
-		if spoolFile.DDName == ddName {
-			content, err := jm.GetSpoolFileContent(jobName, jobID, spoolFile.ID)
-			if err != nil {
-				return "", fmt.Errorf("failed to get content for DD %s: %w", ddName, err)
-			}
-			return content, nil
+// MaxInternalReaderLrecl is the largest record length the internal reader accepts
+const MaxInternalReaderLrecl = 32760
+
+// validateInternalReader checks the internal reader record format and length of a submission
+func validateInternalReader(request *SubmitJobRequest) error {
+	if request.InternalReaderRecfm != "" {
+		switch strings.ToUpper(request.InternalReaderRecfm) {
+		case "F", "V":
+		default:
+			return fmt.Errorf("internal reader recfm must be F or V, got: %s", request.InternalReaderRecfm)
 		}
 	}
-
-	return "", fmt.Errorf("DD name %s not found for job %s", ddName, correlator)
+	if request.InternalReaderLrecl < 0 {
+		return fmt.Errorf("internal reader lrecl cannot be negative")
+	}
+	if request.InternalReaderLrecl > MaxInternalReaderLrecl {
+		return fmt.Errorf("internal reader lrecl cannot exceed %d, got: %d", MaxInternalReaderLrecl, request.InternalReaderLrecl)
+	}
+	return nil
 }
 
 // ValidateJobRequest validates a job submission request
@@ -285,8 +572,8 @@ func ValidateJobRequest(request *SubmitJobRequest) error {
 	}
 
 	// Check that at least one job source is specified
-	if request.JobStatement == "" && request.JobDataSet == "" && request.JobLocalFile == "" {
-		return fmt.Errorf("at least one job source must be specified (jobStatement, jobDataSet, or jobLocalFile)")
+	if request.JobStatement == "" && request.JobDataSet == "" && request.JobUSSFile == "" && request.JobLocalFile == "" {
+		return fmt.Errorf("at least one job source must be specified (jobStatement, jobDataSet, jobUSSFile, or jobLocalFile)")
 	}
 
 	// Validate job statement
@@ -298,14 +585,283 @@ func ValidateJobRequest(request *SubmitJobRequest) error {
 
 	// Validate dataset name
 	if request.JobDataSet != "" {
-		if !isValidDatasetName(request.JobDataSet) {
-			return fmt.Errorf("invalid dataset name: %s", request.JobDataSet)
+		if _, _, err := parseDatasetReference(request.JobDataSet); err != nil {
+			return err
+		}
+	}
+
+	// USS files are absolute z/OS UNIX paths
+	if request.JobUSSFile != "" && (!strings.HasPrefix(request.JobUSSFile, "/") || strings.HasPrefix(request.JobUSSFile, "//")) {
+		return fmt.Errorf("USS file must be an absolute path: %s", request.JobUSSFile)
+	}
+
+	if err := validateInternalReader(request); err != nil {
+		return err
+	}
+
+	if err := ValidateJCLSymbols(request.Symbols); err != nil {
+		return err
+	}
+	if err := ValidateUserCorrelator(request.UserCorrelator); err != nil {
+		return err
+	}
+
+	// Fixed-length records cannot be longer than the lrecl
+	if request.JobStatement != "" && request.InternalReaderLrecl > 0 && strings.ToUpper(request.InternalReaderRecfm) == "F" {
+		for i, line := range strings.Split(strings.TrimRight(request.JobStatement, "\r\n"), "\n") {
+			if len(strings.TrimSuffix(line, "\r")) > request.InternalReaderLrecl {
+				return fmt.Errorf("JCL line %d is longer than lrecl %d", i+1, request.InternalReaderLrecl)
+			}
 		}
 	}
 
 	return nil
 }
 
+// ResubmitJob submits the JCL of an earlier job again
+func (jm *ZOSMFJobManager) ResubmitJob(correlator string) (*SubmitJobResponse, error) {
+	result, err := jm.ResubmitJobWithOptions(correlator, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result.Response, nil
+}
+
+// ResubmitJobWithOptions fetches the JCL of an earlier job, applies the replacements
+// in opts and submits it, unless opts.DryRun is set
+func (jm *ZOSMFJobManager) ResubmitJobWithOptions(correlator string, opts *ResubmitOptions) (*ResubmitResult, error) {
+	if opts == nil {
+		opts = &ResubmitOptions{}
+	}
+
+	jcl, err := jm.GetJobJCL(correlator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve JCL: %w", err)
+	}
+	if strings.TrimSpace(jcl) == "" {
+		return nil, fmt.Errorf("job %s has no JCL to resubmit", correlator)
+	}
+
+	result := &ResubmitResult{JCL: applyJCLReplacements(jcl, opts.Replacements)}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	result.Response, err = jm.SubmitJobStatement(result.JCL)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyJCLReplacements replaces every key of replacements in jcl, longest key first
+// so overlapping keys behave predictably
+func applyJCLReplacements(jcl string, replacements map[string]string) string {
+	if len(replacements) == 0 {
+		return jcl
+	}
+
+	keys := make([]string, 0, len(replacements))
+	for key := range replacements {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	pairs := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		pairs = append(pairs, key, replacements[key])
+	}
+	return strings.NewReplacer(pairs...).Replace(jcl)
+}
+
+// ValidateRecordRange validates an X-IBM-Record-Range value ("SSS-EEE" or "SSS,NNN")
+func ValidateRecordRange(recordRange string) error {
+	_, _, err := parseRecordRange(recordRange)
+	return err
+}
+
+// parseRecordRange returns the first record and the record count of an X-IBM-Record-Range value
+func parseRecordRange(recordRange string) (int, int, error) {
+	sep := strings.IndexAny(recordRange, "-,")
+	if sep <= 0 || sep == len(recordRange)-1 {
+		return 0, 0, fmt.Errorf("invalid record range: %s (expected SSS-EEE or SSS,NNN)", recordRange)
+	}
+
+	first, err := strconv.Atoi(recordRange[:sep])
+	if err != nil || first < 0 {
+		return 0, 0, fmt.Errorf("invalid record range: %s (expected SSS-EEE or SSS,NNN)", recordRange)
+	}
+	second, err := strconv.Atoi(recordRange[sep+1:])
+	if err != nil || second < 0 {
+		return 0, 0, fmt.Errorf("invalid record range: %s (expected SSS-EEE or SSS,NNN)", recordRange)
+	}
+	if recordRange[sep] == '-' {
+		if second < first {
+			return 0, 0, fmt.Errorf("invalid record range: %s (end is before start)", recordRange)
+		}
+		return first, second - first + 1, nil
+	}
+
+	return first, second, nil
+}
+
+// GetSpoolFileTail copies the last n records of a spool file to w. When z/OSMF reports the
+// record count only those records are requested; otherwise the file is streamed and only
+// the last n lines are written.
+func (jm *ZOSMFJobManager) GetSpoolFileTail(jobName, jobID string, spoolID int, n int, w io.Writer) (int64, error) {
+	return jm.spoolFileTail(context.Background(), jobName, jobID, spoolID, n, w)
+}
+
+// MaxJCLSymbolValueLength is the longest value z/OSMF accepts for a JCL symbol
+const MaxJCLSymbolValueLength = 255
+
+// MaxUserCorrelatorLength is the longest user correlator z/OSMF accepts
+const MaxUserCorrelatorLength = 32
+
+// ValidateUserCorrelator checks that a user correlator fits the X-IBM-User-Correlator header.
+// An empty correlator is valid and means none is sent.
+func ValidateUserCorrelator(correlator string) error {
+	if len(correlator) > MaxUserCorrelatorLength {
+		return fmt.Errorf("user correlator cannot exceed %d characters, got: %d", MaxUserCorrelatorLength, len(correlator))
+	}
+	for _, char := range correlator {
+		if char <= ' ' || char > '~' || char == ':' {
+			return fmt.Errorf("user correlator contains invalid character %q", char)
+		}
+	}
+	return nil
+}
+
+// NewUserCorrelator returns a random 32-character user correlator
+func NewUserCorrelator() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate user correlator: %w", err)
+	}
+	// Start with a letter, as job correlators do, and keep within 32 characters
+	return "Z" + strings.ToUpper(hex.EncodeToString(b))[:MaxUserCorrelatorLength-1], nil
+}
+
+// VerifySubmission looks for a job submitted with the given user correlator.
+// It returns nil without an error when no such job exists, so a submission
+// whose response was lost can be retried safely.
+func (jm *ZOSMFJobManager) VerifySubmission(userCorrelator string) (*Job, error) {
+	if userCorrelator == "" {
+		return nil, fmt.Errorf("user correlator is required")
+	}
+
+	jobList, err := jm.ListJobs(&JobFilter{
+		Owner:          "*",
+		UserCorrelator: userCorrelator,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up user correlator %s: %w", userCorrelator, err)
+	}
+
+	switch len(jobList.Jobs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &jobList.Jobs[0], nil
+	default:
+		return nil, fmt.Errorf("user correlator %s matched %d jobs", userCorrelator, len(jobList.Jobs))
+	}
+}
+
+// SubmitJobWithRetry submits a job, retrying up to maxAttempts times when the request
+// fails before a response arrives. Retries wait request.DefaultRetryBackoff, doubling
+// each time. The request is not modified: a copy is submitted, with a generated user
+// correlator when none is set (set one to know the value used), and before each retry
+// VerifySubmission checks whether the earlier attempt reached JES, so the job is never
+// submitted twice.
+func (jm *ZOSMFJobManager) SubmitJobWithRetry(submitRequest *SubmitJobRequest, maxAttempts int) (*SubmitJobResponse, error) {
+	if submitRequest == nil {
+		return nil, fmt.Errorf("job request cannot be nil")
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	submission := *submitRequest
+	if submission.UserCorrelator == "" {
+		correlator, err := NewUserCorrelator()
+		if err != nil {
+			return nil, err
+		}
+		submission.UserCorrelator = correlator
+	}
+
+	var lastErr error
+	wait := request.DefaultRetryBackoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(wait)
+			wait *= 2
+
+			job, err := jm.VerifySubmission(submission.UserCorrelator)
+			if err != nil {
+				return nil, err
+			}
+			if job != nil {
+				return &SubmitJobResponse{JobID: job.JobID, JobName: job.JobName, Owner: job.Owner, Status: job.Status, URL: job.URL, JobCorrelator: job.JobCorrelator}, nil
+			}
+		}
+
+		resp, err := jm.SubmitJob(&submission)
+		if err == nil {
+			return resp, nil
+		}
+		// Only transport failures leave the outcome unknown; API errors are final
+		var urlErr *url.Error
+		if !errors.As(err, &urlErr) {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("job submission failed after %d attempts: %w", maxAttempts, lastErr)
+}
+
+// ValidateJCLSymbols validates JCL symbol names (1-8 characters, starting with
+// a letter or national character) and values (at most 255 characters)
+func ValidateJCLSymbols(symbols map[string]string) error {
+	for name, value := range symbols {
+		if !isValidJCLName(name) {
+			return fmt.Errorf("invalid JCL symbol name: %q", name)
+		}
+		if len(value) > MaxJCLSymbolValueLength {
+			return fmt.Errorf("JCL symbol %s value cannot exceed %d characters", name, MaxJCLSymbolValueLength)
+		}
+	}
+	return nil
+}
+
+// isValidJCLName checks a JCL name (job, step or symbol): 1-8 characters,
+// starting with a letter or national character
+func isValidJCLName(name string) bool {
+	if len(name) == 0 || len(name) > 8 {
+		return false
+	}
+	for i, char := range name {
+		isNational := char == '@' || char == '#' || char == '$'
+		isAlpha := char >= 'A' && char <= 'Z'
+		isDigit := char >= '0' && char <= '9'
+		if i == 0 && !isAlpha && !isNational {
+			return false
+		}
+		if !isAlpha && !isNational && !isDigit {
+			return false
+		}
+	}
+	return true
+}
+
 // isValidDatasetName validates a z/OS dataset name
 func isValidDatasetName(dataset string) bool {
 	// Basic validation for z/OS dataset names
@@ -337,6 +893,63 @@ func isValidDatasetChar(char rune) bool {
 		char == '@' || char == '#' || char == '$' || char == '-' || char == '.'
 }
 
+// isValidMemberName checks a partitioned dataset member name: 1-8 characters,
+// not starting with a digit
+func isValidMemberName(member string) bool {
+	if len(member) == 0 || len(member) > 8 {
+		return false
+	}
+	if member[0] >= '0' && member[0] <= '9' {
+		return false
+	}
+	for _, char := range member {
+		if !(char >= 'A' && char <= 'Z') && !(char >= '0' && char <= '9') && char != '@' && char != '#' && char != '$' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseDatasetReference splits a dataset reference, "DSN", "DSN(MEMBER)" or a relative
+// GDG generation such as "GDG(+1)", into its uppercased dataset and member (or generation)
+// names. The reference may be quoted and start with //.
+func parseDatasetReference(reference string) (string, string, error) {
+	ref := strings.TrimPrefix(strings.TrimSpace(reference), "//")
+	if len(ref) >= 2 && strings.HasPrefix(ref, "'") && strings.HasSuffix(ref, "'") {
+		ref = ref[1 : len(ref)-1]
+	}
+	ref = strings.ToUpper(ref)
+
+	dataset, member := ref, ""
+	if open := strings.Index(ref, "("); open >= 0 {
+		if !strings.HasSuffix(ref, ")") {
+			return "", "", fmt.Errorf("invalid dataset name: %s", reference)
+		}
+		dataset, member = ref[:open], ref[open+1:len(ref)-1]
+		if !isValidMemberName(member) && !gdg.IsRelativeGeneration(member) {
+			return "", "", fmt.Errorf("invalid member name in %s", reference)
+		}
+	}
+	if !isValidDatasetName(dataset) {
+		return "", "", fmt.Errorf("invalid dataset name: %s", reference)
+	}
+	return dataset, member, nil
+}
+
+// datasetFileReference builds the fully qualified reference z/OSMF expects for JCL in
+// a dataset: "//'DSN'" or "//'DSN(MEMBER)'". Unquoted names would be taken as relative
+// to the user's TSO prefix.
+func datasetFileReference(reference string) (string, error) {
+	dataset, member, err := parseDatasetReference(reference)
+	if err != nil {
+		return "", err
+	}
+	if member != "" {
+		dataset += "(" + member + ")"
+	}
+	return "//'" + dataset + "'", nil
+}
+
 // CreateSimpleJobStatement creates a simple JCL job statement
 func CreateSimpleJobStatement(jobName, account, user, msgClass, msgLevel string) string {
 	if jobName == "" {
@@ -359,16 +972,25 @@ func CreateSimpleJobStatement(jobName, account, user, msgClass, msgLevel string)
 		jobName, account, user, msgClass, msgLevel)
 }
 
-// CreateJobWithStep creates a complete JCL job with a step
+// CreateJobWithStep creates a complete JCL job with a step. Its JOB statement is neither
+// validated nor continued past column 71.
+//
+// Deprecated: use JobCardBuilder.BuildJob, which validates the job and continues long statements.
 func CreateJobWithStep(jobName, account, user, msgClass, msgLevel, stepName, pgm string, ddStatements []string) string {
+	deprecation.Report("jobs.CreateJobWithStep", "jobs.(*JobCardBuilder).BuildJob")
 	jobStatement := CreateSimpleJobStatement(jobName, account, user, msgClass, msgLevel)
 
-	jcl := jobStatement + "\n"
-
 	if stepName == "" {
 		stepName = "STEP1"
 	}
 
+	return appendJobStep(jobStatement, stepName, pgm, ddStatements)
+}
+
+// appendJobStep adds an EXEC statement and its DD statements to a JOB statement
+func appendJobStep(jobStatement, stepName, pgm string, ddStatements []string) string {
+	jcl := jobStatement + "\n"
+
 	jcl += fmt.Sprintf("//%s EXEC PGM=%s\n", stepName, pgm)
 
 	for _, ddStatement := range ddStatements {
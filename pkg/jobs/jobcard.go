@@ -0,0 +1,192 @@
+package jobs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JCL statements occupy columns 1-71; column 72 is the continuation column
+// and 73-80 hold sequence numbers
+const (
+	MaxJCLStatementColumn   = 71
+	jclContinuationIndent   = "//             " // Continued parameters start in column 16
+	maxProgrammerNameLength = 20
+)
+
+// JobCardBuilder builds a JOB statement. Empty fields are omitted, except the job
+// name, accounting information and programmer name, which default like
+// CreateSimpleJobStatement.
+type JobCardBuilder struct {
+	JobName    string // 1-8 characters, defaults to GOJOB
+	Account    string // Accounting information, parentheses are added when missing
+	Programmer string // Programmer name, at most 20 characters, quoted automatically
+	Class      string // Job class, e.g. "A"
+	MsgClass   string // Output class for the job log, e.g. "X"
+	MsgLevel   string // e.g. "(1,1)"
+	Region     string // e.g. "0M" or "64M"
+	Time       string // e.g. "1440", "(5,30)" or "NOLIMIT"
+	Notify     string // User ID to notify, e.g. "&SYSUID"
+	TypRun     string // SCAN, HOLD, JCLHOLD or COPY
+	// Extra holds any other keyword parameters (e.g. "COND": "(4,LT)"),
+	// written after the others in key order
+	Extra map[string]string
+}
+
+// builtinJobKeywords are the JOB parameters JobCardBuilder has fields for
+var builtinJobKeywords = map[string]bool{
+	"CLASS": true, "MSGCLASS": true, "MSGLEVEL": true, "REGION": true,
+	"TIME": true, "NOTIFY": true, "TYPRUN": true,
+}
+
+// Build returns the JOB statement, continued onto further lines where it does
+// not fit in columns 1-71. Lines are broken after a comma between parameters.
+func (b *JobCardBuilder) Build() (string, error) {
+	jobName := b.JobName
+	if jobName == "" {
+		jobName = "GOJOB"
+	}
+	if !isValidJCLName(jobName) {
+		return "", fmt.Errorf("invalid job name %q: must be 1-8 characters A-Z, 0-9, @, # or $, not starting with a digit", jobName)
+	}
+
+	params, err := b.parameters()
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	line := "//" + jobName + " JOB "
+	lineHasParams := false
+	for i, param := range params {
+		if i < len(params)-1 {
+			param += ","
+		}
+		if lineHasParams && len(line)+len(param) > MaxJCLStatementColumn {
+			lines = append(lines, line)
+			line = jclContinuationIndent
+		}
+		line += param
+		lineHasParams = true
+		if len(line) > MaxJCLStatementColumn {
+			return "", fmt.Errorf("JOB parameter %q is too long to fit on a JCL line", strings.TrimSuffix(param, ","))
+		}
+	}
+	lines = append(lines, line)
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// parameters returns the validated positional and keyword parameters in order
+func (b *JobCardBuilder) parameters() ([]string, error) {
+	account := b.Account
+	if account == "" {
+		account = "ACCT"
+	}
+	if !strings.HasPrefix(account, "(") {
+		account = "(" + account + ")"
+	}
+
+	programmer := b.Programmer
+	if programmer == "" {
+		programmer = "USER"
+	}
+	if len(programmer) > maxProgrammerNameLength {
+		return nil, fmt.Errorf("programmer name cannot exceed %d characters: %q", maxProgrammerNameLength, programmer)
+	}
+	programmer = "'" + strings.ReplaceAll(programmer, "'", "''") + "'"
+
+	params := []string{account, programmer}
+
+	keywords := []struct {
+		name  string
+		value string
+	}{
+		{"CLASS", b.Class},
+		{"MSGCLASS", b.MsgClass},
+		{"MSGLEVEL", b.MsgLevel},
+		{"REGION", b.Region},
+		{"TIME", b.Time},
+		{"NOTIFY", b.Notify},
+		{"TYPRUN", b.TypRun},
+	}
+	for _, keyword := range keywords {
+		if keyword.value != "" {
+			params = append(params, keyword.name+"="+keyword.value)
+		}
+	}
+
+	if err := b.validateKeywords(); err != nil {
+		return nil, err
+	}
+
+	extraNames := make([]string, 0, len(b.Extra))
+	for name := range b.Extra {
+		extraNames = append(extraNames, name)
+	}
+	sort.Strings(extraNames)
+	for _, name := range extraNames {
+		if !isValidJCLName(name) {
+			return nil, fmt.Errorf("invalid JOB parameter name %q", name)
+		}
+		if builtinJobKeywords[name] {
+			return nil, fmt.Errorf("JOB parameter %s must be set with its own field, not Extra", name)
+		}
+		if b.Extra[name] == "" {
+			return nil, fmt.Errorf("JOB parameter %s has no value", name)
+		}
+		params = append(params, name+"="+b.Extra[name])
+	}
+
+	return params, nil
+}
+
+// validateKeywords checks the values of the well-known keyword parameters
+func (b *JobCardBuilder) validateKeywords() error {
+	if b.Class != "" && !isValidOutputClass(b.Class) {
+		return fmt.Errorf("invalid job class %q: must be a single character A-Z or 0-9", b.Class)
+	}
+	if b.MsgClass != "" && !isValidOutputClass(b.MsgClass) {
+		return fmt.Errorf("invalid message class %q: must be a single character A-Z or 0-9", b.MsgClass)
+	}
+	if b.Notify != "" && b.Notify != "&SYSUID" && !isValidJCLName(b.Notify) {
+		return fmt.Errorf("invalid notify user %q", b.Notify)
+	}
+	switch b.TypRun {
+	case "", "SCAN", "HOLD", "JCLHOLD", "COPY":
+	default:
+		return fmt.Errorf("invalid TYPRUN %q: must be SCAN, HOLD, JCLHOLD or COPY", b.TypRun)
+	}
+	return nil
+}
+
+// isValidOutputClass checks a single-character job or output class
+func isValidOutputClass(class string) bool {
+	if len(class) != 1 {
+		return false
+	}
+	c := class[0]
+	return (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// BuildJob creates a complete JCL job with one step: the JOB statement from Build, an
+// EXEC statement running pgm and the DD statements. The step name defaults to STEP1
+// and, like the program name, must be a valid JCL name.
+func (b *JobCardBuilder) BuildJob(stepName, pgm string, ddStatements []string) (string, error) {
+	jobStatement, err := b.Build()
+	if err != nil {
+		return "", err
+	}
+
+	if stepName == "" {
+		stepName = "STEP1"
+	}
+	if !isValidJCLName(stepName) {
+		return "", fmt.Errorf("invalid step name %q", stepName)
+	}
+	if !isValidJCLName(pgm) {
+		return "", fmt.Errorf("invalid program name %q", pgm)
+	}
+
+	return appendJobStep(jobStatement, stepName, pgm, ddStatements), nil
+}
@@ -1,17 +1,28 @@
 package jobs
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+	"github.com/zowe/zowe-client-go-sdk/pkg/zosmftest"
 )
 
 // createTestProfile creates a profile for testing with the given server URL
@@ -51,6 +62,7 @@ func TestNewJobManager(t *testing.T) {
 	jm := NewJobManager(session)
 	assert.NotNil(t, jm)
 	assert.Equal(t, session, jm.session)
+	assert.Same(t, session, jm.Session())
 }
 
 func TestNewJobManagerFromProfile(t *testing.T) {
@@ -190,6 +202,79 @@ func TestGetJob(t *testing.T) {
 	assert.Equal(t, "CC 0000", job.RetCode)
 }
 
+func TestGetJobWithSteps(t *testing.T) {
+	const stepDataJSON = `{
+  "owner": "IBMUSER", "phase": 20, "subsystem": "JES2", "phase-name": "Job is on the hard copy queue",
+  "jobid": "JOB12345", "class": "A", "type": "JOB", "jobname": "COMPILE", "status": "OUTPUT", "retcode": "ABEND S0C4",
+  "step-data": [
+    {"smfid": "SY1", "completion": "CC 0000", "active": false, "step-number": 1, "proc-step-name": "COMPILE",
+     "selected-time": "2024-06-03T10:15:02.120Z", "owner-name": "IBMUSER", "program-name": "IGYCRCTL",
+     "step-name": "COBOL", "path-name": "", "substep-number": 1, "end-time": "2024-06-03T10:15:04.620Z",
+     "abend-reason-code": null, "cpu-time": 0.42},
+    {"smfid": "SY1", "completion": "ABEND S0C4", "active": false, "step-number": 2, "proc-step-name": "GO",
+     "selected-time": "2024-06-03T10:15:04.700Z", "owner-name": "IBMUSER", "program-name": "PAYROLL",
+     "step-name": "RUN", "substep-number": 1, "end-time": "2024-06-03T10:15:05.000Z",
+     "abend-reason-code": "00000011", "cpu-time": "00:00:00.08"},
+    {"step-number": 3, "step-name": "REPORT", "active": false}
+  ]
+}`
+
+	var withSteps bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/restjobs/jobs/COMPILE/JOB12345", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("step-data") == "Y" && withSteps {
+			w.Write([]byte(stepDataJSON))
+			return
+		}
+		// Older z/OSMF levels ignore step-data
+		w.Write([]byte(`{"jobid": "JOB12345", "jobname": "COMPILE", "status": "OUTPUT", "retcode": "ABEND S0C4"}`))
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	job, err := jm.GetJobWithSteps("COMPILE", "JOB12345")
+	require.NoError(t, err)
+	assert.Equal(t, "ABEND S0C4", job.RetCode)
+	assert.Empty(t, job.Steps)
+
+	withSteps = true
+	job, err = jm.GetJobWithSteps("COMPILE", "JOB12345")
+	require.NoError(t, err)
+	require.Len(t, job.Steps, 3)
+
+	compile := job.Steps[0]
+	assert.Equal(t, 1, compile.StepNumber)
+	assert.Equal(t, "COBOL", compile.StepName)
+	assert.Equal(t, "COMPILE", compile.ProcStepName)
+	assert.Equal(t, "IGYCRCTL", compile.ProgramName)
+	assert.Equal(t, "CC 0000", compile.Completion)
+	assert.Equal(t, "0.42", compile.CPUTime)
+	elapsed, ok := compile.Elapsed()
+	require.True(t, ok)
+	assert.Equal(t, 2500*time.Millisecond, elapsed)
+
+	run := job.Steps[1]
+	assert.Equal(t, "ABEND S0C4", run.Completion)
+	assert.Equal(t, "00000011", run.AbendReasonCode)
+	assert.Equal(t, "00:00:00.08", run.CPUTime)
+
+	report := job.Steps[2]
+	assert.Equal(t, "REPORT", report.StepName)
+	assert.Empty(t, report.CPUTime)
+	_, ok = report.Elapsed()
+	assert.False(t, ok)
+
+	// GetJobByNameID does not ask for step data
+	job, err = jm.GetJobByNameID("COMPILE", "JOB12345")
+	require.NoError(t, err)
+	assert.Empty(t, job.Steps)
+}
+
 func TestGetJobStatus(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -337,13 +422,76 @@ func TestSubmitJobFromDataset(t *testing.T) {
 	assert.Equal(t, "JOB001", response.JobID)
 }
 
+func TestSubmitJobFromDatasetQualified(t *testing.T) {
+	var lastBody map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastBody = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&lastBody))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(SubmitJobResponse{JobID: "JOB001", JobName: "TESTJOB"})
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	// The session user is testuser; its HLQ must be kept
+	tests := []struct {
+		dataset string
+		want    string
+	}{
+		{"TEST.JCL", "//'TEST.JCL'"},
+		{"TESTUSER.JCL", "//'TESTUSER.JCL'"},
+		{"testuserx.jcl(myjob)", "//'TESTUSERX.JCL(MYJOB)'"},
+		{"//'USER.JCL(MYJOB)'", "//'USER.JCL(MYJOB)'"},
+		{"//USER.JCL", "//'USER.JCL'"},
+		{"USER.JCL.GDG(0)", "//'USER.JCL.GDG(0)'"},
+		{"user.jcl.gdg(-1)", "//'USER.JCL.GDG(-1)'"},
+		{"//'USER.JCL.GDG(+1)'", "//'USER.JCL.GDG(+1)'"},
+	}
+	for _, tt := range tests {
+		_, err := jm.SubmitJobFromDataset(tt.dataset, "")
+		require.NoError(t, err, tt.dataset)
+		assert.Equal(t, tt.want, lastBody["file"], tt.dataset)
+		assert.NotContains(t, lastBody, "volume")
+	}
+
+	_, err = jm.SubmitJobFromDatasetMember("USER.JCL", "MYJOB", "VOL001")
+	require.NoError(t, err)
+	assert.Equal(t, "//'USER.JCL(MYJOB)'", lastBody["file"])
+	assert.Equal(t, "VOL001", lastBody["volume"])
+
+	// Invalid references fail before a request is made
+	lastBody = nil
+	_, err = jm.SubmitJobFromDatasetMember("USER.JCL", "", "")
+	assert.Error(t, err)
+	_, err = jm.SubmitJobFromDatasetMember("USER.JCL", "TOOLONGNAME", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid member name")
+	_, err = jm.SubmitJobFromDataset("USER.JCL(MYJOB", "")
+	assert.Error(t, err)
+	for _, generation := range []string{"USER.GDG(1)", "USER.GDG(+0)", "USER.GDG(+256)", "USER.GDG(-01)"} {
+		_, err = jm.SubmitJobFromDataset(generation, "")
+		assert.Error(t, err, generation)
+	}
+	assert.Nil(t, lastBody)
+}
+
 func TestCancelJob(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "PUT", r.Method)
-		assert.Equal(t, "/api/v1/restjobs/jobs/JOB001/cancel", r.URL.Path)
+		assert.Equal(t, "/api/v1/restjobs/jobs/TESTJOB1/JOB001", r.URL.Path)
 
-		w.WriteHeader(http.StatusNoContent)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "cancel", body["request"])
+		assert.Equal(t, "2.0", body["version"])
+
+		w.WriteHeader(http.StatusAccepted)
 	}))
 	defer server.Close()
 
@@ -356,7 +504,7 @@ func TestCancelJob(t *testing.T) {
 	jm := NewJobManager(session)
 
 	// Test cancel job
-	err = jm.CancelJob("JOB001")
+	err = jm.CancelJob("TESTJOB1:JOB001")
 	require.NoError(t, err)
 }
 
@@ -384,36 +532,25 @@ func TestDeleteJob(t *testing.T) {
 }
 
 func TestGetSpoolFiles(t *testing.T) {
-	// Create test server
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "/api/v1/restjobs/jobs/TESTJOB/JOB001/files", r.URL.Path)
-
-		// Return mock response
-		spoolFiles := []SpoolFile{
-			{
-				ID:      1,
-				DDName:  "JESMSGLG",
-				Records: 10,
-				Bytes:   1000,
-			},
-			{
-				ID:      2,
-				DDName:  "JESJCL",
-				Records: 5,
-				Bytes:   500,
-			},
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(spoolFiles)
-	}))
-	defer server.Close()
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListSpoolFiles("TESTJOB", "JOB001").Return([]SpoolFile{
+		{
+			ID:      1,
+			DDName:  "JESMSGLG",
+			Records: 10,
+			Bytes:   1000,
+		},
+		{
+			ID:      2,
+			DDName:  "JESJCL",
+			Records: 5,
+			Bytes:   500,
+		},
+	})
 
 	// Create job manager
-	profile := createTestProfile(server.URL)
-
-	session, err := profile.NewSession()
+	session, err := mock.NewSession()
 	require.NoError(t, err)
 
 	jm := NewJobManager(session)
@@ -428,6 +565,33 @@ func TestGetSpoolFiles(t *testing.T) {
 	assert.Equal(t, 2, spoolFiles[1].ID)
 }
 
+func TestGetSpoolFileByDDName(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListSpoolFiles("TESTJOB", "JOB001").Return([]SpoolFile{
+		{ID: 2, DDName: "JESMSGLG"},
+		{ID: 102, DDName: "SYSPRINT", StepName: "STEP1"},
+		{ID: 103, DDName: "SYSPRINT", StepName: "STEP2"},
+	})
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	spoolFile, err := jm.GetSpoolFileByDDName("TESTJOB", "JOB001", "sysprint")
+	require.NoError(t, err)
+	assert.Equal(t, 102, spoolFile.ID)
+	assert.Equal(t, "STEP1", spoolFile.StepName)
+
+	_, err = jm.GetSpoolFileByDDName("TESTJOB", "JOB001", "SYSUT2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DD name SYSUT2 not found for job TESTJOB(JOB001)")
+
+	_, err = jm.GetSpoolFileByDDName("TESTJOB", "JOB001", "")
+	assert.Error(t, err)
+	assert.Len(t, mock.Requests(), 2)
+}
+
 func TestGetSpoolFileContent(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -457,8 +621,8 @@ func TestGetSpoolFileContent(t *testing.T) {
 func TestPurgeJob(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "PUT", r.Method)
-		assert.Equal(t, "/api/v1/restjobs/jobs/JOB001/purge", r.URL.Path)
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/api/v1/restjobs/jobs/TESTJOB1/JOB001", r.URL.Path)
 
 		w.WriteHeader(http.StatusNoContent)
 	}))
@@ -473,8 +637,43 @@ func TestPurgeJob(t *testing.T) {
 	jm := NewJobManager(session)
 
 	// Test purge job
-	err = jm.PurgeJob("JOB001")
+	err = jm.PurgeJob("TESTJOB1:JOB001")
+	require.NoError(t, err)
+}
+
+func TestJobLifecycleAcceptsSameCorrelator(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			// Bare job ID lookup
+			assert.Equal(t, "JOB001", r.URL.Query().Get("jobid"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"jobid":"JOB001","jobname":"TESTJOB1","owner":"TESTUSER","status":"ACTIVE"}]`))
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
 	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	for _, correlator := range []string{"TESTJOB1:JOB001", "JOB001"} {
+		requests = nil
+		require.NoError(t, jm.CancelJob(correlator))
+		require.NoError(t, jm.DeleteJob(correlator))
+		require.NoError(t, jm.PurgeJob(correlator))
+		assert.Equal(t, []string{
+			"PUT /api/v1/restjobs/jobs/TESTJOB1/JOB001",
+			"DELETE /api/v1/restjobs/jobs/TESTJOB1/JOB001",
+			"DELETE /api/v1/restjobs/jobs/TESTJOB1/JOB001",
+		}, requests, correlator)
+	}
+
+	assert.Error(t, jm.CancelJob("TESTJOB1:JOB001:EXTRA"))
 }
 
 func TestIsJobComplete(t *testing.T) {
@@ -491,6 +690,7 @@ func TestIsJobComplete(t *testing.T) {
 	assert.False(t, isJobComplete("ACTIVE"))
 	assert.False(t, isJobComplete("INPUT"))
 	assert.False(t, isJobComplete("RUNNING"))
+	assert.False(t, isJobComplete("OUTPUT1"))
 }
 
 func TestValidateJobRequest(t *testing.T) {
@@ -874,6 +1074,34 @@ func TestWaitForJobCompletionTimeout(t *testing.T) {
 	_, err = jm.WaitForJobCompletion("TESTJOB1:JOB001", 100*time.Millisecond, 50*time.Millisecond)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "timeout waiting for job")
+
+	// No timeout means no time to wait, rather than waiting forever
+	for _, timeout := range []time.Duration{0, -time.Second} {
+		start := time.Now()
+		_, err = jm.WaitForJobCompletion("TESTJOB1:JOB001", timeout, 50*time.Millisecond)
+		assert.ErrorContains(t, err, "timeout waiting for job")
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+	}
+}
+
+func TestWaitForJobCompletionNoTimeout(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Job{JobID: "JOB001", JobName: "TESTJOB", Status: "OUTPUT", RetCode: "CC 0000"})
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	// A job that has already completed is reported from a single check
+	status, err := jm.WaitForJobCompletion("TESTJOB:JOB001", 0, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "OUTPUT", status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
 }
 
 func TestWaitForJobCompletionError(t *testing.T) {
@@ -935,9 +1163,11 @@ func TestSubmitJobWithAllSources(t *testing.T) {
 	assert.Equal(t, "JOB001", response.JobID)
 
 	// Test submit job with local file
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test.jcl"), []byte("//TESTJOB JOB\n"), 0644))
 	request = &SubmitJobRequest{
-		JobLocalFile: "test.jcl",
-		Directory:    "/tmp",
+		JobLocalFile: "test",
+		Directory:    dir,
 		Extension:    "jcl",
 	}
 	response, err = jm.SubmitJob(request)
@@ -1049,3 +1279,2146 @@ func TestGetJobInfoError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "API request failed with status 404")
 }
+
+func TestSubmitJobInternalReaderHeaders(t *testing.T) {
+	// Create test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/api/v1/restjobs/jobs", r.URL.Path)
+
+		// Check internal reader headers
+		assert.Equal(t, "A", r.Header.Get("X-IBM-Intrdr-Class"))
+		assert.Equal(t, "F", r.Header.Get("X-IBM-Intrdr-Recfm"))
+		assert.Equal(t, "80", r.Header.Get("X-IBM-Intrdr-Lrecl"))
+
+		response := SubmitJobResponse{
+			JobID:   "JOB001",
+			JobName: "TESTJOB",
+			Owner:   "testuser",
+			Status:  "INPUT",
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	// Create job manager
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	// Test submit with fixed-form internal reader settings
+	request := &SubmitJobRequest{
+		JobStatement:        "//TESTJOB JOB (ACCT),'USER',MSGCLASS=A                                00010000",
+		InternalReaderClass: "A",
+		InternalReaderRecfm: "f",
+		InternalReaderLrecl: 80,
+	}
+	response, err := jm.SubmitJob(request)
+	require.NoError(t, err)
+	assert.Equal(t, "JOB001", response.JobID)
+}
+
+func TestSubmitJobWithoutInternalReaderHeaders(t *testing.T) {
+	// Create test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// No internal reader headers unless requested
+		assert.Empty(t, r.Header.Get("X-IBM-Intrdr-Class"))
+		assert.Empty(t, r.Header.Get("X-IBM-Intrdr-Recfm"))
+		assert.Empty(t, r.Header.Get("X-IBM-Intrdr-Lrecl"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(SubmitJobResponse{JobID: "JOB001", JobName: "TESTJOB"})
+	}))
+	defer server.Close()
+
+	// Create job manager
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	_, err = jm.SubmitJobStatement("//TESTJOB JOB (ACCT),'USER',MSGCLASS=A")
+	require.NoError(t, err)
+}
+
+func TestValidateJobRequestInternalReader(t *testing.T) {
+	// Valid fixed-form settings
+	err := ValidateJobRequest(&SubmitJobRequest{
+		JobStatement:        "//TESTJOB JOB (ACCT),'USER',MSGCLASS=A",
+		InternalReaderRecfm: "F",
+		InternalReaderLrecl: 80,
+	})
+	assert.NoError(t, err)
+
+	// Invalid record format
+	err = ValidateJobRequest(&SubmitJobRequest{
+		JobStatement:        "//TESTJOB JOB (ACCT),'USER',MSGCLASS=A",
+		InternalReaderRecfm: "FB",
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "internal reader recfm must be F or V")
+
+	// Negative record length
+	err = ValidateJobRequest(&SubmitJobRequest{
+		JobStatement:        "//TESTJOB JOB (ACCT),'USER',MSGCLASS=A",
+		InternalReaderLrecl: -1,
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "internal reader lrecl cannot be negative")
+}
+
+func TestGetSpoolFileContentStream(t *testing.T) {
+	var recordRange string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/restjobs/jobs/TESTJOB/JOB12345/files/2/records", r.URL.Path)
+		recordRange = r.Header.Get("X-IBM-Record-Range")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("LINE 1\nLINE 2\n"))
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	var buf strings.Builder
+	n, err := jm.GetSpoolFileContentStream("TESTJOB", "JOB12345", 2, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(14), n)
+	assert.Equal(t, "LINE 1\nLINE 2\n", buf.String())
+	assert.Empty(t, recordRange)
+
+	buf.Reset()
+	_, err = jm.GetSpoolFileContentStreamWithOptions("TESTJOB", "JOB12345", 2, &SpoolContentOptions{RecordRange: "10-19"}, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, "10-19", recordRange)
+
+	_, err = jm.GetSpoolFileContentStreamWithOptions("TESTJOB", "JOB12345", 2, &SpoolContentOptions{RecordRange: "19-10"}, &buf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid record range")
+}
+
+func TestGetSpoolFileContentWithInfo(t *testing.T) {
+	var maxItems []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/restjobs/jobs/BIGJOB/JOB12345/files":
+			// z/OSMF names the sizes record-count and byte-count
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"id": 2, "ddname": "SYSPRINT", "record-count": 5, "byte-count": 400, "records-url": "https://host/files/2/records"}]`))
+		case "/api/v1/restjobs/jobs/BIGJOB/JOB12345/files/2/records":
+			maxItems = append(maxItems, r.Header.Get("X-IBM-Max-Items"))
+			switch {
+			case r.Header.Get("X-IBM-Record-Range") == "3,10":
+				w.Write([]byte("LINE 4\nLINE 5\n"))
+			case r.Header.Get("X-IBM-Max-Items") == "0":
+				w.Write([]byte("LINE 1\nLINE 2\nLINE 3\nLINE 4\nLINE 5\n"))
+			default:
+				w.Write([]byte("LINE 1\nLINE 2\nLINE 3"))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	files, err := jm.GetSpoolFiles("BIGJOB", "JOB12345")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, 5, files[0].Records)
+	assert.Equal(t, 400, files[0].Bytes)
+	assert.Equal(t, "https://host/files/2/records", files[0].ContentURL)
+
+	// The default limit cut the output short
+	result, err := jm.GetSpoolFileContentWithInfo("BIGJOB", "JOB12345", 2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Records)
+	assert.Equal(t, int64(20), result.Bytes)
+	assert.Equal(t, 5, result.TotalRecords)
+	assert.Equal(t, 400, result.TotalBytes)
+	assert.True(t, result.Truncated)
+
+	result, err = jm.GetSpoolFileContentWithInfo("BIGJOB", "JOB12345", 2, &SpoolContentOptions{AllRecords: true})
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Records)
+	assert.False(t, result.Truncated)
+	assert.Equal(t, []string{"", "0"}, maxItems)
+
+	// A record range is complete when it returns the records the file holds in that range
+	result, err = jm.GetSpoolFileContentWithInfo("BIGJOB", "JOB12345", 2, &SpoolContentOptions{RecordRange: "3,10"})
+	require.NoError(t, err)
+	assert.Equal(t, "LINE 4\nLINE 5\n", result.Content)
+	assert.False(t, result.Truncated)
+
+	_, err = jm.GetSpoolFileContentWithInfo("BIGJOB", "JOB12345", 9, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spool file 9 not found")
+}
+
+func TestGetSpoolFileTail(t *testing.T) {
+	var recordRange string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/restjobs/jobs/TESTJOB/JOB12345/files":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"id": 3, "ddname": "JESYSMSG", "records": 250}, {"id": 4, "ddname": "SYSOUT"}]`))
+		case "/api/v1/restjobs/jobs/TESTJOB/JOB12345/files/3/records":
+			recordRange = r.Header.Get("X-IBM-Record-Range")
+			w.Write([]byte("tail"))
+		case "/api/v1/restjobs/jobs/TESTJOB/JOB12345/files/4/records":
+			recordRange = r.Header.Get("X-IBM-Record-Range")
+			w.Write([]byte("LINE 1\r\nLINE 2\r\nLINE 3\r\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	var buf strings.Builder
+	_, err = jm.GetSpoolFileTail("TESTJOB", "JOB12345", 3, 100, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, "150,100", recordRange)
+	assert.Equal(t, "tail", buf.String())
+
+	// Without a record count the file is streamed and only the last lines are written
+	recordRange = ""
+	buf.Reset()
+	written, err := jm.GetSpoolFileTail("TESTJOB", "JOB12345", 4, 2, &buf)
+	require.NoError(t, err)
+	assert.Empty(t, recordRange)
+	assert.Equal(t, "LINE 2\nLINE 3\n", buf.String())
+	assert.Equal(t, int64(buf.Len()), written)
+
+	_, err = jm.GetSpoolFileTail("TESTJOB", "JOB12345", 9, 100, &buf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "spool file 9 not found")
+	_, err = jm.GetSpoolFileTail("TESTJOB", "JOB12345", 3, 0, &buf)
+	assert.Error(t, err)
+}
+
+func TestValidateRecordRange(t *testing.T) {
+	assert.NoError(t, ValidateRecordRange("0-99"))
+	assert.NoError(t, ValidateRecordRange("100,50"))
+	assert.Error(t, ValidateRecordRange(""))
+	assert.Error(t, ValidateRecordRange("10"))
+	assert.Error(t, ValidateRecordRange("-10"))
+	assert.Error(t, ValidateRecordRange("a-b"))
+	assert.Error(t, ValidateRecordRange("20-10"))
+}
+
+func TestGetSpoolFileContentWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/restjobs/jobs/TESTJOB/JOB12345/files/4/records", r.URL.Path)
+		assert.Equal(t, "IBM-037", r.URL.Query().Get("fileEncoding"))
+		assert.Equal(t, "0,10", r.Header.Get("X-IBM-Record-Range"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("REPORT"))
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	content, err := jm.GetSpoolFileContentWithOptions("TESTJOB", "JOB12345", 4, &SpoolContentOptions{
+		RecordRange: "0,10",
+		Encoding:    "IBM-037",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "REPORT", content)
+}
+
+func TestGetJobByBareJobID(t *testing.T) {
+	var response string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/restjobs/jobs", r.URL.Path)
+		assert.Equal(t, "JOB00042", r.URL.Query().Get("jobid"))
+		assert.Equal(t, "*", r.URL.Query().Get("owner"))
+		assert.Equal(t, "1000", r.URL.Query().Get("max-jobs"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	// Single match
+	response = `[{"jobid": "JOB00042", "jobname": "PAYROLL", "owner": "OTHER", "status": "OUTPUT", "retcode": "CC 0000"}]`
+	job, err := jm.GetJob("JOB00042")
+	require.NoError(t, err)
+	assert.Equal(t, "PAYROLL", job.JobName)
+	assert.Equal(t, "OTHER", job.Owner)
+	assert.Equal(t, "CC 0000", job.RetCode)
+
+	// No match
+	response = `[]`
+	_, err = jm.GetJob("JOB00042")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "job with ID JOB00042 not found")
+
+	// Ambiguous match
+	response = `[{"jobid": "JOB00042", "jobname": "A"}, {"jobid": "JOB00042", "jobname": "B"}]`
+	_, err = jm.GetJob("JOB00042")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "matched 2 jobs")
+}
+
+func TestSubmitJobFromReader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "V", r.Header.Get("X-IBM-Intrdr-Recfm"))
+		assert.Equal(t, "255", r.Header.Get("X-IBM-Intrdr-Lrecl"))
+		assert.Equal(t, "A", r.Header.Get("X-IBM-Intrdr-Class"))
+		assert.Equal(t, "build-42", r.Header.Get("X-IBM-User-Correlator"))
+		assert.Equal(t, "PROD", r.Header.Get("X-IBM-JCL-Symbol-ENV"))
+		assert.Equal(t, "USER1.LOAD", r.Header.Get("X-IBM-JCL-Symbol-LIB"))
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(body), "//LONGJOB JOB")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(SubmitJobResponse{JobID: "JOB00100", JobName: "LONGJOB"})
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	jcl := "//LONGJOB JOB (ACCT),'LONG'\n//STEP1 EXEC PGM=IEFBR14,PARM='" + strings.Repeat("X", 100) + "'\n"
+	resp, err := jm.SubmitJobFromReader(strings.NewReader(jcl), &SubmitOptions{
+		InternalReaderClass: "A",
+		InternalReaderRecfm: "V",
+		InternalReaderLrecl: 255,
+		UserCorrelator:      "build-42",
+		Symbols:             map[string]string{"ENV": "PROD", "LIB": "USER1.LOAD"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "JOB00100", resp.JobID)
+}
+
+func TestSubmitJobFromReaderValidation(t *testing.T) {
+	jm := NewJobManager(nil)
+
+	_, err := jm.SubmitJobFromReader(strings.NewReader("//J JOB\n"), &SubmitOptions{InternalReaderLrecl: 32761})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot exceed 32760")
+
+	jcl := "//J JOB\n//S EXEC PGM=IEFBR14,PARM='" + strings.Repeat("X", 80) + "'\n"
+	_, err = jm.SubmitJobFromReader(strings.NewReader(jcl), &SubmitOptions{InternalReaderRecfm: "F", InternalReaderLrecl: 80})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "JCL line 2 is longer than lrecl 80")
+}
+
+func TestSubmitJobInternalReaderValidation(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	// SubmitJob rejects settings the internal reader cannot take before sending anything
+	for _, request := range []*SubmitJobRequest{
+		{JobStatement: "//J JOB", InternalReaderLrecl: 40000},
+		{JobStatement: "//J JOB", InternalReaderLrecl: -1},
+		{JobStatement: "//J JOB", InternalReaderRecfm: "Z"},
+	} {
+		_, err := jm.SubmitJob(request)
+		assert.ErrorContains(t, err, "internal reader")
+	}
+	assert.Zero(t, requests)
+}
+
+func TestSubmitJobStatementWithSymbols(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "USER1.DATA", r.Header.Get("X-IBM-JCL-Symbol-DSN"))
+		assert.Equal(t, "A", r.Header.Get("X-IBM-JCL-Symbol-#CLS"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(SubmitJobResponse{JobID: "JOB00200", JobName: "SYMJOB"})
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	resp, err := jm.SubmitJobStatementWithSymbols("//SYMJOB JOB\n//S1 EXEC PGM=IEFBR14\n//DD1 DD DSN=&DSN,DISP=SHR", map[string]string{
+		"DSN":  "USER1.DATA",
+		"#CLS": "A",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "JOB00200", resp.JobID)
+}
+
+func TestValidateJCLSymbols(t *testing.T) {
+	tests := []struct {
+		name    string
+		symbols map[string]string
+		wantErr bool
+	}{
+		{name: "nil map", symbols: nil},
+		{name: "valid names", symbols: map[string]string{"A": "1", "$SYM": "x", "HLQ12345": "USER1"}},
+		{name: "empty name", symbols: map[string]string{"": "x"}, wantErr: true},
+		{name: "name too long", symbols: map[string]string{"TOOLONGNM": "x"}, wantErr: true},
+		{name: "leading digit", symbols: map[string]string{"1ABC": "x"}, wantErr: true},
+		{name: "lowercase", symbols: map[string]string{"abc": "x"}, wantErr: true},
+		{name: "invalid character", symbols: map[string]string{"A-B": "x"}, wantErr: true},
+		{name: "value too long", symbols: map[string]string{"LONG": strings.Repeat("X", 256)}, wantErr: true},
+		{name: "value at limit", symbols: map[string]string{"LONG": strings.Repeat("X", 255)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJCLSymbols(tt.symbols)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSubmitJobInvalidSymbolsMakesNoRequest(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	_, err = jm.SubmitJobStatementWithSymbols("//J JOB", map[string]string{"BAD NAME": "x"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid JCL symbol name")
+	assert.Equal(t, 0, requests)
+}
+
+func TestListJobsOwnerNormalization(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	// Empty owner defaults to the session user
+	_, err = jm.ListJobs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "testuser", query.Get("owner"))
+
+	_, err = jm.ListJobs(&JobFilter{Prefix: "PAY*"})
+	require.NoError(t, err)
+	assert.Equal(t, "testuser", query.Get("owner"))
+	assert.Equal(t, "PAY*", query.Get("prefix"))
+
+	// "*" is sent literally
+	_, err = jm.ListJobs(&JobFilter{Owner: "*"})
+	require.NoError(t, err)
+	assert.Equal(t, "*", query.Get("owner"))
+
+	// MaxJobs is capped at the z/OSMF limit
+	_, err = jm.ListJobs(&JobFilter{MaxJobs: 1000})
+	require.NoError(t, err)
+	_, err = jm.ListJobs(&JobFilter{MaxJobs: 1001})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "max jobs cannot exceed 1000")
+}
+
+func TestListJobsExecDataAndActiveOnly(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"jobid": "JOB00001", "jobname": "BATCH1", "status": "ACTIVE",
+			"exec-submitted": "2024-03-01T10:00:00.000Z", "exec-started": "2024-03-01T10:00:05.000Z",
+			"exec-ended": "", "exec-member": "SY1", "exec-system": "SY1"}]`))
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	jobList, err := jm.ListJobs(&JobFilter{ExecData: true, ActiveOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Y", query.Get("exec-data"))
+	assert.Equal(t, "active", query.Get("status"))
+	require.Len(t, jobList.Jobs, 1)
+	assert.Equal(t, "2024-03-01T10:00:05.000Z", jobList.Jobs[0].ExecStarted)
+	assert.Equal(t, "2024-03-01T10:00:00.000Z", jobList.Jobs[0].ExecSubmitted)
+	assert.Equal(t, "SY1", jobList.Jobs[0].ExecSystem)
+	assert.Empty(t, jobList.Jobs[0].ExecEnded)
+
+	// Flags are omitted by default
+	_, err = jm.ListJobs(&JobFilter{})
+	require.NoError(t, err)
+	assert.False(t, query.Has("exec-data"))
+	assert.False(t, query.Has("status"))
+
+	_, err = jm.ListJobs(&JobFilter{ActiveOnly: true, Status: "OUTPUT"})
+	assert.Error(t, err)
+}
+
+func TestParseReturnCode(t *testing.T) {
+	tests := []struct {
+		retcode string
+		want    *ReturnCode
+		wantErr bool
+	}{
+		{retcode: "CC 0000", want: &ReturnCode{Kind: ReturnCodeCC, Code: 0, Raw: "CC 0000"}},
+		{retcode: "CC 0004", want: &ReturnCode{Kind: ReturnCodeCC, Code: 4, Raw: "CC 0004"}},
+		{retcode: " cc 0012 ", want: &ReturnCode{Kind: ReturnCodeCC, Code: 12, Raw: " cc 0012 "}},
+		{retcode: "ABEND S0C4", want: &ReturnCode{Kind: ReturnCodeAbend, Code: 0x0C4, Abend: "S0C4", Raw: "ABEND S0C4"}},
+		{retcode: "ABEND S322", want: &ReturnCode{Kind: ReturnCodeAbend, Code: 0x322, Abend: "S322", Raw: "ABEND S322"}},
+		{retcode: "ABEND U4038", want: &ReturnCode{Kind: ReturnCodeAbend, Code: 4038, Abend: "U4038", Raw: "ABEND U4038"}},
+		{retcode: "ABEND", want: &ReturnCode{Kind: ReturnCodeAbend, Raw: "ABEND"}},
+		{retcode: "JCL ERROR", want: &ReturnCode{Kind: ReturnCodeJCLError, Raw: "JCL ERROR"}},
+		{retcode: "CANCELED", want: &ReturnCode{Kind: ReturnCodeCanceled, Raw: "CANCELED"}},
+		{retcode: "CANCELLED", want: &ReturnCode{Kind: ReturnCodeCanceled, Raw: "CANCELLED"}},
+		{retcode: "SEC ERROR", want: &ReturnCode{Kind: ReturnCodeSecError, Raw: "SEC ERROR"}},
+		{retcode: "CONV ERROR", want: &ReturnCode{Kind: ReturnCodeConvError, Raw: "CONV ERROR"}},
+		{retcode: "SYS FAIL", want: &ReturnCode{Kind: ReturnCodeSystemFail, Raw: "SYS FAIL"}},
+		{retcode: "", wantErr: true},
+		{retcode: "CC", wantErr: true},
+		{retcode: "CC ABCD", wantErr: true},
+		{retcode: "ABEND X123", wantErr: true},
+		{retcode: "ABEND SZZZ", wantErr: true},
+		{retcode: "OUTPUT", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.retcode, func(t *testing.T) {
+			rc, err := ParseReturnCode(tt.retcode)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rc)
+		})
+	}
+}
+
+func TestParseJobStatus(t *testing.T) {
+	assert.Equal(t, JobStatusInput, ParseJobStatus("INPUT"))
+	assert.Equal(t, JobStatusActive, ParseJobStatus("active"))
+	assert.Equal(t, JobStatusOutput, ParseJobStatus("OUTPUT"))
+	assert.Equal(t, JobStatus(""), ParseJobStatus("OUTPUT1"))
+	assert.Equal(t, JobStatus(""), ParseJobStatus(""))
+}
+
+func TestJobIsCompleteAndSucceeded(t *testing.T) {
+	tests := []struct {
+		name      string
+		job       Job
+		complete  bool
+		succeeded bool
+	}{
+		{name: "cc 0", job: Job{Status: "OUTPUT", RetCode: "CC 0000"}, complete: true, succeeded: true},
+		{name: "cc 4", job: Job{Status: "OUTPUT", RetCode: "CC 0004"}, complete: true, succeeded: true},
+		{name: "cc 8", job: Job{Status: "OUTPUT", RetCode: "CC 0008"}, complete: true, succeeded: false},
+		{name: "abend", job: Job{Status: "OUTPUT", RetCode: "ABEND S0C4"}, complete: true, succeeded: false},
+		{name: "jcl error", job: Job{Status: "OUTPUT", RetCode: "JCL ERROR"}, complete: true, succeeded: false},
+		{name: "active", job: Job{Status: "ACTIVE"}, complete: false, succeeded: false},
+		{name: "job named like a status", job: Job{JobName: "OUTPUT1", Status: "INPUT"}, complete: false, succeeded: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.complete, tt.job.IsComplete())
+			assert.Equal(t, tt.succeeded, tt.job.Succeeded(4))
+		})
+	}
+}
+
+func TestWaitForJobCompletion(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "ACTIVE"
+		if polls >= 2 {
+			status = "OUTPUT"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Job{JobID: "JOB001", JobName: "TESTJOB1", Status: status, RetCode: "CC 0000"})
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	status, err := jm.WaitForJobCompletion("TESTJOB1:JOB001", time.Second, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "OUTPUT", status)
+	assert.Equal(t, 2, polls)
+}
+
+// fakeClock records each wait and returns at once, advancing its time by the wait
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestWaitForJob(t *testing.T) {
+	// Each entry is one poll: a status, or "" for a failed request
+	var polls []string
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&count, 1)) - 1
+		status := polls[len(polls)-1]
+		if i < len(polls) {
+			status = polls[i]
+		}
+		if status == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Job{JobID: "JOB001", JobName: "TESTJOB1", Status: status, RetCode: "CC 0000"})
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	run := func(statuses []string, opts *WaitOptions) (*Job, *fakeClock, error) {
+		polls = statuses
+		atomic.StoreInt32(&count, 0)
+		clock := &fakeClock{now: time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)}
+		opts.Clock = clock
+		job, err := jm.WaitForJob(context.Background(), "TESTJOB1:JOB001", opts)
+		return job, clock, err
+	}
+
+	// The interval doubles up to the maximum
+	job, clock, err := run([]string{"INPUT", "ACTIVE", "ACTIVE", "ACTIVE", "ACTIVE", "ACTIVE", "OUTPUT"}, &WaitOptions{
+		InitialInterval: time.Second,
+		Multiplier:      2,
+		MaxInterval:     5 * time.Second,
+		Jitter:          -1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "OUTPUT", job.Status)
+	assert.Equal(t, []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second, 5 * time.Second}, clock.waits)
+
+	// Jitter shortens each wait by up to its fraction of the interval
+	_, clock, err = run([]string{"ACTIVE", "ACTIVE", "OUTPUT"}, &WaitOptions{
+		InitialInterval: 10 * time.Second,
+		Jitter:          0.2,
+		Random:          func() float64 { return 0.5 },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{9 * time.Second, 13500 * time.Millisecond}, clock.waits)
+
+	// Failed polls are retried until too many fail in a row
+	job, _, err = run([]string{"ACTIVE", "", "", "ACTIVE", "", "OUTPUT"}, &WaitOptions{Jitter: -1})
+	require.NoError(t, err)
+	assert.Equal(t, "OUTPUT", job.Status)
+
+	job, _, err = run([]string{"ACTIVE", "", "", ""}, &WaitOptions{Jitter: -1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get job status")
+	require.NotNil(t, job)
+	assert.Equal(t, "ACTIVE", job.Status)
+
+	// The last wait is cut short at the timeout and the last job is returned
+	job, clock, err = run([]string{"ACTIVE"}, &WaitOptions{
+		Timeout:         10 * time.Second,
+		InitialInterval: 4 * time.Second,
+		Multiplier:      1,
+		Jitter:          -1,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout waiting for job")
+	require.NotNil(t, job)
+	assert.Equal(t, "ACTIVE", job.Status)
+	assert.Equal(t, []time.Duration{4 * time.Second, 4 * time.Second, 2 * time.Second}, clock.waits)
+
+	// Cancellation stops the wait
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	polls = []string{"ACTIVE"}
+	_, err = jm.WaitForJob(ctx, "TESTJOB1:JOB001", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = jm.WaitForJob(context.Background(), "TESTJOB1:JOB001", &WaitOptions{Multiplier: 0.5})
+	assert.Error(t, err)
+}
+
+func TestWatchJob(t *testing.T) {
+	// Status and phase advance over successive polls, with a repeat and a transient error
+	responses := []struct {
+		status string
+		phase  string
+		code   int
+	}{
+		{"INPUT", "Awaiting execution", http.StatusOK},
+		{"INPUT", "Awaiting execution", http.StatusOK},
+		{"ACTIVE", "Executing", http.StatusOK},
+		{"", "", http.StatusInternalServerError},
+		{"ACTIVE", "Executing", http.StatusOK},
+		{"OUTPUT", "Awaiting output", http.StatusOK},
+	}
+	var mu sync.Mutex
+	poll := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		resp := responses[poll]
+		if poll < len(responses)-1 {
+			poll++
+		}
+		mu.Unlock()
+
+		if resp.code != http.StatusOK {
+			w.WriteHeader(resp.code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Job{JobID: "JOB001", JobName: "TESTJOB1", Status: resp.status, PhaseName: resp.phase})
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	events, err := jm.WatchJob(context.Background(), "TESTJOB1:JOB001", 5*time.Millisecond)
+	require.NoError(t, err)
+
+	var statuses []string
+	errorEvents := 0
+	for event := range events {
+		if event.Err != nil {
+			errorEvents++
+			continue
+		}
+		statuses = append(statuses, event.Job.Status)
+	}
+
+	assert.Equal(t, []string{"INPUT", "ACTIVE", "OUTPUT"}, statuses)
+	assert.Equal(t, 1, errorEvents)
+}
+
+func TestWatchJobStopsAfterConsecutiveErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	events, err := jm.WatchJobWithOptions(context.Background(), "TESTJOB1:JOB001", &WatchOptions{
+		PollInterval:         time.Millisecond,
+		MaxConsecutiveErrors: 2,
+	})
+	require.NoError(t, err)
+
+	count := 0
+	for event := range events {
+		assert.Error(t, event.Err)
+		assert.Contains(t, event.Err.Error(), "failed to get job status")
+		count++
+	}
+	assert.Equal(t, 2, count)
+}
+
+func TestWatchJobContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Job{JobID: "JOB001", JobName: "TESTJOB1", Status: "ACTIVE"})
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := jm.WatchJob(ctx, "TESTJOB1:JOB001", 5*time.Millisecond)
+	require.NoError(t, err)
+
+	event := <-events
+	require.NotNil(t, event.Job)
+	assert.Equal(t, "ACTIVE", event.Job.Status)
+
+	cancel()
+	for range events {
+	}
+
+	_, err = jm.WatchJob(context.Background(), "TESTJOB1:JOB001", 0)
+	assert.Error(t, err)
+}
+
+func TestParseExecTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"utc with millis", "2024-03-05T14:22:07.120Z", time.Date(2024, 3, 5, 14, 22, 7, 120000000, time.UTC), false},
+		{"offset", "2024-03-05T14:22:07+01:00", time.Date(2024, 3, 5, 13, 22, 7, 0, time.UTC), false},
+		{"no zone with millis", "2024-03-05T14:22:07.120", time.Date(2024, 3, 5, 14, 22, 7, 120000000, time.UTC), false},
+		{"no zone", "2024-03-05T14:22:07", time.Date(2024, 3, 5, 14, 22, 7, 0, time.UTC), false},
+		{"empty for active job", "", time.Time{}, false},
+		{"invalid", "yesterday", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseExecTime(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(parsed), "expected %v, got %v", tt.expected, parsed)
+		})
+	}
+
+	job := &Job{ExecSubmitted: "2024-03-05T14:22:07.120Z", ExecStarted: "2024-03-05T14:22:08.000Z"}
+	started, err := job.StartedTime()
+	require.NoError(t, err)
+	assert.Equal(t, 8, started.Second())
+	ended, err := job.EndedTime()
+	require.NoError(t, err)
+	assert.True(t, ended.IsZero())
+}
+
+func TestGetJobsSince(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Y", r.URL.Query().Get("exec-data"))
+		assert.Equal(t, "OPS1", r.URL.Query().Get("owner"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"jobid":"JOB001","jobname":"OLD","owner":"OPS1","status":"OUTPUT","exec-submitted":"2024-03-05T08:00:00.000Z","exec-started":"2024-03-05T08:00:01.000Z","exec-ended":"2024-03-05T08:01:00.000Z"},
+			{"jobid":"JOB002","jobname":"NEW","owner":"OPS1","status":"ACTIVE","exec-submitted":"2024-03-05T13:00:00.000Z","exec-started":"2024-03-05T13:00:02.000Z","exec-ended":""},
+			{"jobid":"JOB003","jobname":"QUEUED","owner":"OPS1","status":"INPUT"}
+		]`))
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	jobList, err := jm.GetJobsSince("OPS1", time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC), 100)
+	require.NoError(t, err)
+	require.Len(t, jobList.Jobs, 1)
+	assert.Equal(t, "JOB002", jobList.Jobs[0].JobID)
+}
+
+func TestGetJobsByCorrelatorPrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"jobid":"JOB001","jobname":"A","owner":"TESTUSER","status":"OUTPUT","job-correlator":"J0000001SYS1....DA1B2C3D.......:NIGHTLY1"},
+			{"jobid":"JOB002","jobname":"B","owner":"TESTUSER","status":"OUTPUT","job-correlator":"J0000002SYS1....DA1B2C3E.......:ADHOC"},
+			{"jobid":"JOB003","jobname":"C","owner":"TESTUSER","status":"OUTPUT","job-correlator":"J0000003SYS1....DA1B2C3F......."}
+		]`))
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	jobList, err := jm.GetJobsByCorrelatorPrefix("NIGHT", 0)
+	require.NoError(t, err)
+	require.Len(t, jobList.Jobs, 1)
+	assert.Equal(t, "JOB001", jobList.Jobs[0].JobID)
+	assert.False(t, jobList.Truncated)
+
+	// The filtered list keeps the truncation of the listing it came from
+	jobList, err = jm.GetJobsByCorrelatorPrefix("NIGHT", 3)
+	require.NoError(t, err)
+	assert.Len(t, jobList.Jobs, 1)
+	assert.True(t, jobList.Truncated)
+
+	_, err = jm.GetJobsByCorrelatorPrefix("", 0)
+	assert.Error(t, err)
+}
+
+func TestDeleteSubmittedJob(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		deleted = append(deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	resp := &SubmitJobResponse{JobName: "TESTJOB1", JobID: "JOB001"}
+	assert.Equal(t, "TESTJOB1:JOB001", resp.Correlator())
+	require.NoError(t, jm.DeleteSubmittedJob(resp))
+
+	job := &Job{JobName: "TESTJOB2", JobID: "JOB002"}
+	assert.Equal(t, "TESTJOB2:JOB002", job.Correlator())
+	require.NoError(t, jm.DeleteListedJob(job))
+
+	assert.Equal(t, []string{
+		"/api/v1/restjobs/jobs/TESTJOB1/JOB001",
+		"/api/v1/restjobs/jobs/TESTJOB2/JOB002",
+	}, deleted)
+
+	// Missing identifiers are rejected before any request is made
+	assert.Error(t, jm.DeleteSubmittedJob(nil))
+	assert.Error(t, jm.DeleteSubmittedJob(&SubmitJobResponse{JobID: "JOB001"}))
+	assert.Error(t, jm.DeleteListedJob(nil))
+	assert.Error(t, jm.PurgeSubmittedJob(nil))
+	assert.Error(t, jm.PurgeListedJob(&Job{JobName: "TESTJOB1"}))
+	assert.Len(t, deleted, 2)
+}
+
+func TestSubmitJobCorrelator(t *testing.T) {
+	const correlator = "J0012348SY1.....DB0D9A52.......:"
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnSubmitJob()
+	mock.On(http.MethodGet, "/restjobs/jobs/"+correlator).Return(strings.Replace(zosmftest.SubmitJobJSON, `"status": "INPUT"`, `"status": "OUTPUT"`, 1))
+	mock.On(http.MethodDelete, "/restjobs/jobs/IBMUSERA/JOB12348").ReturnStatus(http.StatusAccepted, "")
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	resp, err := jm.SubmitJobStatement("//IBMUSERA JOB (ACCT)\n//STEP1 EXEC PGM=IEFBR14")
+	require.NoError(t, err)
+	assert.Equal(t, correlator, resp.JobCorrelator)
+	assert.Equal(t, correlator, resp.Correlator())
+
+	// Follow-up calls look the job up by its correlator
+	job, err := jm.GetJob(resp.Correlator())
+	require.NoError(t, err)
+	assert.Equal(t, "JOB12348", job.JobID)
+	require.NoError(t, jm.DeleteSubmittedJob(resp))
+	assert.Len(t, mock.RequestsTo(http.MethodGet, "/restjobs/jobs/"+correlator), 2)
+	assert.Len(t, mock.RequestsTo(http.MethodDelete, "/restjobs/jobs/IBMUSERA/JOB12348"), 1)
+
+	// Without a usable correlator, the job name and ID are used
+	assert.Equal(t, "IBMUSERA:JOB12348", (&SubmitJobResponse{JobName: "IBMUSERA", JobID: "JOB12348"}).Correlator())
+	assert.Equal(t, "IBMUSERA:JOB12348", (&SubmitJobResponse{JobName: "IBMUSERA", JobID: "JOB12348", JobCorrelator: "not-a-correlator"}).Correlator())
+}
+
+func TestGetSpoolFileContentStreamProgress(t *testing.T) {
+	output := strings.Repeat("IEF142I TESTJOB1 STEP1 - STEP WAS EXECUTED - COND CODE 0000\n", 5000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(output))
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	var last int64
+	calls := 0
+	var sb strings.Builder
+	n, err := jm.GetSpoolFileContentStreamWithOptions("TESTJOB1", "JOB001", 2, &SpoolContentOptions{
+		Progress: func(transferred, total int64) {
+			assert.Greater(t, transferred, last)
+			last = transferred
+			calls++
+		},
+	}, &sb)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(output)), n)
+	assert.Equal(t, int64(len(output)), last)
+	assert.Greater(t, calls, 0)
+}
+
+func TestVerifySubmission(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "*", r.URL.Query().Get("owner"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("user-correlator") {
+		case "ZFOUND":
+			w.Write([]byte(`[{"jobid":"JOB123","jobname":"NIGHTLY","owner":"TESTUSER","status":"INPUT"}]`))
+		case "ZDUP":
+			w.Write([]byte(`[{"jobid":"JOB1","jobname":"A"},{"jobid":"JOB2","jobname":"A"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	job, err := jm.VerifySubmission("ZFOUND")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, "JOB123", job.JobID)
+
+	job, err = jm.VerifySubmission("ZMISSING")
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	_, err = jm.VerifySubmission("ZDUP")
+	assert.Error(t, err)
+}
+
+func TestSubmitJobWithRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		reachedJES    bool
+		expectedPUTs  int
+		expectedJobID string
+	}{
+		{"lost response after job was queued", true, 1, "JOB777"},
+		{"request never reached JES", false, 2, "JOB888"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var correlators []string
+			queued := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == "GET" {
+					if queued && r.URL.Query().Get("user-correlator") == correlators[0] {
+						w.Write([]byte(`[{"jobid":"JOB777","jobname":"TESTJOB","owner":"TESTUSER","status":"INPUT"}]`))
+						return
+					}
+					w.Write([]byte(`[]`))
+					return
+				}
+
+				correlators = append(correlators, r.Header.Get("X-IBM-User-Correlator"))
+				if len(correlators) == 1 {
+					// Drop the connection without a response
+					queued = tt.reachedJES
+					conn, _, err := w.(http.Hijacker).Hijack()
+					require.NoError(t, err)
+					conn.Close()
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"jobid":"JOB888","jobname":"TESTJOB","owner":"TESTUSER","status":"INPUT"}`))
+			}))
+			defer server.Close()
+
+			profile := createTestProfile(server.URL)
+			session, err := profile.NewSession()
+			require.NoError(t, err)
+			jm := NewJobManager(session)
+
+			request := &SubmitJobRequest{JobStatement: "//TESTJOB JOB (ACCT)\n//STEP1 EXEC PGM=IEFBR14"}
+			start := time.Now()
+			resp, err := jm.SubmitJobWithRetry(request, 3)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedJobID, resp.JobID)
+			assert.GreaterOrEqual(t, time.Since(start), 250*time.Millisecond)
+
+			// Every attempt carries the same generated correlator; the request is left alone
+			assert.Len(t, correlators, tt.expectedPUTs)
+			assert.Len(t, correlators[0], MaxUserCorrelatorLength)
+			for _, correlator := range correlators {
+				assert.Equal(t, correlators[0], correlator)
+			}
+			assert.Empty(t, request.UserCorrelator)
+			assert.False(t, request.GenerateUserCorrelator)
+		})
+	}
+}
+
+func TestValidateUserCorrelator(t *testing.T) {
+	assert.NoError(t, ValidateUserCorrelator(""))
+	assert.NoError(t, ValidateUserCorrelator("build-42"))
+	assert.Error(t, ValidateUserCorrelator(strings.Repeat("A", MaxUserCorrelatorLength+1)))
+	assert.Error(t, ValidateUserCorrelator("has space"))
+	assert.Error(t, ValidateUserCorrelator("A:B"))
+
+	first, err := NewUserCorrelator()
+	require.NoError(t, err)
+	second, err := NewUserCorrelator()
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.NoError(t, ValidateUserCorrelator(first))
+}
+
+func TestResubmitJob(t *testing.T) {
+	const jcl = "//NIGHTLY JOB (ACCT),'USER',MSGCLASS=A\n//STEP1 EXEC PGM=REPORT,PARM='DATE=240101'\n"
+	var submitted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/restjobs/jobs/NIGHTLY/JOB123/files/JCL/records":
+			w.Write([]byte(jcl))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/restjobs/jobs/NIGHTLY/JOB999/files/JCL/records":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Job not found"}`))
+		case r.Method == "PUT" && r.URL.Path == "/api/v1/restjobs/jobs":
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			submitted = string(body)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(SubmitJobResponse{JobID: "JOB124", JobName: "NIGHTLY"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	response, err := jm.ResubmitJob("NIGHTLY:JOB123")
+	require.NoError(t, err)
+	assert.Equal(t, "JOB124", response.JobID)
+	assert.Equal(t, jcl, submitted)
+
+	submitted = ""
+	result, err := jm.ResubmitJobWithOptions("NIGHTLY:JOB123", &ResubmitOptions{
+		Replacements: map[string]string{"DATE=24": "X", "DATE=240101": "DATE=240102"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, submitted, "PARM='DATE=240102'")
+	assert.Equal(t, submitted, result.JCL)
+	assert.Equal(t, "JOB124", result.Response.JobID)
+
+	// A dry run returns the JCL without submitting it
+	submitted = ""
+	result, err = jm.ResubmitJobWithOptions("NIGHTLY:JOB123", &ResubmitOptions{
+		Replacements: map[string]string{"REPORT": "REPORT2"},
+		DryRun:       true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.JCL, "PGM=REPORT2")
+	assert.Nil(t, result.Response)
+	assert.Empty(t, submitted)
+
+	_, err = jm.ResubmitJob("NIGHTLY:JOB999")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no longer available")
+}
+
+func TestRunJCL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "PUT":
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(SubmitJobResponse{JobID: "JOB200", JobName: "COMPRESS"})
+		case "GET":
+			assert.Equal(t, "/api/v1/restjobs/jobs/COMPRESS/JOB200", r.URL.Path)
+			json.NewEncoder(w).Encode(Job{JobID: "JOB200", JobName: "COMPRESS", Status: "OUTPUT", RetCode: "CC 0004"})
+		}
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	retCode, err := jm.RunJCL("//COMPRESS JOB (ACCT)\n//STEP1 EXEC PGM=IEFBR14\n", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "CC 0004", retCode)
+}
+
+func TestJobCardBuilder(t *testing.T) {
+	// Defaults match CreateSimpleJobStatement's positional parameters
+	card, err := (&JobCardBuilder{}).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "//GOJOB JOB (ACCT),'USER'", card)
+
+	card, err = (&JobCardBuilder{
+		JobName:    "NIGHTLY",
+		Account:    "(D123,45)",
+		Programmer: "O'BRIEN",
+		Class:      "A",
+		MsgClass:   "X",
+		TypRun:     "SCAN",
+	}).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "//NIGHTLY JOB (D123,45),'O''BRIEN',CLASS=A,MSGCLASS=X,TYPRUN=SCAN", card)
+}
+
+func TestJobCardBuilderWrapping(t *testing.T) {
+	builder := &JobCardBuilder{
+		JobName:    "PAYROLL1",
+		Account:    "ACCT1234,DEPT99,ROOM12",
+		Programmer: "PAYROLL BATCH TEAM",
+		Class:      "B",
+		MsgClass:   "X",
+		MsgLevel:   "(1,1)",
+		Region:     "0M",
+		Time:       "(5,30)",
+		Notify:     "&SYSUID",
+		Extra:      map[string]string{"COND": "(4,LT)", "SCHENV": "DB2PROD", "JESLOG": "SPIN"},
+	}
+	card, err := builder.Build()
+	require.NoError(t, err)
+
+	expected := "//PAYROLL1 JOB (ACCT1234,DEPT99,ROOM12),'PAYROLL BATCH TEAM',CLASS=B,\n" +
+		"//             MSGCLASS=X,MSGLEVEL=(1,1),REGION=0M,TIME=(5,30),\n" +
+		"//             NOTIFY=&SYSUID,COND=(4,LT),JESLOG=SPIN,SCHENV=DB2PROD"
+	assert.Equal(t, expected, card)
+
+	lines := strings.Split(card, "\n")
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), MaxJCLStatementColumn, "line %d: %q", i+1, line)
+		assert.True(t, strings.HasPrefix(line, "//"))
+		if i < len(lines)-1 {
+			// Every continued line ends after a complete parameter
+			assert.True(t, strings.HasSuffix(line, ","), "line %d: %q", i+1, line)
+		}
+		if i > 0 {
+			// Continuations start between columns 4 and 16
+			assert.Equal(t, "//             ", line[:15])
+			assert.NotEqual(t, byte(' '), line[15])
+		}
+	}
+
+	// Joining the continuations gives back the single-line statement
+	joined := lines[0]
+	for _, line := range lines[1:] {
+		joined += strings.TrimLeft(line[2:], " ")
+	}
+	assert.Equal(t, "//PAYROLL1 JOB (ACCT1234,DEPT99,ROOM12),'PAYROLL BATCH TEAM',CLASS=B,MSGCLASS=X,"+
+		"MSGLEVEL=(1,1),REGION=0M,TIME=(5,30),NOTIFY=&SYSUID,COND=(4,LT),JESLOG=SPIN,SCHENV=DB2PROD", joined)
+
+	// A statement ending exactly at column 71 is not continued
+	exact, err := (&JobCardBuilder{JobName: "J", Account: strings.Repeat("A", 57), Programmer: "P"}).Build()
+	require.NoError(t, err)
+	assert.Len(t, exact, MaxJCLStatementColumn)
+	assert.NotContains(t, exact, "\n")
+
+	// One character more moves the last parameter to a continuation line
+	wrapped, err := (&JobCardBuilder{JobName: "J", Account: strings.Repeat("A", 58), Programmer: "P"}).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "//J JOB ("+strings.Repeat("A", 58)+"),\n//             'P'", wrapped)
+}
+
+func TestJobCardBuilderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder JobCardBuilder
+	}{
+		{"job name too long", JobCardBuilder{JobName: "TOOLONGJOB"}},
+		{"job name starts with digit", JobCardBuilder{JobName: "1JOB"}},
+		{"lowercase job name", JobCardBuilder{JobName: "myjob"}},
+		{"programmer name too long", JobCardBuilder{Programmer: strings.Repeat("X", 21)}},
+		{"class", JobCardBuilder{Class: "AB"}},
+		{"message class", JobCardBuilder{MsgClass: "*"}},
+		{"notify", JobCardBuilder{Notify: "NOTAUSERID"}},
+		{"typrun", JobCardBuilder{TypRun: "NOW"}},
+		{"extra name", JobCardBuilder{Extra: map[string]string{"BAD NAME": "X"}}},
+		{"extra duplicates a field", JobCardBuilder{Extra: map[string]string{"CLASS": "A"}}},
+		{"extra without value", JobCardBuilder{Extra: map[string]string{"COND": ""}}},
+		{"parameter longer than a line", JobCardBuilder{Account: strings.Repeat("A", 60)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJobCardBuilderBuildJob(t *testing.T) {
+	card := &JobCardBuilder{JobName: "TESTJOB", Class: "A"}
+	jcl, err := card.BuildJob("", "IEFBR14", []string{"//DD1 DD DUMMY"})
+	require.NoError(t, err)
+	assert.Equal(t, "//TESTJOB JOB (ACCT),'USER',CLASS=A\n//STEP1 EXEC PGM=IEFBR14\n//DD1 DD DUMMY\n", jcl)
+
+	_, err = (&JobCardBuilder{}).BuildJob("STEP-1", "IEFBR14", nil)
+	assert.Error(t, err)
+	_, err = (&JobCardBuilder{JobName: "TOOLONGJOB"}).BuildJob("STEP1", "IEFBR14", nil)
+	assert.Error(t, err)
+
+	// A long JOB statement is continued past column 71
+	card = &JobCardBuilder{JobName: "LONGJOB", Account: "ACCOUNT-NUMBER-1234,DEPARTMENT-99", Programmer: "PAYROLL BATCH TEAM", MsgClass: "A", MsgLevel: "(1,1)"}
+	jcl, err = card.BuildJob("", "IEFBR14", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "//LONGJOB JOB (ACCOUNT-NUMBER-1234,DEPARTMENT-99),'PAYROLL BATCH TEAM',\n//             MSGCLASS=A,MSGLEVEL=(1,1)\n//STEP1 EXEC PGM=IEFBR14\n", jcl)
+
+	// CreateJobWithStep is deprecated in its favor
+	var deprecations []profile.Deprecation
+	profile.SetDeprecationLogger(func(d profile.Deprecation) {
+		deprecations = append(deprecations, d)
+	})
+	defer profile.SetDeprecationLogger(nil)
+
+	CreateJobWithStep("TESTJOB", "", "", "", "", "", "IEFBR14", nil)
+	assert.Equal(t, []profile.Deprecation{
+		{Function: "jobs.CreateJobWithStep", Replacement: "jobs.(*JobCardBuilder).BuildJob"},
+	}, deprecations)
+}
+
+func TestGetSpoolFileReader(t *testing.T) {
+	const lines = 200000
+	var recordRange string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/restjobs/jobs/DUMPJOB/JOB777/files":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"id": 2, "ddname": "SYSUDUMP"}, {"id": 3, "ddname": "SYSPRINT", "records": 40}]`))
+		case "/api/v1/restjobs/jobs/DUMPJOB/JOB777/files/2/records":
+			// Stream a large body in chunks, as a dump DD would be
+			bw := bufio.NewWriter(w)
+			for i := 1; i <= lines; i++ {
+				fmt.Fprintf(bw, "DUMP RECORD %06d %s\n", i, strings.Repeat("X", 60))
+			}
+			bw.Flush()
+		case "/api/v1/restjobs/jobs/DUMPJOB/JOB777/files/3/records":
+			recordRange = r.Header.Get("X-IBM-Record-Range")
+			w.Write([]byte("LINE 38\r\nLINE 39\r\nLINE 40\r\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	reader, err := jm.GetSpoolFileReader("DUMPJOB", "JOB777", 2)
+	require.NoError(t, err)
+	scanner := bufio.NewScanner(reader)
+	count := 0
+	for scanner.Scan() {
+		count++
+	}
+	require.NoError(t, scanner.Err())
+	require.NoError(t, reader.Close())
+	assert.Equal(t, lines, count)
+
+	// Without a record count the tail is taken client-side
+	tail, err := jm.GetSpoolFileTailLines(context.Background(), "DUMPJOB", "JOB777", 2, 3)
+	require.NoError(t, err)
+	require.Len(t, tail, 3)
+	assert.True(t, strings.HasPrefix(tail[0], "DUMP RECORD 199998 "))
+	assert.True(t, strings.HasPrefix(tail[2], "DUMP RECORD 200000 "))
+
+	// With a record count only the last records are requested
+	tail, err = jm.GetSpoolFileTailLines(context.Background(), "DUMPJOB", "JOB777", 3, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "37,3", recordRange)
+	assert.Equal(t, []string{"LINE 38", "LINE 39", "LINE 40"}, tail)
+
+	_, err = jm.GetSpoolFileReader("DUMPJOB", "JOB777", 9)
+	assert.Error(t, err)
+	_, err = jm.GetSpoolFileTailLines(context.Background(), "DUMPJOB", "JOB777", 9, 3)
+	assert.ErrorContains(t, err, "spool file 9 not found")
+}
+
+func TestGetSpoolFileReaderCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("FIRST CHUNK\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reader, err := jm.GetSpoolFileReaderWithContext(ctx, "DUMPJOB", "JOB777", 2, nil)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	buf := make([]byte, 64)
+	n, err := reader.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "FIRST CHUNK\n", string(buf[:n]))
+
+	cancel()
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTailLines(t *testing.T) {
+	tail, err := tailLines(strings.NewReader("a\nb\n"), 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, tail)
+
+	tail, err = tailLines(strings.NewReader("a\nb\nc\nd\ne"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d", "e"}, tail)
+
+	tail, err = tailLines(strings.NewReader(""), 2)
+	require.NoError(t, err)
+	assert.Empty(t, tail)
+}
+
+func TestListJobsResponseShapes(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      []string
+		total     int
+		truncated bool
+		wantErr   bool
+	}{
+		{name: "empty array", body: `[]`, want: []string{}},
+		{name: "array", body: ` [{"jobid":"JOB001"},{"jobid":"JOB002"}]`, want: []string{"JOB001", "JOB002"}},
+		{name: "empty object", body: `{}`, want: []string{}},
+		{name: "jobs key", body: `{"jobs":[{"jobid":"JOB001"}]}`, want: []string{"JOB001"}},
+		{name: "empty jobs key", body: `{"jobs":[]}`, want: []string{}},
+		{name: "items key", body: `{"items":[{"jobid":"JOB003"}],"returnedRows":1}`, want: []string{"JOB003"}},
+		{name: "total rows", body: `{"items":[{"jobid":"JOB003"}],"returnedRows":1,"totalRows":5000}`, want: []string{"JOB003"}, total: 5000, truncated: true},
+		{name: "total rows all returned", body: `{"jobs":[{"jobid":"JOB001"}],"totalRows":1}`, want: []string{"JOB001"}, total: 1},
+		{name: "malformed total rows", body: `{"jobs":[{"jobid":"JOB001"}],"totalRows":"many"}`, want: []string{"JOB001"}},
+		{name: "unknown object", body: `{"rc":4,"message":"no jobs"}`, wantErr: true},
+		{name: "garbage", body: `<html>gateway error</html>`, wantErr: true},
+		{name: "truncated", body: `[{"jobid":"JOB001"`, wantErr: true},
+		{name: "empty body", body: ``, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			profile := createTestProfile(server.URL)
+			session, err := profile.NewSession()
+			require.NoError(t, err)
+			jm := NewJobManager(session)
+
+			jobList, err := jm.ListJobs(nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.body != "" {
+					assert.Contains(t, err.Error(), tt.body)
+				}
+				return
+			}
+			require.NoError(t, err)
+			ids := []string{}
+			for _, job := range jobList.Jobs {
+				ids = append(ids, job.JobID)
+			}
+			assert.Equal(t, tt.want, ids)
+			assert.Equal(t, tt.total, jobList.TotalRows)
+			assert.Equal(t, tt.truncated, jobList.Truncated)
+		})
+	}
+}
+
+// mockSession serves requests from a handler function without a server
+type mockSession struct {
+	handler func(r *http.Request) *http.Response
+}
+
+func (m *mockSession) GetBaseURL() string {
+	return "https://mock/zosmf"
+}
+
+func (m *mockSession) GetHeaders() map[string]string {
+	return map[string]string{"X-CSRF-ZOSMF-HEADER": ""}
+}
+
+func (m *mockSession) GetUser() string {
+	return "MOCKUSER"
+}
+
+func (m *mockSession) GetHTTPClient() *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return m.handler(r), nil
+	})}
+}
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestJobManagerWithMockSession(t *testing.T) {
+	var requested *http.Request
+	session := &mockSession{handler: func(r *http.Request) *http.Response {
+		requested = r
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`[{"jobid":"JOB001","jobname":"MOCKJOB"}]`)),
+		}
+	}}
+
+	jm := NewJobManager(session)
+	assert.Same(t, session, jm.Session())
+
+	jobList, err := jm.ListJobs(nil)
+	require.NoError(t, err)
+	require.Len(t, jobList.Jobs, 1)
+	assert.Equal(t, "MOCKJOB", jobList.Jobs[0].JobName)
+
+	require.NotNil(t, requested)
+	assert.Equal(t, "/zosmf/restjobs/jobs", requested.URL.Path)
+	assert.Equal(t, "MOCKUSER", requested.URL.Query().Get("owner"))
+	_, ok := requested.Header["X-Csrf-Zosmf-Header"]
+	assert.True(t, ok)
+}
+
+// newPagingServer lists jobs like z/OSMF: filtered by prefix and cut off at max-jobs
+func newPagingServer(t *testing.T, jobs []Job, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		query := r.URL.Query()
+		maxJobs, err := strconv.Atoi(query.Get("max-jobs"))
+		require.NoError(t, err)
+		prefix := query.Get("prefix")
+		if prefix == "" {
+			prefix = "*"
+		}
+
+		matched := []Job{}
+		for _, job := range jobs {
+			ok, err := path.Match(strings.ReplaceAll(prefix, "%", "?"), job.JobName)
+			require.NoError(t, err)
+			if ok && len(matched) < maxJobs {
+				matched = append(matched, job)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(matched)
+	}))
+}
+
+func TestListJobsTruncated(t *testing.T) {
+	var requests int32
+	jobs := []Job{{JobID: "JOB1", JobName: "A"}, {JobID: "JOB2", JobName: "B"}}
+	server := newPagingServer(t, jobs, &requests)
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	jobList, err := jm.ListJobs(nil)
+	require.NoError(t, err)
+	assert.Len(t, jobList.Jobs, 2)
+	assert.False(t, jobList.Truncated)
+
+	jobList, err = jm.ListJobs(&JobFilter{MaxJobs: 2})
+	require.NoError(t, err)
+	assert.True(t, jobList.Truncated)
+	assert.Len(t, jobList.Jobs, 2)
+	assert.Zero(t, jobList.TotalRows)
+}
+
+func TestListAllJobs(t *testing.T) {
+	var requests int32
+	jobs := []Job{{JobID: "JOB00001", JobName: "PAY"}}
+	for i := 1; i <= 6; i++ {
+		jobs = append(jobs, Job{JobID: fmt.Sprintf("JOB0001%d", i), JobName: fmt.Sprintf("PAYA%d", i)})
+	}
+	jobs = append(jobs,
+		Job{JobID: "JOB00021", JobName: "PAYB1"},
+		Job{JobID: "JOB00022", JobName: "PAYB2"},
+		Job{JobID: "JOB00031", JobName: "TEST1"},
+		Job{JobID: "JOB00032", JobName: "TEST2"},
+		Job{JobID: "JOB00041", JobName: "$XJOB"},
+	)
+	for i := 1; i <= 6; i++ {
+		jobs = append(jobs, Job{JobID: fmt.Sprintf("JOB0005%d", i), JobName: "BIG"})
+	}
+	server := newPagingServer(t, jobs, &requests)
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	// Truncated listings are narrowed until every job is found
+	jobList, err := jm.ListAllJobs(&JobFilter{Owner: "*", Prefix: "PAY*", MaxJobs: 5})
+	require.NoError(t, err)
+	assert.False(t, jobList.Truncated)
+	assert.Len(t, jobList.Jobs, 9)
+
+	jobList, err = jm.ListAllJobs(&JobFilter{Owner: "*", MaxJobs: 5})
+	require.NoError(t, err)
+	assert.Len(t, jobList.Jobs, 17)
+	assert.True(t, jobList.Truncated, "BIG has more jobs than fit in a page")
+
+	// A listing that fits takes a single request
+	atomic.StoreInt32(&requests, 0)
+	jobList, err = jm.ListAllJobs(&JobFilter{Owner: "*", Prefix: "TEST*", MaxJobs: 5})
+	require.NoError(t, err)
+	assert.Len(t, jobList.Jobs, 2)
+	assert.False(t, jobList.Truncated)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// Prefixes with inner wildcards cannot be narrowed
+	jobList, err = jm.ListAllJobs(&JobFilter{Owner: "*", Prefix: "PAYA%", MaxJobs: 5})
+	require.NoError(t, err)
+	assert.Len(t, jobList.Jobs, 5)
+	assert.True(t, jobList.Truncated)
+
+	// Narrowing stops at the request cap
+	defer func(limit int) { maxJobListRequests = limit }(maxJobListRequests)
+	maxJobListRequests = 3
+	atomic.StoreInt32(&requests, 0)
+	jobList, err = jm.ListAllJobs(&JobFilter{Owner: "*", Prefix: "PAY*", MaxJobs: 5})
+	require.NoError(t, err)
+	assert.True(t, jobList.Truncated)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestSubmitJobFromUSSFile(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/api/v1/restjobs/jobs", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(SubmitJobResponse{JobID: "JOB00123", JobName: "USSJOB"})
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	response, err := jm.SubmitJobFromUSSFile("/u/user/job.jcl")
+	require.NoError(t, err)
+	assert.Equal(t, "JOB00123", response.JobID)
+	assert.Equal(t, map[string]interface{}{"file": "/u/user/job.jcl"}, body)
+
+	// Relative paths and dataset-style names are rejected before sending
+	for _, ussPath := range []string{"u/user/job.jcl", "//USER.JCL(JOB)"} {
+		_, err = jm.SubmitJobFromUSSFile(ussPath)
+		assert.Error(t, err)
+		assert.Error(t, ValidateJobRequest(&SubmitJobRequest{JobUSSFile: ussPath}))
+	}
+	assert.NoError(t, ValidateJobRequest(&SubmitJobRequest{JobUSSFile: "/u/user/job.jcl"}))
+}
+
+func TestSubmitJobFromLocalFile(t *testing.T) {
+	var contentType, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		contentType = r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(SubmitJobResponse{JobID: "JOB00077", JobName: "LOCAL"})
+	}))
+	defer server.Close()
+
+	profile := createTestProfile(server.URL)
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	jcl := "//LOCAL    JOB (ACCT),'USER'\n//STEP1    EXEC PGM=IEFBR14\n"
+	dir := t.TempDir()
+	jclPath := filepath.Join(dir, "local.jcl")
+	require.NoError(t, os.WriteFile(jclPath, []byte(jcl), 0644))
+
+	// The file content is uploaded, not its name
+	response, err := jm.SubmitJobFromLocalFile(jclPath, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "JOB00077", response.JobID)
+	assert.Equal(t, "text/plain", contentType)
+	assert.Equal(t, jcl, body)
+
+	// Relative names resolve against the directory and get the extension
+	body = ""
+	_, err = jm.SubmitJobFromLocalFile("local", dir, ".jcl")
+	require.NoError(t, err)
+	assert.Equal(t, jcl, body)
+
+	_, err = jm.SubmitJobFromLocalFile("missing.jcl", dir, "")
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.jcl"), []byte("  \n"), 0644))
+	_, err = jm.SubmitJobFromLocalFile("empty.jcl", dir, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "empty")
+}
+
+func TestSpoolProfileEncoding(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListSpoolFiles("IBMUSERA", "JOB12345")
+	mock.OnReadSpoolFile("IBMUSERA", "JOB12345", 2)
+	mock.On(http.MethodGet, "/restjobs/jobs/IBMUSERA/JOB12345/files/JCL/records").Return("//IBMUSERA JOB\n")
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	// Without a profile encoding z/OSMF's default applies
+	_, err = jm.GetSpoolFileContent("IBMUSERA", "JOB12345", 2)
+	require.NoError(t, err)
+	assert.Empty(t, mock.LastRequest().Query.Get("fileEncoding"))
+
+	session.Profile.Encoding = "1140"
+	_, err = jm.GetSpoolFileContent("IBMUSERA", "JOB12345", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "IBM-1140", mock.LastRequest().Query.Get("fileEncoding"))
+
+	// Wrapping the session with retries keeps the profile's encoding
+	_, err = NewJobManager(session, WithRetries(1)).GetSpoolFileContentByCorrelator("IBMUSERA:JOB12345", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "IBM-1140", mock.LastRequest().Query.Get("fileEncoding"))
+
+	_, err = jm.GetJobJCL("IBMUSERA:JOB12345")
+	require.NoError(t, err)
+	assert.Equal(t, "IBM-1140", mock.LastRequest().Query.Get("fileEncoding"))
+
+	// A per-request encoding wins
+	_, err = jm.GetSpoolFileContentWithInfo("IBMUSERA", "JOB12345", 2, &SpoolContentOptions{Encoding: "IBM-037"})
+	require.NoError(t, err)
+	assert.Equal(t, "IBM-037", mock.LastRequest().Query.Get("fileEncoding"))
+}
+
+func TestParseMessages(t *testing.T) {
+	readLog := func(t *testing.T, name string) string {
+		content, err := os.ReadFile(filepath.Join("testdata", "joblogs", name))
+		require.NoError(t, err)
+		return string(content)
+	}
+	ids := func(messages []SystemMessage) []string {
+		var list []string
+		for _, message := range messages {
+			list = append(list, message.ID)
+		}
+		return list
+	}
+
+	tests := []struct {
+		file  string
+		ids   []string
+		abend string
+	}{
+		{"jes2_jesmsglg_rc0.txt", []string{"IRR010I", "ICH70001I", "$HASP373", "IEF403I", "IEF404I", "$HASP395"}, ""},
+		{"jes2_jesmsglg_abend.txt", []string{"IRR010I", "$HASP373", "IEF403I", "IEA995I", "IEF450I", "IEF404I", "$HASP395"}, "S0C4"},
+		{"jes2_jesysmsg_abend.txt", []string{"ICH70001I", "IEF236I", "IEF237I", "IEF237I", "IEF450I", "IEF472I", "IEF285I", "IEF285I", "IEF373I", "IEF032I", "IEF375I", "IEF033I"}, "S0C4"},
+		{"jesysmsg_user_abend.txt", []string{"IEF236I", "IEF237I", "IEF237I", "CEE3250C", "IEF450I", "IEF142I", "IEF374I"}, "U4038"},
+		{"jes3_jesmsglg.txt", []string{"IAT6140", "IRR010I", "IEF403I", "IEF450I", "IEF404I", "IAT4401", "IAT6108"}, "S806"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			messages, err := ParseMessages(readLog(t, tt.file))
+			require.NoError(t, err)
+			assert.Equal(t, tt.ids, ids(messages))
+
+			abend, found := FindAbendCode(messages)
+			assert.Equal(t, tt.abend != "", found)
+			assert.Equal(t, tt.abend, abend)
+		})
+	}
+
+	t.Run("JES2 timestamps and continuations", func(t *testing.T) {
+		messages, err := ParseMessages(readLog(t, "jes2_jesmsglg_abend.txt"))
+		require.NoError(t, err)
+
+		dump := messages[3]
+		assert.Equal(t, "IEA995I", dump.ID)
+		assert.Equal(t, "23.59.59", dump.Timestamp)
+		assert.Equal(t, time.Date(2024, time.June, 7, 23, 59, 59, 0, time.UTC), dump.Time)
+		assert.Equal(t, "JOB23456", dump.JobID)
+		assert.Equal(t, 7, dump.Line)
+		lines := strings.Split(dump.Text, "\n")
+		require.Len(t, lines, 4)
+		assert.Equal(t, "SYSTEM COMPLETION CODE=0C4  REASON CODE=00000004", lines[1])
+
+		assert.Equal(t, "COMPILE", messages[4].StepName)
+		assert.Empty(t, messages[5].StepName)
+	})
+
+	t.Run("JES3 timestamps", func(t *testing.T) {
+		messages, err := ParseMessages(readLog(t, "jes3_jesmsglg.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "08:30:11", messages[0].Timestamp)
+		assert.Equal(t, time.Date(2024, time.June, 11, 8, 30, 11, 0, time.UTC), messages[0].Time)
+		assert.Empty(t, messages[0].JobID)
+		assert.Equal(t, "LOAD", messages[3].StepName)
+	})
+
+	t.Run("JESYSMSG steps", func(t *testing.T) {
+		messages, err := ParseMessages(readLog(t, "jes2_jesysmsg_abend.txt"))
+		require.NoError(t, err)
+		for _, message := range messages[1:10] {
+			assert.Equal(t, "COMPILE", message.StepName, message.ID)
+		}
+		assert.Empty(t, messages[10].StepName)
+		assert.True(t, messages[0].Time.IsZero())
+		assert.Contains(t, messages[9].Text, "\nVIRT- ALLOC:")
+
+		messages, err = ParseMessages(readLog(t, "jesysmsg_user_abend.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "RUN", messages[3].StepName)
+		assert.Contains(t, messages[3].Text, "\nFrom entry point main")
+		assert.Equal(t, "CLEANUP", messages[5].StepName)
+	})
+
+	t.Run("no messages", func(t *testing.T) {
+		messages, err := ParseMessages("")
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+}
+
+func TestGetJobMessages(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListSpoolFiles("IBMUSERB", "JOB23456")
+	abendLog, err := os.ReadFile(filepath.Join("testdata", "joblogs", "jes2_jesmsglg_abend.txt"))
+	require.NoError(t, err)
+	sysmsg, err := os.ReadFile(filepath.Join("testdata", "joblogs", "jes2_jesysmsg_abend.txt"))
+	require.NoError(t, err)
+	mock.OnReadSpoolFile("IBMUSERB", "JOB23456", 2).Return(string(abendLog))
+	mock.OnReadSpoolFile("IBMUSERB", "JOB23456", 4).Return(string(sysmsg))
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	messages, err := jm.GetJobMessages("IBMUSERB:JOB23456")
+	require.NoError(t, err)
+	require.Len(t, messages, 19)
+	assert.Equal(t, "JESMSGLG", messages[0].DDName)
+	assert.Equal(t, "JESYSMSG", messages[7].DDName)
+	assert.Equal(t, "ICH70001I", messages[7].ID)
+	assert.Empty(t, mock.RequestsTo(http.MethodGet, "/restjobs/jobs/IBMUSERB/JOB23456/files/3/records"))
+
+	abend, found := FindAbendCode(messages)
+	assert.True(t, found)
+	assert.Equal(t, "S0C4", abend)
+
+	mock.On(http.MethodGet, "/restjobs/jobs/IBMUSERC/JOB34567/files").Return("[]")
+	_, err = jm.GetJobMessages("IBMUSERC:JOB34567")
+	assert.Error(t, err)
+}
+
+func TestJobManagerAPIError(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnGetJob("MYJOB", "JOB00009").ReturnError(http.StatusNotFound, "No job found for reference: 'MYJOB(JOB00009)'")
+
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	_, err = jm.GetJobByNameID("MYJOB", "JOB00009")
+	require.Error(t, err)
+	var apiErr *profile.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, 4, apiErr.RC)
+	assert.Equal(t, "No job found for reference: 'MYJOB(JOB00009)'", apiErr.Message)
+
+	_, err = jm.GetSpoolFiles("MYJOB", "JOB00009")
+	assert.ErrorAs(t, err, &apiErr)
+}
+
+// mixedStatusJobs returns jobs in every state, with one of each return code bucket
+func mixedStatusJobs() []Job {
+	return []Job{
+		{JobID: "JOB00101", JobName: "PAYA1", Status: "OUTPUT", RetCode: "CC 0000"},
+		{JobID: "JOB00102", JobName: "PAYA2", Status: "OUTPUT", RetCode: "CC 0000"},
+		{JobID: "JOB00103", JobName: "PAYA3", Status: "OUTPUT", RetCode: "CC 0004"},
+		{JobID: "JOB00104", JobName: "PAYA4", Status: "OUTPUT", RetCode: "ABEND S0C4"},
+		{JobID: "JOB00105", JobName: "PAYB1", Status: "OUTPUT", RetCode: "ABEND U4038"},
+		{JobID: "JOB00106", JobName: "PAYB2", Status: "OUTPUT", RetCode: "JCL ERROR"},
+		{JobID: "JOB00107", JobName: "PAYB3", Status: "OUTPUT", RetCode: "CANCELED"},
+		{JobID: "JOB00108", JobName: "PAYB4", Status: "OUTPUT"},
+		{JobID: "JOB00109", JobName: "PAYC1", Status: "ACTIVE"},
+		{JobID: "JOB00110", JobName: "PAYC2", Status: "ACTIVE"},
+		{JobID: "JOB00111", JobName: "PAYC3", Status: "INPUT"},
+	}
+}
+
+func TestSummarizeJobs(t *testing.T) {
+	var requests int32
+	server := newPagingServer(t, mixedStatusJobs(), &requests)
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	// A page of 4 forces the listing to be narrowed; every job is still counted
+	counts, err := jm.SummarizeJobs(&JobFilter{Owner: "*", Prefix: "PAY*", MaxJobs: 4})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"OUTPUT":         8,
+		"ACTIVE":         2,
+		"INPUT":          1,
+		SummaryCCZero:    2,
+		SummaryCCNonZero: 1,
+		SummaryAbend:     2,
+		SummaryJCLError:  1,
+		SummaryOtherRC:   2,
+	}, counts)
+	assert.Greater(t, atomic.LoadInt32(&requests), int32(1))
+
+	// A listing that cannot be narrowed returns its counts with an error
+	counts, err = jm.SummarizeJobs(&JobFilter{Owner: "*", Prefix: "PAY%1", MaxJobs: 2})
+	require.ErrorIs(t, err, ErrJobListTruncated)
+	assert.Equal(t, 2, counts["OUTPUT"]+counts["ACTIVE"]+counts["INPUT"])
+}
+
+func TestGetActiveJobs(t *testing.T) {
+	// The test server ignores the status filter, as some z/OSMF levels do
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mixedStatusJobs())
+	}))
+	defer server.Close()
+
+	session, err := createTestProfile(server.URL).NewSession()
+	require.NoError(t, err)
+	jm := NewJobManager(session)
+
+	jobList, err := jm.GetActiveJobs("*")
+	require.NoError(t, err)
+	require.Len(t, jobList.Jobs, 2)
+	assert.Equal(t, "PAYC1", jobList.Jobs[0].JobName)
+	assert.Equal(t, "PAYC2", jobList.Jobs[1].JobName)
+	assert.False(t, jobList.Truncated)
+	assert.Equal(t, "active", query.Get("status"))
+	assert.Equal(t, "*", query.Get("owner"))
+}
+
+func TestIsNativeCorrelator(t *testing.T) {
+	for _, correlator := range []string{
+		"J0012345SY1.....DB0D9A2E.......:",
+		"J0012345SY1.....DB0D9A2E.......:NIGHTLY",
+		"S0000042SYSA....C3D5F0A1.......:",
+		"T0001234SY1.....DB0D9A2E.......:USER PORTION OF THIRTY-TWO CHARS",
+	} {
+		assert.True(t, IsNativeCorrelator(correlator), correlator)
+	}
+	for _, correlator := range []string{
+		"IBMUSERA:JOB12345",
+		"JOB12345",
+		"J0012345SY1.....DB0D9A2E.......",
+		"J0012345SY1:JOB1",
+		"",
+	} {
+		assert.False(t, IsNativeCorrelator(correlator), correlator)
+	}
+	assert.Equal(t, "IBMUSERA:JOB12345", MakeCorrelator("IBMUSERA", "JOB12345"))
+}
+
+func TestCorrelatorFormats(t *testing.T) {
+	const native = "J0012345SY1.....DB0D9A2E.......:"
+	formats := map[string]string{
+		"native":       native,
+		"name and ID":  MakeCorrelator("IBMUSERA", "JOB12345"),
+		"bare job ID":  "JOB12345",
+		"user portion": "J0012345SY1.....DB0D9A2E.......:NIGHTLY RUN",
+	}
+
+	for name, correlator := range formats {
+		t.Run(name, func(t *testing.T) {
+			mock := zosmftest.NewMockZOSMF()
+			defer mock.Close()
+			mock.On("GET", "/restjobs/jobs/J0012345SY1.....DB0D9A2E.......:*").Return(zosmftest.JobJSON)
+			mock.OnGetJob("IBMUSERA", "JOB12345")
+			mock.OnListJobs().WithQuery("jobid", "JOB12345").Return("[" + zosmftest.JobJSON + "]")
+			mock.OnListSpoolFiles("IBMUSERA", "JOB12345")
+			for _, id := range []int{2, 3, 4} {
+				mock.OnReadSpoolFile("IBMUSERA", "JOB12345", id)
+			}
+			mock.On("DELETE", "/restjobs/jobs/IBMUSERA/JOB12345").ReturnStatus(http.StatusAccepted, `{"status": 0}`)
+			session, err := mock.NewSession()
+			require.NoError(t, err)
+			jm := NewJobManager(session)
+
+			job, err := jm.GetJob(correlator)
+			require.NoError(t, err)
+			assert.Equal(t, "JOB12345", job.JobID)
+			assert.Equal(t, native, job.JobCorrelator)
+			if IsNativeCorrelator(correlator) {
+				assert.Equal(t, "/restjobs/jobs/"+correlator, mock.LastRequest().Path)
+			}
+
+			files, err := jm.GetSpoolFilesByCorrelator(correlator)
+			require.NoError(t, err)
+			assert.Len(t, files, 3)
+
+			output, err := jm.GetJobOutput(correlator)
+			require.NoError(t, err)
+			assert.Contains(t, output["JESMSGLG"], "$HASP395 IBMUSERA ENDED")
+
+			require.NoError(t, jm.DeleteJob(correlator))
+			assert.Len(t, mock.RequestsTo("DELETE", "/restjobs/jobs/IBMUSERA/JOB12345"), 1)
+		})
+	}
+}
+
+func TestNewJobManagerOptions(t *testing.T) {
+	var paths []string
+	failed := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		if !failed && r.URL.Path == "/ibmzosmf/api/v1/restjobs/jobs/TESTJOB/JOB00001" {
+			failed = true
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jobid":"JOB00001","jobname":"TESTJOB","status":"OUTPUT","retcode":"CC 0000"}`))
+	}))
+	defer server.Close()
+
+	var deprecations []profile.Deprecation
+	profile.SetDeprecationLogger(func(d profile.Deprecation) {
+		deprecations = append(deprecations, d)
+	})
+	defer profile.SetDeprecationLogger(nil)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	require.NoError(t, err)
+
+	// The old constructors delegate to New
+	session, err := profile.CreateSessionDirectWithOptions(serverURL.Hostname(), port, "testuser", "testpass", false, "/api/v1")
+	require.NoError(t, err)
+	assert.Equal(t, New(session), NewJobManager(session))
+	assert.Equal(t, server.URL+"/ibmzosmf/api/v1", NewJobManager(session, WithBasePath("/ibmzosmf/api/v1")).Session().GetBaseURL())
+
+	jm, err := CreateJobManagerDirectWithOptions(serverURL.Hostname(), port, "testuser", "testpass", false, "/api/v1")
+	require.NoError(t, err)
+	_, err = jm.GetJobByNameID("TESTJOB", "JOB00001")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /api/v1/restjobs/jobs/TESTJOB/JOB00001"}, paths)
+
+	_, err = CreateJobManagerDirect(serverURL.Hostname(), port, "testuser", "testpass")
+	require.NoError(t, err)
+	assert.Equal(t, []profile.Deprecation{
+		{Function: "jobs.CreateJobManagerDirectWithOptions", Replacement: "jobs.New"},
+		{Function: "jobs.(*ZOSMFJobManager).GetJobByNameID", Replacement: "jobs.(*ZOSMFJobManager).GetJob"},
+		{Function: "jobs.CreateJobManagerDirect", Replacement: "jobs.New"},
+	}, deprecations)
+
+	// The correlator variants report nothing
+	deprecations = nil
+	_, err = jm.GetJob("TESTJOB:JOB00001")
+	require.NoError(t, err)
+	assert.Empty(t, deprecations)
+
+	// Nor do the spool file methods, which address a job by name and ID throughout
+	_, _ = jm.GetSpoolFiles("TESTJOB", "JOB00001")
+	_, _ = jm.GetSpoolFileContent("TESTJOB", "JOB00001", 1)
+	assert.Empty(t, deprecations)
+
+	// A 502 is retried under the overriding base path; deletes are sent once
+	paths = nil
+	jm = New(session, WithBasePath("/ibmzosmf/api/v1"), WithRetries(2))
+	job, err := jm.GetJobByNameID("TESTJOB", "JOB00001")
+	require.NoError(t, err)
+	assert.Equal(t, "CC 0000", job.RetCode)
+	require.NoError(t, jm.DeleteJobByNameID("TESTJOB", "JOB00001"))
+	assert.Equal(t, []string{
+		"GET /ibmzosmf/api/v1/restjobs/jobs/TESTJOB/JOB00001",
+		"GET /ibmzosmf/api/v1/restjobs/jobs/TESTJOB/JOB00001",
+		"DELETE /ibmzosmf/api/v1/restjobs/jobs/TESTJOB/JOB00001",
+	}, paths)
+}
@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/zowe/zowe-client-go-sdk/pkg/internal/deprecation"
 	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
 )
 
@@ -34,11 +36,26 @@ const (
 	JobFilesJCLEndpoint  = "/files/JCL/records"
 )
 
-// NewJobManager creates a job manager with the given session
-func NewJobManager(session *profile.Session) *ZOSMFJobManager {
-	return &ZOSMFJobManager{
+// Ensure ZOSMFJobManager implements JobManager
+var _ JobManager = (*ZOSMFJobManager)(nil)
+
+// MaxJobsLimit is the largest max-jobs value z/OSMF accepts
+const MaxJobsLimit = 1000
+
+// New creates a job manager with the given session, usually a *profile.Session
+func New(session profile.HTTPSession, opts ...ManagerOption) *ZOSMFJobManager {
+	jm := &ZOSMFJobManager{
 		session: session,
 	}
+	for _, opt := range opts {
+		opt(jm)
+	}
+	return jm
+}
+
+// NewJobManager creates a job manager with the given session; it is the same as New
+func NewJobManager(session profile.HTTPSession, opts ...ManagerOption) *ZOSMFJobManager {
+	return New(session, opts...)
 }
 
 // NewJobManagerFromProfile creates a job manager from a profile
@@ -47,25 +64,66 @@ func NewJobManagerFromProfile(profile *profile.ZOSMFProfile) (*ZOSMFJobManager,
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
-	return NewJobManager(session), nil
+	return New(session), nil
+}
+
+// Session returns the session the manager sends requests with, wrapped when
+// WithBasePath or WithRetries was given
+func (jm *ZOSMFJobManager) Session() profile.HTTPSession {
+	return jm.session
+}
+
+// do sends a request to a z/OSMF path through the manager's session; the caller
+// must close the response body
+func (jm *ZOSMFJobManager) do(method, path string, query url.Values, headers map[string]string, body io.Reader) (*http.Response, error) {
+	return profile.DoRequest(context.Background(), jm.session, method, path, query, headers, body)
+}
+
+// doJSON sends a request and decodes its JSON response into out, which may be nil
+func (jm *ZOSMFJobManager) doJSON(method, path string, query url.Values, headers map[string]string, body io.Reader, out interface{}) error {
+	return profile.DoJSONRequest(context.Background(), jm.session, method, path, query, headers, body, out)
+}
+
+// jobPath returns the path of a job, optionally followed by a sub-resource such as /files
+func jobPath(jobName, jobID, suffix string) string {
+	return fmt.Sprintf(JobByNameIDEndpoint, url.PathEscape(jobName), url.PathEscape(jobID)) + suffix
 }
 
 // ListJobs gets jobs matching the filter
 func (jm *ZOSMFJobManager) ListJobs(filter *JobFilter) (*JobList, error) {
-	session := jm.session.(*profile.Session)
+	session := jm.session
+
+	if filter != nil && filter.MaxJobs > MaxJobsLimit {
+		return nil, fmt.Errorf("max jobs cannot exceed %d, got: %d", MaxJobsLimit, filter.MaxJobs)
+	}
+	if filter != nil && filter.ActiveOnly && filter.Status != "" && !strings.EqualFold(filter.Status, "active") {
+		return nil, fmt.Errorf("active only cannot be combined with status %s", filter.Status)
+	}
 
 	// Build query parameters
 	params := url.Values{}
+
+	// Always send max-jobs so a full page can be recognized as truncated;
+	// without it z/OSMF silently applies its own default
+	limit := MaxJobsLimit
+	if filter != nil && filter.MaxJobs > 0 {
+		limit = filter.MaxJobs
+	}
+	params.Set("max-jobs", strconv.Itoa(limit))
+
+	// Owner defaults to the session user, as z/OSMF does; "*" lists all owners
+	owner := session.GetUser()
+	if filter != nil && filter.Owner != "" {
+		owner = filter.Owner
+	}
+	if owner != "" {
+		params.Set("owner", owner)
+	}
+
 	if filter != nil {
-		if filter.Owner != "" {
-			params.Set("owner", filter.Owner)
-		}
 		if filter.Prefix != "" {
 			params.Set("prefix", filter.Prefix)
 		}
-		if filter.MaxJobs > 0 {
-			params.Set("max-jobs", strconv.Itoa(filter.MaxJobs))
-		}
 		if filter.JobID != "" {
 			params.Set("jobid", filter.JobID)
 		}
@@ -78,58 +136,89 @@ func (jm *ZOSMFJobManager) ListJobs(filter *JobFilter) (*JobList, error) {
 		if filter.UserCorrelator != "" {
 			params.Set("user-correlator", filter.UserCorrelator)
 		}
+		if filter.ExecData {
+			params.Set("exec-data", "Y")
+		}
+		if filter.ActiveOnly && filter.Status == "" {
+			params.Set("status", "active")
+		}
 	}
 
-	// Build URL
-	apiURL := session.GetBaseURL() + JobsEndpoint
-	if len(params) > 0 {
-		apiURL += "?" + params.Encode()
-	}
-
-	// Create request
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+	resp, err := jm.do("GET", JobsEndpoint, params, nil, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Check response status
+	bodyBytes, err := io.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, profile.NewAPIError(resp.StatusCode, bodyBytes)
 	}
-
-	// Parse response with fallback for array responses
-	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
-	// First try object with jobs field
-	var jobList JobList
-	if err := json.Unmarshal(bodyBytes, &jobList); err == nil && (len(jobList.Jobs) > 0 || string(bodyBytes) == "{}") {
-		return &jobList, nil
+	jobList, err := decodeJobList(bodyBytes)
+	if err != nil {
+		return nil, err
 	}
-	// Fallback: direct array response
-	var jobsArr []Job
-	if err := json.Unmarshal(bodyBytes, &jobsArr); err == nil {
-		return &JobList{Jobs: jobsArr}, nil
+	// z/OSMF gives no other indication that more jobs matched
+	jobList.Truncated = len(jobList.Jobs) >= limit || jobList.TotalRows > len(jobList.Jobs)
+	return jobList, nil
+}
+
+// decodeJobList parses a job list response. z/OSMF returns a bare array of jobs,
+// while some levels and proxies wrap it in an object under "jobs" or "items",
+// possibly with a "totalRows" count.
+func decodeJobList(body []byte) (*JobList, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("failed to decode response: empty body")
+	}
+
+	switch trimmed[0] {
+	case '[':
+		jobs := []Job{}
+		if err := json.Unmarshal(trimmed, &jobs); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w: %s", err, string(body))
+		}
+		return &JobList{Jobs: jobs}, nil
+	case '{':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &fields); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w: %s", err, string(body))
+		}
+		if len(fields) == 0 {
+			return &JobList{Jobs: []Job{}}, nil
+		}
+		for _, key := range []string{"jobs", "items"} {
+			raw, ok := fields[key]
+			if !ok {
+				continue
+			}
+			jobs := []Job{}
+			if err := json.Unmarshal(raw, &jobs); err != nil {
+				return nil, fmt.Errorf("failed to decode %q in response: %w: %s", key, err, string(body))
+			}
+			jobList := &JobList{Jobs: jobs}
+			if total, ok := fields["totalRows"]; ok {
+				// A malformed count is ignored rather than failing the listing
+				json.Unmarshal(total, &jobList.TotalRows)
+			}
+			return jobList, nil
+		}
+		return nil, fmt.Errorf("failed to decode response: no jobs or items field: %s", string(body))
+	default:
+		return nil, fmt.Errorf("failed to decode response: %s", string(body))
 	}
-	return nil, fmt.Errorf("failed to decode response: %s", string(bodyBytes))
 }
 
-// GetJob retrieves detailed information about a specific job by correlator or job ID
+// GetJob retrieves detailed information about a specific job by a z/OSMF job correlator,
+// a "jobname:jobid" correlator or a job ID
 func (jm *ZOSMFJobManager) GetJob(correlator string) (*Job, error) {
+	if IsNativeCorrelator(correlator) {
+		return jm.GetJobByCorrelator(correlator)
+	}
+
 	// Check if it's already in correlator format (jobname:jobid)
 	if strings.Contains(correlator, ":") {
 		// Parse correlator to get jobname and jobid
@@ -137,72 +226,38 @@ func (jm *ZOSMFJobManager) GetJob(correlator string) (*Job, error) {
 		if err != nil {
 			return nil, fmt.Errorf("invalid correlator format: %w", err)
 		}
-		return jm.GetJobByNameID(jobName, jobID)
-	}
-
-	// If it's just a job ID, we need to find the job first
-	// List jobs and find the one with this job ID
-	jobFilter := &JobFilter{
-		JobID:   correlator,
-		MaxJobs: 100, // Get more jobs to find the one we need
+		return jm.getJobByNameID(jobName, jobID, nil)
 	}
 
-	jobList, err := jm.ListJobs(jobFilter)
+	// A bare job ID is unique on the system, so query it directly for any owner
+	jobList, err := jm.ListJobs(&JobFilter{
+		Owner: "*",
+		JobID: correlator,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find job with ID %s: %w", correlator, err)
 	}
 
-	// Find the job with the specified job ID
-	for _, job := range jobList.Jobs {
-		if job.JobID == correlator {
-			return jm.GetJobByNameID(job.JobName, job.JobID)
-		}
+	switch len(jobList.Jobs) {
+	case 0:
+		return nil, fmt.Errorf("job with ID %s not found", correlator)
+	case 1:
+		return &jobList.Jobs[0], nil
+	default:
+		return nil, fmt.Errorf("job ID %s matched %d jobs, use jobname:jobid instead", correlator, len(jobList.Jobs))
 	}
-
-	return nil, fmt.Errorf("job with ID %s not found", correlator)
 }
 
 // GetJobInfo retrieves job information
 func (jm *ZOSMFJobManager) GetJobInfo(correlator string) (*JobInfo, error) {
-	// Parse correlator to get jobname and jobid
-	jobName, jobID, err := parseCorrelator(correlator)
-	if err != nil {
-		return nil, fmt.Errorf("invalid correlator format: %w", err)
-	}
-
-	session := jm.session.(*profile.Session)
-
-	// Build URL using jobname/jobid format
-	apiURL := session.GetBaseURL() + fmt.Sprintf(JobByNameIDEndpoint, url.PathEscape(jobName), url.PathEscape(jobID)) + JobFilesEndpoint
-
-	// Create request
-	req, err := http.NewRequest("GET", apiURL, nil)
+	jobName, jobID, err := jm.resolveJobNameID(correlator)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	// Parse response
 	var jobInfo JobInfo
-	if err := json.NewDecoder(resp.Body).Decode(&jobInfo); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := jm.doJSON("GET", jobPath(jobName, jobID, JobFilesEndpoint), nil, nil, nil, &jobInfo); err != nil {
+		return nil, err
 	}
 
 	return &jobInfo, nil
@@ -218,67 +273,52 @@ func (jm *ZOSMFJobManager) GetJobStatus(correlator string) (string, error) {
 }
 
 // GetJobByNameID retrieves a job by job name and job id
+//
+// Deprecated: use GetJob with a "jobname:jobid" correlator.
 func (jm *ZOSMFJobManager) GetJobByNameID(jobName, jobID string) (*Job, error) {
-	session := jm.session.(*profile.Session)
-	apiURL := session.GetBaseURL() + fmt.Sprintf(JobByNameIDEndpoint, url.PathEscape(jobName), url.PathEscape(jobID))
+	deprecation.Report("jobs.(*ZOSMFJobManager).GetJobByNameID", "jobs.(*ZOSMFJobManager).GetJob")
+	return jm.getJobByNameID(jobName, jobID, nil)
+}
 
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-	resp, err := session.GetHTTPClient().Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
+// getJobByNameID retrieves a job by job name and job id with optional query parameters
+func (jm *ZOSMFJobManager) getJobByNameID(jobName, jobID string, params url.Values) (*Job, error) {
 	var job Job
-	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := jm.doJSON("GET", jobPath(jobName, jobID, ""), params, nil, nil, &job); err != nil {
+		return nil, err
 	}
 	return &job, nil
 }
 
 // GetJobByCorrelator retrieves a job by correlator
 func (jm *ZOSMFJobManager) GetJobByCorrelator(correlator string) (*Job, error) {
-	session := jm.session.(*profile.Session)
-	apiURL := session.GetBaseURL() + fmt.Sprintf(JobByCorrelatorEndpoint, url.PathEscape(correlator))
-
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-	resp, err := session.GetHTTPClient().Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
 	var job Job
-	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := jm.doJSON("GET", fmt.Sprintf(JobByCorrelatorEndpoint, url.PathEscape(correlator)), nil, nil, nil, &job); err != nil {
+		return nil, err
 	}
 	return &job, nil
 }
 
 // SubmitJob submits a new job
 func (jm *ZOSMFJobManager) SubmitJob(request *SubmitJobRequest) (*SubmitJobResponse, error) {
-	session := jm.session.(*profile.Session)
+	// Reject internal reader settings and symbols z/OSMF would not accept as headers
+	if err := validateInternalReader(request); err != nil {
+		return nil, err
+	}
+	if err := ValidateJCLSymbols(request.Symbols); err != nil {
+		return nil, err
+	}
 
-	// Build URL
-	apiURL := session.GetBaseURL() + JobsEndpoint
+	// Tag the submission so it can be found again if the response is lost
+	if request.UserCorrelator == "" && request.GenerateUserCorrelator {
+		correlator, err := NewUserCorrelator()
+		if err != nil {
+			return nil, err
+		}
+		request.UserCorrelator = correlator
+	}
+	if err := ValidateUserCorrelator(request.UserCorrelator); err != nil {
+		return nil, err
+	}
 
 	// Prepare request body and content type based on submission type
 	var requestBody []byte
@@ -290,11 +330,10 @@ func (jm *ZOSMFJobManager) SubmitJob(request *SubmitJobRequest) (*SubmitJobRespo
 		requestBody = []byte(request.JobStatement)
 		contentType = "text/plain"
 	} else if request.JobDataSet != "" {
-		// Submit job from dataset using JSON format
-		// z/OSMF expects the dataset name to be prefixed with "//" for absolute path
-		datasetPath := request.JobDataSet
-		if !strings.HasPrefix(datasetPath, "//") {
-			datasetPath = "//" + datasetPath
+		// Submit job from dataset using JSON format, with the name fully qualified
+		datasetPath, err := datasetFileReference(request.JobDataSet)
+		if err != nil {
+			return nil, err
 		}
 
 		body := map[string]interface{}{
@@ -308,276 +347,268 @@ func (jm *ZOSMFJobManager) SubmitJob(request *SubmitJobRequest) (*SubmitJobRespo
 			return nil, fmt.Errorf("failed to marshal dataset job request: %w", err)
 		}
 		contentType = "application/json"
-	} else if request.JobLocalFile != "" {
-		// Submit job from local file using JSON format
-		body := map[string]interface{}{
-			"file": request.JobLocalFile,
-		}
-		if request.Directory != "" {
-			body["directory"] = request.Directory
-		}
-		if request.Extension != "" {
-			body["extension"] = request.Extension
+	} else if request.JobUSSFile != "" {
+		// Submit job from a z/OS UNIX file; z/OSMF tells it apart from a dataset
+		// by the absolute path, so it is sent without the "//" prefix
+		if !strings.HasPrefix(request.JobUSSFile, "/") || strings.HasPrefix(request.JobUSSFile, "//") {
+			return nil, fmt.Errorf("USS file must be an absolute path: %s", request.JobUSSFile)
 		}
-		requestBody, err = json.Marshal(body)
+		requestBody, err = json.Marshal(map[string]interface{}{
+			"file": request.JobUSSFile,
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal local file job request: %w", err)
+			return nil, fmt.Errorf("failed to marshal USS file job request: %w", err)
 		}
 		contentType = "application/json"
+	} else if request.JobLocalFile != "" {
+		// Read the JCL from the local file and submit it as text, like a job statement
+		jcl, err := readLocalJCL(request.JobLocalFile, request.Directory, request.Extension)
+		if err != nil {
+			return nil, err
+		}
+		requestBody = jcl
+		contentType = "text/plain"
 	} else {
-		return nil, fmt.Errorf("no job source specified (jobStatement, jobDataSet, or jobLocalFile)")
-	}
-
-	// Create request (use PUT per z/OSMF documentation)
-	req, err := http.NewRequest("PUT", apiURL, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
+		return nil, fmt.Errorf("no job source specified (jobStatement, jobDataSet, jobUSSFile, or jobLocalFile)")
 	}
-	req.Header.Set("Content-Type", contentType)
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
+	headers := map[string]string{"Content-Type": contentType}
+	setInternalReaderHeaders(headers, request)
+	setSubmitHeaders(headers, request)
 
-	// Parse response
+	// Submit with PUT per z/OSMF documentation
 	var submitResponse SubmitJobResponse
-	if err := json.NewDecoder(resp.Body).Decode(&submitResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := jm.doJSON("PUT", JobsEndpoint, nil, headers, bytes.NewReader(requestBody), &submitResponse); err != nil {
+		return nil, err
 	}
 
 	return &submitResponse, nil
 }
 
-// CancelJob cancels a running job
-func (jm *ZOSMFJobManager) CancelJob(correlator string) error {
-	session := jm.session.(*profile.Session)
-
-	// Build URL
-	apiURL := session.GetBaseURL() + fmt.Sprintf(JobByCorrelatorEndpoint, url.PathEscape(correlator)) + CancelEndpoint
-
-	// Create request
-	req, err := http.NewRequest("PUT", apiURL, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// setInternalReaderHeaders adds the X-IBM-Intrdr-* headers requested on a submission
+func setInternalReaderHeaders(headers map[string]string, request *SubmitJobRequest) {
+	if request.InternalReaderClass != "" {
+		headers["X-IBM-Intrdr-Class"] = request.InternalReaderClass
+	}
+	if request.InternalReaderRecfm != "" {
+		headers["X-IBM-Intrdr-Recfm"] = strings.ToUpper(request.InternalReaderRecfm)
+	}
+	if request.InternalReaderLrecl > 0 {
+		headers["X-IBM-Intrdr-Lrecl"] = strconv.Itoa(request.InternalReaderLrecl)
 	}
+}
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
+// setSubmitHeaders adds the user correlator and JCL symbol headers requested on a submission
+func setSubmitHeaders(headers map[string]string, request *SubmitJobRequest) {
+	if request.UserCorrelator != "" {
+		headers["X-IBM-User-Correlator"] = request.UserCorrelator
+	}
+	for name, value := range request.Symbols {
+		headers["X-IBM-JCL-Symbol-"+name] = value
 	}
+}
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+// CancelJob cancels a job using a z/OSMF job correlator, a jobname:jobid correlator or a bare job ID
+func (jm *ZOSMFJobManager) CancelJob(correlator string) error {
+	jobName, jobID, err := jm.resolveJobNameID(correlator)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
+	return jm.cancelJobByNameID(jobName, jobID)
+}
 
-	return nil
+// CancelJobByNameID cancels a job using separate jobName and jobID
+//
+// Deprecated: use CancelJob with a "jobname:jobid" correlator.
+func (jm *ZOSMFJobManager) CancelJobByNameID(jobName, jobID string) error {
+	deprecation.Report("jobs.(*ZOSMFJobManager).CancelJobByNameID", "jobs.(*ZOSMFJobManager).CancelJob")
+	return jm.cancelJobByNameID(jobName, jobID)
 }
 
-// DeleteJob deletes a job using correlator format (jobname:jobid)
-func (jm *ZOSMFJobManager) DeleteJob(correlator string) error {
-	// Parse correlator to get jobname and jobid
-	jobName, jobID, err := parseCorrelator(correlator)
+// cancelJobByNameID cancels a job using separate jobName and jobID
+func (jm *ZOSMFJobManager) cancelJobByNameID(jobName, jobID string) error {
+	// z/OSMF cancels a job through a PUT carrying a cancel request
+	jsonBody, err := json.Marshal(map[string]string{"request": "cancel", "version": "2.0"})
 	if err != nil {
-		return fmt.Errorf("invalid correlator format: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	return jm.DeleteJobByNameID(jobName, jobID)
+	headers := map[string]string{"Content-Type": "application/json"}
+	return jm.doJSON("PUT", jobPath(jobName, jobID, ""), nil, headers, bytes.NewReader(jsonBody), nil)
 }
 
-// DeleteJobByNameID deletes a job using separate jobName and jobID
-func (jm *ZOSMFJobManager) DeleteJobByNameID(jobName, jobID string) error {
-	session := jm.session.(*profile.Session)
-
-	// Build URL using jobName and jobID format
-	apiURL := session.GetBaseURL() + fmt.Sprintf(JobByNameIDEndpoint, url.PathEscape(jobName), url.PathEscape(jobID))
-
-	// Create request
-	req, err := http.NewRequest("DELETE", apiURL, nil)
+// DeleteJob deletes a job using a z/OSMF job correlator, a jobname:jobid correlator or a bare job ID
+func (jm *ZOSMFJobManager) DeleteJob(correlator string) error {
+	jobName, jobID, err := jm.resolveJobNameID(correlator)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
+	return jm.deleteJobByNameID(jobName, jobID)
+}
+
+// resolveJobNameID returns the job name and ID for a jobname:jobid correlator,
+// looking the job up when a z/OSMF job correlator or only a job ID is given
+func (jm *ZOSMFJobManager) resolveJobNameID(correlator string) (string, string, error) {
+	if !IsNativeCorrelator(correlator) && strings.Contains(correlator, ":") {
+		jobName, jobID, err := parseCorrelator(correlator)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid correlator format: %w", err)
+		}
+		return jobName, jobID, nil
 	}
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+	job, err := jm.GetJob(correlator)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return "", "", err
 	}
-	defer resp.Body.Close()
+	return job.JobName, job.JobID, nil
+}
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
+// DeleteJobByNameID deletes a job using separate jobName and jobID
+//
+// Deprecated: use DeleteJob with a "jobname:jobid" correlator.
+func (jm *ZOSMFJobManager) DeleteJobByNameID(jobName, jobID string) error {
+	deprecation.Report("jobs.(*ZOSMFJobManager).DeleteJobByNameID", "jobs.(*ZOSMFJobManager).DeleteJob")
+	return jm.deleteJobByNameID(jobName, jobID)
+}
 
-	return nil
+// deleteJobByNameID deletes a job using separate jobName and jobID
+func (jm *ZOSMFJobManager) deleteJobByNameID(jobName, jobID string) error {
+	return jm.doJSON("DELETE", jobPath(jobName, jobID, ""), nil, nil, nil, nil)
 }
 
 // GetSpoolFiles retrieves spool files for a job using jobname and jobid
 func (jm *ZOSMFJobManager) GetSpoolFiles(jobName, jobID string) ([]SpoolFile, error) {
-	session := jm.session.(*profile.Session)
-
-	// Build URL using the correct z/OSMF format: /restjobs/jobs/{jobname}/{jobid}/files
-	apiURL := session.GetBaseURL() + fmt.Sprintf(JobByNameIDEndpoint, url.PathEscape(jobName), url.PathEscape(jobID)) + JobFilesEndpoint
-
-	// Create request
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	// Path in the z/OSMF format: /restjobs/jobs/{jobname}/{jobid}/files
+	var spoolFiles []SpoolFile
+	if err := jm.doJSON("GET", jobPath(jobName, jobID, JobFilesEndpoint), nil, nil, nil, &spoolFiles); err != nil {
+		return nil, err
 	}
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
+	return spoolFiles, nil
+}
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+// GetSpoolFileByDDName returns the descriptor of a job's spool file with the given
+// DD name. When several steps write the same DD name, the first one is returned.
+func (jm *ZOSMFJobManager) GetSpoolFileByDDName(jobName, jobID, ddName string) (*SpoolFile, error) {
+	if ddName == "" {
+		return nil, fmt.Errorf("DD name is required")
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	spoolFiles, err := jm.GetSpoolFiles(jobName, jobID)
+	if err != nil {
+		return nil, err
 	}
-
-	// Parse response
-	var spoolFiles []SpoolFile
-	if err := json.NewDecoder(resp.Body).Decode(&spoolFiles); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	for i := range spoolFiles {
+		if strings.EqualFold(spoolFiles[i].DDName, ddName) {
+			return &spoolFiles[i], nil
+		}
 	}
-
-	return spoolFiles, nil
+	return nil, fmt.Errorf("DD name %s not found for job %s(%s)", ddName, jobName, jobID)
 }
 
 // GetSpoolFileContent retrieves the content of a specific spool file
 func (jm *ZOSMFJobManager) GetSpoolFileContent(jobName, jobID string, spoolID int) (string, error) {
-	session := jm.session.(*profile.Session)
+	return jm.GetSpoolFileContentWithOptions(jobName, jobID, spoolID, nil)
+}
+
+// GetSpoolFileContentWithOptions retrieves the content of a spool file using a record range and encoding
+func (jm *ZOSMFJobManager) GetSpoolFileContentWithOptions(jobName, jobID string, spoolID int, opts *SpoolContentOptions) (string, error) {
+	var buf bytes.Buffer
+	if _, err := jm.GetSpoolFileContentStreamWithOptions(jobName, jobID, spoolID, opts, &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
 
-	// Build URL using the correct z/OSMF format: /restjobs/jobs/{jobname}/{jobid}/files/{id}/records
-	apiURL := session.GetBaseURL() + fmt.Sprintf(JobByNameIDEndpoint, url.PathEscape(jobName), url.PathEscape(jobID)) + fmt.Sprintf(JobFilesByIDEndpoint, strconv.Itoa(spoolID))
+// GetSpoolFileContentStream copies the content of a spool file to w without buffering it in memory
+func (jm *ZOSMFJobManager) GetSpoolFileContentStream(jobName, jobID string, spoolID int, w io.Writer) (int64, error) {
+	return jm.GetSpoolFileContentStreamWithOptions(jobName, jobID, spoolID, nil, w)
+}
 
-	// Create request
-	req, err := http.NewRequest("GET", apiURL, nil)
+// GetSpoolFileContentStreamWithOptions copies the content of a spool file to w, honoring a record range and encoding
+func (jm *ZOSMFJobManager) GetSpoolFileContentStreamWithOptions(jobName, jobID string, spoolID int, opts *SpoolContentOptions, w io.Writer) (int64, error) {
+	body, err := jm.GetSpoolFileReaderWithContext(context.Background(), jobName, jobID, spoolID, opts)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return 0, err
 	}
+	defer body.Close()
 
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
+	// Copy response body
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+	return n, nil
+}
+
+// GetJobJCL retrieves the JCL a job was submitted with, using a z/OSMF job correlator, a jobname:jobid correlator or a bare job ID
+func (jm *ZOSMFJobManager) GetJobJCL(correlator string) (string, error) {
+	jobName, jobID, err := jm.resolveJobNameID(correlator)
 	if err != nil {
-		return "", fmt.Errorf("failed to make request: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	// Path: /restjobs/jobs/{jobname}/{jobid}/files/JCL/records
+	resp, err := jm.do("GET", jobPath(jobName, jobID, JobFilesJCLEndpoint), jm.fileEncodingParams(""), nil, nil)
+	if err != nil {
+		return "", err
 	}
+	defer resp.Body.Close()
 
-	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	// Check response status
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("JCL for job %s(%s) is no longer available, the job may have been purged: %s", jobName, jobID, string(body))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", profile.NewAPIError(resp.StatusCode, body)
+	}
+
 	return string(body), nil
 }
 
-// GetSpoolFilesByCorrelator retrieves spool files for a job using correlator format (jobname:jobid)
-// This is a convenience method that maintains backward compatibility
+// GetSpoolFilesByCorrelator retrieves spool files for a job using a z/OSMF job correlator,
+// a jobname:jobid correlator or a bare job ID
 func (jm *ZOSMFJobManager) GetSpoolFilesByCorrelator(correlator string) ([]SpoolFile, error) {
-	jobName, jobID, err := parseCorrelator(correlator)
+	jobName, jobID, err := jm.resolveJobNameID(correlator)
 	if err != nil {
-		return nil, fmt.Errorf("invalid correlator format: %w", err)
+		return nil, err
 	}
 	return jm.GetSpoolFiles(jobName, jobID)
 }
 
-// GetSpoolFileContentByCorrelator retrieves the content of a specific spool file using correlator format
-// This is a convenience method that maintains backward compatibility
+// GetSpoolFileContentByCorrelator retrieves the content of a specific spool file using a
+// z/OSMF job correlator, a jobname:jobid correlator or a bare job ID
 func (jm *ZOSMFJobManager) GetSpoolFileContentByCorrelator(correlator string, spoolID int) (string, error) {
-	jobName, jobID, err := parseCorrelator(correlator)
+	jobName, jobID, err := jm.resolveJobNameID(correlator)
 	if err != nil {
-		return "", fmt.Errorf("invalid correlator format: %w", err)
+		return "", err
 	}
-	return jm.GetSpoolFileContent(jobName, jobID, spoolID)
+	return jm.GetSpoolFileContentWithOptions(jobName, jobID, spoolID, nil)
 }
 
-// PurgeJob purges a job (removes it from the system)
+// PurgeJob purges a job and its output using a z/OSMF job correlator, a jobname:jobid correlator or a bare job ID.
+// z/OSMF purges through the same DELETE request as DeleteJob.
 func (jm *ZOSMFJobManager) PurgeJob(correlator string) error {
-	session := jm.session.(*profile.Session)
-
-	// Build URL
-	apiURL := session.GetBaseURL() + fmt.Sprintf(JobByCorrelatorEndpoint, url.PathEscape(correlator)) + PurgeEndpoint
-
-	// Create request
-	req, err := http.NewRequest("PUT", apiURL, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add headers
-	for key, value := range session.GetHeaders() {
-		req.Header.Set(key, value)
-	}
-
-	// Make request
-	resp, err := session.GetHTTPClient().Do(req)
+	jobName, jobID, err := jm.resolveJobNameID(correlator)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return err
 	}
 
-	return nil
+	return jm.deleteJobByNameID(jobName, jobID)
 }
 
 // CloseJobManager closes the job manager and its underlying HTTP connections
 func (jm *ZOSMFJobManager) CloseJobManager() error {
-	session := jm.session.(*profile.Session)
+	session := jm.session
 
 	// Close idle connections in the HTTP client
 	if client := session.GetHTTPClient(); client != nil {
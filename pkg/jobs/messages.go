@@ -0,0 +1,257 @@
+package jobs
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// messageDDNames are the spool files GetJobMessages reads, in the order they are returned
+var messageDDNames = []string{"JESMSGLG", "JESYSMSG"}
+
+// messageIDPattern matches a message ID such as IEF450I, $HASP395, IAT6140 or ICH70001I
+var messageIDPattern = regexp.MustCompile(`^(?:\$HASP\d{3,4}|[A-Z][A-Z0-9@#$]{2,4}\d{3,5}[A-Z]?)$`)
+
+// logTimePattern matches the time of day at the start of a log line: hh.mm.ss on JES2
+// and hh:mm:ss on JES3
+var logTimePattern = regexp.MustCompile(`^(\d{2})[.:](\d{2})[.:](\d{2})$`)
+
+// logJobIDPattern matches the job ID JES2 writes after the time of day
+var logJobIDPattern = regexp.MustCompile(`^(?:(?:JOB|STC|TSU)\d{5}|[JST]\d{7})$`)
+
+// logDatePattern matches the date line, e.g. "---- MONDAY,    03 JUN 2024 ----"
+var logDatePattern = regexp.MustCompile(`^-{4}\s+[A-Z]+,\s+(\d{1,2})\s+([A-Z]{3})\s+(\d{4})\s+-{4}$`)
+
+// stepSlashPattern finds the step name in messages such as IEF373I STEP/STEP1 /START
+var stepSlashPattern = regexp.MustCompile(`\bSTEP/([A-Z0-9@#$]{1,8})\s*/`)
+
+// stepMessages are messages of the form "ID jobname stepname [procstep] - ..."
+var stepMessages = map[string]bool{"IEF142I": true, "IEF272I": true, "IEF450I": true, "IEF472I": true}
+
+// abendPatterns find an abend in message text; the first group is the system code and
+// the second the user code, either of which may be absent
+var abendPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`ABEND=S([0-9A-F]{3})\s+U(\d{4})`),
+	regexp.MustCompile(`SYSTEM=([0-9A-F]{3})\s+USER=(\d{4})`),
+	regexp.MustCompile(`ABEND[= ]S([0-9A-F]{3})()\b`),
+	regexp.MustCompile(`ABEND[= ]()U(\d{4})\b`),
+	regexp.MustCompile(`SYSTEM COMPLETION CODE=([0-9A-F]{3})()`),
+	regexp.MustCompile(`USER COMPLETION CODE=()(\d{4})`),
+}
+
+// ParseMessages parses the content of a JESMSGLG or JESYSMSG spool file into system
+// messages. JES2 lines ("10.15.02 JOB12345  IEF403I ...") and JES3 lines
+// ("10:15:02 IAT6140 ...") are both understood, as are lines with no time of day, and
+// indented lines are joined to the message they continue. Headers, statistics and
+// other lines that carry no message are skipped.
+func ParseMessages(spoolContent string) ([]SystemMessage, error) {
+	messages := []SystemMessage{}
+	var current *SystemMessage
+	var connectID string
+	var date time.Time
+	var step string
+	var endsStep bool
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if current.StepName == "" {
+			current.StepName = step
+		}
+		messages = append(messages, *current)
+		current = nil
+		if endsStep {
+			step = ""
+			endsStep = false
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(spoolContent))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		// Indented lines without a time of day continue the current message; JES2
+		// starts them with the connect ID of a multi-line message
+		fields := strings.Fields(line)
+		text := strings.TrimSpace(line)
+		if current != nil && (line[0] == ' ' || line[0] == '\t') && !logTimePattern.MatchString(fields[0]) && !strings.HasPrefix(text, "---") {
+			if connectID != "" && fields[0] == connectID {
+				text = strings.TrimSpace(strings.TrimPrefix(text, connectID))
+			}
+			current.Text += "\n" + text
+			continue
+		}
+
+		timestamp, jobID, rest := splitLogPrefix(line)
+		if m := logDatePattern.FindStringSubmatch(strings.TrimSpace(rest)); m != nil {
+			flush()
+			if parsed, err := time.Parse("2 Jan 2006", m[1]+" "+m[2]+" "+m[3]); err == nil {
+				date = parsed
+			}
+			continue
+		}
+
+		flush()
+		text = strings.TrimSpace(rest)
+		id := strings.TrimLeft(firstField(text), "+*@")
+		if !messageIDPattern.MatchString(id) {
+			continue
+		}
+
+		current = &SystemMessage{
+			ID:        id,
+			Timestamp: timestamp,
+			JobID:     jobID,
+			Text:      text,
+			Line:      lineNumber,
+		}
+		if timestamp != "" && !date.IsZero() {
+			if clock, err := time.Parse("15:04:05", strings.ReplaceAll(timestamp, ".", ":")); err == nil {
+				current.Time = date.Add(clock.Sub(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)))
+			}
+		}
+		connectID = ""
+		if last := fields[len(fields)-1]; len(fields) > 2 && strings.Trim(last, "0123456789") == "" {
+			connectID = last
+		}
+
+		// Allocation and step start messages name the step the messages after them
+		// belong to, until the step ends
+		current.StepName = messageStepName(id, text)
+		switch id {
+		case "IEF236I", "IEF373I":
+			if current.StepName != "" {
+				step = current.StepName
+			}
+		case "IEF374I", "IEF032I", "IEF375I", "IEF033I":
+			endsStep = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read spool content: %w", err)
+	}
+	flush()
+
+	return messages, nil
+}
+
+// splitLogPrefix splits the time of day and JES2 job ID off the start of a log line
+func splitLogPrefix(line string) (timestamp, jobID, rest string) {
+	rest = line
+	trimmed := strings.TrimLeft(rest, " ")
+	if field := firstField(trimmed); logTimePattern.MatchString(field) {
+		timestamp = field
+		rest = trimmed[len(field):]
+		trimmed = strings.TrimLeft(rest, " ")
+		if field := firstField(trimmed); logJobIDPattern.MatchString(field) {
+			jobID = field
+			rest = trimmed[len(field):]
+		}
+	}
+	return timestamp, jobID, rest
+}
+
+// firstField returns the text up to the first blank
+func firstField(s string) string {
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// messageStepName returns the step a message names, or "" if it names none
+func messageStepName(id, text string) string {
+	if m := stepSlashPattern.FindStringSubmatch(text); m != nil {
+		return m[1]
+	}
+
+	fields := strings.Fields(text)
+	switch {
+	case id == "IEF236I":
+		// IEF236I ALLOC. FOR jobname stepname
+		for i := 1; i+2 < len(fields); i++ {
+			if fields[i] == "FOR" {
+				return fields[i+2]
+			}
+		}
+	case stepMessages[id]:
+		// ID jobname stepname [procstep] - ...
+		if len(fields) > 3 && fields[2] != "-" {
+			return fields[2]
+		}
+	}
+	return ""
+}
+
+// FindAbendCode returns the first abend code the messages report, such as "S0C4" or
+// "U4038", and whether one was found
+func FindAbendCode(messages []SystemMessage) (string, bool) {
+	for _, message := range messages {
+		text := strings.ToUpper(message.Text)
+		for _, pattern := range abendPatterns {
+			m := pattern.FindStringSubmatch(text)
+			if m == nil {
+				continue
+			}
+			if m[1] != "" && m[1] != "000" {
+				return "S" + m[1], true
+			}
+			if m[2] != "" && m[2] != "0000" {
+				return "U" + m[2], true
+			}
+		}
+	}
+	return "", false
+}
+
+// GetJobMessages fetches a job's JESMSGLG and JESYSMSG spool files and parses them into
+// system messages, JESMSGLG first. Each message records the DD name it came from.
+func (jm *ZOSMFJobManager) GetJobMessages(correlator string) ([]SystemMessage, error) {
+	jobName, jobID, err := jm.resolveJobNameID(correlator)
+	if err != nil {
+		return nil, err
+	}
+
+	spoolFiles, err := jm.GetSpoolFiles(jobName, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []SystemMessage{}
+	found := false
+	for _, ddName := range messageDDNames {
+		for _, spoolFile := range spoolFiles {
+			if !strings.EqualFold(spoolFile.DDName, ddName) {
+				continue
+			}
+			found = true
+			content, err := jm.GetSpoolFileContentWithOptions(jobName, jobID, spoolFile.ID, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", ddName, err)
+			}
+			parsed, err := ParseMessages(content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", ddName, err)
+			}
+			for i := range parsed {
+				parsed[i].DDName = ddName
+			}
+			messages = append(messages, parsed...)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("job %s(%s) has no JESMSGLG or JESYSMSG spool file", jobName, jobID)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import "github.com/zowe/zowe-client-go-sdk/pkg/internal/request"
+
+// ManagerOption customizes a job manager created by New
+type ManagerOption func(*ZOSMFJobManager)
+
+// WithBasePath sends requests under basePath instead of the session's base path, e.g.
+// "/ibmzosmf/api/v1" through the API Mediation Layer
+func WithBasePath(basePath string) ManagerOption {
+	return func(jm *ZOSMFJobManager) {
+		jm.session = request.WithBasePath(jm.session, basePath)
+	}
+}
+
+// WithRetries retries GET and HEAD requests up to n times when they fail with a
+// transport error or a 502, 503 or 504 status, waiting 250ms and then twice as long
+// before each further retry. Submissions, cancels and deletes are never retried.
+func WithRetries(n int) ManagerOption {
+	return func(jm *ZOSMFJobManager) {
+		jm.session = request.WithRetries(jm.session, n, request.DefaultRetryBackoff)
+	}
+}
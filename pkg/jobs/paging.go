@@ -0,0 +1,117 @@
+package jobs
+
+import "strings"
+
+// Characters allowed in job names, used to narrow a truncated prefix listing
+const (
+	jobNameFirstChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ$#@"
+	jobNameChars      = jobNameFirstChars + "0123456789"
+	maxJobNameLength  = 8
+)
+
+// maxJobListRequests caps the listings one ListAllJobs call makes, since every
+// truncated prefix is narrowed into up to 37 more
+var maxJobListRequests = 1000
+
+// ListAllJobs lists every job matching the filter. z/OSMF returns at most 1000 jobs per
+// request and has no paging parameters, so a truncated listing is repeated with the job
+// name prefix narrowed by one character at a time until each listing fits in a page.
+// filter.MaxJobs sets the page size (default 1000). The result is still marked Truncated
+// when a listing cannot be narrowed: a single job name with more jobs than fit in a page,
+// a prefix with wildcards other than a trailing *, or a JobID filter. It is also marked
+// Truncated when narrowing stops after 1000 listings.
+func (jm *ZOSMFJobManager) ListAllJobs(filter *JobFilter) (*JobList, error) {
+	base := JobFilter{}
+	if filter != nil {
+		base = *filter
+	}
+
+	stem := strings.ToUpper(base.Prefix)
+	wildcard := stem == "" || strings.HasSuffix(stem, "*")
+	stem = strings.TrimSuffix(stem, "*")
+	narrowable := wildcard && base.JobID == "" && !strings.ContainsAny(stem, "*%")
+
+	lister := &jobLister{jm: jm, base: base, result: &JobList{Jobs: []Job{}}, seen: make(map[string]bool)}
+	if narrowable {
+		if err := lister.listPrefix(stem); err != nil {
+			return nil, err
+		}
+	} else {
+		truncated, err := lister.list(base.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		lister.result.Truncated = truncated
+	}
+	return lister.result, nil
+}
+
+// jobLister collects the jobs of several listings, skipping duplicates
+type jobLister struct {
+	jm       *ZOSMFJobManager
+	base     JobFilter
+	result   *JobList
+	seen     map[string]bool
+	requests int
+}
+
+// list runs one listing with the given prefix and reports whether it was truncated.
+// Once maxJobListRequests is reached it lists nothing and marks the result Truncated.
+func (l *jobLister) list(prefix string) (bool, error) {
+	if l.requests >= maxJobListRequests {
+		l.result.Truncated = true
+		return false, nil
+	}
+	l.requests++
+
+	filter := l.base
+	filter.Prefix = prefix
+	jobList, err := l.jm.ListJobs(&filter)
+	if err != nil {
+		return false, err
+	}
+	for _, job := range jobList.Jobs {
+		key := job.JobID
+		if key == "" {
+			key = job.JobName
+		}
+		if l.seen[key] {
+			continue
+		}
+		l.seen[key] = true
+		l.result.Jobs = append(l.result.Jobs, job)
+	}
+	return jobList.Truncated, nil
+}
+
+// listPrefix lists the jobs whose names start with stem, narrowing while truncated
+func (l *jobLister) listPrefix(stem string) error {
+	truncated, err := l.list(stem + "*")
+	if err != nil || !truncated {
+		return err
+	}
+	if len(stem) >= maxJobNameLength {
+		l.result.Truncated = true
+		return nil
+	}
+
+	// Jobs named exactly stem are not covered by the longer prefixes
+	chars := jobNameFirstChars
+	if stem != "" {
+		chars = jobNameChars
+		truncated, err := l.list(stem)
+		if err != nil {
+			return err
+		}
+		if truncated {
+			l.result.Truncated = true
+		}
+	}
+
+	for _, c := range chars {
+		if err := l.listPrefix(stem + string(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package jobs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+)
+
+// maxSpoolLineLength bounds the longest line GetSpoolFileTailLines can scan
+const maxSpoolLineLength = 1024 * 1024
+
+// UnmarshalJSON also accepts the record-count, byte-count and records-url names
+// z/OSMF uses in spool file listings
+func (f *SpoolFile) UnmarshalJSON(data []byte) error {
+	type plainSpoolFile SpoolFile
+	var aux struct {
+		plainSpoolFile
+		RecordCount int    `json:"record-count"`
+		ByteCount   int    `json:"byte-count"`
+		RecordsURL  string `json:"records-url"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*f = SpoolFile(aux.plainSpoolFile)
+	if f.Records == 0 {
+		f.Records = aux.RecordCount
+	}
+	if f.Bytes == 0 {
+		f.Bytes = aux.ByteCount
+	}
+	if f.ContentURL == "" {
+		f.ContentURL = aux.RecordsURL
+	}
+	return nil
+}
+
+// spoolFileByID returns the descriptor of a job's spool file with the given ID
+func (jm *ZOSMFJobManager) spoolFileByID(jobName, jobID string, spoolID int) (*SpoolFile, error) {
+	spoolFiles, err := jm.GetSpoolFiles(jobName, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spool files: %w", err)
+	}
+	for i := range spoolFiles {
+		if spoolFiles[i].ID == spoolID {
+			return &spoolFiles[i], nil
+		}
+	}
+	return nil, fmt.Errorf("spool file %d not found for job %s(%s)", spoolID, jobName, jobID)
+}
+
+// GetSpoolFileContentWithInfo retrieves the content of a spool file along with the number
+// of records and bytes returned and the totals the spool file declares, so callers can
+// tell when z/OSMF returned only part of a large file. Set opts.AllRecords to ask for
+// every record.
+func (jm *ZOSMFJobManager) GetSpoolFileContentWithInfo(jobName, jobID string, spoolID int, opts *SpoolContentOptions) (*SpoolContent, error) {
+	spoolFile, err := jm.spoolFileByID(jobName, jobID, spoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	n, err := jm.GetSpoolFileContentStreamWithOptions(jobName, jobID, spoolID, opts, &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	content := buf.String()
+	result := &SpoolContent{
+		Content:      content,
+		Records:      countRecords(content),
+		Bytes:        n,
+		TotalRecords: spoolFile.Records,
+		TotalBytes:   spoolFile.Bytes,
+	}
+
+	// Compare against the part of the file that was asked for
+	expected := spoolFile.Records
+	if opts != nil && opts.RecordRange != "" {
+		start, count, err := parseRecordRange(opts.RecordRange)
+		if err != nil {
+			return nil, err
+		}
+		expected -= start
+		if expected > count {
+			expected = count
+		}
+	}
+	result.Truncated = spoolFile.Records > 0 && result.Records < expected
+
+	return result, nil
+}
+
+// countRecords returns the number of lines in spool content
+func countRecords(content string) int {
+	if content == "" {
+		return 0
+	}
+	records := strings.Count(content, "\n")
+	if !strings.HasSuffix(content, "\n") {
+		records++
+	}
+	return records
+}
+
+// GetSpoolFileReader returns the content of a spool file as a stream, so large
+// SYSOUT such as dumps never has to fit in memory. The caller must close it.
+func (jm *ZOSMFJobManager) GetSpoolFileReader(jobName, jobID string, spoolID int) (io.ReadCloser, error) {
+	return jm.GetSpoolFileReaderWithContext(context.Background(), jobName, jobID, spoolID, nil)
+}
+
+// GetSpoolFileReaderWithContext is GetSpoolFileReader with options and a context.
+// Canceling ctx aborts the request, including a read in progress.
+func (jm *ZOSMFJobManager) GetSpoolFileReaderWithContext(ctx context.Context, jobName, jobID string, spoolID int, opts *SpoolContentOptions) (io.ReadCloser, error) {
+	resp, err := jm.openSpoolFile(ctx, jobName, jobID, spoolID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil || opts.Progress == nil {
+		return resp.Body, nil
+	}
+	return &progressReadCloser{
+		Reader: profile.NewProgressReader(resp.Body, resp.ContentLength, opts.Progress),
+		Closer: resp.Body,
+	}, nil
+}
+
+// progressReadCloser reports progress while reading and closes the underlying body
+type progressReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// fileEncodingParams returns the fileEncoding query parameter for reading spool records:
+// the requested code page, else the profile's encoding, else none for the z/OSMF default
+func (jm *ZOSMFJobManager) fileEncodingParams(requested string) url.Values {
+	encoding := profile.NormalizeEncoding(requested)
+	if encoding == "" {
+		encoding = profile.SessionEncoding(jm.session)
+	}
+	if encoding == "" {
+		return nil
+	}
+	return url.Values{"fileEncoding": {encoding}}
+}
+
+// openSpoolFile requests the records of a spool file and returns the successful response
+func (jm *ZOSMFJobManager) openSpoolFile(ctx context.Context, jobName, jobID string, spoolID int, opts *SpoolContentOptions) (*http.Response, error) {
+	if opts != nil && opts.RecordRange != "" {
+		if err := ValidateRecordRange(opts.RecordRange); err != nil {
+			return nil, err
+		}
+	}
+
+	// Path in the z/OSMF format: /restjobs/jobs/{jobname}/{jobid}/files/{id}/records
+	path := fmt.Sprintf(JobByNameIDEndpoint, url.PathEscape(jobName), url.PathEscape(jobID)) + fmt.Sprintf(JobFilesByIDEndpoint, strconv.Itoa(spoolID))
+	requested := ""
+	headers := map[string]string{}
+	if opts != nil {
+		requested = opts.Encoding
+		if opts.RecordRange != "" {
+			headers["X-IBM-Record-Range"] = opts.RecordRange
+		}
+		if opts.AllRecords {
+			headers["X-IBM-Max-Items"] = "0"
+		}
+	}
+
+	resp, err := profile.DoRequest(ctx, jm.session, "GET", path, jm.fileEncodingParams(requested), headers, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check response status
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, profile.NewAPIError(resp.StatusCode, body)
+	}
+
+	return resp, nil
+}
+
+// GetSpoolFileTailLines returns the last lines of a spool file, read as GetSpoolFileTail does
+func (jm *ZOSMFJobManager) GetSpoolFileTailLines(ctx context.Context, jobName, jobID string, spoolID, lines int) ([]string, error) {
+	if lines <= 0 {
+		return nil, fmt.Errorf("line count must be positive")
+	}
+
+	var buf bytes.Buffer
+	if _, err := jm.spoolFileTail(ctx, jobName, jobID, spoolID, lines, &buf); err != nil {
+		return nil, err
+	}
+	return tailLines(&buf, lines)
+}
+
+// spoolFileTail copies the last n records of a spool file to w. Spool files without a
+// record count are streamed, keeping only the last n lines in memory.
+func (jm *ZOSMFJobManager) spoolFileTail(ctx context.Context, jobName, jobID string, spoolID, n int, w io.Writer) (int64, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("record count must be positive")
+	}
+
+	spoolFile, err := jm.spoolFileByID(jobName, jobID, spoolID)
+	if err != nil {
+		return 0, err
+	}
+
+	var opts *SpoolContentOptions
+	if spoolFile.Records > 0 {
+		start := spoolFile.Records - n
+		if start < 0 {
+			start = 0
+		}
+		opts = &SpoolContentOptions{RecordRange: fmt.Sprintf("%d,%d", start, n)}
+	}
+
+	body, err := jm.GetSpoolFileReaderWithContext(ctx, jobName, jobID, spoolID, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	if opts != nil {
+		written, err := io.Copy(w, body)
+		if err != nil {
+			return written, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return written, nil
+	}
+
+	lines, err := tailLines(body, n)
+	if err != nil {
+		return 0, err
+	}
+	var written int64
+	for _, line := range lines {
+		count, err := io.WriteString(w, line+"\n")
+		written += int64(count)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// tailLines returns the last n lines read from r, holding at most n lines in memory
+func tailLines(r io.Reader, n int) ([]string, error) {
+	ring := make([]string, n)
+	count := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSpoolLineLength)
+	for scanner.Scan() {
+		ring[count%n] = strings.TrimSuffix(scanner.Text(), "\r")
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read spool file: %w", err)
+	}
+
+	if count <= n {
+		return ring[:count], nil
+	}
+	start := count % n
+	return append(ring[start:], ring[:start]...), nil
+}
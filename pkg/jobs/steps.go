@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// GetJobWithSteps retrieves a job by job name and job id together with its step data:
+// the program, completion code and timings of each step. z/OSMF levels without step
+// data support return the job with no Steps.
+func (jm *ZOSMFJobManager) GetJobWithSteps(jobName, jobID string) (*Job, error) {
+	params := url.Values{}
+	params.Set("step-data", "Y")
+	return jm.getJobByNameID(jobName, jobID, params)
+}
+
+// UnmarshalJSON accepts the CPU time as either a string or a number; any other
+// value is ignored rather than failing the whole job
+func (s *JobStepData) UnmarshalJSON(data []byte) error {
+	type plainStepData JobStepData
+	var aux struct {
+		plainStepData
+		CPUTime interface{} `json:"cpu-time"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*s = JobStepData(aux.plainStepData)
+
+	switch cpuTime := aux.CPUTime.(type) {
+	case string:
+		s.CPUTime = cpuTime
+	case float64:
+		s.CPUTime = strconv.FormatFloat(cpuTime, 'f', -1, 64)
+	}
+	return nil
+}
+
+// Elapsed returns how long the step ran, or false when the step has not ended
+// or its times are missing
+func (s *JobStepData) Elapsed() (time.Duration, bool) {
+	if s.SelectedTime == "" || s.EndTime == "" {
+		return 0, false
+	}
+	start, err := time.Parse(time.RFC3339Nano, s.SelectedTime)
+	if err != nil {
+		return 0, false
+	}
+	end, err := time.Parse(time.RFC3339Nano, s.EndTime)
+	if err != nil {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
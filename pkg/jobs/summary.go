@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"errors"
+	"strings"
+)
+
+// Return code buckets counted by SummarizeJobs for completed jobs
+const (
+	SummaryCCZero    = "CC 0000"   // Condition code 0
+	SummaryCCNonZero = "CC >0"     // Any other condition code
+	SummaryAbend     = "ABEND"     // System or user abend
+	SummaryJCLError  = "JCL ERROR" // Failed JCL conversion
+	SummaryOtherRC   = "OTHER RC"  // Canceled, security, converter or system failure, or no return code
+)
+
+// ErrJobListTruncated is returned by SummarizeJobs, along with the counts, when not every
+// matching job could be listed
+var ErrJobListTruncated = errors.New("job list truncated; counts do not include every matching job")
+
+// GetActiveJobs lists every running job of an owner. An empty owner selects the
+// session user and "*" every owner.
+func (jm *ZOSMFJobManager) GetActiveJobs(owner string) (*JobList, error) {
+	jobList, err := jm.ListAllJobs(&JobFilter{Owner: owner, ActiveOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// Levels of z/OSMF that ignore the status filter return jobs in every state
+	result := &JobList{Jobs: []Job{}, Truncated: jobList.Truncated}
+	for _, job := range jobList.Jobs {
+		if ParseJobStatus(job.Status) == JobStatusActive {
+			result.Jobs = append(result.Jobs, job)
+		}
+	}
+	return result, nil
+}
+
+// SummarizeJobs counts the jobs matching the filter. Every job is counted under its
+// status (INPUT, ACTIVE or OUTPUT), and completed jobs are also counted under one of the
+// Summary* return code buckets. Jobs are listed with ListAllJobs, so the counts are not
+// capped at one page; when the listing is still truncated the counts are returned with
+// ErrJobListTruncated.
+func (jm *ZOSMFJobManager) SummarizeJobs(filter *JobFilter) (map[string]int, error) {
+	jobList, err := jm.ListAllJobs(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, job := range jobList.Jobs {
+		status := ParseJobStatus(job.Status)
+		if status == "" {
+			status = JobStatus(strings.ToUpper(strings.TrimSpace(job.Status)))
+		}
+		counts[string(status)]++
+		if status == JobStatusOutput {
+			counts[returnCodeBucket(job.RetCode)]++
+		}
+	}
+
+	if jobList.Truncated {
+		return counts, ErrJobListTruncated
+	}
+	return counts, nil
+}
+
+// returnCodeBucket returns the summary bucket of a completed job's return code
+func returnCodeBucket(retcode string) string {
+	rc, err := ParseReturnCode(retcode)
+	if err != nil {
+		return SummaryOtherRC
+	}
+	switch rc.Kind {
+	case ReturnCodeCC:
+		if rc.Code == 0 {
+			return SummaryCCZero
+		}
+		return SummaryCCNonZero
+	case ReturnCodeAbend:
+		return SummaryAbend
+	case ReturnCodeJCLError:
+		return SummaryJCLError
+	}
+	return SummaryOtherRC
+}
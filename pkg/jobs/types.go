@@ -1,9 +1,54 @@
 package jobs
 
 import (
+	"io"
 	"time"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+)
+
+// JobStatus represents the z/OSMF status of a job
+type JobStatus string
+
+const (
+	JobStatusInput  JobStatus = "INPUT"  // Waiting for execution
+	JobStatusActive JobStatus = "ACTIVE" // Running
+	JobStatusOutput JobStatus = "OUTPUT" // Finished, output on spool
+)
+
+// ReturnCodeKind classifies a job return code
+type ReturnCodeKind string
+
+const (
+	ReturnCodeCC         ReturnCodeKind = "CC"         // Completed with a condition code
+	ReturnCodeAbend      ReturnCodeKind = "ABEND"      // System or user abend
+	ReturnCodeJCLError   ReturnCodeKind = "JCL ERROR"  // Failed JCL conversion
+	ReturnCodeCanceled   ReturnCodeKind = "CANCELED"   // Canceled by an operator or user
+	ReturnCodeSecError   ReturnCodeKind = "SEC ERROR"  // Security failure
+	ReturnCodeConvError  ReturnCodeKind = "CONV ERROR" // Converter error
+	ReturnCodeSystemFail ReturnCodeKind = "SYS FAIL"   // System failure
 )
 
+// ReturnCode is a parsed job return code such as "CC 0004" or "ABEND S0C4"
+type ReturnCode struct {
+	Kind  ReturnCodeKind `json:"kind"`
+	Code  int            `json:"code"`            // Condition code, or abend code (hex for system abends)
+	Abend string         `json:"abend,omitempty"` // Abend code as reported, e.g. "S0C4" or "U4038"
+	Raw   string         `json:"raw"`
+}
+
+// SystemMessage is a system message from a job log such as JESMSGLG or JESYSMSG
+type SystemMessage struct {
+	ID        string    `json:"id"`                  // Message ID, e.g. IEF450I or $HASP395
+	Timestamp string    `json:"timestamp,omitempty"` // Time of day as logged, e.g. 10.15.02
+	Time      time.Time `json:"time,omitempty"`      // Timestamp on the log's date, when it has a date line
+	JobID     string    `json:"jobId,omitempty"`     // Job ID the line is prefixed with (JES2)
+	StepName  string    `json:"stepName,omitempty"`  // Step the message is about, when it can be told
+	Text      string    `json:"text"`                // Message ID and text, continuation lines joined by newlines
+	DDName    string    `json:"ddName,omitempty"`    // Spool file the message came from, set by GetJobMessages
+	Line      int       `json:"line"`                // 1-based line the message starts on
+}
+
 // Job represents a z/OS job
 type Job struct {
 	JobID       string            `json:"jobid"`
@@ -21,8 +66,34 @@ type Job struct {
 	JobCorrelator string          `json:"job-correlator,omitempty"`
 	ExecutionClass string         `json:"execution-class,omitempty"`
 	ExecutionMode string          `json:"execution-mode,omitempty"`
+	// Execution data, returned when listing with JobFilter.ExecData
+	ExecSubmitted string          `json:"exec-submitted,omitempty"`
+	ExecStarted string            `json:"exec-started,omitempty"`
+	ExecEnded   string            `json:"exec-ended,omitempty"`
+	ExecMember  string            `json:"exec-member,omitempty"`
+	ExecSystem  string            `json:"exec-system,omitempty"`
 	JobInfo     *JobInfo          `json:"job-info,omitempty"`
 	SpoolFiles  []SpoolFile       `json:"spool-files,omitempty"`
+	// Steps is returned by GetJobWithSteps; older z/OSMF levels leave it empty
+	Steps []JobStepData `json:"step-data,omitempty"`
+}
+
+// JobStepData describes one step of a job, as returned with step-data=Y
+type JobStepData struct {
+	StepNumber      int    `json:"step-number"`
+	StepName        string `json:"step-name"`
+	ProcStepName    string `json:"proc-step-name,omitempty"`
+	ProgramName     string `json:"program-name,omitempty"`
+	PathName        string `json:"path-name,omitempty"`
+	Completion      string `json:"completion,omitempty"` // e.g. "CC 0000" or "ABEND S0C4"
+	AbendReasonCode string `json:"abend-reason-code,omitempty"`
+	Active          bool   `json:"active"`
+	SMFID           string `json:"smfid,omitempty"`
+	OwnerName       string `json:"owner-name,omitempty"`
+	SubstepNumber   int    `json:"substep-number,omitempty"`
+	SelectedTime    string `json:"selected-time,omitempty"` // when the step was started
+	EndTime         string `json:"end-time,omitempty"`
+	CPUTime         string `json:"cpu-time,omitempty"` // only sent by some z/OSMF levels
 }
 
 // JobInfo contains detailed information about a job
@@ -59,19 +130,82 @@ type SpoolFile struct {
 	ContentURL  string `json:"content-url,omitempty"`
 }
 
-// JobList represents a list of jobs
+// SpoolContentOptions controls how spool file records are retrieved
+type SpoolContentOptions struct {
+	// RecordRange is sent as X-IBM-Record-Range, either "SSS-EEE" (first to
+	// last record, zero based) or "SSS,NNN" (start record and count)
+	RecordRange string `json:"recordRange,omitempty"`
+	// Encoding is the code page of the spool data (e.g. "IBM-1047"),
+	// sent as the fileEncoding query parameter. Defaults to the profile's encoding.
+	Encoding string `json:"encoding,omitempty"`
+	// Progress is called as spool data is received; the total is -1 when
+	// the server does not send a Content-Length
+	Progress profile.ProgressFunc `json:"-"`
+	// AllRecords sends X-IBM-Max-Items: 0 so z/OSMF returns every record
+	// instead of stopping at its default limit
+	AllRecords bool `json:"allRecords,omitempty"`
+}
+
+// SpoolContent is the content of a spool file together with how much of it was returned
+type SpoolContent struct {
+	Content string `json:"content"`
+	// Records and Bytes count what was returned
+	Records int   `json:"records"`
+	Bytes   int64 `json:"bytes"`
+	// TotalRecords and TotalBytes are the sizes the spool file declares, 0 when unknown
+	TotalRecords int `json:"totalRecords,omitempty"`
+	TotalBytes   int `json:"totalBytes,omitempty"`
+	// Truncated is set when fewer records were returned than the spool file
+	// (or the requested record range) holds
+	Truncated bool `json:"truncated"`
+}
+
+// JobList represents a list of jobs. z/OSMF has no paging parameters for job lists;
+// use ListAllJobs to list past a truncated page.
 type JobList struct {
 	Jobs []Job `json:"jobs"`
+	// TotalRows is the number of matching jobs, when the response reports it. z/OSMF
+	// itself does not, so it is 0 unless a gateway wraps the list with a totalRows count.
+	TotalRows int `json:"totalRows,omitempty"`
+	// Truncated is set when the list filled max-jobs, so more jobs may match the filter
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // SubmitJobRequest represents a job submission request
 type SubmitJobRequest struct {
+	// JobDataSet is a fully qualified dataset, "DSN" or "DSN(MEMBER)", holding the JCL
 	JobDataSet string `json:"jobDataSet,omitempty"`
+	// JobLocalFile is a file on this machine whose JCL is read and uploaded.
+	// A relative path is resolved against Directory, and Extension is added
+	// when the file name has none.
 	JobLocalFile string `json:"jobLocalFile,omitempty"`
+	// JobUSSFile is an absolute z/OS UNIX path of JCL already on the host
+	JobUSSFile string `json:"jobUSSFile,omitempty"`
 	JobStatement string `json:"jobStatement,omitempty"`
 	Directory string `json:"directory,omitempty"`
 	Extension string `json:"extension,omitempty"`
 	Volume string `json:"volume,omitempty"`
+	// Internal reader settings, sent as X-IBM-Intrdr-* headers when set.
+	// Fixed-form JCL with sequence numbers needs recfm F and lrecl 80.
+	InternalReaderClass string `json:"internalReaderClass,omitempty"`
+	InternalReaderRecfm string `json:"internalReaderRecfm,omitempty"`
+	InternalReaderLrecl int `json:"internalReaderLrecl,omitempty"`
+	// UserCorrelator is sent as X-IBM-User-Correlator
+	UserCorrelator string `json:"userCorrelator,omitempty"`
+	// GenerateUserCorrelator fills an empty UserCorrelator with a unique value
+	// before submitting, so the submission can be found again with VerifySubmission
+	GenerateUserCorrelator bool `json:"generateUserCorrelator,omitempty"`
+	// Symbols are sent as X-IBM-JCL-Symbol-<name> headers
+	Symbols map[string]string `json:"symbols,omitempty"`
+}
+
+// SubmitOptions holds the internal reader and header settings for a submission
+type SubmitOptions struct {
+	InternalReaderClass string `json:"internalReaderClass,omitempty"`
+	InternalReaderRecfm string `json:"internalReaderRecfm,omitempty"`
+	InternalReaderLrecl int `json:"internalReaderLrecl,omitempty"`
+	UserCorrelator string `json:"userCorrelator,omitempty"`
+	Symbols map[string]string `json:"symbols,omitempty"`
 }
 
 // SubmitJobResponse represents a job submission response
@@ -81,9 +215,29 @@ type SubmitJobResponse struct {
 	Owner   string `json:"owner"`
 	Status  string `json:"status"`
 	URL     string `json:"url,omitempty"`
+	// JobCorrelator is the z/OSMF job correlator, returned by z/OSMF V2R2 and later
+	JobCorrelator string `json:"job-correlator,omitempty"`
+}
+
+// ResubmitOptions controls how ResubmitJobWithOptions rebuilds a job
+type ResubmitOptions struct {
+	// Replacements are applied to the original JCL before it is submitted,
+	// longest match first (e.g. {"DATE=240101": "DATE=240102"})
+	Replacements map[string]string `json:"replacements,omitempty"`
+	// DryRun returns the JCL that would be submitted without submitting it
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// ResubmitResult holds the JCL of a resubmission and, unless it was a dry run, the new job
+type ResubmitResult struct {
+	JCL      string             `json:"jcl"`
+	Response *SubmitJobResponse `json:"response,omitempty"`
 }
 
-// JobFilter represents filters for job queries
+// JobFilter represents filters for job queries.
+// An empty Owner means the session user; "*" matches every owner.
+// Owner and Prefix accept the * and % wildcards. MaxJobs is capped at 1000;
+// 0 requests the full 1000.
 type JobFilter struct {
 	Owner       string `json:"owner,omitempty"`
 	Prefix      string `json:"prefix,omitempty"`
@@ -92,26 +246,43 @@ type JobFilter struct {
 	JobName     string `json:"jobname,omitempty"`
 	Status      string `json:"status,omitempty"`
 	UserCorrelator string `json:"user-correlator,omitempty"`
+	ExecData    bool   `json:"exec-data,omitempty"`   // Include exec-* fields in results
+	ActiveOnly  bool   `json:"active-only,omitempty"` // Only jobs that are currently running
 }
 
 // JobManager interface for job management operations
 type JobManager interface {
 	ListJobs(filter *JobFilter) (*JobList, error)
-	GetJob(jobID string) (*Job, error)
-	GetJobInfo(jobID string) (*JobInfo, error)
-	GetJobStatus(jobID string) (string, error)
+	ListAllJobs(filter *JobFilter) (*JobList, error)
+	GetJob(correlator string) (*Job, error)
+	GetJobInfo(correlator string) (*JobInfo, error)
+	GetJobStatus(correlator string) (string, error)
+	// Deprecated: use GetJob with a "jobname:jobid" correlator.
 	GetJobByNameID(jobName, jobID string) (*Job, error)
+	GetJobWithSteps(jobName, jobID string) (*Job, error)
 	GetJobByCorrelator(correlator string) (*Job, error)
 	SubmitJob(request *SubmitJobRequest) (*SubmitJobResponse, error)
-	CancelJob(jobID string) error
-	DeleteJob(jobID string) error
-	GetSpoolFiles(jobID string) ([]SpoolFile, error)
-	GetSpoolFileContent(jobID string, spoolID int) (string, error)
-	PurgeJob(jobID string) error
+	CancelJob(correlator string) error
+	// Deprecated: use CancelJob with a "jobname:jobid" correlator.
+	CancelJobByNameID(jobName, jobID string) error
+	DeleteJob(correlator string) error
+	// Deprecated: use DeleteJob with a "jobname:jobid" correlator.
+	DeleteJobByNameID(jobName, jobID string) error
+	// Deprecated: use GetSpoolFilesByCorrelator with a "jobname:jobid" correlator.
+	GetSpoolFiles(jobName, jobID string) ([]SpoolFile, error)
+	GetSpoolFileByDDName(jobName, jobID, ddName string) (*SpoolFile, error)
+	// Deprecated: use GetSpoolFileContentByCorrelator with a "jobname:jobid" correlator.
+	GetSpoolFileContent(jobName, jobID string, spoolID int) (string, error)
+	GetSpoolFileContentWithInfo(jobName, jobID string, spoolID int, opts *SpoolContentOptions) (*SpoolContent, error)
+	GetSpoolFileContentStream(jobName, jobID string, spoolID int, w io.Writer) (int64, error)
+	GetSpoolFileReader(jobName, jobID string, spoolID int) (io.ReadCloser, error)
+	GetJobJCL(correlator string) (string, error)
+	GetJobMessages(correlator string) ([]SystemMessage, error)
+	PurgeJob(correlator string) error
 	CloseJobManager() error
 }
 
 // ZOSMFJobManager implements JobManager for ZOSMF
 type ZOSMFJobManager struct {
-	session interface{} // Will be *profile.Session
+	session profile.HTTPSession
 }
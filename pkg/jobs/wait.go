@@ -0,0 +1,204 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Defaults for WaitOptions
+const (
+	DefaultWaitInitialInterval = time.Second
+	DefaultWaitMultiplier      = 1.5
+	DefaultWaitMaxInterval     = 30 * time.Second
+	DefaultWaitJitter          = 0.2
+	DefaultWaitMaxErrors       = 3
+)
+
+// Clock is the time source used while waiting for a job. Tests can supply a fake
+// one to control how long each wait lasts.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WaitOptions controls how WaitForJob polls. The interval starts at InitialInterval
+// and grows by Multiplier after every poll up to MaxInterval. Each wait is shortened
+// by a random amount of up to Jitter times the interval, so that many waiters started
+// together spread their requests out.
+type WaitOptions struct {
+	Timeout         time.Duration // Zero waits until the context ends
+	InitialInterval time.Duration // Default 1s
+	Multiplier      float64       // Default 1.5; 1 polls at a fixed interval
+	MaxInterval     time.Duration // Default 30s, and never less than InitialInterval
+	// Jitter is the fraction of each interval that is randomized, at most 1.
+	// Zero means the default of 0.2; a negative value turns jitter off.
+	Jitter float64
+	// MaxConsecutiveErrors gives up after this many failed polls in a row (default 3)
+	MaxConsecutiveErrors int
+	Clock                Clock          // Default is the system clock
+	Random               func() float64 // Source of jitter in [0, 1); default math/rand
+}
+
+// waitSchedule is a WaitOptions with the defaults filled in
+type waitSchedule struct {
+	interval    time.Duration
+	multiplier  float64
+	maxInterval time.Duration
+	jitter      float64
+	maxErrors   int
+	clock       Clock
+	random      func() float64
+}
+
+func newWaitSchedule(opts *WaitOptions) (*waitSchedule, error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	if opts.InitialInterval < 0 || opts.MaxInterval < 0 || opts.Timeout < 0 {
+		return nil, fmt.Errorf("wait intervals and timeout must not be negative")
+	}
+	if opts.Multiplier != 0 && opts.Multiplier < 1 {
+		return nil, fmt.Errorf("wait multiplier must be at least 1")
+	}
+	if opts.Jitter > 1 {
+		return nil, fmt.Errorf("wait jitter must be at most 1")
+	}
+
+	s := &waitSchedule{
+		interval:    opts.InitialInterval,
+		multiplier:  opts.Multiplier,
+		maxInterval: opts.MaxInterval,
+		jitter:      opts.Jitter,
+		maxErrors:   opts.MaxConsecutiveErrors,
+		clock:       opts.Clock,
+		random:      opts.Random,
+	}
+	if s.interval == 0 {
+		s.interval = DefaultWaitInitialInterval
+	}
+	if s.multiplier == 0 {
+		s.multiplier = DefaultWaitMultiplier
+	}
+	if s.maxInterval == 0 {
+		s.maxInterval = DefaultWaitMaxInterval
+	}
+	if s.maxInterval < s.interval {
+		s.maxInterval = s.interval
+	}
+	if s.jitter == 0 {
+		s.jitter = DefaultWaitJitter
+	} else if s.jitter < 0 {
+		s.jitter = 0
+	}
+	if s.maxErrors <= 0 {
+		s.maxErrors = DefaultWaitMaxErrors
+	}
+	if s.clock == nil {
+		s.clock = realClock{}
+	}
+	if s.random == nil {
+		s.random = rand.Float64
+	}
+	return s, nil
+}
+
+// next returns how long to wait before the next poll and grows the interval
+func (s *waitSchedule) next() time.Duration {
+	wait := s.interval - time.Duration(s.jitter*s.random()*float64(s.interval))
+
+	grown := time.Duration(float64(s.interval) * s.multiplier)
+	if grown > s.maxInterval || grown < s.interval {
+		grown = s.maxInterval
+	}
+	s.interval = grown
+	return wait
+}
+
+// WaitForJob polls a job until it completes and returns the last snapshot of it.
+// Polling backs off as set by opts, which may be nil for the defaults. A failed poll
+// is retried until MaxConsecutiveErrors fail in a row. On timeout, cancellation or
+// too many errors the last job seen is returned along with the error; it is nil if
+// no poll succeeded.
+func (jm *ZOSMFJobManager) WaitForJob(ctx context.Context, correlator string, opts *WaitOptions) (*Job, error) {
+	if correlator == "" {
+		return nil, fmt.Errorf("correlator cannot be empty")
+	}
+	schedule, err := newWaitSchedule(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var deadline time.Time
+	if opts != nil && opts.Timeout > 0 {
+		deadline = schedule.clock.Now().Add(opts.Timeout)
+	}
+
+	var last *Job
+	errorCount := 0
+	for {
+		job, err := jm.GetJob(correlator)
+		if err != nil {
+			errorCount++
+			if errorCount >= schedule.maxErrors {
+				return last, fmt.Errorf("failed to get job status: %w", err)
+			}
+		} else {
+			errorCount = 0
+			last = job
+			if job.IsComplete() {
+				return job, nil
+			}
+		}
+
+		wait := schedule.next()
+		if !deadline.IsZero() {
+			remaining := deadline.Sub(schedule.clock.Now())
+			if remaining <= 0 {
+				return last, fmt.Errorf("timeout waiting for job %s to complete", correlator)
+			}
+			if wait > remaining {
+				wait = remaining
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("stopped waiting for job %s: %w", correlator, ctx.Err())
+		case <-schedule.clock.After(wait):
+		}
+	}
+}
+
+// WaitForJobCompletion waits for a job to complete and returns the final status.
+// Polling starts at pollInterval and backs off with the WaitForJob defaults. A timeout
+// of zero or less leaves no time to wait: the job is checked once, and unless it has
+// already completed a timeout error is returned.
+func (jm *ZOSMFJobManager) WaitForJobCompletion(correlator string, timeout time.Duration, pollInterval time.Duration) (string, error) {
+	if timeout <= 0 {
+		job, err := jm.GetJob(correlator)
+		if err != nil {
+			return "", fmt.Errorf("failed to get job status: %w", err)
+		}
+		if !job.IsComplete() {
+			return "", fmt.Errorf("timeout waiting for job %s to complete", correlator)
+		}
+		return job.Status, nil
+	}
+
+	job, err := jm.WaitForJob(context.Background(), correlator, &WaitOptions{
+		Timeout:         timeout,
+		InitialInterval: pollInterval,
+	})
+	if err != nil {
+		return "", err
+	}
+	return job.Status, nil
+}
@@ -0,0 +1,108 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultWatchMaxErrors is the number of consecutive polling errors a watcher tolerates
+const DefaultWatchMaxErrors = 3
+
+// JobEvent is sent by a job watcher when a job changes or polling fails
+type JobEvent struct {
+	Job  *Job      // Job snapshot, nil for error events
+	Err  error     // Polling error, nil for change events
+	Time time.Time // When the event was observed
+}
+
+// WatchOptions controls how a job is watched
+type WatchOptions struct {
+	PollInterval time.Duration
+	// MaxConsecutiveErrors stops the watcher after this many polling errors
+	// in a row (default 3); every error is still sent as an event
+	MaxConsecutiveErrors int
+}
+
+// WatchJob polls a job and sends an event whenever its status or phase changes.
+// The channel is closed once the job completes, the context is canceled, or
+// polling fails three times in a row.
+func (jm *ZOSMFJobManager) WatchJob(ctx context.Context, correlator string, pollInterval time.Duration) (<-chan JobEvent, error) {
+	return jm.WatchJobWithOptions(ctx, correlator, &WatchOptions{PollInterval: pollInterval})
+}
+
+// WatchJobWithOptions is WatchJob with a configurable error tolerance
+func (jm *ZOSMFJobManager) WatchJobWithOptions(ctx context.Context, correlator string, opts *WatchOptions) (<-chan JobEvent, error) {
+	if correlator == "" {
+		return nil, fmt.Errorf("correlator cannot be empty")
+	}
+	if opts == nil || opts.PollInterval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive")
+	}
+	maxErrors := opts.MaxConsecutiveErrors
+	if maxErrors <= 0 {
+		maxErrors = DefaultWatchMaxErrors
+	}
+
+	events := make(chan JobEvent)
+	go func() {
+		defer close(events)
+
+		var last *Job
+		errorCount := 0
+		ticker := time.NewTicker(opts.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			job, err := jm.GetJob(correlator)
+			if err != nil {
+				errorCount++
+				if !sendJobEvent(ctx, events, JobEvent{Err: fmt.Errorf("failed to get job status: %w", err), Time: time.Now()}) {
+					return
+				}
+				if errorCount >= maxErrors {
+					return
+				}
+			} else {
+				errorCount = 0
+				if jobChanged(last, job) {
+					if !sendJobEvent(ctx, events, JobEvent{Job: job, Time: time.Now()}) {
+						return
+					}
+					last = job
+				}
+				if job.IsComplete() {
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// sendJobEvent delivers an event unless the context is canceled first
+func sendJobEvent(ctx context.Context, events chan<- JobEvent, event JobEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// jobChanged reports whether the status or phase differs from the last snapshot
+func jobChanged(last, current *Job) bool {
+	if last == nil {
+		return true
+	}
+	return last.Status != current.Status ||
+		last.PhaseName != current.PhaseName ||
+		last.PhaseNumber != current.PhaseNumber
+}
@@ -0,0 +1,106 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// configRacyWindow is how long after its last modification a config file is read
+// again on every call. File systems record mtime at a coarse granularity, so a file
+// rewritten within the same tick with the same size would otherwise look unchanged.
+const configRacyWindow = 2 * time.Second
+
+// configSnapshot is a parsed config file together with the file state it was read from
+type configSnapshot struct {
+	config   *ZoweConfig
+	modTime  time.Time
+	size     int64
+	loadedAt time.Time
+}
+
+// current reports whether the snapshot still reflects a file with the given state
+func (s *configSnapshot) current(info os.FileInfo) bool {
+	if s == nil {
+		return false
+	}
+	if !info.ModTime().Equal(s.modTime) || info.Size() != s.size {
+		return false
+	}
+	return s.modTime.Before(s.loadedAt.Add(-configRacyWindow))
+}
+
+// Reload discards the cached configuration and reads the config file again. Changes
+// are normally picked up from the file's modification time and size; Reload is for
+// callers that need to see an update immediately.
+func (pm *ZOSMFProfileManager) Reload() error {
+	pm.configMu.Lock()
+	defer pm.configMu.Unlock()
+	pm.config = nil
+
+	info, err := os.Stat(pm.configPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("zowe config file not found at %s", pm.configPath)
+	}
+	_, err = pm.readConfigLocked(info)
+	return err
+}
+
+// readConfigLocked reads and parses the config file and caches the result. info is
+// the file state taken before reading, so a change made during the read is seen as
+// a newer file on the next call. pm.configMu must be held.
+func (pm *ZOSMFProfileManager) readConfigLocked(info os.FileInfo) (*ZoweConfig, error) {
+	loadedAt := time.Now()
+
+	// Read the config file directly as JSON
+	data, err := os.ReadFile(pm.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config ZoweConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	if info != nil {
+		pm.config = &configSnapshot{config: &config, modTime: info.ModTime(), size: info.Size(), loadedAt: loadedAt}
+	}
+	return &config, nil
+}
+
+// cloneConfig deep copies the maps of a config so it can be modified without
+// affecting the cached copy
+func cloneConfig(config *ZoweConfig) *ZoweConfig {
+	cloned := *config
+	if config.Defaults != nil {
+		cloned.Defaults = make(map[string]string, len(config.Defaults))
+		for key, value := range config.Defaults {
+			cloned.Defaults[key] = value
+		}
+	}
+	cloned.Profiles = cloneProfiles(config.Profiles)
+	return &cloned
+}
+
+// cloneProfiles deep copies a profiles map, including nested profiles
+func cloneProfiles(profiles map[string]ZoweProfile) map[string]ZoweProfile {
+	if profiles == nil {
+		return nil
+	}
+	cloned := make(map[string]ZoweProfile, len(profiles))
+	for name, profile := range profiles {
+		if profile.Properties != nil {
+			properties := make(map[string]interface{}, len(profile.Properties))
+			for key, value := range profile.Properties {
+				properties[key] = value
+			}
+			profile.Properties = properties
+		}
+		profile.Secure = append([]string(nil), profile.Secure...)
+		profile.Profiles = cloneProfiles(profile.Profiles)
+		cloned[name] = profile
+	}
+	return cloned
+}
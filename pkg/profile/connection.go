@@ -0,0 +1,164 @@
+package profile
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ConnectionErrorKind says why TestConnection failed
+type ConnectionErrorKind string
+
+const (
+	ConnectionErrorTLS         ConnectionErrorKind = "tls"         // Certificate or handshake failure
+	ConnectionErrorAuth        ConnectionErrorKind = "auth"        // Credentials rejected (401 or 403)
+	ConnectionErrorUnreachable ConnectionErrorKind = "unreachable" // Name lookup failed, connection refused or timed out
+	ConnectionErrorUnexpected  ConnectionErrorKind = "unexpected"  // Any other status, or a response that is not from z/OSMF
+)
+
+// Errors that a *ConnectionError of the matching kind satisfies with errors.Is
+var (
+	ErrTLSFailure      = errors.New("TLS failure")
+	ErrAuthFailed      = errors.New("authentication failed")
+	ErrHostUnreachable = errors.New("host unreachable")
+)
+
+// ConnectionError reports a failed TestConnection
+type ConnectionError struct {
+	Kind       ConnectionErrorKind
+	URL        string
+	StatusCode int   // Set for auth and unexpected failures that got a response
+	Err        error // Underlying error, if any
+}
+
+func (e *ConnectionError) Error() string {
+	var reason string
+	switch e.Kind {
+	case ConnectionErrorTLS:
+		reason = "TLS failure"
+	case ConnectionErrorAuth:
+		reason = fmt.Sprintf("authentication failed with status %d", e.StatusCode)
+	case ConnectionErrorUnreachable:
+		reason = "host unreachable"
+	default:
+		reason = "unexpected response"
+		if e.StatusCode != 0 {
+			reason = fmt.Sprintf("unexpected status %d", e.StatusCode)
+		}
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("connection test to %s failed: %s: %v", e.URL, reason, e.Err)
+	}
+	return fmt.Sprintf("connection test to %s failed: %s", e.URL, reason)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the Err* value of the error's kind
+func (e *ConnectionError) Is(target error) bool {
+	switch e.Kind {
+	case ConnectionErrorTLS:
+		return target == ErrTLSFailure
+	case ConnectionErrorAuth:
+		return target == ErrAuthFailed
+	case ConnectionErrorUnreachable:
+		return target == ErrHostUnreachable
+	}
+	return false
+}
+
+// TestConnection checks that z/OSMF can be reached with the session by getting
+// /info, which returns the z/OSMF version. z/OSMF answers /info without checking
+// credentials, so with basic authentication they are also checked by logging in with
+// POST /services/authenticate; on z/OSMF levels without that service the check is
+// skipped. Failures are returned as a *ConnectionError.
+func (s *Session) TestConnection(ctx context.Context) error {
+	infoURL := strings.TrimRight(s.GetBaseURL(), "/") + ZosmfInfoEndpoint
+	resp, err := DoRequest(ctx, s, "GET", ZosmfInfoEndpoint, nil, map[string]string{"Accept": "application/json"}, nil)
+	if err != nil {
+		return connectionFailure(ctx, infoURL, err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return &ConnectionError{Kind: ConnectionErrorUnreachable, URL: infoURL, Err: err}
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return &ConnectionError{Kind: ConnectionErrorAuth, URL: infoURL, StatusCode: resp.StatusCode}
+	case resp.StatusCode != http.StatusOK:
+		return &ConnectionError{Kind: ConnectionErrorUnexpected, URL: infoURL, StatusCode: resp.StatusCode, Err: NewAPIError(resp.StatusCode, body)}
+	}
+
+	// Anything else answering at the base path, such as a web server's default page
+	// for a wrong basePath, does not return the z/OSMF version
+	var info ZosmfInfo
+	if json.Unmarshal(body, &info) != nil || info.ZosmfVersion == "" {
+		return &ConnectionError{Kind: ConnectionErrorUnexpected, URL: infoURL, Err: fmt.Errorf("response is not z/OSMF information; check the host, port and basePath")}
+	}
+
+	if !strings.HasPrefix(s.GetHeaders()["Authorization"], "Basic ") {
+		return nil
+	}
+	authURL := strings.TrimRight(s.GetBaseURL(), "/") + "/services/authenticate"
+	resp, err = DoRequest(ctx, s, "POST", "/services/authenticate", nil, nil, nil)
+	if err != nil {
+		return connectionFailure(ctx, authURL, err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return &ConnectionError{Kind: ConnectionErrorAuth, URL: authURL, StatusCode: resp.StatusCode}
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented:
+		return nil
+	default:
+		return &ConnectionError{Kind: ConnectionErrorUnexpected, URL: authURL, StatusCode: resp.StatusCode}
+	}
+}
+
+// connectionFailure classifies an error making a connection test request
+func connectionFailure(ctx context.Context, requestURL string, err error) error {
+	if ctx.Err() == context.Canceled {
+		return fmt.Errorf("connection test to %s canceled: %w", requestURL, ctx.Err())
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var verification *tls.CertificateVerificationError
+	var recordHeader tls.RecordHeaderError
+	if errors.As(err, &unknownAuthority) || errors.As(err, &hostname) || errors.As(err, &invalid) ||
+		errors.As(err, &verification) || errors.As(err, &recordHeader) {
+		return &ConnectionError{Kind: ConnectionErrorTLS, URL: requestURL, Err: err}
+	}
+
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var netErr net.Error
+	if errors.As(err, &dnsErr) || errors.As(err, &opErr) || (errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return &ConnectionError{Kind: ConnectionErrorUnreachable, URL: requestURL, Err: err}
+	}
+	return &ConnectionError{Kind: ConnectionErrorUnexpected, URL: requestURL, Err: err}
+}
+
+// TestProfile creates a session from the named profile and tests its connection
+func (pm *ZOSMFProfileManager) TestProfile(name string) error {
+	session, err := pm.CreateSession(name)
+	if err != nil {
+		return err
+	}
+	return session.TestConnection(context.Background())
+}
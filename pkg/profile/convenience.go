@@ -3,6 +3,9 @@ package profile
 import (
 	"fmt"
 	"os"
+	"strings"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/internal/deprecation"
 )
 
 // CreateZOSMFProfile creates a ZOSMF profile with the given parameters
@@ -61,7 +64,8 @@ func CreateSessionDirectWithOptions(host string, port int, user, password string
 	return profile.NewSession()
 }
 
-// ValidateProfile validates that a ZOSMF profile has all required fields
+// ValidateProfile validates that a ZOSMF profile has all required fields, that its
+// protocol is http or https, and that its base path is a path without spaces or a scheme
 func ValidateProfile(profile *ZOSMFProfile) error {
 	if profile.Host == "" {
 		return fmt.Errorf("host is required")
@@ -75,6 +79,17 @@ func ValidateProfile(profile *ZOSMFProfile) error {
 	if profile.Port <= 0 {
 		return fmt.Errorf("port must be greater than 0")
 	}
+	if protocol := sessionProtocol(profile.Protocol, profile.Port); protocol != "http" && protocol != "https" {
+		return fmt.Errorf("invalid protocol %q: must be http or https", profile.Protocol)
+	}
+	if _, err := normalizeBasePath(profile.BasePath); err != nil {
+		return err
+	}
+	if profile.ProxyURL != "" {
+		if _, err := parseProxyURL(profile.ProxyURL); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -93,10 +108,62 @@ func CloneProfile(profile *ZOSMFProfile) *ZOSMFProfile {
 		ResponseTimeout:    profile.ResponseTimeout,
 		CertFile:           profile.CertFile,
 		CertKeyFile:        profile.CertKeyFile,
+		ProxyURL:           profile.ProxyURL,
+		CACertFile:         profile.CACertFile,
+		CACertPath:         profile.CACertPath,
+		ServerName:         profile.ServerName,
+	}
+}
+
+// SaveZoweConfig writes a Zowe configuration to path as indented JSON. The file is
+// replaced atomically, so readers never see a partially written config. Profile names
+// must be non-empty and free of dots, and every default must name an existing profile
+// of its type. Missing profile and default maps are written as empty objects.
+func SaveZoweConfig(path string, config *ZoweConfig) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	if err := validateProfileNames(config.Profiles, ""); err != nil {
+		return err
 	}
+	for profileType, name := range config.Defaults {
+		chain := findProfile(config.Profiles, name)
+		if chain == nil {
+			return fmt.Errorf("default %s profile '%s' not found", profileType, name)
+		}
+		if actual := chain[len(chain)-1].profile.Type; actual != profileType {
+			return fmt.Errorf("default %s profile '%s' has type '%s'", profileType, name, actual)
+		}
+	}
+
+	out := *config
+	if out.Profiles == nil {
+		out.Profiles = map[string]ZoweProfile{}
+	}
+	if out.Defaults == nil {
+		out.Defaults = map[string]string{}
+	}
+	return writeConfigFile(path, &out)
+}
+
+// validateProfileNames checks the names of profiles and their nested profiles
+func validateProfileNames(profiles map[string]ZoweProfile, parent string) error {
+	for name, profile := range profiles {
+		if name == "" || strings.Contains(name, ".") {
+			return fmt.Errorf("invalid profile name '%s'", parent+name)
+		}
+		if err := validateProfileNames(profile.Profiles, parent+name+"."); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// WriteTestConfig writes a test configuration to a file
+// WriteTestConfig writes content to a file as is, without checking that it is a valid
+// configuration. It is meant for tests only.
+//
+// Deprecated: use SaveZoweConfig, which writes a typed ZoweConfig atomically.
 func WriteTestConfig(filename, content string) error {
+	deprecation.Report("profile.WriteTestConfig", "profile.SaveZoweConfig")
 	return os.WriteFile(filename, []byte(content), 0644)
 } 
\ No newline at end of file
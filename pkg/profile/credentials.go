@@ -0,0 +1,103 @@
+package profile
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Zowe CLI keeps secure properties in the OS credential store under this
+// service and account, as base64-encoded JSON keyed by config file path
+const (
+	zoweKeyringService = "Zowe"
+	zoweKeyringAccount = "secure_config_props"
+)
+
+// ErrSecureStoreUnavailable is returned when no OS credential store can be read
+var ErrSecureStoreUnavailable = errors.New("secure credential store unavailable")
+
+// CredentialProvider looks up secure profile properties.
+// propertyPath uses the Zowe team config form, e.g.
+// "profiles.lpar1.properties.password". ok is false when nothing is stored.
+type CredentialProvider interface {
+	GetSecureValue(configPath, propertyPath string) (value string, ok bool, err error)
+}
+
+// KeyringCredentialProvider reads the secure values Zowe CLI stores in the
+// OS credential store (macOS Keychain via security, Linux Secret Service via
+// secret-tool). Other platforms report ErrSecureStoreUnavailable.
+type KeyringCredentialProvider struct {
+	once   sync.Once
+	values map[string]map[string]interface{}
+	err    error
+}
+
+// NewKeyringCredentialProvider creates a provider backed by the OS credential store
+func NewKeyringCredentialProvider() *KeyringCredentialProvider {
+	return &KeyringCredentialProvider{}
+}
+
+// GetSecureValue returns the stored value for a property of a config file
+func (p *KeyringCredentialProvider) GetSecureValue(configPath, propertyPath string) (string, bool, error) {
+	p.once.Do(func() {
+		p.values, p.err = readZoweKeyring()
+	})
+	if p.err != nil {
+		return "", false, p.err
+	}
+
+	value, ok := p.values[configPath][propertyPath]
+	if !ok {
+		return "", false, nil
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true, nil
+	default:
+		return fmt.Sprint(v), true, nil
+	}
+}
+
+// readZoweKeyring loads and decodes the Zowe secure properties blob
+func readZoweKeyring() (map[string]map[string]interface{}, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", zoweKeyringService, "-a", zoweKeyringAccount, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", zoweKeyringService, "account", zoweKeyringAccount)
+	default:
+		return nil, ErrSecureStoreUnavailable
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSecureStoreUnavailable, err)
+	}
+
+	return decodeZoweSecureProps(strings.TrimSpace(string(out)))
+}
+
+// decodeZoweSecureProps decodes the base64 JSON blob stored by Zowe CLI
+func decodeZoweSecureProps(encoded string) (map[string]map[string]interface{}, error) {
+	if encoded == "" {
+		return map[string]map[string]interface{}{}, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secure properties: %w", err)
+	}
+
+	var values map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal secure properties: %w", err)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,24 @@
+package profile
+
+import "github.com/zowe/zowe-client-go-sdk/pkg/internal/deprecation"
+
+// Deprecation describes a call to a deprecated SDK function
+type Deprecation struct {
+	Function    string // Qualified name, e.g. "jobs.CreateJobManagerDirect"
+	Replacement string // What to use instead
+}
+
+// DeprecationLogger receives a Deprecation the first time each deprecated function is called
+type DeprecationLogger func(d Deprecation)
+
+// SetDeprecationLogger reports calls to deprecated SDK functions to logger, once per
+// function. Deprecations are not reported by default; nil turns reporting off again.
+func SetDeprecationLogger(logger DeprecationLogger) {
+	if logger == nil {
+		deprecation.SetHandler(nil)
+		return
+	}
+	deprecation.SetHandler(func(function, replacement string) {
+		logger(Deprecation{Function: function, Replacement: replacement})
+	})
+}
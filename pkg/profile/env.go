@@ -0,0 +1,105 @@
+package profile
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variable suffixes read by LoadProfileFromEnv and ApplyEnvOverrides
+const (
+	EnvHost               = "_HOST"
+	EnvPort               = "_PORT"
+	EnvUser               = "_USER"
+	EnvPassword           = "_PASSWORD"
+	EnvProtocol           = "_PROTOCOL"
+	EnvRejectUnauthorized = "_REJECT_UNAUTHORIZED"
+	EnvBasePath           = "_BASE_PATH"
+	EnvEncoding           = "_ENCODING"
+	EnvResponseTimeout    = "_RESPONSE_TIMEOUT"
+	EnvCertFile           = "_CERT_FILE"
+	EnvCertKeyFile        = "_CERT_KEY_FILE"
+)
+
+// ZoweOptEnvPrefix is the prefix Zowe CLI uses for option environment variables
+// (ZOWE_OPT_HOST, ZOWE_OPT_PORT, ...)
+const ZoweOptEnvPrefix = "ZOWE_OPT"
+
+// FromEnvironment builds a validated profile from the ZOWE_OPT_* variables Zowe CLI honors
+func FromEnvironment() (*ZOSMFProfile, error) {
+	return LoadProfileFromEnv(ZoweOptEnvPrefix)
+}
+
+// LoadProfileFromEnv builds a validated profile from <PREFIX>_HOST, _PORT, _USER,
+// _PASSWORD, _PROTOCOL, _REJECT_UNAUTHORIZED, _BASE_PATH, _ENCODING, _RESPONSE_TIMEOUT,
+// _CERT_FILE and _CERT_KEY_FILE. The port defaults
+// to 443, the protocol to https and rejectUnauthorized to true.
+func LoadProfileFromEnv(prefix string) (*ZOSMFProfile, error) {
+	profile := &ZOSMFProfile{
+		Name:               strings.ToLower(prefix),
+		Port:               443,
+		Protocol:           "https",
+		RejectUnauthorized: true,
+	}
+
+	if err := ApplyEnvOverrides(profile, prefix); err != nil {
+		return nil, err
+	}
+	if err := ValidateProfile(profile); err != nil {
+		return nil, fmt.Errorf("invalid profile from %s_* environment variables: %w", prefix, err)
+	}
+
+	return profile, nil
+}
+
+// ApplyEnvOverrides overwrites profile fields with any <PREFIX>_* environment
+// variables that are set, leaving the others untouched
+func ApplyEnvOverrides(profile *ZOSMFProfile, prefix string) error {
+	if value, ok := os.LookupEnv(prefix + EnvHost); ok {
+		profile.Host = value
+	}
+	if value, ok := os.LookupEnv(prefix + EnvPort); ok {
+		port, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid %s%s: %q is not a number", prefix, EnvPort, value)
+		}
+		profile.Port = port
+	}
+	if value, ok := os.LookupEnv(prefix + EnvUser); ok {
+		profile.User = value
+	}
+	if value, ok := os.LookupEnv(prefix + EnvPassword); ok {
+		profile.Password = value
+	}
+	if value, ok := os.LookupEnv(prefix + EnvProtocol); ok {
+		profile.Protocol = strings.ToLower(value)
+	}
+	if value, ok := os.LookupEnv(prefix + EnvRejectUnauthorized); ok {
+		reject, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid %s%s: %q is not a boolean", prefix, EnvRejectUnauthorized, value)
+		}
+		profile.RejectUnauthorized = reject
+	}
+	if value, ok := os.LookupEnv(prefix + EnvBasePath); ok {
+		profile.BasePath = value
+	}
+	if value, ok := os.LookupEnv(prefix + EnvEncoding); ok {
+		profile.Encoding = value
+	}
+	if value, ok := os.LookupEnv(prefix + EnvResponseTimeout); ok {
+		timeout, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid %s%s: %q is not a number", prefix, EnvResponseTimeout, value)
+		}
+		profile.ResponseTimeout = timeout
+	}
+	if value, ok := os.LookupEnv(prefix + EnvCertFile); ok {
+		profile.CertFile = value
+	}
+	if value, ok := os.LookupEnv(prefix + EnvCertKeyFile); ok {
+		profile.CertKeyFile = value
+	}
+	return nil
+}
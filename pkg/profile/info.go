@@ -0,0 +1,81 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ZosmfInfoEndpoint returns the z/OSMF and z/OS versions; it does not require authentication
+const ZosmfInfoEndpoint = "/info"
+
+// zosmf_version values of z/OSMF releases, for use with ZosmfInfo.AtLeast
+const (
+	ZosmfVersionV2R2 = 25
+	ZosmfVersionV2R3 = 26
+	ZosmfVersionV2R4 = 27
+	ZosmfVersionV2R5 = 28
+	ZosmfVersionV3R1 = 29
+)
+
+// ZosmfInfo describes a z/OSMF server, as returned by /zosmf/info
+type ZosmfInfo struct {
+	ZosmfVersion     string        `json:"zosmf_version"`      // e.g. "27" for z/OS V2R4
+	ZosmfFullVersion string        `json:"zosmf_full_version"` // e.g. "27.0"
+	ZosVersion       string        `json:"zos_version"`        // e.g. "04.27.00"
+	APIVersion       string        `json:"api_version"`
+	Hostname         string        `json:"zosmf_hostname"`
+	Port             string        `json:"zosmf_port"`
+	SAFRealm         string        `json:"zosmf_saf_realm"`
+	Plugins          []ZosmfPlugin `json:"plugins"`
+}
+
+// ZosmfPlugin is a z/OSMF plug-in installed on the server
+type ZosmfPlugin struct {
+	Name    string `json:"pluginDefaultName"`
+	Version string `json:"pluginVersion"`
+	Status  string `json:"pluginStatus"` // ACTIVE or INACTIVE
+}
+
+// AtLeast reports whether the server's zosmf_version is version or later.
+// An unparseable version is treated as older than every release.
+func (i *ZosmfInfo) AtLeast(version int) bool {
+	major, _, _ := strings.Cut(i.ZosmfVersion, ".")
+	v, err := strconv.Atoi(strings.TrimSpace(major))
+	return err == nil && v >= version
+}
+
+// Plugin returns the named plug-in, matched case-insensitively, or nil when it is not installed
+func (i *ZosmfInfo) Plugin(name string) *ZosmfPlugin {
+	for idx := range i.Plugins {
+		if strings.EqualFold(i.Plugins[idx].Name, name) {
+			return &i.Plugins[idx]
+		}
+	}
+	return nil
+}
+
+// GetZosmfInfo returns the z/OSMF server information. The first successful result
+// is cached on the session and shared with its clones, since they talk to the same server.
+func (s *Session) GetZosmfInfo() (*ZosmfInfo, error) {
+	s.mu.RLock()
+	cached := s.info
+	s.mu.RUnlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var info ZosmfInfo
+	if err := DoJSONRequest(context.Background(), s, "GET", ZosmfInfoEndpoint, nil, map[string]string{"Accept": "application/json"}, nil, &info); err != nil {
+		return nil, fmt.Errorf("failed to get z/OSMF information: %w", err)
+	}
+	if info.ZosmfVersion == "" {
+		return nil, fmt.Errorf("failed to get z/OSMF information: response has no zosmf_version")
+	}
+
+	s.mu.Lock()
+	s.info = &info
+	s.mu.Unlock()
+	return &info, nil
+}
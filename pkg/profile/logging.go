@@ -0,0 +1,209 @@
+package profile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogBodyEnvVar enables verbose body logging when set to a byte limit (e.g. "2048")
+const LogBodyEnvVar = "ZOWE_SDK_LOG_BODY_BYTES"
+
+// redactedValue replaces the value of sensitive headers in log records
+const redactedValue = "<redacted>"
+
+// sensitiveHeaders are never written to log records in clear text
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// RequestLog describes a single HTTP exchange made through a Session
+type RequestLog struct {
+	Method       string
+	URL          string
+	StatusCode   int
+	Duration     time.Duration
+	Headers      http.Header // Request headers with credentials redacted
+	RequestBody  string      // Only populated when body logging is enabled
+	ResponseBody string      // Only populated when body logging is enabled
+	Err          error
+}
+
+// String formats the record as a single log line
+func (l *RequestLog) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", l.Method, l.URL)
+	if l.Err != nil {
+		fmt.Fprintf(&b, " error=%q", l.Err.Error())
+	} else {
+		fmt.Fprintf(&b, " status=%d", l.StatusCode)
+	}
+	fmt.Fprintf(&b, " duration=%s", l.Duration)
+
+	if len(l.Headers) > 0 {
+		keys := make([]string, 0, len(l.Headers))
+		for key := range l.Headers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, " %s=%q", key, strings.Join(l.Headers[key], ","))
+		}
+	}
+	if l.RequestBody != "" {
+		fmt.Fprintf(&b, " request-body=%q", l.RequestBody)
+	}
+	if l.ResponseBody != "" {
+		fmt.Fprintf(&b, " response-body=%q", l.ResponseBody)
+	}
+	return b.String()
+}
+
+// Logger receives a record for every request made through a Session
+type Logger interface {
+	LogRequest(entry *RequestLog)
+}
+
+// LoggerFunc adapts a plain function to the Logger interface
+type LoggerFunc func(entry *RequestLog)
+
+// LogRequest calls f(entry)
+func (f LoggerFunc) LogRequest(entry *RequestLog) {
+	f(entry)
+}
+
+// SetLogger installs a logger that is called for every request made with the session.
+// Passing nil disables logging.
+func (s *Session) SetLogger(logger Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger = logger
+}
+
+// SetLogBodyLimit enables request/response body logging, truncated to limit bytes.
+// A limit of 0 disables body logging.
+func (s *Session) SetLogBodyLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit < 0 {
+		limit = 0
+	}
+	s.logBodyLimit = limit
+}
+
+// loggingConfig returns the current logger and body limit
+func (s *Session) loggingConfig() (Logger, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.logger, s.logBodyLimit
+}
+
+// logBodyLimitFromEnv reads the body logging limit from the environment
+func logBodyLimitFromEnv() int {
+	value := os.Getenv(LogBodyEnvVar)
+	if value == "" {
+		return 0
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// sessionTransport wraps the underlying transport so every manager request
+// goes through the session-level hooks
+type sessionTransport struct {
+	base    http.RoundTripper
+	session *Session
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if limiter := t.session.rateLimiter(); limiter != nil {
+		if err := limiter.Wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("rate limit wait: %w", err)
+		}
+	}
+
+	logger, bodyLimit := t.session.loggingConfig()
+	if logger == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	entry := &RequestLog{
+		Method:  req.Method,
+		URL:     req.URL.Redacted(),
+		Headers: redactHeaders(req.Header),
+	}
+	if bodyLimit > 0 && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			entry.RequestBody = readLimited(body, bodyLimit)
+			body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	entry.Duration = time.Since(start)
+	entry.Err = err
+
+	if resp != nil {
+		entry.StatusCode = resp.StatusCode
+		if bodyLimit > 0 && resp.Body != nil {
+			prefix, _ := io.ReadAll(io.LimitReader(resp.Body, int64(bodyLimit)+1))
+			resp.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(prefix), resp.Body), closer: resp.Body}
+			entry.ResponseBody = truncate(prefix, bodyLimit)
+		}
+	}
+
+	logger.LogRequest(entry)
+	return resp, err
+}
+
+// prefixedBody replays the bytes consumed for logging before the rest of the body
+type prefixedBody struct {
+	io.Reader
+	closer io.Closer
+}
+
+// Close closes the original response body
+func (b *prefixedBody) Close() error {
+	return b.closer.Close()
+}
+
+// redactHeaders returns a copy of the headers with credentials removed
+func redactHeaders(headers http.Header) http.Header {
+	redacted := make(http.Header, len(headers))
+	for key, values := range headers {
+		if sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+			redacted[key] = []string{redactedValue}
+			continue
+		}
+		redacted[key] = append([]string(nil), values...)
+	}
+	return redacted
+}
+
+// readLimited reads at most limit bytes from r for logging
+func readLimited(r io.Reader, limit int) string {
+	data, _ := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	return truncate(data, limit)
+}
+
+// truncate shortens data to limit bytes, marking that it was cut
+func truncate(data []byte, limit int) string {
+	if len(data) > limit {
+		return string(data[:limit]) + "...(truncated)"
+	}
+	return string(data)
+}
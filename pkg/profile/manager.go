@@ -6,254 +6,527 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 )
 
 // NewProfileManager creates a profile manager instance
 func NewProfileManager() *ZOSMFProfileManager {
 	configPath := getZoweConfigPath()
 	return &ZOSMFProfileManager{
-		configPath: configPath,
+		configPath:  configPath,
+		credentials: NewKeyringCredentialProvider(),
 	}
 }
 
 // NewProfileManagerWithPath creates a profile manager with custom config path
 func NewProfileManagerWithPath(configPath string) *ZOSMFProfileManager {
 	return &ZOSMFProfileManager{
-		configPath: configPath,
+		configPath:  configPath,
+		credentials: NewKeyringCredentialProvider(),
 	}
 }
 
+// SetCredentialProvider replaces the store used for secure profile properties.
+// Passing nil disables secure lookups, so only values in the JSON are used.
+func (pm *ZOSMFProfileManager) SetCredentialProvider(provider CredentialProvider) {
+	pm.credentials = provider
+}
+
+// SetEnvOverrides makes GetZOSMFProfile apply any ZOWE_OPT_* environment variables
+// on top of the values loaded from the config file
+func (pm *ZOSMFProfileManager) SetEnvOverrides(enabled bool) {
+	pm.envOverrides = enabled
+}
+
 // GetZOSMFProfile gets a ZOSMF profile by name
 func (pm *ZOSMFProfileManager) GetZOSMFProfile(name string) (*ZOSMFProfile, error) {
+	// "default" refers to the configured default zosmf profile
+	if name == "default" {
+		config, err := pm.loadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		name = "zosmf"
+		if defaultName, exists := config.Defaults["zosmf"]; exists {
+			name = defaultName
+		}
+	}
+
+	properties, err := pm.GetProfile(name, "zosmf")
+	if err != nil {
+		return nil, err
+	}
+
+	profile := pm.parseZOSMFProfile(name, properties)
+	if pm.envOverrides {
+		if err := ApplyEnvOverrides(profile, ZoweOptEnvPrefix); err != nil {
+			return nil, err
+		}
+	}
+
+	return profile, nil
+}
+
+// GetProfile gets the merged properties of a profile of any type (zosmf, tso, ssh, ...).
+// The name may be a dotted path to a nested profile ("lpar1.zosmf") or the key of a
+// profile at any depth; an empty name selects the default profile for the type.
+// Properties are inherited from the base profile, then from parent profiles.
+func (pm *ZOSMFProfileManager) GetProfile(name, profileType string) (map[string]interface{}, error) {
 	config, err := pm.loadConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Look for zosmf profile
-	zosmfProfile, exists := config.Profiles["zosmf"]
-	if !exists {
-		return nil, fmt.Errorf("no zosmf profiles found in configuration")
+	if name == "" {
+		defaultName, exists := config.Defaults[profileType]
+		if !exists {
+			return nil, fmt.Errorf("no default %s profile set", profileType)
+		}
+		name = defaultName
 	}
 
-	// Get base profile for inheritance
-	var baseProfile *BaseProfile
-	if baseProfileData, exists := config.Profiles["global_base"]; exists {
-		baseProfile = pm.parseBaseProfile(baseProfileData)
+	chain := findProfile(config.Profiles, name)
+	if chain == nil {
+		return nil, fmt.Errorf("%s profile '%s' not found", profileType, name)
+	}
+	target := chain[len(chain)-1].profile
+	if target.Type != profileType {
+		return nil, fmt.Errorf("profile '%s' has type '%s', not '%s'", name, target.Type, profileType)
 	}
 
-	// Parse the ZOSMF profile
-	profile := pm.parseZOSMFProfile(name, zosmfProfile, baseProfile)
-	if profile == nil {
-		return nil, fmt.Errorf("zosmf profile '%s' not found", name)
+	properties := make(map[string]interface{})
+
+	// Apply base profile properties first
+	if profileType != "base" {
+		if baseChain := findBaseProfile(config); baseChain != nil {
+			pm.applyProperties(properties, baseChain[len(baseChain)-1])
+		}
 	}
 
-	return profile, nil
+	// Parent profiles, then the profile itself, override the base
+	for _, node := range chain {
+		pm.applyProperties(properties, node)
+	}
+
+	return properties, nil
 }
 
-// parseBaseProfile parses the base profile from configuration
-func (pm *ZOSMFProfileManager) parseBaseProfile(baseProfileData ZoweProfile) *BaseProfile {
-	baseProfile := &BaseProfile{
-		RejectUnauthorized: true, // Default to true for security
+// applyProperties merges a profile's properties, replacing secure ones with
+// values from the credential store when it has them
+func (pm *ZOSMFProfileManager) applyProperties(properties map[string]interface{}, node profileNode) {
+	for key, value := range node.profile.Properties {
+		properties[key] = value
 	}
 
-	properties := baseProfileData.Properties
-	if properties != nil {
-		if host, ok := properties["host"].(string); ok {
-			baseProfile.Host = host
-		}
-		if port, ok := properties["port"].(float64); ok {
-			baseProfile.Port = int(port)
+	if pm.credentials == nil {
+		return
+	}
+	for _, key := range node.profile.Secure {
+		value, ok, err := pm.credentials.GetSecureValue(pm.configPath, node.path+".properties."+key)
+		if err != nil || !ok {
+			// Fall back to any value kept in the JSON
+			continue
 		}
-		if user, ok := properties["user"].(string); ok {
-			baseProfile.User = user
+		properties[key] = value
+	}
+}
+
+// profileNode is a profile together with its team config path ("profiles.lpar1.profiles.zosmf")
+type profileNode struct {
+	path    string
+	profile ZoweProfile
+}
+
+// findProfile resolves a profile name to the chain of profiles from the top level
+// down to the match. Dotted paths are tried first, then a search by key at any depth.
+func findProfile(profiles map[string]ZoweProfile, name string) []profileNode {
+	return findProfileUnder(profiles, "profiles", name)
+}
+
+// findProfileUnder resolves a profile name within profiles located at configPath
+func findProfileUnder(profiles map[string]ZoweProfile, configPath, name string) []profileNode {
+	// Dotted path through nested profiles
+	var chain []profileNode
+	current := profiles
+	path := configPath
+	for _, part := range strings.Split(name, ".") {
+		profile, exists := current[part]
+		if !exists {
+			chain = nil
+			break
 		}
-		if password, ok := properties["password"].(string); ok {
-			baseProfile.Password = password
+		path += "." + part
+		chain = append(chain, profileNode{path: path, profile: profile})
+		current = profile.Profiles
+		path += ".profiles"
+	}
+	if chain != nil {
+		return chain
+	}
+
+	// Search nested profiles by key, in sorted order for stable results
+	keys := make([]string, 0, len(profiles))
+	for key := range profiles {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		profile := profiles[key]
+		if len(profile.Profiles) == 0 {
+			continue
 		}
-		if rejectUnauthorized, ok := properties["rejectUnauthorized"].(bool); ok {
-			baseProfile.RejectUnauthorized = rejectUnauthorized
+		parentPath := configPath + "." + key
+		if nested := findProfileUnder(profile.Profiles, parentPath+".profiles", name); nested != nil {
+			return append([]profileNode{{path: parentPath, profile: profile}}, nested...)
 		}
 	}
 
-	return baseProfile
+	return nil
 }
 
-// parseZOSMFProfile parses a ZOSMF profile from configuration
-func (pm *ZOSMFProfileManager) parseZOSMFProfile(name string, zosmfProfile ZoweProfile, baseProfile *BaseProfile) *ZOSMFProfile {
-	// Check if this is the profile we're looking for
-	if zosmfProfile.Type != "zosmf" {
-		return nil
-	}
-
-	// In the new schema, we only have one ZOSMF profile, so we only return it if the name matches
-	// For now, we'll return the profile if the name is "zosmf" or if it's the default
-	if name != "zosmf" && name != "default" {
-		return nil
+// findBaseProfile returns the chain for the default base profile, falling back to "global_base"
+func findBaseProfile(config *ZoweConfig) []profileNode {
+	name := "global_base"
+	if defaultName, exists := config.Defaults["base"]; exists {
+		name = defaultName
 	}
+	return findProfile(config.Profiles, name)
+}
 
+// parseZOSMFProfile builds a ZOSMF profile from merged profile properties
+func (pm *ZOSMFProfileManager) parseZOSMFProfile(name string, properties map[string]interface{}) *ZOSMFProfile {
 	profile := &ZOSMFProfile{
 		Name:               name,
 		RejectUnauthorized: true, // Default to true for security
 		Protocol:           "https", // Default protocol
 	}
 
-	// Apply base profile properties if available
-	if baseProfile != nil {
-		if baseProfile.Host != "" {
-			profile.Host = baseProfile.Host
-		}
-		if baseProfile.Port != 0 {
-			profile.Port = baseProfile.Port
-		}
-		if baseProfile.User != "" {
-			profile.User = baseProfile.User
-		}
-		if baseProfile.Password != "" {
-			profile.Password = baseProfile.Password
-		}
-		profile.RejectUnauthorized = baseProfile.RejectUnauthorized
+	if host, ok := properties["host"].(string); ok {
+		profile.Host = host
 	}
-
-	// Apply ZOSMF profile properties (override base profile)
-	properties := zosmfProfile.Properties
-	if properties != nil {
-		if host, ok := properties["host"].(string); ok {
-			profile.Host = host
-		}
-		if port, ok := properties["port"].(float64); ok {
-			profile.Port = int(port)
-		}
-		if user, ok := properties["user"].(string); ok {
-			profile.User = user
-		}
-		if password, ok := properties["password"].(string); ok {
-			profile.Password = password
-		}
-		if rejectUnauthorized, ok := properties["rejectUnauthorized"].(bool); ok {
-			profile.RejectUnauthorized = rejectUnauthorized
-		}
-		if basePath, ok := properties["basePath"].(string); ok {
-			profile.BasePath = basePath
-		}
-		if protocol, ok := properties["protocol"].(string); ok {
-			profile.Protocol = protocol
-		}
-		if encoding, ok := properties["encoding"].(string); ok {
-			profile.Encoding = encoding
-		}
-		if responseTimeout, ok := properties["responseTimeout"].(float64); ok {
-			profile.ResponseTimeout = int(responseTimeout)
-		}
-		if certFile, ok := properties["certFile"].(string); ok {
-			profile.CertFile = certFile
-		}
-		if certKeyFile, ok := properties["certKeyFile"].(string); ok {
-			profile.CertKeyFile = certKeyFile
-		}
+	if port, ok := properties["port"].(float64); ok {
+		profile.Port = int(port)
+	}
+	if user, ok := properties["user"].(string); ok {
+		profile.User = user
+	}
+	if password, ok := properties["password"].(string); ok {
+		profile.Password = password
+	}
+	if rejectUnauthorized, ok := properties["rejectUnauthorized"].(bool); ok {
+		profile.RejectUnauthorized = rejectUnauthorized
+	}
+	if basePath, ok := properties["basePath"].(string); ok {
+		profile.BasePath = basePath
+	}
+	if protocol, ok := properties["protocol"].(string); ok {
+		profile.Protocol = protocol
+	}
+	if encoding, ok := properties["encoding"].(string); ok {
+		profile.Encoding = encoding
+	}
+	if responseTimeout, ok := properties["responseTimeout"].(float64); ok {
+		profile.ResponseTimeout = int(responseTimeout)
+	}
+	if certFile, ok := properties["certFile"].(string); ok {
+		profile.CertFile = certFile
+	}
+	if certKeyFile, ok := properties["certKeyFile"].(string); ok {
+		profile.CertKeyFile = certKeyFile
+	}
+	if proxyURL, ok := properties["proxyURL"].(string); ok {
+		profile.ProxyURL = proxyURL
+	}
+	if caCertFile, ok := properties["caCertFile"].(string); ok {
+		profile.CACertFile = caCertFile
+	}
+	if caCertPath, ok := properties["caCertPath"].(string); ok {
+		profile.CACertPath = caCertPath
+	}
+	if serverName, ok := properties["serverName"].(string); ok {
+		profile.ServerName = serverName
 	}
 
 	return profile
 }
 
-// ListZOSMFProfiles returns a list of available ZOSMF profile names
+// ListZOSMFProfiles returns the names of all zosmf profiles, including nested
+// profiles as dotted paths ("lpar1.zosmf"), in sorted order
 func (pm *ZOSMFProfileManager) ListZOSMFProfiles() ([]string, error) {
 	config, err := pm.loadConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	zosmfProfile, exists := config.Profiles["zosmf"]
-	if !exists {
-		return []string{}, nil
+	profileNames := collectProfileNames(config.Profiles, "", "zosmf")
+	sort.Strings(profileNames)
+	return profileNames, nil
+}
+
+// collectProfileNames returns the paths of all profiles of the given type
+func collectProfileNames(profiles map[string]ZoweProfile, prefix, profileType string) []string {
+	names := []string{}
+	for name, profile := range profiles {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		if profile.Type == profileType {
+			names = append(names, path)
+		}
+		names = append(names, collectProfileNames(profile.Profiles, path, profileType)...)
 	}
+	return names
+}
 
-	// For the new schema, we need to check if there are sub-profiles
-	// or if this is a single ZOSMF profile
-	if zosmfProfile.Type == "zosmf" {
-		// Single ZOSMF profile, return the default name
-		return []string{"zosmf"}, nil
+// SaveZOSMFProfile saves a ZOSMF profile under its own name, creating it or replacing
+// the connection properties of an existing one. A dotted name ("lpar1.zosmf") saves a
+// nested profile whose parent must already exist; an empty name saves "zosmf".
+// Other profiles, secure property lists and properties the SDK does not manage are
+// kept, and properties listed as secure are never written. The first zosmf profile saved to a config without a default becomes the default.
+func (pm *ZOSMFProfileManager) SaveZOSMFProfile(profile *ZOSMFProfile) error {
+	if profile == nil {
+		return fmt.Errorf("profile cannot be nil")
+	}
+	name := profile.Name
+	if name == "" {
+		name = "zosmf"
 	}
 
-	// Check for sub-profiles
-	if zosmfProfile.Profiles != nil {
-		var profileNames []string
-		for name := range zosmfProfile.Profiles {
-			profileNames = append(profileNames, name)
+	pm.updateMu.Lock()
+	defer pm.updateMu.Unlock()
+
+	config, err := pm.loadConfigForUpdate()
+	if err != nil {
+		if _, statErr := os.Stat(pm.configPath); !os.IsNotExist(statErr) {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		// If config doesn't exist, create a new one
+		config = &ZoweConfig{}
+	}
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]ZoweProfile)
+	}
+	if config.Defaults == nil {
+		config.Defaults = make(map[string]string)
+	}
+
+	container, key, err := profileContainerForSave(config, name)
+	if err != nil {
+		return err
+	}
+
+	zosmfProfile, exists := container[key]
+	if exists && zosmfProfile.Type != "zosmf" {
+		return fmt.Errorf("profile '%s' has type '%s', not 'zosmf'", name, zosmfProfile.Type)
+	}
+	zosmfProfile.Type = "zosmf"
+	if zosmfProfile.Properties == nil {
+		zosmfProfile.Properties = make(map[string]interface{})
+	}
+	properties := zosmfProfile.Properties
+	saved := make(map[string]interface{}, len(zosmfProfile.Secure))
+	for _, secureKey := range zosmfProfile.Secure {
+		if value, ok := properties[secureKey]; ok {
+			saved[secureKey] = value
 		}
-		return profileNames, nil
 	}
 
-	return []string{}, nil
+	// Convert profile to properties
+	properties["host"] = profile.Host
+	properties["port"] = profile.Port
+	properties["user"] = profile.User
+	properties["password"] = profile.Password
+	properties["rejectUnauthorized"] = profile.RejectUnauthorized
+	properties["basePath"] = profile.BasePath
+	properties["protocol"] = profile.Protocol
+
+	setOptionalProperty(properties, "encoding", profile.Encoding, profile.Encoding != "")
+	setOptionalProperty(properties, "responseTimeout", profile.ResponseTimeout, profile.ResponseTimeout != 0)
+	setOptionalProperty(properties, "certFile", profile.CertFile, profile.CertFile != "")
+	setOptionalProperty(properties, "certKeyFile", profile.CertKeyFile, profile.CertKeyFile != "")
+	setOptionalProperty(properties, "proxyURL", profile.ProxyURL, profile.ProxyURL != "")
+	setOptionalProperty(properties, "caCertFile", profile.CACertFile, profile.CACertFile != "")
+	setOptionalProperty(properties, "caCertPath", profile.CACertPath, profile.CACertPath != "")
+	setOptionalProperty(properties, "serverName", profile.ServerName, profile.ServerName != "")
+
+	// Secure properties live in the credential store, and the profile may hold values
+	// read from it or from the environment, so the file keeps what it had for them
+	for _, secureKey := range zosmfProfile.Secure {
+		value, kept := saved[secureKey]
+		setOptionalProperty(properties, secureKey, value, kept)
+	}
+
+	container[key] = zosmfProfile
+
+	if _, hasDefault := config.Defaults["zosmf"]; !hasDefault {
+		config.Defaults["zosmf"] = name
+	}
+
+	return pm.saveConfig(config)
 }
 
-// SaveZOSMFProfile saves a ZOSMF profile to the configuration
-func (pm *ZOSMFProfileManager) SaveZOSMFProfile(profile *ZOSMFProfile) error {
-	config, err := pm.loadConfig()
+// UpdateZOSMFProfile merges changes into the properties of an existing ZOSMF profile
+// and saves the config. Properties not named in changes, including ones the SDK does
+// not model, are kept; a nil value removes a property.
+func (pm *ZOSMFProfileManager) UpdateZOSMFProfile(name string, changes map[string]interface{}) error {
+	pm.updateMu.Lock()
+	defer pm.updateMu.Unlock()
+
+	config, err := pm.loadConfigForUpdate()
 	if err != nil {
-		// If config doesn't exist, create a new one
-		config = &ZoweConfig{
-			Profiles: make(map[string]ZoweProfile),
-			Defaults: make(map[string]string),
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	chain := findProfile(config.Profiles, name)
+	if chain == nil {
+		return fmt.Errorf("zosmf profile '%s' not found", name)
+	}
+	target := chain[len(chain)-1].profile
+	if target.Type != "zosmf" {
+		return fmt.Errorf("profile '%s' has type '%s', not 'zosmf'", name, target.Type)
+	}
+
+	if target.Properties == nil {
+		target.Properties = make(map[string]interface{})
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(target.Properties, key)
+			continue
 		}
+		target.Properties[key] = value
 	}
+	container, key := profileContainer(config, chain)
+	container[key] = target
 
-	// Ensure zosmf profile exists
-	if _, exists := config.Profiles["zosmf"]; !exists {
-		config.Profiles["zosmf"] = ZoweProfile{
-			Type:       "zosmf",
-			Properties: make(map[string]interface{}),
+	return pm.saveConfig(config)
+}
+
+// setOptionalProperty sets a property when it has a value and removes it otherwise
+func setOptionalProperty(properties map[string]interface{}, key string, value interface{}, set bool) {
+	if set {
+		properties[key] = value
+	} else {
+		delete(properties, key)
+	}
+}
+
+// profileContainerForSave returns the map that holds (or will hold) the named profile
+// and its key in that map. Existing profiles are found the same way GetProfile finds
+// them; new ones go at the top level, or under their parent for dotted names.
+func profileContainerForSave(config *ZoweConfig, name string) (map[string]ZoweProfile, string, error) {
+	if chain := findProfile(config.Profiles, name); chain != nil {
+		container, key := profileContainer(config, chain)
+		return container, key, nil
+	}
+
+	parts := strings.Split(name, ".")
+	for _, part := range parts {
+		if part == "" {
+			return nil, "", fmt.Errorf("invalid profile name '%s'", name)
 		}
 	}
+	if len(parts) == 1 {
+		return config.Profiles, name, nil
+	}
 
-	// Convert profile to properties
-	properties := map[string]interface{}{
-		"host":               profile.Host,
-		"port":               profile.Port,
-		"user":               profile.User,
-		"password":           profile.Password,
-		"rejectUnauthorized": profile.RejectUnauthorized,
-		"basePath":           profile.BasePath,
-		"protocol":           profile.Protocol,
+	parentName := strings.Join(parts[:len(parts)-1], ".")
+	parentChain := findProfile(config.Profiles, parentName)
+	if parentChain == nil {
+		return nil, "", fmt.Errorf("parent profile '%s' not found", parentName)
+	}
+	container, parentKey := profileContainer(config, parentChain)
+	parent := container[parentKey]
+	if parent.Profiles == nil {
+		parent.Profiles = make(map[string]ZoweProfile)
+		container[parentKey] = parent
 	}
+	return parent.Profiles, parts[len(parts)-1], nil
+}
 
-	if profile.Encoding != "" {
-		properties["encoding"] = profile.Encoding
+// profileContainer walks a chain from findProfile and returns the map holding its
+// last profile, along with that profile's key
+func profileContainer(config *ZoweConfig, chain []profileNode) (map[string]ZoweProfile, string) {
+	container := config.Profiles
+	for _, node := range chain[:len(chain)-1] {
+		container = container[profileKey(node.path)].Profiles
 	}
-	if profile.ResponseTimeout != 0 {
-		properties["responseTimeout"] = profile.ResponseTimeout
+	return container, profileKey(chain[len(chain)-1].path)
+}
+
+// profileKey returns the last segment of a team config path
+func profileKey(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+// DeleteZOSMFProfile removes a ZOSMF profile, and any profiles nested under it, from
+// the configuration. Defaults that referred to a removed profile are cleared.
+func (pm *ZOSMFProfileManager) DeleteZOSMFProfile(name string) error {
+	pm.updateMu.Lock()
+	defer pm.updateMu.Unlock()
+
+	config, err := pm.loadConfigForUpdate()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
 	}
-	if profile.CertFile != "" {
-		properties["certFile"] = profile.CertFile
+
+	chain := findProfile(config.Profiles, name)
+	if chain == nil {
+		return fmt.Errorf("zosmf profile '%s' not found", name)
 	}
-	if profile.CertKeyFile != "" {
-		properties["certKeyFile"] = profile.CertKeyFile
+	target := chain[len(chain)-1].profile
+	if target.Type != "zosmf" {
+		return fmt.Errorf("profile '%s' has type '%s', not 'zosmf'", name, target.Type)
 	}
 
-	// Update the zosmf profile
-	zosmfProfile := config.Profiles["zosmf"]
-	zosmfProfile.Properties = properties
-	config.Profiles["zosmf"] = zosmfProfile
+	container, key := profileContainer(config, chain)
+	delete(container, key)
+
+	// Drop defaults, of any type, that name the profile or one nested under it, by the
+	// name given or by its full dotted name. Other defaults are left as they are.
+	keys := make([]string, len(chain))
+	for i, node := range chain {
+		keys[i] = profileKey(node.path)
+	}
+	removed := []string{name, strings.Join(keys, ".")}
+	for profileType, defaultName := range config.Defaults {
+		for _, prefix := range removed {
+			if defaultName == prefix || strings.HasPrefix(defaultName, prefix+".") {
+				delete(config.Defaults, profileType)
+				break
+			}
+		}
+	}
 
 	return pm.saveConfig(config)
 }
 
-// DeleteZOSMFProfile deletes a ZOSMF profile from the configuration
-func (pm *ZOSMFProfileManager) DeleteZOSMFProfile(name string) error {
-	config, err := pm.loadConfig()
+// SetDefaultZOSMFProfile makes the named zosmf profile the default
+func (pm *ZOSMFProfileManager) SetDefaultZOSMFProfile(name string) error {
+	pm.updateMu.Lock()
+	defer pm.updateMu.Unlock()
+
+	config, err := pm.loadConfigForUpdate()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	_, exists := config.Profiles["zosmf"]
-	if !exists {
-		return fmt.Errorf("no zosmf profiles found")
+	chain := findProfile(config.Profiles, name)
+	if chain == nil {
+		return fmt.Errorf("zosmf profile '%s' not found", name)
+	}
+	if target := chain[len(chain)-1].profile; target.Type != "zosmf" {
+		return fmt.Errorf("profile '%s' has type '%s', not 'zosmf'", name, target.Type)
 	}
 
-	// For the new schema, we can't delete individual profiles easily
-	// since they're properties of the zosmf profile
-	// This would require more complex logic to handle sub-profiles
-	return fmt.Errorf("deleting individual profiles not supported in new schema")
+	if config.Defaults == nil {
+		config.Defaults = make(map[string]string)
+	}
+	config.Defaults["zosmf"] = name
+
+	return pm.saveConfig(config)
 }
 
 // GetDefaultZOSMFProfile returns the default ZOSMF profile
@@ -271,31 +544,52 @@ func (pm *ZOSMFProfileManager) GetDefaultZOSMFProfile() (*ZOSMFProfile, error) {
 	return pm.GetZOSMFProfile(defaultName)
 }
 
-// loadConfig loads the Zowe configuration from file
+// loadConfig returns the Zowe configuration, reading the file only when it has
+// changed since it was last read. The result is shared with other callers and
+// must not be modified; use loadConfigForUpdate for that.
 func (pm *ZOSMFProfileManager) loadConfig() (*ZoweConfig, error) {
 	// Check if config file exists
-	if _, err := os.Stat(pm.configPath); os.IsNotExist(err) {
+	info, err := os.Stat(pm.configPath)
+	if os.IsNotExist(err) {
 		return nil, fmt.Errorf("zowe config file not found at %s", pm.configPath)
 	}
 
-	// Read the config file directly as JSON
-	data, err := os.ReadFile(pm.configPath)
+	pm.configMu.Lock()
+	defer pm.configMu.Unlock()
+	if err == nil && pm.config.current(info) {
+		return pm.config.config, nil
+	}
+	return pm.readConfigLocked(info)
+}
+
+// loadConfigForUpdate returns a copy of the Zowe configuration that the caller may modify
+func (pm *ZOSMFProfileManager) loadConfigForUpdate() (*ZoweConfig, error) {
+	config, err := pm.loadConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
+	return cloneConfig(config), nil
+}
 
-	var config ZoweConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+// saveConfig saves the Zowe configuration to file. The file is replaced atomically,
+// so readers never see a partially written config. The cached copy is dropped: a file
+// written this recently is read again on every call until configRacyWindow has passed.
+func (pm *ZOSMFProfileManager) saveConfig(config *ZoweConfig) error {
+	if err := writeConfigFile(pm.configPath, config); err != nil {
+		return err
 	}
 
-	return &config, nil
+	pm.configMu.Lock()
+	pm.config = nil
+	pm.configMu.Unlock()
+	return nil
 }
 
-// saveConfig saves the Zowe configuration to file
-func (pm *ZOSMFProfileManager) saveConfig(config *ZoweConfig) error {
+// writeConfigFile writes a Zowe configuration as indented JSON, replacing the file
+// atomically through a temporary file in the same directory
+func writeConfigFile(path string, config *ZoweConfig) error {
 	// Ensure the directory exists
-	configDir := filepath.Dir(pm.configPath)
+	configDir := filepath.Dir(path)
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
@@ -306,11 +600,31 @@ func (pm *ZOSMFProfileManager) saveConfig(config *ZoweConfig) error {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(pm.configPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+	// Keep the permissions of an existing file
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
 	}
 
+	// Write to a temporary file and rename it over the config
+	tmp, err := os.CreateTemp(configDir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
 	return nil
 }
 
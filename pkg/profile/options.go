@@ -0,0 +1,58 @@
+package profile
+
+import "net/http"
+
+// SessionOption customizes a session created by NewSession
+type SessionOption func(*Session)
+
+// WithLogger logs every request made with the session, as SetLogger does
+func WithLogger(logger Logger) SessionOption {
+	return func(s *Session) {
+		s.logger = logger
+	}
+}
+
+// WithLogBodyLimit logs request/response bodies truncated to limit bytes,
+// overriding ZOWE_SDK_LOG_BODY_BYTES
+func WithLogBodyLimit(limit int) SessionOption {
+	return func(s *Session) {
+		if limit < 0 {
+			limit = 0
+		}
+		s.logBodyLimit = limit
+	}
+}
+
+// WithHTTPClient makes the session send requests with a copy of client, keeping its
+// timeout, cookie jar and redirect policy. Its transport is wrapped so session logging
+// still applies; the profile's RejectUnauthorized setting is not applied to it.
+func WithHTTPClient(client *http.Client) SessionOption {
+	return func(s *Session) {
+		if client == nil {
+			return
+		}
+		base := client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		custom := *client
+		custom.Transport = &sessionTransport{base: base, session: s}
+		s.HTTPClient = &custom
+	}
+}
+
+// WithTransport replaces the transport the session's client uses, e.g. to add a proxy,
+// a custom CA pool or a recording transport in tests. The profile's RejectUnauthorized
+// setting is not applied to it.
+func WithTransport(transport http.RoundTripper) SessionOption {
+	return func(s *Session) {
+		if transport == nil {
+			return
+		}
+		if t, ok := s.HTTPClient.Transport.(*sessionTransport); ok {
+			t.base = transport
+			return
+		}
+		s.HTTPClient.Transport = &sessionTransport{base: transport, session: s}
+	}
+}
@@ -1,10 +1,29 @@
 package profile
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -41,6 +60,7 @@ func TestValidateProfile(t *testing.T) {
 		name    string
 		profile *ZOSMFProfile
 		wantErr bool
+		errText string
 	}{
 		{
 			name: "valid profile",
@@ -89,6 +109,35 @@ func TestValidateProfile(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:    "http protocol and custom base path",
+			profile: &ZOSMFProfile{Host: "localhost", Port: 8080, User: "user", Password: "pass", Protocol: "HTTP", BasePath: "/ibm/zosmf/"},
+			wantErr: false,
+		},
+		{
+			name:    "unsupported protocol",
+			profile: &ZOSMFProfile{Host: "localhost", Port: 443, User: "user", Password: "pass", Protocol: "ftp"},
+			wantErr: true,
+			errText: `invalid protocol "ftp"`,
+		},
+		{
+			name:    "protocol with separator",
+			profile: &ZOSMFProfile{Host: "localhost", Port: 443, User: "user", Password: "pass", Protocol: "https://"},
+			wantErr: true,
+			errText: "invalid protocol",
+		},
+		{
+			name:    "base path with spaces",
+			profile: &ZOSMFProfile{Host: "localhost", Port: 443, User: "user", Password: "pass", BasePath: "/z osmf"},
+			wantErr: true,
+			errText: "invalid base path",
+		},
+		{
+			name:    "base path with scheme",
+			profile: &ZOSMFProfile{Host: "localhost", Port: 443, User: "user", Password: "pass", BasePath: "https://host/zosmf"},
+			wantErr: true,
+			errText: "invalid base path",
+		},
 	}
 
 	for _, tt := range tests {
@@ -96,6 +145,9 @@ func TestValidateProfile(t *testing.T) {
 			err := ValidateProfile(tt.profile)
 			if tt.wantErr {
 				assert.Error(t, err)
+				if tt.errText != "" {
+					assert.ErrorContains(t, err, tt.errText)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
@@ -275,6 +327,7 @@ func TestCloneProfile(t *testing.T) {
 		ResponseTimeout:    30,
 		CertFile:           "/path/to/cert.pem",
 		CertKeyFile:        "/path/to/key.pem",
+		ProxyURL:           "http://proxy.example.com:8080",
 	}
 
 	cloned := CloneProfile(original)
@@ -291,6 +344,7 @@ func TestCloneProfile(t *testing.T) {
 	assert.Equal(t, original.ResponseTimeout, cloned.ResponseTimeout)
 	assert.Equal(t, original.CertFile, cloned.CertFile)
 	assert.Equal(t, original.CertKeyFile, cloned.CertKeyFile)
+	assert.Equal(t, original.ProxyURL, cloned.ProxyURL)
 	
 	// Ensure it's a different instance
 	assert.NotSame(t, original, cloned)
@@ -323,8 +377,8 @@ func TestSaveZOSMFProfile(t *testing.T) {
 	err := pm.SaveZOSMFProfile(profile)
 	require.NoError(t, err)
 
-	// Verify the profile was saved
-	savedProfile, err := pm.GetZOSMFProfile("zosmf")
+	// Verify the profile was saved under its own name
+	savedProfile, err := pm.GetZOSMFProfile("test")
 	require.NoError(t, err)
 	assert.Equal(t, "testhost.com", savedProfile.Host)
 	assert.Equal(t, 443, savedProfile.Port)
@@ -344,10 +398,162 @@ func TestDeleteZOSMFProfile(t *testing.T) {
 	// Create profile manager
 	pm := NewProfileManagerWithPath(configPath)
 
-	// Test deleting a profile (should return error in new schema)
+	// Deleting from a config that does not exist fails
 	err := pm.DeleteZOSMFProfile("zosmf")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to load config")
+
+	// A single zosmf profile goes with its default entry and the profiles nested under it
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"profiles": {
+			"zosmf": {
+				"type": "zosmf",
+				"properties": {"host": "example.com", "port": 443},
+				"secure": ["user", "password"],
+				"profiles": {"tso": {"type": "tso", "properties": {"account": "ACCT"}}}
+			},
+			"ssh": {"type": "ssh", "properties": {"port": 22}}
+		},
+		"defaults": {"zosmf": "zosmf", "tso": "zosmf.tso", "ssh": "ssh", "base": "gone", "zftp": "zosmf2"}
+	}`), 0644))
+	require.NoError(t, pm.DeleteZOSMFProfile("zosmf"))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	var saved ZoweConfig
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.NotContains(t, saved.Profiles, "zosmf")
+	assert.Contains(t, saved.Profiles, "ssh")
+	// Defaults that were already dangling, or only share a prefix, are left alone
+	assert.Equal(t, map[string]string{"ssh": "ssh", "base": "gone", "zftp": "zosmf2"}, saved.Defaults)
+
+	_, err = pm.GetZOSMFProfile("zosmf")
+	assert.Error(t, err)
+	assert.Error(t, pm.DeleteZOSMFProfile("zosmf"))
+
+	// A nested profile found by key also clears defaults naming its full dotted name
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"profiles": {
+			"lpar1": {"profiles": {"zosmf": {"type": "zosmf", "properties": {"host": "lpar1.example.com"}}}}
+		},
+		"defaults": {"zosmf": "lpar1.zosmf", "base": "lpar1"}
+	}`), 0644))
+	require.NoError(t, pm.DeleteZOSMFProfile("zosmf"))
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	saved = ZoweConfig{}
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, map[string]string{"base": "lpar1"}, saved.Defaults)
+}
+
+func TestUpdateZOSMFProfile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "zowe.config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"profiles": {
+			"lpar1": {
+				"properties": {"host": "lpar1.example.com"},
+				"profiles": {"zosmf": {"type": "zosmf", "properties": {"port": 443, "encoding": "IBM-037", "customerCode": "X1"}}}
+			},
+			"ssh": {"type": "ssh", "properties": {"port": 22}}
+		},
+		"defaults": {"zosmf": "lpar1.zosmf"}
+	}`), 0644))
+
+	pm := NewProfileManagerWithPath(configPath)
+	pm.SetCredentialProvider(nil)
+
+	require.NoError(t, pm.UpdateZOSMFProfile("lpar1.zosmf", map[string]interface{}{
+		"port":     1443,
+		"user":     "IBMUSER",
+		"encoding": nil,
+	}))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	var saved ZoweConfig
+	require.NoError(t, json.Unmarshal(data, &saved))
+	properties := saved.Profiles["lpar1"].Profiles["zosmf"].Properties
+	assert.Equal(t, map[string]interface{}{"port": float64(1443), "user": "IBMUSER", "customerCode": "X1"}, properties)
+	assert.Equal(t, "lpar1.example.com", saved.Profiles["lpar1"].Properties["host"])
+
+	merged, err := pm.GetZOSMFProfile("lpar1.zosmf")
+	require.NoError(t, err)
+	assert.Equal(t, "lpar1.example.com", merged.Host)
+	assert.Equal(t, 1443, merged.Port)
+
+	assert.Error(t, pm.UpdateZOSMFProfile("missing", map[string]interface{}{"port": 1}))
+	assert.Error(t, pm.UpdateZOSMFProfile("ssh", map[string]interface{}{"port": 1}))
+}
+
+func TestSaveMultipleZOSMFProfiles(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "zowe.config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"profiles": {
+			"lpar1": {
+				"properties": {"host": "lpar1.example.com"},
+				"profiles": {"tso": {"type": "tso", "properties": {"account": "ACCT"}}}
+			},
+			"ssh": {"type": "ssh", "properties": {"port": 22}}
+		},
+		"defaults": {"ssh": "ssh"}
+	}`), 0644))
+
+	pm := NewProfileManagerWithPath(configPath)
+	pm.SetCredentialProvider(nil)
+
+	require.NoError(t, pm.SaveZOSMFProfile(&ZOSMFProfile{Name: "dev", Host: "dev.example.com", Port: 443, User: "DEVUSER", Protocol: "https"}))
+	require.NoError(t, pm.SaveZOSMFProfile(&ZOSMFProfile{Name: "lpar1.zosmf", Port: 1443, User: "PRODUSER", Protocol: "https"}))
+
+	names, err := pm.ListZOSMFProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "lpar1.zosmf"}, names)
+
+	// The first profile saved became the default
+	defaultProfile, err := pm.GetDefaultZOSMFProfile()
+	require.NoError(t, err)
+	assert.Equal(t, "dev.example.com", defaultProfile.Host)
+
+	// Nested profiles inherit from their parent once saved
+	nested, err := pm.GetZOSMFProfile("lpar1.zosmf")
+	require.NoError(t, err)
+	assert.Equal(t, 1443, nested.Port)
+
+	require.NoError(t, pm.SetDefaultZOSMFProfile("lpar1.zosmf"))
+	defaultProfile, err = pm.GetZOSMFProfile("default")
+	require.NoError(t, err)
+	assert.Equal(t, "PRODUSER", defaultProfile.User)
+	assert.Error(t, pm.SetDefaultZOSMFProfile("missing"))
+	assert.Error(t, pm.SetDefaultZOSMFProfile("ssh"))
+
+	// Updating a profile replaces its connection properties in place
+	require.NoError(t, pm.SaveZOSMFProfile(&ZOSMFProfile{Name: "dev", Host: "dev2.example.com", Port: 443, Protocol: "https"}))
+	updated, err := pm.GetZOSMFProfile("dev")
+	require.NoError(t, err)
+	assert.Equal(t, "dev2.example.com", updated.Host)
+
+	// Deleting the default profile clears the default pointer
+	require.NoError(t, pm.DeleteZOSMFProfile("lpar1.zosmf"))
+	assert.Error(t, pm.DeleteZOSMFProfile("lpar1.zosmf"))
+	assert.Error(t, pm.DeleteZOSMFProfile("ssh"))
+
+	reloaded := NewProfileManagerWithPath(configPath)
+	reloaded.SetCredentialProvider(nil)
+	names, err = reloaded.ListZOSMFProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev"}, names)
+	_, err = reloaded.GetDefaultZOSMFProfile()
+	assert.Error(t, err)
+
+	// Unrelated profiles and defaults were preserved
+	tso, err := reloaded.GetProfile("lpar1.tso", "tso")
+	require.NoError(t, err)
+	assert.Equal(t, "ACCT", tso["account"])
+	ssh, err := reloaded.GetProfile("", "ssh")
+	require.NoError(t, err)
+	assert.Equal(t, float64(22), ssh["port"])
+
+	assert.Error(t, pm.SaveZOSMFProfile(&ZOSMFProfile{Name: "missing.zosmf"}))
+	assert.Error(t, pm.SaveZOSMFProfile(&ZOSMFProfile{Name: "ssh"}))
 }
 
 func TestGetZoweConfigPath(t *testing.T) {
@@ -461,8 +667,52 @@ func TestSessionWithDifferentProtocols(t *testing.T) {
 				Port:     443,
 				Protocol: "http",
 			},
+			expected: "http://localhost:443/zosmf",
+		},
+		{
+			name: "explicit https on port 8080",
+			profile: &ZOSMFProfile{
+				Host:     "localhost",
+				Port:     8080,
+				Protocol: "https",
+			},
+			expected: "https://localhost:8080/zosmf",
+		},
+		{
+			name: "explicit https on port 80",
+			profile: &ZOSMFProfile{
+				Host:     "localhost",
+				Port:     80,
+				Protocol: "https",
+			},
+			expected: "https://localhost:80/zosmf",
+		},
+		{
+			name: "explicit http on custom port",
+			profile: &ZOSMFProfile{
+				Host:     "localhost",
+				Port:     9000,
+				Protocol: "http",
+			},
+			expected: "http://localhost:9000/zosmf",
+		},
+		{
+			name: "explicit http on port 80",
+			profile: &ZOSMFProfile{
+				Host:     "localhost",
+				Port:     80,
+				Protocol: "HTTP",
+			},
 			expected: "http://localhost/zosmf",
 		},
+		{
+			name: "no protocol on custom port",
+			profile: &ZOSMFProfile{
+				Host: "localhost",
+				Port: 9000,
+			},
+			expected: "https://localhost:9000/zosmf",
+		},
 	}
 
 	for _, tt := range tests {
@@ -486,4 +736,1573 @@ func TestWriteTestConfig(t *testing.T) {
 	data, err := os.ReadFile(configPath)
 	require.NoError(t, err)
 	assert.Equal(t, content, string(data))
-} 
\ No newline at end of file
+} 
+func TestSaveZoweConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "nested", "zowe.config.json")
+
+	config := &ZoweConfig{
+		Profiles: map[string]ZoweProfile{
+			"lpar1": {
+				Properties: map[string]interface{}{"host": "lpar1.example.com"},
+				Profiles: map[string]ZoweProfile{
+					"zosmf": {Type: "zosmf", Properties: map[string]interface{}{"port": 443}},
+				},
+			},
+		},
+		Defaults: map[string]string{"zosmf": "lpar1.zosmf"},
+	}
+	require.NoError(t, SaveZoweConfig(configPath, config))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "\n  \"profiles\": {")
+	var saved ZoweConfig
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, "lpar1.zosmf", saved.Defaults["zosmf"])
+
+	// The saved file is a config the profile manager can use
+	profile, err := NewProfileManagerWithPath(configPath).GetDefaultZOSMFProfile()
+	require.NoError(t, err)
+	assert.Equal(t, "lpar1.example.com", profile.Host)
+	assert.Equal(t, 443, profile.Port)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(configPath))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// Missing maps are written as empty objects
+	emptyPath := filepath.Join(tempDir, "empty.json")
+	require.NoError(t, SaveZoweConfig(emptyPath, &ZoweConfig{}))
+	data, err = os.ReadFile(emptyPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"profiles": {}, "defaults": {}}`, string(data))
+
+	// Invalid configs are rejected and nothing is written
+	badPath := filepath.Join(tempDir, "bad.json")
+	assert.Error(t, SaveZoweConfig(badPath, nil))
+	assert.Error(t, SaveZoweConfig(badPath, &ZoweConfig{Defaults: map[string]string{"zosmf": "missing"}}))
+	assert.Error(t, SaveZoweConfig(badPath, &ZoweConfig{
+		Profiles: map[string]ZoweProfile{"tso": {Type: "tso"}},
+		Defaults: map[string]string{"zosmf": "tso"},
+	}))
+	assert.Error(t, SaveZoweConfig(badPath, &ZoweConfig{Profiles: map[string]ZoweProfile{"a.b": {Type: "zosmf"}}}))
+	_, err = os.Stat(badPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+// newTestServerSession creates a session pointing at the given test server
+func newTestServerSession(t *testing.T, serverURL string) *Session {
+	profile := &ZOSMFProfile{
+		Host:     strings.TrimPrefix(serverURL, "http://"),
+		User:     "user",
+		Password: "s3cr3t-pass",
+		BasePath: "/api/v1",
+		Protocol: "http",
+	}
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+	return session
+}
+
+// doSessionRequest issues a request the same way the managers do
+func doSessionRequest(t *testing.T, session *Session, method, path, body string) *http.Response {
+	resp, err := session.Do(context.Background(), method, path, nil, nil, strings.NewReader(body))
+	require.NoError(t, err)
+	return resp
+}
+
+func TestSessionDo(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		switch r.URL.Path {
+		case "/api/v1/restjobs/jobs/MYJOB/JOB00001":
+			w.Write([]byte(`{"jobname":"MYJOB","jobid":"JOB00001"}`))
+		case "/api/v1/restjobs/jobs/MYJOB/JOB00002":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"rc":4,"reason":10,"category":6,"message":"No job found for reference: 'MYJOB(JOB00009)'"}`))
+		}
+	}))
+	defer server.Close()
+
+	session := newTestServerSession(t, server.URL)
+	ctx := context.Background()
+
+	t.Run("joins paths and merges headers", func(t *testing.T) {
+		query := url.Values{"owner": {"IBMUSER"}, "prefix": {"MY*"}}
+		headers := map[string]string{"Content-Type": "text/plain", "X-IBM-Max-Items": "10"}
+		resp, err := session.Do(ctx, "PUT", "restjobs/jobs", query, headers, strings.NewReader("//MYJOB JOB"))
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, "PUT", got.Method)
+		assert.Equal(t, "/api/v1/restjobs/jobs", got.URL.Path)
+		assert.Equal(t, "IBMUSER", got.URL.Query().Get("owner"))
+		assert.Equal(t, "MY*", got.URL.Query().Get("prefix"))
+		assert.Equal(t, "text/plain", got.Header.Get("Content-Type"))
+		assert.Equal(t, "10", got.Header.Get("X-IBM-Max-Items"))
+		assert.Equal(t, "application/json", got.Header.Get("Accept"))
+		assert.True(t, strings.HasPrefix(got.Header.Get("Authorization"), "Basic "))
+		assert.Equal(t, "//MYJOB JOB", gotBody)
+		assert.Equal(t, "application/json", session.Headers["Content-Type"], "session headers are not changed")
+
+		// A query is added to one already in the path; a trailing slash on the base is ignored
+		session.BaseURL += "/"
+		defer func() { session.BaseURL = strings.TrimSuffix(session.BaseURL, "/") }()
+		resp, err = session.Do(ctx, "GET", "/restjobs/jobs?status=active", url.Values{"owner": {"*"}}, nil, nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, "/api/v1/restjobs/jobs", got.URL.Path)
+		assert.Equal(t, "active", got.URL.Query().Get("status"))
+		assert.Equal(t, "*", got.URL.Query().Get("owner"))
+	})
+
+	t.Run("returns failed responses", func(t *testing.T) {
+		resp, err := session.Do(ctx, "GET", "/restjobs/jobs/MYJOB/JOB00009", nil, nil, nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("DoJSON decodes the response", func(t *testing.T) {
+		var job struct {
+			JobName string `json:"jobname"`
+			JobID   string `json:"jobid"`
+		}
+		require.NoError(t, session.DoJSON(ctx, "GET", "/restjobs/jobs/MYJOB/JOB00001", nil, nil, nil, &job))
+		assert.Equal(t, "MYJOB", job.JobName)
+		assert.Equal(t, "JOB00001", job.JobID)
+
+		// An empty response leaves out alone
+		require.NoError(t, session.DoJSON(ctx, "DELETE", "/restjobs/jobs/MYJOB/JOB00002", nil, nil, nil, &job))
+		assert.Equal(t, "MYJOB", job.JobName)
+	})
+
+	t.Run("DoJSON translates errors", func(t *testing.T) {
+		err := session.DoJSON(ctx, "GET", "/restjobs/jobs/MYJOB/JOB00009", nil, nil, nil, nil)
+		require.Error(t, err)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, 4, apiErr.RC)
+		assert.Equal(t, 10, apiErr.Reason)
+		assert.Equal(t, 6, apiErr.Category)
+		assert.Equal(t, "No job found for reference: 'MYJOB(JOB00009)'", apiErr.Message)
+		assert.Contains(t, err.Error(), "API request failed with status 404: ")
+	})
+
+	t.Run("reports transport errors", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := session.Do(canceled, "GET", "/restjobs/jobs", nil, nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to make request")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestSessionLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+
+	session := newTestServerSession(t, server.URL)
+
+	var entries []*RequestLog
+	session.SetLogger(LoggerFunc(func(entry *RequestLog) {
+		entries = append(entries, entry)
+	}))
+
+	resp := doSessionRequest(t, session, "GET", "/restfiles/ds?dslevel=USER.*", "")
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, `{"items":[]}`, string(body))
+
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "GET", entry.Method)
+	assert.Equal(t, server.URL+"/api/v1/restfiles/ds?dslevel=USER.*", entry.URL)
+	assert.Equal(t, http.StatusOK, entry.StatusCode)
+	assert.True(t, entry.Duration > 0)
+	assert.Equal(t, "<redacted>", entry.Headers.Get("Authorization"))
+	assert.Equal(t, "application/json", entry.Headers.Get("Accept"))
+
+	// Bodies are not logged unless enabled
+	assert.Empty(t, entry.RequestBody)
+	assert.Empty(t, entry.ResponseBody)
+}
+
+func TestSessionLoggerNeverLogsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "LtpaToken2", Value: "token-value"})
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	session := newTestServerSession(t, server.URL)
+	session.AddHeader("Cookie", "LtpaToken2=token-value")
+	session.SetLogBodyLimit(1024)
+
+	var output strings.Builder
+	session.SetLogger(LoggerFunc(func(entry *RequestLog) {
+		output.WriteString(entry.String())
+		output.WriteString("\n")
+	}))
+
+	resp := doSessionRequest(t, session, "PUT", "/restjobs/jobs", "//JOB JOB")
+	resp.Body.Close()
+
+	logged := output.String()
+	encoded := base64.StdEncoding.EncodeToString([]byte("user:s3cr3t-pass"))
+	assert.Contains(t, logged, "status=401")
+	assert.NotContains(t, logged, "s3cr3t-pass")
+	assert.NotContains(t, logged, encoded)
+	assert.NotContains(t, logged, "token-value")
+}
+
+func TestSessionLoggerBodyLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789ABCDEF"))
+	}))
+	defer server.Close()
+
+	t.Setenv(LogBodyEnvVar, "8")
+	session := newTestServerSession(t, server.URL)
+
+	var entries []*RequestLog
+	session.SetLogger(LoggerFunc(func(entry *RequestLog) {
+		entries = append(entries, entry)
+	}))
+
+	resp := doSessionRequest(t, session, "PUT", "/restfiles/ds/TEST.DATA", "HELLO WORLD")
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+
+	// The caller still sees the full response body
+	assert.Equal(t, "0123456789ABCDEF", string(body))
+
+	require.Len(t, entries, 1)
+	assert.Equal(t, "HELLO WO...(truncated)", entries[0].RequestBody)
+	assert.Equal(t, "01234567...(truncated)", entries[0].ResponseBody)
+
+	// Disabling the logger stops further records
+	session.SetLogger(nil)
+	resp = doSessionRequest(t, session, "GET", "/restfiles/ds", "")
+	resp.Body.Close()
+	assert.Len(t, entries, 1)
+}
+
+func TestGetProfile(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "zowe.config.json")
+
+	configJSON := `{
+  "profiles": {
+    "lpar1": {
+      "properties": {"host": "lpar1.example.com"},
+      "profiles": {
+        "zosmf": {"type": "zosmf", "properties": {"port": 443, "basePath": "/zosmf/api/v1"}},
+        "tso": {"type": "tso", "properties": {"account": "ACCT#", "logonProcedure": "IZUFPROC"}}
+      }
+    },
+    "ssh": {"type": "ssh", "properties": {"host": "ssh.example.com", "port": 22}},
+    "global_base": {"type": "base", "properties": {"host": "base.example.com", "user": "baseuser", "rejectUnauthorized": false}}
+  },
+  "defaults": {"zosmf": "lpar1.zosmf", "tso": "lpar1.tso", "base": "global_base"}
+}`
+	require.NoError(t, os.WriteFile(configPath, []byte(configJSON), 0644))
+	pm := NewProfileManagerWithPath(configPath)
+
+	// Nested profile by dotted path inherits from base and parent
+	props, err := pm.GetProfile("lpar1.tso", "tso")
+	require.NoError(t, err)
+	assert.Equal(t, "lpar1.example.com", props["host"])
+	assert.Equal(t, "baseuser", props["user"])
+	assert.Equal(t, "ACCT#", props["account"])
+
+	// Nested profile by key
+	props, err = pm.GetProfile("tso", "tso")
+	require.NoError(t, err)
+	assert.Equal(t, "IZUFPROC", props["logonProcedure"])
+
+	// Top-level profile of another type
+	props, err = pm.GetProfile("ssh", "ssh")
+	require.NoError(t, err)
+	assert.Equal(t, "ssh.example.com", props["host"])
+	assert.Equal(t, float64(22), props["port"])
+
+	// Empty name selects the default for the type
+	props, err = pm.GetProfile("", "zosmf")
+	require.NoError(t, err)
+	assert.Equal(t, "/zosmf/api/v1", props["basePath"])
+
+	// Type mismatch and missing profiles
+	_, err = pm.GetProfile("ssh", "zosmf")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has type 'ssh'")
+	_, err = pm.GetProfile("missing", "tso")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	_, err = pm.GetProfile("", "ftp")
+	assert.Error(t, err)
+
+	// Typed wrapper uses the same resolution
+	zosmfProfile, err := pm.GetZOSMFProfile("lpar1.zosmf")
+	require.NoError(t, err)
+	assert.Equal(t, "lpar1.example.com", zosmfProfile.Host)
+	assert.Equal(t, 443, zosmfProfile.Port)
+	assert.Equal(t, "baseuser", zosmfProfile.User)
+	assert.False(t, zosmfProfile.RejectUnauthorized)
+}
+
+func TestNamedZOSMFProfiles(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "zowe.config.json")
+
+	configJSON := `{
+  "profiles": {
+    "lpar1": {"type": "zosmf", "properties": {"host": "lpar1.example.com", "port": 443}},
+    "lpar2": {"type": "zosmf", "properties": {"host": "lpar2.example.com", "port": 10443, "rejectUnauthorized": false}},
+    "sysplex": {
+      "properties": {"port": 8443},
+      "profiles": {
+        "dev": {"type": "zosmf", "properties": {"host": "dev.example.com"}},
+        "tso": {"type": "tso", "properties": {"account": "ACCT"}}
+      }
+    },
+    "global_base": {"type": "base", "properties": {"user": "baseuser", "password": "basepass"}}
+  },
+  "defaults": {"zosmf": "lpar2"}
+}`
+	require.NoError(t, os.WriteFile(configPath, []byte(configJSON), 0644))
+	pm := NewProfileManagerWithPath(configPath)
+
+	names, err := pm.ListZOSMFProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lpar1", "lpar2", "sysplex.dev"}, names)
+
+	lpar1, err := pm.GetZOSMFProfile("lpar1")
+	require.NoError(t, err)
+	assert.Equal(t, "lpar1", lpar1.Name)
+	assert.Equal(t, "lpar1.example.com", lpar1.Host)
+	assert.Equal(t, "baseuser", lpar1.User)
+	assert.True(t, lpar1.RejectUnauthorized)
+
+	dev, err := pm.GetZOSMFProfile("sysplex.dev")
+	require.NoError(t, err)
+	assert.Equal(t, "dev.example.com", dev.Host)
+	assert.Equal(t, 8443, dev.Port)
+
+	defaultProfile, err := pm.GetDefaultZOSMFProfile()
+	require.NoError(t, err)
+	assert.Equal(t, "lpar2.example.com", defaultProfile.Host)
+	assert.False(t, defaultProfile.RejectUnauthorized)
+
+	byAlias, err := pm.GetZOSMFProfile("default")
+	require.NoError(t, err)
+	assert.Equal(t, "lpar2.example.com", byAlias.Host)
+
+	// A profile of another type is not a zosmf profile
+	_, err = pm.GetZOSMFProfile("sysplex.tso")
+	assert.Error(t, err)
+}
+
+// fakeCredentialProvider serves secure values from a map keyed by property path
+type fakeCredentialProvider struct {
+	values map[string]string
+	err    error
+}
+
+func (f *fakeCredentialProvider) GetSecureValue(configPath, propertyPath string) (string, bool, error) {
+	if f.err != nil {
+		return "", false, f.err
+	}
+	value, ok := f.values[propertyPath]
+	return value, ok, nil
+}
+
+func TestSecureCredentials(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "zowe.config.json")
+
+	configJSON := `{
+  "profiles": {
+    "lpar1": {
+      "properties": {"host": "lpar1.example.com"},
+      "profiles": {
+        "zosmf": {"type": "zosmf", "properties": {"port": 443, "password": "json-pass"}, "secure": ["password"]}
+      }
+    },
+    "global_base": {"type": "base", "properties": {}, "secure": ["user", "password"]}
+  },
+  "defaults": {"zosmf": "lpar1.zosmf"}
+}`
+	require.NoError(t, os.WriteFile(configPath, []byte(configJSON), 0644))
+	pm := NewProfileManagerWithPath(configPath)
+
+	// Values come from the secure store, nested profile overriding base
+	pm.SetCredentialProvider(&fakeCredentialProvider{values: map[string]string{
+		"profiles.global_base.properties.user":              "secureuser",
+		"profiles.global_base.properties.password":          "basepass",
+		"profiles.lpar1.profiles.zosmf.properties.password": "zosmfpass",
+	}})
+	profile, err := pm.GetZOSMFProfile("lpar1.zosmf")
+	require.NoError(t, err)
+	assert.Equal(t, "secureuser", profile.User)
+	assert.Equal(t, "zosmfpass", profile.Password)
+
+	// Missing secure values keep the JSON value
+	pm.SetCredentialProvider(&fakeCredentialProvider{values: map[string]string{}})
+	profile, err = pm.GetZOSMFProfile("lpar1.zosmf")
+	require.NoError(t, err)
+	assert.Equal(t, "json-pass", profile.Password)
+
+	// An unavailable store falls back to the JSON value
+	pm.SetCredentialProvider(&fakeCredentialProvider{err: ErrSecureStoreUnavailable})
+	profile, err = pm.GetZOSMFProfile("lpar1.zosmf")
+	require.NoError(t, err)
+	assert.Equal(t, "json-pass", profile.Password)
+}
+
+func TestSaveZOSMFProfileKeepsSecureValuesOutOfFile(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "zowe.config.json")
+
+	configJSON := `{
+  "profiles": {
+    "zosmf": {"type": "zosmf", "properties": {"host": "mainframe.example.com", "port": 443}, "secure": ["user", "password"]}
+  },
+  "defaults": {"zosmf": "zosmf"}
+}`
+	require.NoError(t, os.WriteFile(configPath, []byte(configJSON), 0644))
+	pm := NewProfileManagerWithPath(configPath)
+	pm.SetCredentialProvider(&fakeCredentialProvider{values: map[string]string{
+		"profiles.zosmf.properties.user":     "keyuser",
+		"profiles.zosmf.properties.password": "keyringpass",
+	}})
+
+	// Load, change and save: the secure values read from the store stay out of the file
+	profile, err := pm.GetZOSMFProfile("zosmf")
+	require.NoError(t, err)
+	require.Equal(t, "keyringpass", profile.Password)
+	profile.Port = 10443
+	require.NoError(t, pm.SaveZOSMFProfile(profile))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "keyringpass")
+	assert.NotContains(t, string(data), "keyuser")
+
+	// So do values taken from the environment
+	t.Setenv("ZOWE_OPT_PASSWORD", "envpass")
+	pm.SetEnvOverrides(true)
+	profile, err = pm.GetZOSMFProfile("zosmf")
+	require.NoError(t, err)
+	require.Equal(t, "envpass", profile.Password)
+	require.NoError(t, pm.SaveZOSMFProfile(profile))
+
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "envpass")
+
+	var config ZoweConfig
+	require.NoError(t, json.Unmarshal(data, &config))
+	saved := config.Profiles["zosmf"]
+	assert.Equal(t, []string{"user", "password"}, saved.Secure)
+	assert.Equal(t, float64(10443), saved.Properties["port"])
+	assert.NotContains(t, saved.Properties, "password")
+	assert.NotContains(t, saved.Properties, "user")
+
+	// The round trip still reads the secure values from the store
+	pm.SetEnvOverrides(false)
+	profile, err = pm.GetZOSMFProfile("zosmf")
+	require.NoError(t, err)
+	assert.Equal(t, "keyuser", profile.User)
+	assert.Equal(t, "keyringpass", profile.Password)
+	assert.Equal(t, 10443, profile.Port)
+}
+
+func TestDecodeZoweSecureProps(t *testing.T) {
+	blob := base64.StdEncoding.EncodeToString([]byte(`{"/home/u/.zowe/zowe.config.json":{"profiles.base.properties.user":"u1","profiles.base.properties.port":443}}`))
+	values, err := decodeZoweSecureProps(blob)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", values["/home/u/.zowe/zowe.config.json"]["profiles.base.properties.user"])
+
+	values, err = decodeZoweSecureProps("")
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	_, err = decodeZoweSecureProps("not base64!")
+	assert.Error(t, err)
+}
+
+func TestLoadProfileFromEnv(t *testing.T) {
+	t.Setenv("CI_ZOSMF_HOST", "ci.example.com")
+	t.Setenv("CI_ZOSMF_PORT", "10443")
+	t.Setenv("CI_ZOSMF_USER", "ciuser")
+	t.Setenv("CI_ZOSMF_PASSWORD", "cipass")
+	t.Setenv("CI_ZOSMF_REJECT_UNAUTHORIZED", "false")
+
+	profile, err := LoadProfileFromEnv("CI_ZOSMF")
+	require.NoError(t, err)
+	assert.Equal(t, "ci_zosmf", profile.Name)
+	assert.Equal(t, "ci.example.com", profile.Host)
+	assert.Equal(t, 10443, profile.Port)
+	assert.Equal(t, "ciuser", profile.User)
+	assert.Equal(t, "cipass", profile.Password)
+	assert.Equal(t, "https", profile.Protocol)
+	assert.False(t, profile.RejectUnauthorized)
+
+	// Parse errors name the variable
+	t.Setenv("CI_ZOSMF_PORT", "ten")
+	_, err = LoadProfileFromEnv("CI_ZOSMF")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "CI_ZOSMF_PORT")
+
+	t.Setenv("CI_ZOSMF_PORT", "443")
+	t.Setenv("CI_ZOSMF_REJECT_UNAUTHORIZED", "maybe")
+	_, err = LoadProfileFromEnv("CI_ZOSMF")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "CI_ZOSMF_REJECT_UNAUTHORIZED")
+
+	// Missing required values fail validation
+	_, err = LoadProfileFromEnv("UNSET_PREFIX")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "host is required")
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	profile := CreateZOSMFProfileWithOptions("cfg", "config.example.com", 443, "cfguser", "cfgpass", true, "/api/v1")
+
+	t.Setenv("OVR_USER", "envuser")
+	t.Setenv("OVR_BASE_PATH", "/zosmf")
+
+	require.NoError(t, ApplyEnvOverrides(profile, "OVR"))
+	assert.Equal(t, "config.example.com", profile.Host)
+	assert.Equal(t, "envuser", profile.User)
+	assert.Equal(t, "cfgpass", profile.Password)
+	assert.Equal(t, "/zosmf", profile.BasePath)
+}
+
+func TestFromEnvironment(t *testing.T) {
+	t.Setenv("ZOWE_OPT_HOST", "env.example.com")
+	t.Setenv("ZOWE_OPT_USER", "envuser")
+	t.Setenv("ZOWE_OPT_PASSWORD", "envpass")
+	t.Setenv("ZOWE_OPT_RESPONSE_TIMEOUT", "60")
+
+	profile, err := FromEnvironment()
+	require.NoError(t, err)
+	assert.Equal(t, "env.example.com", profile.Host)
+	assert.Equal(t, 443, profile.Port)
+	assert.Equal(t, 60, profile.ResponseTimeout)
+	assert.True(t, profile.RejectUnauthorized)
+
+	t.Setenv("ZOWE_OPT_RESPONSE_TIMEOUT", "1m")
+	_, err = FromEnvironment()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ZOWE_OPT_RESPONSE_TIMEOUT")
+}
+
+func TestGetZOSMFProfileEnvOverrides(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "zowe.config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"profiles": {
+			"lpar1": {"type": "zosmf", "properties": {"host": "config.example.com", "port": 1443, "user": "cfguser"}}
+		},
+		"defaults": {"zosmf": "lpar1"}
+	}`), 0644))
+
+	pm := NewProfileManagerWithPath(configPath)
+	pm.SetCredentialProvider(nil)
+
+	t.Setenv("ZOWE_OPT_USER", "envuser")
+	t.Setenv("ZOWE_OPT_REJECT_UNAUTHORIZED", "false")
+
+	// Overrides are off by default
+	profile, err := pm.GetZOSMFProfile("lpar1")
+	require.NoError(t, err)
+	assert.Equal(t, "cfguser", profile.User)
+
+	// Environment beats the config file, which beats the defaults
+	pm.SetEnvOverrides(true)
+	profile, err = pm.GetZOSMFProfile("lpar1")
+	require.NoError(t, err)
+	assert.Equal(t, "envuser", profile.User)
+	assert.Equal(t, "config.example.com", profile.Host)
+	assert.Equal(t, 1443, profile.Port)
+	assert.Equal(t, "https", profile.Protocol)
+	assert.False(t, profile.RejectUnauthorized)
+
+	t.Setenv("ZOWE_OPT_PORT", "https")
+	_, err = pm.GetZOSMFProfile("default")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ZOWE_OPT_PORT")
+}
+
+func TestSessionBasePathNormalization(t *testing.T) {
+	tests := []struct {
+		name     string
+		basePath string
+		expected string
+		wantErr  bool
+	}{
+		{"default", "", "https://localhost/zosmf", false},
+		{"no leading slash", "api/v1", "https://localhost/api/v1", false},
+		{"trailing slash", "/api/v1/", "https://localhost/api/v1", false},
+		{"duplicate slashes", "//zosmf//api///v1//", "https://localhost/zosmf/api/v1", false},
+		{"root", "/", "https://localhost", false},
+		{"scheme and host", "https://mainframe.example.com/zosmf", "", true},
+		{"host and port", "mainframe.example.com:443/zosmf", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &ZOSMFProfile{Host: "localhost", Port: 443, BasePath: tt.basePath}
+			session, err := profile.NewSession()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, session.BaseURL)
+		})
+	}
+}
+
+func TestProgressReader(t *testing.T) {
+	payload := strings.Repeat("x", 3*ProgressByteInterval+100)
+
+	var calls [][2]int64
+	r := NewProgressReader(strings.NewReader(payload), int64(len(payload)), func(transferred, total int64) {
+		calls = append(calls, [2]int64{transferred, total})
+	})
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(data))
+
+	require.NotEmpty(t, calls)
+	for i := 1; i < len(calls); i++ {
+		assert.Greater(t, calls[i][0], calls[i-1][0])
+	}
+	assert.Equal(t, [2]int64{int64(len(payload)), int64(len(payload))}, calls[len(calls)-1])
+	assert.LessOrEqual(t, len(calls), 5)
+
+	// Unknown size reports -1 and still ends with the final count
+	calls = nil
+	r = NewProgressReader(strings.NewReader("abc"), -1, func(transferred, total int64) {
+		calls = append(calls, [2]int64{transferred, total})
+	})
+	_, err = io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, [][2]int64{{3, -1}}, calls)
+
+	// A nil callback leaves the reader unwrapped
+	src := strings.NewReader("abc")
+	assert.Same(t, src, NewProgressReader(src, 3, nil))
+}
+
+func TestSessionLoggerOption(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`created`))
+	}))
+	defer server.Close()
+
+	// Logging is off by default
+	session := newTestServerSession(t, server.URL)
+	logger, limit := session.loggingConfig()
+	assert.Nil(t, logger)
+	assert.Equal(t, 0, limit)
+
+	var entries []*RequestLog
+	profile := &ZOSMFProfile{
+		Host:     strings.TrimPrefix(server.URL, "http://"),
+		User:     "user",
+		Password: "s3cr3t-pass",
+		BasePath: "/api/v1",
+		Protocol: "http",
+	}
+	session, err := profile.NewSession(
+		WithLogger(LoggerFunc(func(entry *RequestLog) {
+			entries = append(entries, entry)
+		})),
+		WithLogBodyLimit(4),
+	)
+	require.NoError(t, err)
+
+	resp := doSessionRequest(t, session, "PUT", "/restfiles/ds/USER.DATA", "hello world")
+	resp.Body.Close()
+
+	require.Len(t, entries, 1)
+	assert.Equal(t, "PUT", entries[0].Method)
+	assert.Equal(t, http.StatusCreated, entries[0].StatusCode)
+	assert.Equal(t, "<redacted>", entries[0].Headers.Get("Authorization"))
+	assert.Equal(t, "hell...(truncated)", entries[0].RequestBody)
+	assert.Equal(t, "crea...(truncated)", entries[0].ResponseBody)
+}
+
+func TestSessionUpdateCredentials(t *testing.T) {
+	var authHeaders []string
+	var cookies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		cookies = append(cookies, r.Header.Get("Cookie"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	session := newTestServerSession(t, server.URL)
+
+	session.UpdateCredentials("newuser", "n3w-pass")
+	assert.Equal(t, "newuser", session.GetUser())
+	resp := doSessionRequest(t, session, "GET", "/restjobs/jobs", "")
+	resp.Body.Close()
+	expected := "Basic " + base64.StdEncoding.EncodeToString([]byte("newuser:n3w-pass"))
+	assert.Equal(t, expected, authHeaders[0])
+
+	session.UpdateToken("LtpaToken2", "abc123")
+	resp = doSessionRequest(t, session, "GET", "/restjobs/jobs", "")
+	resp.Body.Close()
+	assert.Empty(t, authHeaders[1])
+	assert.Equal(t, "LtpaToken2=abc123", cookies[1])
+
+	session.UpdateToken("bearer", "jwt.value")
+	resp = doSessionRequest(t, session, "GET", "/restjobs/jobs", "")
+	resp.Body.Close()
+	assert.Equal(t, "Bearer jwt.value", authHeaders[2])
+	assert.Empty(t, cookies[2])
+
+	// A cookie jar is replaced so cached session cookies are dropped
+	session.HTTPClient.Jar = &recordingJar{}
+	session.UpdateCredentials("newuser", "n3w-pass")
+	_, isOld := session.HTTPClient.Jar.(*recordingJar)
+	assert.False(t, isOld)
+}
+
+func TestSessionClone(t *testing.T) {
+	session := newTestServerSession(t, "http://localhost:1234")
+	session.SetLogger(LoggerFunc(func(entry *RequestLog) {}))
+
+	clone := session.Clone()
+	clone.AddHeader("X-Clone", "yes")
+	clone.UpdateCredentials("other", "pw")
+
+	assert.Equal(t, session.GetBaseURL(), clone.GetBaseURL())
+	assert.NotContains(t, session.GetHeaders(), "X-Clone")
+	assert.Equal(t, "user", session.GetUser())
+	assert.Equal(t, "other", clone.GetUser())
+	assert.NotSame(t, session.GetHTTPClient(), clone.GetHTTPClient())
+
+	// The clone's transport reports to the clone's logger settings
+	transport := clone.GetHTTPClient().Transport.(*sessionTransport)
+	assert.Same(t, clone, transport.session)
+}
+
+func TestSessionCredentialRotationConcurrent(t *testing.T) {
+	valid := map[string]bool{
+		"Basic " + base64.StdEncoding.EncodeToString([]byte("user:s3cr3t-pass")): true,
+		"Basic " + base64.StdEncoding.EncodeToString([]byte("user:rotated-1")):   true,
+		"Basic " + base64.StdEncoding.EncodeToString([]byte("user:rotated-2")):   true,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !valid[r.Header.Get("Authorization")] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	session := newTestServerSession(t, server.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				req, err := http.NewRequest("GET", session.GetBaseURL()+"/restjobs/jobs", nil)
+				if !assert.NoError(t, err) {
+					return
+				}
+				for key, value := range session.GetHeaders() {
+					req.Header.Set(key, value)
+				}
+				resp, err := session.GetHTTPClient().Do(req)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				resp.Body.Close()
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		session.UpdateCredentials("user", fmt.Sprintf("rotated-%d", i%2+1))
+		session.GetUser()
+	}
+	wg.Wait()
+}
+
+// recordingJar is a minimal cookie jar used to check that jars are replaced
+type recordingJar struct{}
+
+func (j *recordingJar) SetCookies(u *url.URL, cookies []*http.Cookie) {}
+func (j *recordingJar) Cookies(u *url.URL) []*http.Cookie              { return nil }
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestSessionCustomTransport(t *testing.T) {
+	var recorded []*http.Request
+	recorder := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		recorded = append(recorded, req)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})
+
+	var entries []*RequestLog
+	profile := &ZOSMFProfile{Host: "mainframe.example.com", Port: 443, User: "user", Password: "pass"}
+	session, err := profile.NewSession(
+		WithTransport(recorder),
+		WithLogger(LoggerFunc(func(entry *RequestLog) { entries = append(entries, entry) })),
+	)
+	require.NoError(t, err)
+
+	resp := doSessionRequest(t, session, "GET", "/restjobs/jobs", "")
+	resp.Body.Close()
+
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "https://mainframe.example.com/zosmf/restjobs/jobs", recorded[0].URL.String())
+	assert.NotEmpty(t, recorded[0].Header.Get("Authorization"))
+	assert.Len(t, entries, 1, "session logging still applies to a custom transport")
+}
+
+func TestSessionCustomHTTPClient(t *testing.T) {
+	var recorded int
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			recorded++
+			assert.Equal(t, "application/json", req.Header.Get("Accept"))
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header), Request: req}, nil
+		}),
+	}
+
+	profile := &ZOSMFProfile{Host: "mainframe.example.com", Port: 443, User: "user", Password: "pass"}
+	session, err := profile.NewSession(WithHTTPClient(client))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, session.GetHTTPClient().Timeout)
+	assert.NotSame(t, client, session.GetHTTPClient())
+
+	resp := doSessionRequest(t, session, "GET", "/restjobs/jobs", "")
+	resp.Body.Close()
+	assert.Equal(t, 1, recorded)
+
+	// A client without a transport falls back to the default transport
+	session, err = profile.NewSession(WithHTTPClient(&http.Client{}))
+	require.NoError(t, err)
+	transport := session.GetHTTPClient().Transport.(*sessionTransport)
+	assert.Equal(t, http.DefaultTransport, transport.base)
+}
+
+func TestSessionHeadersConcurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	session := newTestServerSession(t, server.URL)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			session.AddHeader("X-Correlation-ID", fmt.Sprintf("req-%d", i))
+			if i%10 == 0 {
+				session.RemoveHeader("X-Correlation-ID")
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		// Simulated manager request loop: build requests from the header copy
+		for i := 0; i < 50; i++ {
+			headers := session.GetHeaders()
+			headers["X-Local-Only"] = "mutating the copy is safe"
+			resp := doSessionRequest(t, session, "GET", "/restfiles/ds?dslevel=USER.*", "")
+			resp.Body.Close()
+		}
+	}()
+	wg.Wait()
+
+	assert.NotContains(t, session.GetHeaders(), "X-Local-Only")
+}
+
+func TestSessionProxy(t *testing.T) {
+	target, err := http.NewRequest("GET", "https://mainframe.example.com/zosmf/restjobs/jobs", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		proxyURL string
+		expected string
+		wantErr  bool
+	}{
+		{"http proxy", "http://proxy.example.com:8080", "http://proxy.example.com:8080", false},
+		{"socks proxy", "socks5://proxy.example.com:1080", "socks5://proxy.example.com:1080", false},
+		{"unsupported scheme", "ftp://proxy.example.com", "", true},
+		{"missing host", "http://", "", true},
+		{"not a URL", "proxy.example.com:8080", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &ZOSMFProfile{Host: "mainframe.example.com", Port: 443, User: "user", Password: "pass", ProxyURL: tt.proxyURL}
+			session, err := profile.NewSession()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Error(t, ValidateProfile(profile))
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, ValidateProfile(profile))
+
+			transport := session.GetHTTPClient().Transport.(*sessionTransport).base.(*http.Transport)
+			proxy, err := transport.Proxy(target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, proxy.String())
+		})
+	}
+}
+
+func TestSessionProxyFromEnvironment(t *testing.T) {
+	// Without a ProxyURL the transport defers to HTTPS_PROXY/HTTP_PROXY/NO_PROXY
+	profile := &ZOSMFProfile{Host: "mainframe.example.com", Port: 443}
+	session, err := profile.NewSession()
+	require.NoError(t, err)
+
+	transport := session.GetHTTPClient().Transport.(*sessionTransport).base.(*http.Transport)
+	assert.NotNil(t, transport.Proxy)
+}
+
+func TestSessionCACertFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, pemData, 0600))
+
+	newProfile := func(caCertFile string, rejectUnauthorized bool) *ZOSMFProfile {
+		return &ZOSMFProfile{
+			Host:               strings.TrimPrefix(server.URL, "https://"),
+			User:               "user",
+			Password:           "pass",
+			Protocol:           "https",
+			BasePath:           "/api/v1",
+			RejectUnauthorized: rejectUnauthorized,
+			CACertFile:         caCertFile,
+		}
+	}
+
+	// Verification succeeds against the custom root
+	session, err := newProfile(caFile, true).NewSession()
+	require.NoError(t, err)
+	resp := doSessionRequest(t, session, "GET", "/restjobs/jobs", "")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// A CA file keeps verification on even when RejectUnauthorized is false
+	session, err = newProfile(caFile, false).NewSession()
+	require.NoError(t, err)
+	transport := session.GetHTTPClient().Transport.(*sessionTransport).base.(*http.Transport)
+	assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.NotNil(t, transport.TLSClientConfig.RootCAs)
+
+	// Without the CA the self-signed server is rejected
+	session, err = newProfile("", true).NewSession()
+	require.NoError(t, err)
+	req, err := http.NewRequest("GET", session.GetBaseURL()+"/restjobs/jobs", nil)
+	require.NoError(t, err)
+	_, err = session.GetHTTPClient().Do(req)
+	assert.Error(t, err)
+
+	// Unreadable or empty bundles are reported when the session is created
+	_, err = newProfile(filepath.Join(dir, "missing.pem"), true).NewSession()
+	assert.Error(t, err)
+	emptyFile := filepath.Join(dir, "empty.pem")
+	require.NoError(t, os.WriteFile(emptyFile, []byte("not a certificate"), 0600))
+	_, err = newProfile(emptyFile, true).NewSession()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no PEM certificates")
+}
+
+// newTestCA returns a PEM encoded CA certificate and a server certificate it
+// signed for serverName
+func newTestCA(t *testing.T, serverName string) ([]byte, tls.Certificate) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Internal Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serverTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: serverName},
+		DNSNames:     []string{serverName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	serverDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caCert, &serverKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	return caPEM, tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey}
+}
+
+func TestSessionPrivateCA(t *testing.T) {
+	const serverName = "zosmf.internal.example"
+	caPEM, serverCert := newTestCA(t, serverName)
+
+	var sni string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sni = r.TLS.ServerName
+		w.Write([]byte(`{"zosmf_version": "27"}`))
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{serverCert}}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0600))
+	caDir := filepath.Join(dir, "certs")
+	require.NoError(t, os.Mkdir(caDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(caDir, "internal-ca.crt"), caPEM, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(caDir, "README"), []byte("CA certificates for the sysplex"), 0600))
+
+	newProfile := func(caCertFile, caCertPath, serverName string) *ZOSMFProfile {
+		return &ZOSMFProfile{
+			Host:               strings.TrimPrefix(server.URL, "https://"),
+			User:               "user",
+			Password:           "pass",
+			Protocol:           "https",
+			BasePath:           "/zosmf",
+			RejectUnauthorized: true,
+			CACertFile:         caCertFile,
+			CACertPath:         caCertPath,
+			ServerName:         serverName,
+		}
+	}
+
+	// The server is reached by IP address, so its name is given for verification
+	for name, p := range map[string]*ZOSMFProfile{
+		"CA file":      newProfile(caFile, "", serverName),
+		"CA directory": newProfile("", caDir, serverName),
+	} {
+		t.Run(name, func(t *testing.T) {
+			session, err := p.NewSession()
+			require.NoError(t, err)
+			transport := session.GetHTTPClient().Transport.(*sessionTransport).base.(*http.Transport)
+			assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+
+			require.NoError(t, session.TestConnection(context.Background()))
+			assert.Equal(t, serverName, sni)
+		})
+	}
+
+	// Without the server name the certificate does not match the address
+	session, err := newProfile(caFile, "", "").NewSession()
+	require.NoError(t, err)
+	assert.ErrorIs(t, session.TestConnection(context.Background()), ErrTLSFailure)
+
+	// Without the CA the certificate is not trusted
+	session, err = newProfile("", "", serverName).NewSession()
+	require.NoError(t, err)
+	assert.ErrorIs(t, session.TestConnection(context.Background()), ErrTLSFailure)
+
+	// Loading errors name the file or directory
+	missing := filepath.Join(dir, "missing.pem")
+	_, err = newProfile(missing, "", "").NewSession()
+	assert.ErrorContains(t, err, missing)
+	emptyDir := filepath.Join(dir, "empty")
+	require.NoError(t, os.Mkdir(emptyDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(emptyDir, "README"), []byte("nothing here"), 0600))
+	_, err = newProfile("", emptyDir, "").NewSession()
+	assert.ErrorContains(t, err, "no PEM certificates found in CA certificate directory "+emptyDir)
+	_, err = newProfile("", filepath.Join(dir, "nodir"), "").NewSession()
+	assert.ErrorContains(t, err, filepath.Join(dir, "nodir"))
+
+	// Both settings are read from and written to the config file
+	configPath := filepath.Join(dir, "zowe.config.json")
+	pm := NewProfileManagerWithPath(configPath)
+	saved := newProfile("", caDir, serverName)
+	saved.Name = "internal"
+	require.NoError(t, pm.SaveZOSMFProfile(saved))
+	loaded, err := NewProfileManagerWithPath(configPath).GetZOSMFProfile("internal")
+	require.NoError(t, err)
+	assert.Equal(t, caDir, loaded.CACertPath)
+	assert.Equal(t, serverName, loaded.ServerName)
+}
+
+func TestSessionRequestsPerSecond(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	profile := &ZOSMFProfile{
+		Host:     strings.TrimPrefix(server.URL, "http://"),
+		BasePath: "/api/v1",
+		Protocol: "http",
+	}
+	session, err := profile.NewSession(WithRequestsPerSecond(20))
+	require.NoError(t, err)
+
+	// The first 20 requests use the burst, the next 5 wait 50ms each
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		resp := doSessionRequest(t, session, "GET", "/restjobs/jobs", "")
+		resp.Body.Close()
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+	// A request whose context ends while waiting fails instead of blocking
+	session.SetRequestsPerSecond(0.5)
+	resp := doSessionRequest(t, session, "GET", "/restjobs/jobs", "")
+	resp.Body.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", session.GetBaseURL()+"/restjobs/jobs", nil)
+	require.NoError(t, err)
+	_, err = session.GetHTTPClient().Do(req)
+	assert.Error(t, err)
+
+	// Without a limit requests are not delayed
+	session.SetRequestsPerSecond(0)
+	start = time.Now()
+	for i := 0; i < 25; i++ {
+		resp := doSessionRequest(t, session, "GET", "/restjobs/jobs", "")
+		resp.Body.Close()
+	}
+	assert.Less(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestBuildBaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		host     string
+		port     int
+		basePath string
+		expected string
+		wantErr  bool
+	}{
+		{name: "defaults", host: "mf.example.com", expected: "https://mf.example.com/zosmf"},
+		{name: "https default port", host: "mf.example.com", port: 443, expected: "https://mf.example.com/zosmf"},
+		{name: "https custom port", host: "mf.example.com", port: 10443, expected: "https://mf.example.com:10443/zosmf"},
+		{name: "port 80 guesses http", host: "mf.example.com", port: 80, expected: "http://mf.example.com/zosmf"},
+		{name: "port 8080 guesses http", host: "mf.example.com", port: 8080, expected: "http://mf.example.com:8080/zosmf"},
+		{name: "explicit https on 8080", protocol: "https", host: "mf.example.com", port: 8080, expected: "https://mf.example.com:8080/zosmf"},
+		{name: "explicit https on 80", protocol: "https", host: "mf.example.com", port: 80, expected: "https://mf.example.com:80/zosmf"},
+		{name: "explicit http on 443", protocol: "http", host: "mf.example.com", port: 443, expected: "http://mf.example.com:443/zosmf"},
+		{name: "explicit http default port", protocol: "http", host: "mf.example.com", port: 80, expected: "http://mf.example.com/zosmf"},
+		{name: "protocol case", protocol: "HTTPS", host: "mf.example.com", port: 443, expected: "https://mf.example.com/zosmf"},
+		{name: "base path without leading slash", host: "mf.example.com", port: 443, basePath: "api/v1", expected: "https://mf.example.com/api/v1"},
+		{name: "base path with trailing slash", host: "mf.example.com", port: 443, basePath: "/api/v1/", expected: "https://mf.example.com/api/v1"},
+		{name: "base path both", host: "mf.example.com", port: 8443, basePath: "zosmf/", expected: "https://mf.example.com:8443/zosmf"},
+		{name: "root base path", host: "mf.example.com", port: 443, basePath: "/", expected: "https://mf.example.com"},
+		{name: "host trailing slash", host: "mf.example.com/", port: 443, expected: "https://mf.example.com/zosmf"},
+		{name: "host with port", protocol: "http", host: "127.0.0.1:9000", expected: "http://127.0.0.1:9000/zosmf"},
+		{name: "ipv6 host", host: "::1", port: 8443, expected: "https://[::1]:8443/zosmf"},
+		{name: "ipv6 host default port", host: "2001:db8::10", port: 443, expected: "https://[2001:db8::10]/zosmf"},
+		{name: "ipv6 host without port", host: "fe80::1", expected: "https://[fe80::1]/zosmf"},
+		{name: "bracketed ipv6 host", host: "[::1]", expected: "https://[::1]/zosmf"},
+		{name: "bracketed ipv6 host with port", host: "[::1]:9443", expected: "https://[::1]:9443/zosmf"},
+		{name: "unknown protocol", protocol: "ftp", host: "mf.example.com", wantErr: true},
+		{name: "missing host", port: 443, wantErr: true},
+		{name: "host with scheme", host: "https://mf.example.com", wantErr: true},
+		{name: "base path with host", host: "mf.example.com", basePath: "https://other/zosmf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &ZOSMFProfile{Protocol: tt.protocol, Host: tt.host, Port: tt.port, BasePath: tt.basePath}
+			baseURL, err := BuildBaseURL(profile)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, baseURL)
+			assert.False(t, strings.HasSuffix(baseURL, "/"))
+
+			// Sessions use the same URL
+			session, err := profile.NewSession()
+			require.NoError(t, err)
+			assert.Equal(t, baseURL, session.BaseURL)
+		})
+	}
+
+	_, err := BuildBaseURL(nil)
+	assert.Error(t, err)
+}
+
+func TestProfileManagerConfigCache(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, "zowe.config.json")
+	// An mtime outside the racy window lets the manager trust its cached copy
+	modTime := time.Now().Add(-time.Hour)
+	writeConfig := func(host string) {
+		content := fmt.Sprintf(`{"profiles": {"zosmf": {"type": "zosmf", "properties": {"host": %q}}}, "defaults": {"zosmf": "zosmf"}}`, host)
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+		require.NoError(t, os.Chtimes(configPath, modTime, modTime))
+	}
+	getHost := func(pm *ZOSMFProfileManager, name string) string {
+		profile, err := pm.GetZOSMFProfile(name)
+		require.NoError(t, err)
+		return profile.Host
+	}
+
+	writeConfig("host1.com")
+	pm := NewProfileManagerWithPath(configPath)
+	assert.Equal(t, "host1.com", getHost(pm, "zosmf"))
+
+	// Same size and mtime, so the cached config is used
+	writeConfig("host2.com")
+	assert.Equal(t, "host1.com", getHost(pm, "zosmf"))
+
+	require.NoError(t, pm.Reload())
+	assert.Equal(t, "host2.com", getHost(pm, "zosmf"))
+
+	// A change in size is picked up without Reload
+	writeConfig("longer-host3.com")
+	assert.Equal(t, "longer-host3.com", getHost(pm, "default"))
+
+	// Saving drops the cached copy, so the saved file is read back, and leaves no
+	// temporary file behind
+	require.NoError(t, pm.SaveZOSMFProfile(&ZOSMFProfile{Name: "lpar2", Host: "lpar2.com", Protocol: "https"}))
+	assert.Nil(t, pm.config)
+	names, err := pm.ListZOSMFProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lpar2", "zosmf"}, names)
+	assert.Equal(t, "lpar2.com", getHost(pm, "lpar2"))
+	assert.Equal(t, "longer-host3.com", getHost(pm, "zosmf"))
+	entries, err := os.ReadDir(configDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// A removed file is reported even though the config was cached
+	require.NoError(t, os.Remove(configPath))
+	_, err = pm.GetZOSMFProfile("zosmf")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	assert.Error(t, pm.Reload())
+}
+
+func TestProfileManagerConcurrentAccess(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "zowe.config.json")
+	pm := NewProfileManagerWithPath(configPath)
+	require.NoError(t, pm.SaveZOSMFProfile(&ZOSMFProfile{Name: "zosmf", Host: "host.com", Protocol: "https"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				profile, err := pm.GetZOSMFProfile("zosmf")
+				if assert.NoError(t, err) {
+					assert.Equal(t, "host.com", profile.Host)
+				}
+				_, err = pm.ListZOSMFProfiles()
+				assert.NoError(t, err)
+				_, err = pm.GetDefaultZOSMFProfile()
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				profile := &ZOSMFProfile{Name: fmt.Sprintf("lpar%d", i), Host: fmt.Sprintf("lpar%d-%d.com", i, j), Protocol: "https"}
+				assert.NoError(t, pm.SaveZOSMFProfile(profile))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// No update was lost to a concurrent read-modify-write
+	require.NoError(t, pm.Reload())
+	names, err := pm.ListZOSMFProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lpar0", "lpar1", "lpar2", "lpar3", "zosmf"}, names)
+	for i := 0; i < 4; i++ {
+		profile, err := pm.GetZOSMFProfile(fmt.Sprintf("lpar%d", i))
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("lpar%d-9.com", i), profile.Host)
+	}
+}
+
+func TestNormalizeEncoding(t *testing.T) {
+	assert.Equal(t, "IBM-1047", NormalizeEncoding("1047"))
+	assert.Equal(t, "IBM-037", NormalizeEncoding(" IBM-037 "))
+	assert.Equal(t, "UTF-8", NormalizeEncoding("UTF-8"))
+	assert.Equal(t, "", NormalizeEncoding(""))
+
+	session, err := (&ZOSMFProfile{Host: "zosmf.example.com", Port: 443, Encoding: "1140"}).NewSession()
+	require.NoError(t, err)
+	assert.Equal(t, "1140", session.GetEncoding())
+	assert.Equal(t, "IBM-1140", SessionEncoding(session))
+	assert.Equal(t, "", SessionEncoding(struct{ HTTPSession }{session}), "sessions without GetEncoding carry none")
+}
+
+func TestSessionTestConnection(t *testing.T) {
+	const info = `{"zos_version": "04.27.00", "zosmf_port": "443", "zosmf_version": "27", "zosmf_hostname": "sys1.example.com"}`
+
+	t.Run("success", func(t *testing.T) {
+		var paths []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.Method+" "+r.URL.Path)
+			if r.URL.Path == "/api/v1/info" {
+				w.Write([]byte(info))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		require.NoError(t, newTestServerSession(t, server.URL).TestConnection(context.Background()))
+		assert.Equal(t, []string{"GET /api/v1/info", "POST /api/v1/services/authenticate"}, paths)
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/v1/info" {
+				w.Write([]byte(info))
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		err := newTestServerSession(t, server.URL).TestConnection(context.Background())
+		require.ErrorIs(t, err, ErrAuthFailed)
+		var connErr *ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Equal(t, ConnectionErrorAuth, connErr.Kind)
+		assert.Equal(t, http.StatusUnauthorized, connErr.StatusCode)
+		assert.Equal(t, server.URL+"/api/v1/services/authenticate", connErr.URL)
+	})
+
+	t.Run("info requires authentication", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		err := newTestServerSession(t, server.URL).TestConnection(context.Background())
+		assert.ErrorIs(t, err, ErrAuthFailed)
+	})
+
+	t.Run("no authenticate service", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/v1/info" {
+				w.Write([]byte(info))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		assert.NoError(t, newTestServerSession(t, server.URL).TestConnection(context.Background()))
+	})
+
+	t.Run("not z/OSMF", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html>It works!</html>"))
+		}))
+		defer server.Close()
+
+		err := newTestServerSession(t, server.URL).TestConnection(context.Background())
+		var connErr *ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Equal(t, ConnectionErrorUnexpected, connErr.Kind)
+		assert.Contains(t, err.Error(), "not z/OSMF information")
+		assert.NotErrorIs(t, err, ErrAuthFailed)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		session := newTestServerSession(t, server.URL)
+		server.Close()
+
+		err := session.TestConnection(context.Background())
+		require.ErrorIs(t, err, ErrHostUnreachable)
+		assert.Contains(t, err.Error(), "host unreachable")
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(info))
+		}))
+		defer server.Close()
+
+		session, err := (&ZOSMFProfile{
+			Host:               strings.TrimPrefix(server.URL, "https://"),
+			User:               "user",
+			Password:           "pass",
+			Protocol:           "https",
+			BasePath:           "/api/v1",
+			RejectUnauthorized: true,
+		}).NewSession()
+		require.NoError(t, err)
+
+		err = session.TestConnection(context.Background())
+		require.ErrorIs(t, err, ErrTLSFailure)
+		assert.NotErrorIs(t, err, ErrHostUnreachable)
+	})
+}
+
+func TestProfileManagerTestProfile(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{"zosmf_version": "27"}`))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	require.NoError(t, err)
+
+	configPath := filepath.Join(t.TempDir(), "zowe.config.json")
+	configData, err := json.Marshal(ZoweConfig{
+		Profiles: map[string]ZoweProfile{
+			"dev": {
+				Type: "zosmf",
+				Properties: map[string]interface{}{
+					"host":     serverURL.Hostname(),
+					"port":     port,
+					"protocol": "http",
+					"basePath": "/zosmf",
+					"user":     "IBMUSER",
+					"password": "secret",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configPath, configData, 0600))
+
+	pm := NewProfileManagerWithPath(configPath)
+	require.NoError(t, pm.TestProfile("dev"))
+	assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("IBMUSER:secret")), authorization)
+
+	assert.Error(t, pm.TestProfile("missing"))
+}
+
+func TestSessionGetZosmfInfo(t *testing.T) {
+	requests := 0
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/api/v1/info", r.URL.Path)
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte(`{"zos_version": "04.27.00", "zosmf_port": "443", "zosmf_version": "27", "zosmf_hostname": "sys1.example.com",
+			  "zosmf_full_version": "27.0", "api_version": "1",
+			  "plugins": [{"pluginVersion": "HSMA240;PH12345", "pluginStatus": "ACTIVE", "pluginDefaultName": "z/OS Operator Consoles"}]}`))
+		} else {
+			w.Write([]byte(`{"rc": 8, "reason": 1, "message": "service unavailable"}`))
+		}
+	}))
+	defer server.Close()
+
+	// Errors are returned as API errors and not cached
+	status = http.StatusServiceUnavailable
+	session := newTestServerSession(t, server.URL)
+	_, err := session.GetZosmfInfo()
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+
+	status = http.StatusOK
+	info, err := session.GetZosmfInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "27", info.ZosmfVersion)
+	assert.Equal(t, "04.27.00", info.ZosVersion)
+	assert.Equal(t, "sys1.example.com", info.Hostname)
+	require.Len(t, info.Plugins, 1)
+	assert.Equal(t, "HSMA240;PH12345", info.Plugin("z/os operator consoles").Version)
+	assert.Nil(t, info.Plugin("Workflow"))
+
+	assert.True(t, info.AtLeast(ZosmfVersionV2R3))
+	assert.True(t, info.AtLeast(ZosmfVersionV2R4))
+	assert.False(t, info.AtLeast(ZosmfVersionV2R5))
+	assert.False(t, (&ZosmfInfo{ZosmfVersion: "unknown"}).AtLeast(ZosmfVersionV2R2))
+
+	// Later calls and clones reuse the first result
+	again, err := session.Clone().GetZosmfInfo()
+	require.NoError(t, err)
+	assert.Same(t, info, again)
+	assert.Equal(t, 2, requests)
+}
+
+func TestSetDeprecationLogger(t *testing.T) {
+	var reported []Deprecation
+	SetDeprecationLogger(func(d Deprecation) {
+		reported = append(reported, d)
+	})
+	defer SetDeprecationLogger(nil)
+
+	configPath := filepath.Join(t.TempDir(), "zowe.config.json")
+	require.NoError(t, WriteTestConfig(configPath, "{}"))
+	require.NoError(t, WriteTestConfig(configPath, "{}"))
+
+	// Each function is reported once
+	assert.Equal(t, []Deprecation{{Function: "profile.WriteTestConfig", Replacement: "profile.SaveZoweConfig"}}, reported)
+
+	SetDeprecationLogger(nil)
+	require.NoError(t, WriteTestConfig(configPath, "{}"))
+	assert.Len(t, reported, 1)
+}
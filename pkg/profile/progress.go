@@ -0,0 +1,77 @@
+package profile
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/internal/request"
+)
+
+// Progress reporting is throttled to at most one call per interval, plus a final call
+const (
+	ProgressByteInterval = 64 * 1024
+	ProgressTimeInterval = 100 * time.Millisecond
+)
+
+// ProgressFunc receives the bytes transferred so far and the total size, which is -1 when unknown
+type ProgressFunc func(bytesTransferred, totalBytes int64)
+
+// progressReader reports the bytes read through it to a ProgressFunc
+type progressReader struct {
+	r          io.Reader
+	total      int64
+	fn         ProgressFunc
+	read       int64
+	reported   int64
+	lastReport time.Time
+	done       bool
+}
+
+// NewProgressReader wraps r so fn is called as data is read, at most every
+// ProgressByteInterval bytes or ProgressTimeInterval, and once when r is exhausted.
+// A nil fn returns r unchanged.
+func NewProgressReader(r io.Reader, totalBytes int64, fn ProgressFunc) io.Reader {
+	if fn == nil {
+		return r
+	}
+	if totalBytes < 0 {
+		totalBytes = -1
+	}
+	return &progressReader{r: r, total: totalBytes, fn: fn, lastReport: time.Now()}
+}
+
+// NewProgressBody returns content as a request body that calls fn as it is sent, like
+// NewProgressReader. DoRequest sends its length, and redirects and body logging read
+// the content again without reporting progress twice.
+func NewProgressBody(content []byte, fn ProgressFunc) io.Reader {
+	return &request.ReplayableBody{
+		Reader:  NewProgressReader(bytes.NewReader(content), int64(len(content)), fn),
+		Content: content,
+	}
+}
+
+// Read reads from the wrapped reader and reports progress when due
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+
+	if p.done {
+		return n, err
+	}
+	finished := err == io.EOF || (p.total >= 0 && p.read >= p.total)
+	if finished {
+		p.done = true
+		p.report()
+	} else if n > 0 && (p.read-p.reported >= ProgressByteInterval || time.Since(p.lastReport) >= ProgressTimeInterval) {
+		p.report()
+	}
+	return n, err
+}
+
+// report calls the ProgressFunc with the current count
+func (p *progressReader) report() {
+	p.reported = p.read
+	p.lastReport = time.Now()
+	p.fn(p.read, p.total)
+}
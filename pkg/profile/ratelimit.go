@@ -0,0 +1,42 @@
+package profile
+
+import (
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRequestsPerSecond limits the session to n requests per second, with bursts of up
+// to one second's worth of requests. Requests wait for their turn, or fail when their
+// context ends first. Sessions cloned from this one share the limit. n <= 0 means no limit.
+func WithRequestsPerSecond(n float64) SessionOption {
+	return func(s *Session) {
+		s.limiter = newRateLimiter(n)
+	}
+}
+
+// SetRequestsPerSecond changes the session's request rate limit; n <= 0 removes it
+func (s *Session) SetRequestsPerSecond(n float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limiter = newRateLimiter(n)
+}
+
+// newRateLimiter returns a limiter for n requests per second, or nil for no limit
+func newRateLimiter(n float64) *rate.Limiter {
+	if n <= 0 || math.IsInf(n, 1) || math.IsNaN(n) {
+		return nil
+	}
+	burst := int(n)
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(n), burst)
+}
+
+// rateLimiter returns the session's limiter, nil when requests are not limited
+func (s *Session) rateLimiter() *rate.Limiter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.limiter
+}
@@ -0,0 +1,85 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/internal/request"
+)
+
+// APIError is a z/OSMF request that failed with a status outside 2xx. When the
+// response body is a z/OSMF error document, its fields are filled in from it.
+type APIError struct {
+	StatusCode int
+	Category   int      // z/OSMF error category
+	RC         int      // z/OSMF return code
+	Reason     int      // z/OSMF reason code
+	Message    string   // z/OSMF message
+	Details    []string // Further z/OSMF messages
+	Body       string   // Raw response body
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// NewAPIError builds an *APIError from the status code and body of a failed response
+func NewAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Body: string(body)}
+	if doc, ok := request.ParseErrorDocument(body); ok {
+		apiErr.Category = doc.Category
+		apiErr.RC = doc.RC
+		apiErr.Reason = doc.Reason
+		apiErr.Message = doc.Message
+		apiErr.Details = doc.Details
+	}
+	return apiErr
+}
+
+// DoRequest sends a request to path under the session's base URL, e.g.
+// "/restfiles/ds" for https://host/zosmf/restfiles/ds. The session's headers are
+// sent along with headers, which take precedence over them. The response is
+// returned whatever its status, and the caller must close its body.
+func DoRequest(ctx context.Context, session HTTPSession, method, path string, query url.Values, headers map[string]string, body io.Reader) (*http.Response, error) {
+	return request.Do(ctx, session, method, path, query, headers, body)
+}
+
+// DoJSONRequest sends a request like DoRequest and decodes a JSON response into out,
+// which may be nil to discard it. A status outside 2xx is returned as an *APIError.
+func DoJSONRequest(ctx context.Context, session HTTPSession, method, path string, query url.Values, headers map[string]string, body io.Reader, out interface{}) error {
+	resp, err := DoRequest(ctx, session, method, path, query, headers, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return NewAPIError(resp.StatusCode, data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if out == nil || len(strings.TrimSpace(string(data))) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// Do sends a request to an endpoint the SDK does not wrap; see DoRequest
+func (s *Session) Do(ctx context.Context, method, path string, query url.Values, headers map[string]string, body io.Reader) (*http.Response, error) {
+	return DoRequest(ctx, s, method, path, query, headers, body)
+}
+
+// DoJSON sends a request and decodes its JSON response into out; see DoJSONRequest
+func (s *Session) DoJSON(ctx context.Context, method, path string, query url.Values, headers map[string]string, body io.Reader, out interface{}) error {
+	return DoJSONRequest(ctx, s, method, path, query, headers, body, out)
+}
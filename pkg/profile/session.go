@@ -2,75 +2,242 @@ package profile
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
+	"net"
 	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// NewSession creates a session from a ZOSMF profile
-func (p *ZOSMFProfile) NewSession() (*Session, error) {
-	// Set up HTTP client with TLS config
+// NewSession creates a session from a ZOSMF profile, applying any options in order
+func (p *ZOSMFProfile) NewSession(opts ...SessionOption) (*Session, error) {
+	// Set up HTTP client with TLS config. A CA bundle or directory keeps
+	// verification on even when RejectUnauthorized is false.
+	customCA := p.CACertFile != "" || p.CACertPath != ""
 	tlsConfig := &tls.Config{
-		InsecureSkipVerify: !p.RejectUnauthorized,
+		InsecureSkipVerify: !p.RejectUnauthorized && !customCA,
+		ServerName:         p.ServerName,
+	}
+	if customCA {
+		pool, err := loadCACertPool(p.CACertFile, p.CACertPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
 	}
 	
+	// Use the profile's proxy, falling back to HTTPS_PROXY/HTTP_PROXY/NO_PROXY
+	proxy := http.ProxyFromEnvironment
+	if p.ProxyURL != "" {
+		proxyURL, err := parseProxyURL(p.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
 	transport := &http.Transport{
 		TLSClientConfig: tlsConfig,
+		Proxy:           proxy,
 	}
 	
-	client := &http.Client{
-		Transport: transport,
-		Timeout:   30 * time.Second,
+	baseURL, err := BuildBaseURL(p)
+	if err != nil {
+		return nil, err
 	}
 	
-	// Figure out protocol and build base URL
-	protocol := p.Protocol
-	if protocol == "" {
-		protocol = "https"
+	// Set up default headers
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "application/json",
 	}
-	if p.Port == 80 {
-		protocol = "http"
-	} else if p.Port == 8080 {
-		protocol = "http"
+	if p.User != "" && p.Password != "" {
+		b := base64.StdEncoding.EncodeToString([]byte(p.User + ":" + p.Password))
+		headers["Authorization"] = "Basic " + b
 	}
 	
-	baseURL := protocol + "://" + p.Host
-	if p.Port != 0 && p.Port != 80 && p.Port != 443 {
-		baseURL += ":" + fmt.Sprintf("%d", p.Port)
+	session := &Session{
+		Profile:      p,
+		Host:         p.Host,
+		Port:         p.Port,
+		User:         p.User,
+		Password:     p.Password,
+		BaseURL:      baseURL,
+		Headers:      headers,
+		logBodyLimit: logBodyLimitFromEnv(),
+	}
+
+	// Route requests through the session transport so logging applies to every manager
+	session.HTTPClient = &http.Client{
+		Transport: &sessionTransport{base: transport, session: session},
+		Timeout:   30 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(session)
+	}
+
+	return session, nil
+}
+
+// loadCACertPool returns the system roots plus the certificates in a PEM file
+// and in the files of a directory, either of which may be empty
+func loadCACertPool(caCertFile, caCertPath string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if caCertFile != "" {
+		pemData, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no PEM certificates found in CA certificate file %s", caCertFile)
+		}
+	}
+
+	if caCertPath != "" {
+		// Like SSL_CERT_DIR, every file is tried and those without certificates,
+		// such as a README or CRL, are skipped
+		entries, err := os.ReadDir(caCertPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate directory: %w", err)
+		}
+		found := false
+		for _, entry := range entries {
+			path := filepath.Join(caCertPath, entry.Name())
+			if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			pemData, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+			}
+			if pool.AppendCertsFromPEM(pemData) {
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no PEM certificates found in CA certificate directory %s", caCertPath)
+		}
+	}
+	return pool, nil
+}
+
+// parseProxyURL parses and validates a proxy URL such as http://proxy.example.com:8080
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", rawURL, err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", rawURL)
+	}
+	if proxyURL.Hostname() == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", rawURL)
+	}
+	return proxyURL, nil
+}
+
+// BuildBaseURL returns the URL that z/OSMF REST endpoints are appended to, e.g.
+// https://host:port/zosmf. An explicit Protocol always wins; only when it is empty
+// do ports 80 and 8080 mean http. The port is left out when it is the protocol's
+// default, and the URL never ends with a slash.
+func BuildBaseURL(p *ZOSMFProfile) (string, error) {
+	if p == nil {
+		return "", fmt.Errorf("profile cannot be nil")
+	}
+
+	protocol := sessionProtocol(p.Protocol, p.Port)
+	if protocol != "http" && protocol != "https" {
+		return "", fmt.Errorf("invalid protocol %q: must be http or https", p.Protocol)
+	}
+
+	host := strings.TrimRight(strings.TrimSpace(p.Host), "/")
+	if host == "" {
+		return "", fmt.Errorf("host is required")
+	}
+	if strings.Contains(host, "://") {
+		return "", fmt.Errorf("invalid host %q: must not contain a scheme", p.Host)
+	}
+
+	// A host that already carries a port (host:port) is used as it is; an IPv6
+	// literal is bracketed whether or not a port follows
+	baseURL := protocol + "://" + host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = strings.Trim(host, "[]")
+		if strings.Contains(host, ":") {
+			baseURL = protocol + "://[" + host + "]"
+		}
+		if p.Port != 0 && p.Port != defaultPort(protocol) {
+			baseURL = protocol + "://" + net.JoinHostPort(host, strconv.Itoa(p.Port))
+		}
 	}
 
 	// Add base path (default to /zosmf)
-	basePath := p.BasePath
+	basePath, err := normalizeBasePath(p.BasePath)
+	if err != nil {
+		return "", err
+	}
+	return baseURL + basePath, nil
+}
+
+// normalizeBasePath returns the base path with a leading slash and no duplicate or trailing slashes
+func normalizeBasePath(basePath string) (string, error) {
+	basePath = strings.TrimSpace(basePath)
 	if basePath == "" {
-		basePath = "/zosmf"
+		return "/zosmf", nil
 	}
-	// Make sure it starts with /
-	if basePath[0] != '/' {
-		basePath = "/" + basePath
+	if strings.Contains(basePath, "://") {
+		return "", fmt.Errorf("invalid base path %q: must not contain a scheme or host", basePath)
 	}
-	baseURL += basePath
-	
-	// Set up default headers
-	headers := map[string]string{
-		"Content-Type": "application/json",
-		"Accept":       "application/json",
+	if strings.ContainsAny(basePath, " \t\r\n") {
+		return "", fmt.Errorf("invalid base path %q: must not contain spaces", basePath)
 	}
-	if p.User != "" && p.Password != "" {
-		b := base64.StdEncoding.EncodeToString([]byte(p.User + ":" + p.Password))
-		headers["Authorization"] = "Basic " + b
+
+	var segments []string
+	for _, segment := range strings.Split(basePath, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
 	}
-	
-	return &Session{
-		Profile:    p,
-		Host:       p.Host,
-		Port:       p.Port,
-		User:       p.User,
-		Password:   p.Password,
-		BaseURL:    baseURL,
-		HTTPClient: client,
-		Headers:    headers,
-	}, nil
+	if len(segments) > 0 && strings.Contains(segments[0], ":") {
+		return "", fmt.Errorf("invalid base path %q: must not contain a scheme or host", basePath)
+	}
+	if len(segments) == 0 {
+		return "", nil
+	}
+	return "/" + strings.Join(segments, "/"), nil
+}
+
+// sessionProtocol returns the configured protocol, guessing from the port only when it is unset
+func sessionProtocol(protocol string, port int) string {
+	if protocol != "" {
+		return strings.ToLower(protocol)
+	}
+	if port == 80 || port == 8080 {
+		return "http"
+	}
+	return "https"
+}
+
+// defaultPort returns the port implied by a protocol, which is left out of the base URL
+func defaultPort(protocol string) int {
+	if protocol == "http" {
+		return 80
+	}
+	return 443
 }
 
 // GetBaseURL returns the base URL for the session
@@ -80,20 +247,171 @@ func (s *Session) GetBaseURL() string {
 
 // GetHTTPClient returns the HTTP client for the session
 func (s *Session) GetHTTPClient() *http.Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.HTTPClient
 }
 
-// GetHeaders returns the headers for the session
+// GetHeaders returns a copy of the headers for the session
 func (s *Session) GetHeaders() map[string]string {
-	return s.Headers
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	headers := make(map[string]string, len(s.Headers))
+	for key, value := range s.Headers {
+		headers[key] = value
+	}
+	return headers
+}
+
+// GetUser returns the user the session authenticates as
+func (s *Session) GetUser() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.User
+}
+
+// GetEncoding returns the host code page set by the profile's encoding property,
+// or "" when the profile does not set one
+func (s *Session) GetEncoding() string {
+	if s.Profile == nil {
+		return ""
+	}
+	return s.Profile.Encoding
+}
+
+// EncodingSession is implemented by sessions that carry a profile's encoding, such as *Session
+type EncodingSession interface {
+	GetEncoding() string
+}
+
+// SessionEncoding returns the normalized encoding of the profile a session was created
+// from, or "" when the session carries none
+func SessionEncoding(session HTTPSession) string {
+	if encoded, ok := session.(EncodingSession); ok {
+		return NormalizeEncoding(encoded.GetEncoding())
+	}
+	return ""
+}
+
+// NormalizeEncoding turns a bare code page number, as Zowe profiles often hold it,
+// into the IBM-nnnn form z/OSMF expects; other names are returned unchanged
+func NormalizeEncoding(encoding string) string {
+	encoding = strings.TrimSpace(encoding)
+	if encoding == "" {
+		return ""
+	}
+	for _, char := range encoding {
+		if char < '0' || char > '9' {
+			return encoding
+		}
+	}
+	return "IBM-" + encoding
+}
+
+// UpdateCredentials switches the session to basic authentication with new credentials.
+// Managers holding the session use them for their next request.
+func (s *Session) UpdateCredentials(user, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.User = user
+	s.Password = password
+	s.Headers = copyHeadersWithout(s.Headers, "Authorization", "Cookie")
+	if user != "" && password != "" {
+		b := base64.StdEncoding.EncodeToString([]byte(user + ":" + password))
+		s.Headers["Authorization"] = "Basic " + b
+	}
+	s.clearCookies()
+}
+
+// UpdateToken switches the session to token authentication. A "bearer" token is sent as
+// an Authorization header; other types (LtpaToken2, jwtToken, apimlAuthenticationToken)
+// are sent as a cookie named after the type.
+func (s *Session) UpdateToken(tokenType, tokenValue string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Headers = copyHeadersWithout(s.Headers, "Authorization", "Cookie")
+	if strings.EqualFold(tokenType, "bearer") {
+		s.Headers["Authorization"] = "Bearer " + tokenValue
+	} else {
+		s.Headers["Cookie"] = tokenType + "=" + tokenValue
+	}
+	s.clearCookies()
+}
+
+// Clone returns an independent copy of the session. Header changes on the clone do
+// not affect the original; both share the underlying connection pool.
+func (s *Session) Clone() *Session {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clone := &Session{
+		Profile:      s.Profile,
+		Host:         s.Host,
+		Port:         s.Port,
+		User:         s.User,
+		Password:     s.Password,
+		BaseURL:      s.BaseURL,
+		Headers:      copyHeadersWithout(s.Headers),
+		logger:       s.logger,
+		logBodyLimit: s.logBodyLimit,
+		limiter:      s.limiter,
+		info:         s.info,
+	}
+	if s.Profile != nil {
+		clone.Profile = CloneProfile(s.Profile)
+	}
+
+	clone.HTTPClient = s.HTTPClient
+	if s.HTTPClient != nil {
+		client := *s.HTTPClient
+		// Rebind the session transport so hooks use the clone's settings
+		if t, ok := client.Transport.(*sessionTransport); ok {
+			client.Transport = &sessionTransport{base: t.base, session: clone}
+		}
+		if client.Jar != nil {
+			client.Jar, _ = cookiejar.New(nil)
+		}
+		clone.HTTPClient = &client
+	}
+
+	return clone
+}
+
+// clearCookies drops cookies cached from earlier responses; callers hold s.mu.
+// The client is replaced rather than modified so in-flight requests keep a consistent view.
+func (s *Session) clearCookies() {
+	if s.HTTPClient != nil && s.HTTPClient.Jar != nil {
+		client := *s.HTTPClient
+		client.Jar, _ = cookiejar.New(nil)
+		s.HTTPClient = &client
+	}
+}
+
+// copyHeadersWithout copies headers, leaving out the given keys
+func copyHeadersWithout(headers map[string]string, exclude ...string) map[string]string {
+	copied := make(map[string]string, len(headers))
+	for key, value := range headers {
+		copied[key] = value
+	}
+	for _, key := range exclude {
+		delete(copied, key)
+	}
+	return copied
 }
 
 // AddHeader adds a header to the session
 func (s *Session) AddHeader(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Headers == nil {
+		s.Headers = make(map[string]string)
+	}
 	s.Headers[key] = value
 }
 
 // RemoveHeader removes a header from the session
 func (s *Session) RemoveHeader(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	delete(s.Headers, key)
 }
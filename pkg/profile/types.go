@@ -2,6 +2,9 @@ package profile
 
 import (
 	"net/http"
+	"sync"
+
+	"golang.org/x/time/rate"
 )
 
 
@@ -36,6 +39,10 @@ type ZOSMFProfile struct {
 	ResponseTimeout    int    `json:"responseTimeout,omitempty"`
 	CertFile           string `json:"certFile,omitempty"`
 	CertKeyFile        string `json:"certKeyFile,omitempty"`
+	ProxyURL           string `json:"proxyURL,omitempty"` // http, https or socks5 proxy; HTTPS_PROXY/HTTP_PROXY are used when empty
+	CACertFile         string `json:"caCertFile,omitempty"` // PEM bundle trusted in addition to the system roots
+	CACertPath         string `json:"caCertPath,omitempty"` // Directory of PEM certificates trusted in addition to the system roots
+	ServerName         string `json:"serverName,omitempty"` // Name sent for SNI and verified against the certificate, when it differs from Host
 }
 
 // BaseProfile represents the global base profile properties
@@ -61,17 +68,45 @@ type Session struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	Headers    map[string]string
+
+	mu           sync.RWMutex
+	logger       Logger
+	logBodyLimit int
+	limiter      *rate.Limiter
+	info         *ZosmfInfo // cached by GetZosmfInfo
+}
+
+// HTTPSession is the part of a session that the job and dataset managers use.
+// *Session implements it; tests can pass their own implementation instead.
+type HTTPSession interface {
+	GetBaseURL() string
+	GetHTTPClient() *http.Client
+	GetHeaders() map[string]string
+	GetUser() string
 }
 
+// Ensure Session implements HTTPSession
+var _ HTTPSession = (*Session)(nil)
+
 // ProfileManager interface for managing profiles
 type ProfileManager interface {
 	GetZOSMFProfile(name string) (*ZOSMFProfile, error)
+	GetProfile(name, profileType string) (map[string]interface{}, error)
 	ListZOSMFProfiles() ([]string, error)
 	SaveZOSMFProfile(profile *ZOSMFProfile) error
+	UpdateZOSMFProfile(name string, changes map[string]interface{}) error
 	DeleteZOSMFProfile(name string) error
+	SetDefaultZOSMFProfile(name string) error
+	TestProfile(name string) error
 }
 
 // ZOSMFProfileManager implements ProfileManager for ZOSMF profiles
 type ZOSMFProfileManager struct {
-	configPath string
+	configPath   string
+	credentials  CredentialProvider
+	envOverrides bool
+
+	configMu sync.Mutex      // guards config
+	config   *configSnapshot // parsed config file, shared by readers
+	updateMu sync.Mutex      // serializes read-modify-write updates of the file
 } 
\ No newline at end of file
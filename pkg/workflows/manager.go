@@ -0,0 +1,169 @@
+package workflows
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+)
+
+// z/OSMF workflow API endpoints
+const (
+	// Main workflows endpoint
+	WorkflowsEndpoint = "/workflow/rest/1.0/workflows"
+
+	// Workflow by key
+	WorkflowByKeyEndpoint = "/workflow/rest/1.0/workflows/%s"
+
+	// Workflow operations
+	StartEndpoint = "/operations/start"
+)
+
+// Ensure ZOSMFWorkflowManager implements WorkflowManager
+var _ WorkflowManager = (*ZOSMFWorkflowManager)(nil)
+
+// NewWorkflowManager creates a workflow manager with the given session, usually a *profile.Session
+func NewWorkflowManager(session profile.HTTPSession) *ZOSMFWorkflowManager {
+	return &ZOSMFWorkflowManager{
+		session: session,
+	}
+}
+
+// NewWorkflowManagerFromProfile creates a workflow manager from a profile
+func NewWorkflowManagerFromProfile(profile *profile.ZOSMFProfile) (*ZOSMFWorkflowManager, error) {
+	session, err := profile.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	return NewWorkflowManager(session), nil
+}
+
+// Session returns the session the manager sends requests with
+func (wm *ZOSMFWorkflowManager) Session() profile.HTTPSession {
+	return wm.session
+}
+
+// doJSON sends a request and decodes its JSON response into out, which may be nil
+func (wm *ZOSMFWorkflowManager) doJSON(method, path string, query url.Values, body interface{}, out interface{}) error {
+	var reader io.Reader
+	headers := map[string]string{}
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reader = bytes.NewReader(jsonBody)
+		headers["Content-Type"] = "application/json"
+	}
+	return profile.DoJSONRequest(context.Background(), wm.session, method, path, query, headers, reader, out)
+}
+
+// workflowPath returns the path of a workflow instance
+func workflowPath(workflowKey string) string {
+	return fmt.Sprintf(WorkflowByKeyEndpoint, url.PathEscape(workflowKey))
+}
+
+// CreateWorkflow creates a workflow instance from a definition file. The workflow is
+// not started; call StartWorkflow or RunWorkflowAndWait with the returned key.
+func (wm *ZOSMFWorkflowManager) CreateWorkflow(request *CreateWorkflowRequest) (*CreateWorkflowResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("create workflow request is required")
+	}
+	if request.WorkflowName == "" || request.DefinitionFile == "" {
+		return nil, fmt.Errorf("workflow name and definition file are required")
+	}
+	if request.System == "" || request.Owner == "" {
+		return nil, fmt.Errorf("workflow system and owner are required")
+	}
+
+	var created CreateWorkflowResponse
+	if err := wm.doJSON("POST", WorkflowsEndpoint, nil, request, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// ListWorkflows lists the workflow instances matching the filter, which may be nil
+func (wm *ZOSMFWorkflowManager) ListWorkflows(filter *WorkflowFilter) (*WorkflowList, error) {
+	params := url.Values{}
+	if filter != nil {
+		if filter.WorkflowName != "" {
+			params.Set("workflowName", filter.WorkflowName)
+		}
+		if filter.Category != "" {
+			params.Set("category", filter.Category)
+		}
+		if filter.System != "" {
+			params.Set("system", filter.System)
+		}
+		if filter.Owner != "" {
+			params.Set("owner", filter.Owner)
+		}
+		if filter.Vendor != "" {
+			params.Set("vendor", filter.Vendor)
+		}
+		if filter.Status != "" {
+			params.Set("statusName", string(filter.Status))
+		}
+	}
+
+	list := &WorkflowList{}
+	if err := wm.doJSON("GET", WorkflowsEndpoint, params, nil, list); err != nil {
+		return nil, err
+	}
+	if list.Workflows == nil {
+		list.Workflows = []WorkflowInfo{}
+	}
+	return list, nil
+}
+
+// GetWorkflowProperties gets the status of a workflow instance, with its steps and
+// variables when asked for
+func (wm *ZOSMFWorkflowManager) GetWorkflowProperties(workflowKey string, includeSteps, includeVariables bool) (*WorkflowProperties, error) {
+	if workflowKey == "" {
+		return nil, fmt.Errorf("workflow key cannot be empty")
+	}
+
+	var returnData []string
+	if includeSteps {
+		returnData = append(returnData, "steps")
+	}
+	if includeVariables {
+		returnData = append(returnData, "variables")
+	}
+	params := url.Values{}
+	if len(returnData) > 0 {
+		params.Set("returnData", strings.Join(returnData, ","))
+	}
+
+	var properties WorkflowProperties
+	if err := wm.doJSON("GET", workflowPath(workflowKey), params, nil, &properties); err != nil {
+		return nil, err
+	}
+	return &properties, nil
+}
+
+// StartWorkflow starts running the automated steps of a workflow instance. z/OSMF
+// runs them in the background; poll GetWorkflowProperties, or use RunWorkflowAndWait.
+func (wm *ZOSMFWorkflowManager) StartWorkflow(workflowKey string, request *StartWorkflowRequest) error {
+	if workflowKey == "" {
+		return fmt.Errorf("workflow key cannot be empty")
+	}
+	if request == nil {
+		request = &StartWorkflowRequest{}
+	}
+	return wm.doJSON("PUT", workflowPath(workflowKey)+StartEndpoint, nil, request, nil)
+}
+
+// DeleteWorkflow removes a workflow instance. Jobs and output it created are kept.
+func (wm *ZOSMFWorkflowManager) DeleteWorkflow(workflowKey string) error {
+	if workflowKey == "" {
+		return fmt.Errorf("workflow key cannot be empty")
+	}
+	return wm.doJSON("DELETE", workflowPath(workflowKey), nil, nil, nil)
+}
@@ -0,0 +1,179 @@
+package workflows
+
+import (
+	"context"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+)
+
+// WorkflowStatus is the z/OSMF status of a workflow instance
+type WorkflowStatus string
+
+const (
+	WorkflowStatusInProgress           WorkflowStatus = "in-progress"            // Created, or started and stopped before the end
+	WorkflowStatusComplete             WorkflowStatus = "complete"               // Every step is complete or skipped
+	WorkflowStatusAutomationInProgress WorkflowStatus = "automation-in-progress" // Steps are running automatically
+	WorkflowStatusCanceled             WorkflowStatus = "canceled"               // Canceled by its owner
+)
+
+// StepState is the z/OSMF state of a workflow step
+type StepState string
+
+const (
+	StepStateUnassigned            StepState = "Unassigned"
+	StepStateAssigned              StepState = "Assigned"
+	StepStateNotReady              StepState = "Not Ready"
+	StepStateReady                 StepState = "Ready"
+	StepStateInProgress            StepState = "In Progress"
+	StepStateSubmitted             StepState = "Submitted"
+	StepStateComplete              StepState = "Complete"
+	StepStateCompleteOverride      StepState = "Complete (Override)"
+	StepStateSkipped               StepState = "Skipped"
+	StepStateFailed                StepState = "Failed"
+	StepStateConflicts             StepState = "Conflicts"
+	StepStateConditionNotSatisfied StepState = "Condition Not Satisfied"
+)
+
+// Variable is a workflow variable, as given to CreateWorkflow or returned with a workflow
+type Variable struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Type       string `json:"type,omitempty"`       // boolean, string, integer, ...
+	Visibility string `json:"visibility,omitempty"` // public or private
+	Scope      string `json:"scope,omitempty"`      // instance or global
+}
+
+// CreateWorkflowRequest describes a workflow instance to create from a definition file
+type CreateWorkflowRequest struct {
+	WorkflowName string `json:"workflowName"`
+	// DefinitionFile is the workflow definition XML, as a USS path or a sequential
+	// dataset or member, e.g. "/usr/lpp/zosmf/samples/workflow_sample.xml" or "SYS1.WF(PROV)"
+	DefinitionFile string `json:"workflowDefinitionFile"`
+	// VariableInputFile is an optional properties file of variable values, a USS path or dataset
+	VariableInputFile string     `json:"variableInputFile,omitempty"`
+	Variables         []Variable `json:"variables,omitempty"` // Override VariableInputFile
+	System            string     `json:"system"`              // System the workflow runs on, e.g. SY1
+	Owner             string     `json:"owner"`               // Owner user ID
+	AssignToOwner     bool       `json:"assignToOwner,omitempty"`
+	AccessType        string     `json:"accessType,omitempty"` // Public, Restricted or Private
+	Comments          string     `json:"comments,omitempty"`
+	// DeleteCompletedJobs purges the jobs of steps once they complete
+	DeleteCompletedJobs bool   `json:"deleteCompletedJobs,omitempty"`
+	JobStatement        string `json:"jobStatement,omitempty"` // JOB card for the workflow's jobs
+}
+
+// CreateWorkflowResponse identifies a created workflow instance
+type CreateWorkflowResponse struct {
+	WorkflowKey         string `json:"workflowKey"`
+	WorkflowDescription string `json:"workflowDescription,omitempty"`
+	WorkflowID          string `json:"workflowID,omitempty"`
+	WorkflowVersion     string `json:"workflowVersion,omitempty"`
+	Vendor              string `json:"vendor,omitempty"`
+}
+
+// WorkflowFilter selects the workflows ListWorkflows returns; empty fields match any
+type WorkflowFilter struct {
+	WorkflowName string         `json:"workflowName,omitempty"` // May use * wildcards
+	Category     string         `json:"category,omitempty"`     // general or configuration
+	System       string         `json:"system,omitempty"`
+	Owner        string         `json:"owner,omitempty"`
+	Vendor       string         `json:"vendor,omitempty"`
+	Status       WorkflowStatus `json:"statusName,omitempty"`
+}
+
+// WorkflowInfo is a workflow as listed by ListWorkflows
+type WorkflowInfo struct {
+	WorkflowKey         string         `json:"workflowKey"`
+	WorkflowName        string         `json:"workflowName"`
+	WorkflowDescription string         `json:"workflowDescription,omitempty"`
+	WorkflowID          string         `json:"workflowID,omitempty"`
+	WorkflowVersion     string         `json:"workflowVersion,omitempty"`
+	Vendor              string         `json:"vendor,omitempty"`
+	Owner               string         `json:"owner,omitempty"`
+	System              string         `json:"system,omitempty"`
+	Category            string         `json:"category,omitempty"`
+	Status              WorkflowStatus `json:"statusName,omitempty"`
+	URL                 string         `json:"url,omitempty"`
+}
+
+// WorkflowList is the response of ListWorkflows
+type WorkflowList struct {
+	Workflows []WorkflowInfo `json:"workflows"`
+}
+
+// AutomationStatus reports the last automated run of a workflow
+type AutomationStatus struct {
+	StartUser         string `json:"startUser,omitempty"`
+	StartedTime       int64  `json:"startedTime,omitempty"` // Milliseconds since the epoch
+	StoppedTime       int64  `json:"stoppedTime,omitempty"` // Zero while automation runs
+	CurrentStepName   string `json:"currentStepName,omitempty"`
+	CurrentStepNumber string `json:"currentStepNumber,omitempty"`
+	CurrentStepTitle  string `json:"currentStepTitle,omitempty"`
+	MessageID         string `json:"messageID,omitempty"`
+	MessageText       string `json:"messageText,omitempty"` // Why automation stopped
+}
+
+// Step is a workflow step; parent steps contain their sub-steps
+type Step struct {
+	Name              string    `json:"name"`
+	Title             string    `json:"title,omitempty"`
+	Description       string    `json:"description,omitempty"`
+	StepNumber        string    `json:"stepNumber,omitempty"` // Dotted, e.g. "2.1"
+	State             StepState `json:"state"`
+	Owner             string    `json:"owner,omitempty"`
+	Optional          bool      `json:"optional,omitempty"`
+	AutoEnable        bool      `json:"autoEnable,omitempty"`
+	IsRestStep        bool      `json:"isRestStep,omitempty"`
+	SubmitAs          string    `json:"submitAs,omitempty"`   // JCL, TSO-REXX, shell-JCL, ...
+	ReturnCode        string    `json:"returnCode,omitempty"` // Of the step's job, e.g. "CC 0000"
+	CalledWorkflowKey string    `json:"calledWorkflowKey,omitempty"`
+	Steps             []Step    `json:"steps,omitempty"`
+}
+
+// WorkflowProperties is a workflow instance as returned by GetWorkflowProperties
+type WorkflowProperties struct {
+	WorkflowKey           string            `json:"workflowKey"`
+	WorkflowName          string            `json:"workflowName"`
+	WorkflowDescription   string            `json:"workflowDescription,omitempty"`
+	WorkflowID            string            `json:"workflowID,omitempty"`
+	WorkflowVersion       string            `json:"workflowVersion,omitempty"`
+	Vendor                string            `json:"vendor,omitempty"`
+	Owner                 string            `json:"owner,omitempty"`
+	System                string            `json:"system,omitempty"`
+	Category              string            `json:"category,omitempty"`
+	Status                WorkflowStatus    `json:"statusName"`
+	PercentComplete       int               `json:"percentComplete"`
+	AccessType            string            `json:"accessType,omitempty"`
+	DeleteCompletedJobs   bool              `json:"deleteCompletedJobs,omitempty"`
+	ContainsParallelSteps bool              `json:"containsParallelSteps,omitempty"`
+	AutomationStatus      *AutomationStatus `json:"automationStatus,omitempty"`
+	Steps                 []Step            `json:"steps,omitempty"`     // Only when requested
+	Variables             []Variable        `json:"variables,omitempty"` // Only when requested
+}
+
+// StartWorkflowRequest controls how StartWorkflow runs a workflow's automated steps.
+// A nil request starts from the first step and runs every step after it.
+type StartWorkflowRequest struct {
+	// ResolveConflictByUsing says which value wins when a variable conflicts with
+	// a global one: outputFileValue, existingValue or leaveConflict (the default)
+	ResolveConflictByUsing string `json:"resolveConflictByUsing,omitempty"`
+	StepName               string `json:"stepName,omitempty"` // Step to start at
+	// PerformSubsequent runs the steps after StepName as well; nil means true
+	PerformSubsequent *bool  `json:"performSubsequent,omitempty"`
+	NotificationURL   string `json:"notificationUrl,omitempty"`
+}
+
+// WorkflowManager interface for z/OSMF workflow operations
+type WorkflowManager interface {
+	CreateWorkflow(request *CreateWorkflowRequest) (*CreateWorkflowResponse, error)
+	ListWorkflows(filter *WorkflowFilter) (*WorkflowList, error)
+	GetWorkflowProperties(workflowKey string, includeSteps, includeVariables bool) (*WorkflowProperties, error)
+	StartWorkflow(workflowKey string, request *StartWorkflowRequest) error
+	DeleteWorkflow(workflowKey string) error
+	RunWorkflowAndWait(ctx context.Context, workflowKey string, request *StartWorkflowRequest, opts *WaitOptions) (*WorkflowProperties, error)
+}
+
+// ZOSMFWorkflowManager implements WorkflowManager for ZOSMF
+type ZOSMFWorkflowManager struct {
+	session profile.HTTPSession
+}
@@ -0,0 +1,131 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultWaitPollInterval is how often RunWorkflowAndWait polls by default
+const DefaultWaitPollInterval = 5 * time.Second
+
+// ErrWorkflowFailed is returned when a workflow is canceled, a step fails, or
+// automation stops before the workflow is complete
+var ErrWorkflowFailed = errors.New("workflow did not complete")
+
+// WaitOptions controls how RunWorkflowAndWait polls
+type WaitOptions struct {
+	Timeout      time.Duration // Zero waits until the context ends
+	PollInterval time.Duration // Default 5s
+}
+
+// RunWorkflowAndWait starts a workflow and polls it until it is complete. A canceled
+// workflow, a failed step, or automation stopping early, e.g. at a manual step, is
+// reported as an error wrapping ErrWorkflowFailed. request and opts may be nil for the
+// defaults. The last properties seen are returned along with any error.
+func (wm *ZOSMFWorkflowManager) RunWorkflowAndWait(ctx context.Context, workflowKey string, request *StartWorkflowRequest, opts *WaitOptions) (*WorkflowProperties, error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	if opts.Timeout < 0 || opts.PollInterval < 0 {
+		return nil, fmt.Errorf("wait timeout and poll interval must not be negative")
+	}
+	interval := opts.PollInterval
+	if interval == 0 {
+		interval = DefaultWaitPollInterval
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	// Remember the previous automation run, so that its stop is not taken for ours
+	before, err := wm.GetWorkflowProperties(workflowKey, false, false)
+	if err != nil {
+		return nil, err
+	}
+	if before.Status == WorkflowStatusComplete {
+		return before, nil
+	}
+	var previousStart int64
+	if before.AutomationStatus != nil {
+		previousStart = before.AutomationStatus.StartedTime
+	}
+
+	if err := wm.StartWorkflow(workflowKey, request); err != nil {
+		return before, fmt.Errorf("failed to start workflow %s: %w", workflowKey, err)
+	}
+
+	last := before
+	running := false
+	for {
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("stopped waiting for workflow %s: %w", workflowKey, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		properties, err := wm.GetWorkflowProperties(workflowKey, true, false)
+		if err != nil {
+			return last, fmt.Errorf("failed to get workflow status: %w", err)
+		}
+		last = properties
+
+		switch properties.Status {
+		case WorkflowStatusComplete:
+			return properties, nil
+		case WorkflowStatusCanceled:
+			return properties, fmt.Errorf("%w: workflow %s was canceled", ErrWorkflowFailed, workflowKey)
+		case WorkflowStatusAutomationInProgress:
+			running = true
+		}
+		if step := findFailedStep(properties.Steps); step != nil {
+			return properties, fmt.Errorf("%w: step %s (%s) of workflow %s failed%s", ErrWorkflowFailed, step.StepNumber, step.Name, workflowKey, returnCodeSuffix(step.ReturnCode))
+		}
+		if properties.Status == WorkflowStatusAutomationInProgress {
+			continue
+		}
+
+		automation := properties.AutomationStatus
+		stopped := running
+		if automation != nil && automation.StoppedTime != 0 && automation.StartedTime != previousStart {
+			stopped = true
+		}
+		if stopped {
+			message := "automation stopped"
+			if automation != nil {
+				if automation.CurrentStepName != "" {
+					message += " at step " + automation.CurrentStepName
+				}
+				if automation.MessageText != "" {
+					message += ": " + automation.MessageText
+				}
+			}
+			return properties, fmt.Errorf("%w: workflow %s %s", ErrWorkflowFailed, workflowKey, message)
+		}
+	}
+}
+
+// findFailedStep returns the first failed step, preferring a failed sub-step to the
+// parent step it fails
+func findFailedStep(steps []Step) *Step {
+	for i := range steps {
+		if step := findFailedStep(steps[i].Steps); step != nil {
+			return step
+		}
+		if steps[i].State == StepStateFailed {
+			return &steps[i]
+		}
+	}
+	return nil
+}
+
+// returnCodeSuffix describes a step's return code, if it has one
+func returnCodeSuffix(returnCode string) string {
+	if returnCode == "" {
+		return ""
+	}
+	return " with " + returnCode
+}
@@ -0,0 +1,202 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+	"github.com/zowe/zowe-client-go-sdk/pkg/zosmftest"
+)
+
+const testWorkflowKey = "d043b5f1-adab-48e7-b7c3-d41cd95fa4b0"
+
+// testWorkflowPath is the mock path of the test workflow
+const testWorkflowPath = "/workflow/rest/1.0/workflows/" + testWorkflowKey
+
+// newTestManager starts a mock z/OSMF and returns a workflow manager connected to it
+func newTestManager(t *testing.T) (*ZOSMFWorkflowManager, *zosmftest.MockZOSMF) {
+	mock := zosmftest.NewMockZOSMF()
+	t.Cleanup(mock.Close)
+	session, err := mock.NewSession()
+	require.NoError(t, err)
+	return NewWorkflowManager(session), mock
+}
+
+// workflowJSON returns GetWorkflowProperties output with the given status and steps
+func workflowJSON(status WorkflowStatus, automation string, steps string) string {
+	if automation == "" {
+		automation = "null"
+	}
+	if steps == "" {
+		steps = "[]"
+	}
+	return `{"workflowKey": "` + testWorkflowKey + `", "workflowName": "Provision CICS", "owner": "IBMUSER", "system": "SY1",
+	  "statusName": "` + string(status) + `", "percentComplete": 50, "automationStatus": ` + automation + `, "steps": ` + steps + `}`
+}
+
+func TestNewWorkflowManagerFromProfile(t *testing.T) {
+	wm, err := NewWorkflowManagerFromProfile(&profile.ZOSMFProfile{
+		Name:     "test",
+		Host:     "example.com",
+		Port:     443,
+		User:     "IBMUSER",
+		Password: "secret",
+		Protocol: "https",
+		BasePath: "/zosmf",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/zosmf", wm.Session().GetBaseURL())
+}
+
+func TestWorkflowLifecycle(t *testing.T) {
+	wm, mock := newTestManager(t)
+	mock.On("POST", "/workflow/rest/1.0/workflows").ReturnStatus(http.StatusCreated, `{"workflowKey": "`+testWorkflowKey+`", "workflowID": "ProvisionCICS", "workflowVersion": "1.0", "vendor": "IBM"}`)
+	mock.On("GET", "/workflow/rest/1.0/workflows").Return(`{"workflows": [
+	  {"workflowKey": "` + testWorkflowKey + `", "workflowName": "Provision CICS", "owner": "IBMUSER", "system": "SY1", "category": "configuration", "statusName": "in-progress"}
+	]}`)
+	mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusInProgress, "", `[
+	  {"name": "alloc", "title": "Allocate datasets", "stepNumber": "1", "state": "Complete", "submitAs": "JCL", "returnCode": "CC 0000"},
+	  {"name": "define", "title": "Define region", "stepNumber": "2", "state": "Ready",
+	   "steps": [{"name": "define-csd", "stepNumber": "2.1", "state": "Ready"}]}
+	]`))
+	mock.On("DELETE", testWorkflowPath).ReturnStatus(http.StatusNoContent, nil)
+
+	created, err := wm.CreateWorkflow(&CreateWorkflowRequest{
+		WorkflowName:      "Provision CICS",
+		DefinitionFile:    "/usr/lpp/zosmf/workflows/cics.xml",
+		VariableInputFile: "IBMUSER.WF.VARS(CICS)",
+		Variables:         []Variable{{Name: "REGION", Value: "CICSA"}},
+		System:            "SY1",
+		Owner:             "IBMUSER",
+		AssignToOwner:     true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testWorkflowKey, created.WorkflowKey)
+	assert.Equal(t, "ProvisionCICS", created.WorkflowID)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(mock.LastRequest().Body, &body))
+	assert.Equal(t, "/usr/lpp/zosmf/workflows/cics.xml", body["workflowDefinitionFile"])
+	assert.Equal(t, "IBMUSER.WF.VARS(CICS)", body["variableInputFile"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "REGION", "value": "CICSA"}}, body["variables"])
+	assert.Equal(t, true, body["assignToOwner"])
+	assert.Equal(t, "application/json", mock.LastRequest().Header.Get("Content-Type"))
+
+	list, err := wm.ListWorkflows(&WorkflowFilter{Owner: "IBMUSER", Status: WorkflowStatusInProgress})
+	require.NoError(t, err)
+	require.Len(t, list.Workflows, 1)
+	assert.Equal(t, "Provision CICS", list.Workflows[0].WorkflowName)
+	assert.Equal(t, WorkflowStatusInProgress, list.Workflows[0].Status)
+	assert.Equal(t, "IBMUSER", mock.LastRequest().Query.Get("owner"))
+	assert.Equal(t, "in-progress", mock.LastRequest().Query.Get("statusName"))
+
+	properties, err := wm.GetWorkflowProperties(testWorkflowKey, true, true)
+	require.NoError(t, err)
+	assert.Equal(t, "steps,variables", mock.LastRequest().Query.Get("returnData"))
+	assert.Equal(t, 50, properties.PercentComplete)
+	require.Len(t, properties.Steps, 2)
+	assert.Equal(t, StepStateComplete, properties.Steps[0].State)
+	assert.Equal(t, "CC 0000", properties.Steps[0].ReturnCode)
+	require.Len(t, properties.Steps[1].Steps, 1)
+	assert.Equal(t, "2.1", properties.Steps[1].Steps[0].StepNumber)
+
+	require.NoError(t, wm.DeleteWorkflow(testWorkflowKey))
+	assert.Len(t, mock.RequestsTo("DELETE", testWorkflowPath), 1)
+}
+
+func TestCreateWorkflowValidation(t *testing.T) {
+	wm, mock := newTestManager(t)
+
+	_, err := wm.CreateWorkflow(nil)
+	assert.Error(t, err)
+	_, err = wm.CreateWorkflow(&CreateWorkflowRequest{WorkflowName: "wf", System: "SY1", Owner: "IBMUSER"})
+	assert.Error(t, err)
+	_, err = wm.CreateWorkflow(&CreateWorkflowRequest{WorkflowName: "wf", DefinitionFile: "/wf.xml"})
+	assert.Error(t, err)
+	assert.Empty(t, mock.Requests())
+}
+
+func TestRunWorkflowAndWait(t *testing.T) {
+	wm, mock := newTestManager(t)
+	mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusInProgress, `{"startedTime": 1000, "stoppedTime": 2000}`, "")).Times(1)
+	mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusAutomationInProgress, `{"startedTime": 5000, "currentStepName": "define"}`, "")).Times(2)
+	mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusComplete, `{"startedTime": 5000, "stoppedTime": 9000}`, ""))
+	start := mock.On("PUT", testWorkflowPath+"/operations/start").ReturnStatus(http.StatusAccepted, nil)
+
+	properties, err := wm.RunWorkflowAndWait(context.Background(), testWorkflowKey, &StartWorkflowRequest{StepName: "define"}, &WaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, WorkflowStatusComplete, properties.Status)
+	assert.Equal(t, 1, start.Calls())
+	assert.Len(t, mock.RequestsTo("GET", testWorkflowPath), 4)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(mock.RequestsTo("PUT", testWorkflowPath+"/operations/start")[0].Body, &body))
+	assert.Equal(t, map[string]interface{}{"stepName": "define"}, body)
+}
+
+func TestRunWorkflowAndWaitFailure(t *testing.T) {
+	t.Run("failed step", func(t *testing.T) {
+		wm, mock := newTestManager(t)
+		mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusInProgress, "", "")).Times(1)
+		mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusAutomationInProgress, `{"startedTime": 5000}`, `[
+		  {"name": "define", "stepNumber": "2", "state": "Failed",
+		   "steps": [{"name": "define-csd", "stepNumber": "2.1", "state": "Failed", "returnCode": "CC 0012"}]}
+		]`))
+		mock.On("PUT", testWorkflowPath+"/operations/start").ReturnStatus(http.StatusAccepted, nil)
+
+		properties, err := wm.RunWorkflowAndWait(context.Background(), testWorkflowKey, nil, &WaitOptions{PollInterval: time.Millisecond})
+		require.ErrorIs(t, err, ErrWorkflowFailed)
+		assert.Contains(t, err.Error(), "step 2.1 (define-csd)")
+		assert.Contains(t, err.Error(), "CC 0012")
+		require.NotNil(t, properties)
+	})
+
+	t.Run("automation stopped", func(t *testing.T) {
+		wm, mock := newTestManager(t)
+		mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusInProgress, `{"startedTime": 1000, "stoppedTime": 2000}`, "")).Times(1)
+		mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusInProgress, `{"startedTime": 5000, "stoppedTime": 6000, "currentStepName": "approve", "messageID": "IZUWF0162I", "messageText": "Step approve is not automated."}`, ""))
+		mock.On("PUT", testWorkflowPath+"/operations/start").ReturnStatus(http.StatusAccepted, nil)
+
+		_, err := wm.RunWorkflowAndWait(context.Background(), testWorkflowKey, nil, &WaitOptions{PollInterval: time.Millisecond})
+		require.ErrorIs(t, err, ErrWorkflowFailed)
+		assert.Contains(t, err.Error(), "at step approve: Step approve is not automated.")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		wm, mock := newTestManager(t)
+		mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusInProgress, "", "")).Times(1)
+		mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusCanceled, "", ""))
+		mock.On("PUT", testWorkflowPath+"/operations/start").ReturnStatus(http.StatusAccepted, nil)
+
+		_, err := wm.RunWorkflowAndWait(context.Background(), testWorkflowKey, nil, &WaitOptions{PollInterval: time.Millisecond})
+		require.ErrorIs(t, err, ErrWorkflowFailed)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		wm, mock := newTestManager(t)
+		mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusAutomationInProgress, `{"startedTime": 5000}`, ""))
+		mock.On("PUT", testWorkflowPath+"/operations/start").ReturnStatus(http.StatusAccepted, nil)
+
+		properties, err := wm.RunWorkflowAndWait(context.Background(), testWorkflowKey, nil, &WaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NotErrorIs(t, err, ErrWorkflowFailed)
+		assert.Equal(t, WorkflowStatusAutomationInProgress, properties.Status)
+	})
+
+	t.Run("start rejected", func(t *testing.T) {
+		wm, mock := newTestManager(t)
+		mock.On("GET", testWorkflowPath).Return(workflowJSON(WorkflowStatusInProgress, "", ""))
+		mock.On("PUT", testWorkflowPath+"/operations/start").ReturnError(http.StatusConflict, "IZUWF0145E: The workflow is already running.")
+
+		_, err := wm.RunWorkflowAndWait(context.Background(), testWorkflowKey, nil, nil)
+		var apiErr *profile.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+		assert.Contains(t, apiErr.Message, "IZUWF0145E")
+	})
+}
@@ -0,0 +1,102 @@
+package zosmftest
+
+// Canned responses in the shape z/OSMF returns them. The typed On* helpers answer
+// with these until a test calls Return.
+const (
+	// DatasetListJSON is a dataset listing with X-IBM-Attributes: base
+	DatasetListJSON = `{
+  "items": [
+    {"dsname": "IBMUSER.CNTL", "blksz": "3200", "catnm": "CATALOG.USER", "cdate": "2024/01/15", "dev": "3390", "dsntp": "PDS", "dsorg": "PO", "edate": "***None***", "extx": "1", "lrecl": "80", "migr": "NO", "mvol": "N", "ovf": "NO", "rdate": "2024/06/03", "recfm": "FB", "sizex": "15", "spacu": "TRACKS", "used": "20", "vol": "USR001", "vols": "USR001"},
+    {"dsname": "IBMUSER.DATA", "blksz": "27920", "catnm": "CATALOG.USER", "cdate": "2024/02/01", "dev": "3390", "dsntp": "BASIC", "dsorg": "PS", "edate": "***None***", "extx": "1", "lrecl": "80", "migr": "NO", "mvol": "N", "ovf": "NO", "rdate": "2024/06/01", "recfm": "FB", "sizex": "5", "spacu": "CYLINDERS", "used": "1", "vol": "USR002", "vols": "USR002"},
+    {"dsname": "IBMUSER.LOADLIB", "blksz": "32760", "catnm": "CATALOG.USER", "cdate": "2023/11/20", "dev": "3390", "dsntp": "LIBRARY", "dsorg": "PO-E", "edate": "***None***", "extx": "2", "lrecl": "0", "migr": "NO", "mvol": "N", "ovf": "NO", "rdate": "2024/05/28", "recfm": "U", "sizex": "30", "spacu": "TRACKS", "used": "45", "vol": "USR001", "vols": "USR001"},
+    {"dsname": "IBMUSER.OLD.DATA", "migr": "YES", "vol": "MIGRAT"}
+  ],
+  "returnedRows": 4,
+  "totalRows": 4,
+  "JSONversion": 1
+}`
+
+	// MemberListJSON is the member listing of a partitioned dataset
+	MemberListJSON = `{
+  "items": [
+    {"member": "ALLOC", "vers": 1, "mod": 3, "c4date": "2024/01/15", "m4date": "2024/05/30", "cnorc": 12, "inorc": 10, "mnorc": 0, "mtime": "09:14", "msec": "22", "user": "IBMUSER", "sclm": "N"},
+    {"member": "COMPILE", "vers": 1, "mod": 0, "c4date": "2024/02/10", "m4date": "2024/02/10", "cnorc": 25, "inorc": 25, "mnorc": 0, "mtime": "14:02", "msec": "51", "user": "IBMUSER", "sclm": "N"},
+    {"member": "IEFBR14", "vers": 1, "mod": 1, "c4date": "2023/12/01", "m4date": "2024/03/18", "cnorc": 3, "inorc": 3, "mnorc": 0, "mtime": "11:40", "msec": "05", "user": "IBMUSER", "sclm": "N"}
+  ],
+  "returnedRows": 3,
+  "JSONversion": 1
+}`
+
+	// DatasetContent is the text of a small JCL member
+	DatasetContent = "//IBMUSERA JOB (ACCT),'IBMUSER',CLASS=A,MSGCLASS=X\n" +
+		"//STEP1    EXEC PGM=IEFBR14\n"
+
+	// JobJSON is the status of a completed job
+	JobJSON = `{
+  "owner": "IBMUSER",
+  "phase": 20,
+  "subsystem": "JES2",
+  "phase-name": "Job is on the hard copy queue",
+  "job-correlator": "J0012345SY1.....DB0D9A2E.......:",
+  "type": "JOB",
+  "url": "https://zosmf.example.com/zosmf/restjobs/jobs/J0012345SY1.....DB0D9A2E.......%3A",
+  "jobid": "JOB12345",
+  "class": "A",
+  "files-url": "https://zosmf.example.com/zosmf/restjobs/jobs/J0012345SY1.....DB0D9A2E.......%3A/files",
+  "jobname": "IBMUSERA",
+  "status": "OUTPUT",
+  "retcode": "CC 0000"
+}`
+
+	// JobListJSON is a job listing as a bare array, as z/OSMF returns it
+	JobListJSON = `[
+  {"owner": "IBMUSER", "phase": 20, "subsystem": "JES2", "phase-name": "Job is on the hard copy queue", "job-correlator": "J0012345SY1.....DB0D9A2E.......:", "type": "JOB", "jobid": "JOB12345", "class": "A", "jobname": "IBMUSERA", "status": "OUTPUT", "retcode": "CC 0000"},
+  {"owner": "IBMUSER", "phase": 20, "subsystem": "JES2", "phase-name": "Job is on the hard copy queue", "job-correlator": "J0012346SY1.....DB0D9A31.......:", "type": "JOB", "jobid": "JOB12346", "class": "A", "jobname": "IBMUSERB", "status": "OUTPUT", "retcode": "ABEND S0C4"},
+  {"owner": "IBMUSER", "phase": 14, "subsystem": "JES2", "phase-name": "Job is actively executing", "job-correlator": "J0012347SY1.....DB0D9A40.......:", "type": "JOB", "jobid": "JOB12347", "class": "A", "jobname": "IBMUSERC", "status": "ACTIVE", "retcode": null}
+]`
+
+	// SubmitJobJSON is the response to a job submission
+	SubmitJobJSON = `{
+  "owner": "IBMUSER",
+  "phase": 130,
+  "subsystem": "JES2",
+  "phase-name": "Job is queued for execution",
+  "job-correlator": "J0012348SY1.....DB0D9A52.......:",
+  "type": "JOB",
+  "jobid": "JOB12348",
+  "class": "A",
+  "jobname": "IBMUSERA",
+  "status": "INPUT",
+  "retcode": null
+}`
+
+	// SpoolFilesJSON is the spool file list of a job
+	SpoolFilesJSON = `[
+  {"recfm": "UA", "records-url": "https://zosmf.example.com/zosmf/restjobs/jobs/J0012345SY1.....DB0D9A2E.......%3A/files/2/records", "stepname": "JES2", "subsystem": "JES2", "job-correlator": "J0012345SY1.....DB0D9A2E.......:", "byte-count": 1180, "lrecl": 133, "jobid": "JOB12345", "ddname": "JESMSGLG", "id": 2, "record-count": 20, "class": "X", "jobname": "IBMUSERA", "procstep": null},
+  {"recfm": "V", "records-url": "https://zosmf.example.com/zosmf/restjobs/jobs/J0012345SY1.....DB0D9A2E.......%3A/files/3/records", "stepname": "JES2", "subsystem": "JES2", "job-correlator": "J0012345SY1.....DB0D9A2E.......:", "byte-count": 245, "lrecl": 136, "jobid": "JOB12345", "ddname": "JESJCL", "id": 3, "record-count": 4, "class": "X", "jobname": "IBMUSERA", "procstep": null},
+  {"recfm": "VBA", "records-url": "https://zosmf.example.com/zosmf/restjobs/jobs/J0012345SY1.....DB0D9A2E.......%3A/files/4/records", "stepname": "JES2", "subsystem": "JES2", "job-correlator": "J0012345SY1.....DB0D9A2E.......:", "byte-count": 912, "lrecl": 137, "jobid": "JOB12345", "ddname": "JESYSMSG", "id": 4, "record-count": 12, "class": "X", "jobname": "IBMUSERA", "procstep": null}
+]`
+
+	// SpoolContent is the text of a JESMSGLG spool file
+	SpoolContent = "                    J E S 2  J O B  L O G  --  S Y S T E M  S Y 1  --  N O D E  N 1\n" +
+		"\n" +
+		"10.15.02 JOB12345 ---- MONDAY,    03 JUN 2024 ----\n" +
+		"10.15.02 JOB12345  IRR010I  USERID IBMUSER  IS ASSIGNED TO THIS JOB.\n" +
+		"10.15.02 JOB12345  $HASP373 IBMUSERA STARTED - INIT 1    - CLASS A        - SYS SY1\n" +
+		"10.15.03 JOB12345  $HASP395 IBMUSERA ENDED - RC=0000\n"
+
+	// InfoJSON is the /zosmf/info response of a z/OS V2R5 system
+	InfoJSON = `{
+  "zos_version": "04.28.00",
+  "zosmf_port": "443",
+  "zosmf_version": "28",
+  "zosmf_hostname": "zosmf.example.com",
+  "zosmf_saf_realm": "SAFRealm",
+  "zosmf_full_version": "28.0",
+  "api_version": "1",
+  "plugins": [
+    {"pluginVersion": "HSMA250;PH41234;2024-02-20T04:17:11", "pluginStatus": "ACTIVE", "pluginDefaultName": "z/OS Operator Consoles"},
+    {"pluginVersion": "HSMA250;PH40987;2023-11-07T10:02:44", "pluginStatus": "ACTIVE", "pluginDefaultName": "Workflow"}
+  ]
+}`
+)
@@ -0,0 +1,449 @@
+// Package zosmftest provides an in-process z/OSMF mock server for testing code
+// built on the SDK without a mainframe.
+package zosmftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+)
+
+// BasePath is the path the mock serves the z/OSMF REST APIs under
+const BasePath = "/zosmf"
+
+// MockZOSMF is an HTTP server that answers z/OSMF requests from registered stubs.
+// Requests are matched against stubs in the order they were registered; requests
+// that match no stub get a 404. Every request is recorded for later assertions.
+type MockZOSMF struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	stubs    []*Stub
+	requests []RecordedRequest
+	latency  time.Duration
+}
+
+// RecordedRequest is a request received by the mock. Path is relative to BasePath.
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// NewMockZOSMF starts a mock server. Call Close when done.
+func NewMockZOSMF() *MockZOSMF {
+	m := &MockZOSMF{}
+	m.server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	return m
+}
+
+// Close shuts the server down
+func (m *MockZOSMF) Close() {
+	m.server.Close()
+}
+
+// URL returns the server root URL, without BasePath
+func (m *MockZOSMF) URL() string {
+	return m.server.URL
+}
+
+// Profile returns a profile that connects to the mock server
+func (m *MockZOSMF) Profile() *profile.ZOSMFProfile {
+	u, _ := url.Parse(m.server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	return &profile.ZOSMFProfile{
+		Name:     "zosmftest",
+		Host:     u.Hostname(),
+		Port:     port,
+		User:     "IBMUSER",
+		Password: "password",
+		BasePath: BasePath,
+		Protocol: "http",
+	}
+}
+
+// NewSession returns a session for the mock server
+func (m *MockZOSMF) NewSession(opts ...profile.SessionOption) (*profile.Session, error) {
+	return m.Profile().NewSession(opts...)
+}
+
+// SetLatency delays every response by d, in addition to any per-stub delay
+func (m *MockZOSMF) SetLatency(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latency = d
+}
+
+// Requests returns the requests received so far
+func (m *MockZOSMF) Requests() []RecordedRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RecordedRequest(nil), m.requests...)
+}
+
+// RequestsTo returns the requests received for a method and path (relative to BasePath)
+func (m *MockZOSMF) RequestsTo(method, requestPath string) []RecordedRequest {
+	var matched []RecordedRequest
+	for _, req := range m.Requests() {
+		if req.Method == method && req.Path == requestPath {
+			matched = append(matched, req)
+		}
+	}
+	return matched
+}
+
+// LastRequest returns the most recent request, or nil if there was none
+func (m *MockZOSMF) LastRequest() *RecordedRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.requests) == 0 {
+		return nil
+	}
+	req := m.requests[len(m.requests)-1]
+	return &req
+}
+
+// Reset removes all stubs and recorded requests
+func (m *MockZOSMF) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stubs = nil
+	m.requests = nil
+	m.latency = 0
+}
+
+// On registers a stub for a method and a path relative to BasePath. The path may
+// use path.Match patterns, e.g. "/restjobs/jobs/*/*". The stub answers 200 with an
+// empty body until Return is called.
+func (m *MockZOSMF) On(method, pathPattern string) *Stub {
+	stub := &Stub{
+		mock:    m,
+		method:  method,
+		pattern: pathPattern,
+		query:   url.Values{},
+		status:  http.StatusOK,
+		header:  http.Header{},
+	}
+	m.mu.Lock()
+	m.stubs = append(m.stubs, stub)
+	m.mu.Unlock()
+	return stub
+}
+
+// OnListDatasets stubs a dataset listing. An empty pattern matches any dslevel.
+// The stub returns DatasetListJSON until Return is called.
+func (m *MockZOSMF) OnListDatasets(pattern string) *Stub {
+	stub := m.On(http.MethodGet, "/restfiles/ds").Return(DatasetListJSON)
+	if pattern != "" {
+		stub.WithQuery("dslevel", pattern)
+	}
+	return stub
+}
+
+// OnListMembers stubs the member listing of a partitioned dataset.
+// The stub returns MemberListJSON until Return is called.
+func (m *MockZOSMF) OnListMembers(datasetName string) *Stub {
+	return m.On(http.MethodGet, "/restfiles/ds/"+datasetName+"/member").Return(MemberListJSON)
+}
+
+// OnReadDataset stubs reading a dataset, or a member when memberName is set.
+// The stub returns DatasetContent until Return is called.
+func (m *MockZOSMF) OnReadDataset(datasetName, memberName string) *Stub {
+	return m.On(http.MethodGet, datasetPath(datasetName, memberName)).Return(DatasetContent)
+}
+
+// OnWriteDataset stubs writing a dataset, or a member when memberName is set.
+// The stub answers 204 until Return is called.
+func (m *MockZOSMF) OnWriteDataset(datasetName, memberName string) *Stub {
+	return m.On(http.MethodPut, datasetPath(datasetName, memberName)).ReturnStatus(http.StatusNoContent, nil)
+}
+
+// OnCreateDataset stubs creating a dataset. The stub answers 201 until Return is called.
+func (m *MockZOSMF) OnCreateDataset(datasetName string) *Stub {
+	return m.On(http.MethodPost, datasetPath(datasetName, "")).ReturnStatus(http.StatusCreated, nil)
+}
+
+// OnDeleteDataset stubs deleting a dataset. The stub answers 204 until Return is called.
+func (m *MockZOSMF) OnDeleteDataset(datasetName string) *Stub {
+	return m.On(http.MethodDelete, datasetPath(datasetName, "")).ReturnStatus(http.StatusNoContent, nil)
+}
+
+// OnListJobs stubs a job listing. The stub returns JobListJSON until Return is called.
+func (m *MockZOSMF) OnListJobs() *Stub {
+	return m.On(http.MethodGet, "/restjobs/jobs").Return(JobListJSON)
+}
+
+// OnSubmitJob stubs job submission, from JCL or a dataset.
+// The stub answers 201 with SubmitJobJSON until Return is called.
+func (m *MockZOSMF) OnSubmitJob() *Stub {
+	return m.On(http.MethodPut, "/restjobs/jobs").ReturnStatus(http.StatusCreated, SubmitJobJSON)
+}
+
+// OnGetJob stubs the status of a job. The stub returns JobJSON until Return is called.
+func (m *MockZOSMF) OnGetJob(jobName, jobID string) *Stub {
+	return m.On(http.MethodGet, "/restjobs/jobs/"+jobName+"/"+jobID).Return(JobJSON)
+}
+
+// OnListSpoolFiles stubs the spool file list of a job.
+// The stub returns SpoolFilesJSON until Return is called.
+func (m *MockZOSMF) OnListSpoolFiles(jobName, jobID string) *Stub {
+	return m.On(http.MethodGet, "/restjobs/jobs/"+jobName+"/"+jobID+"/files").Return(SpoolFilesJSON)
+}
+
+// OnReadSpoolFile stubs the content of a spool file.
+// The stub returns SpoolContent until Return is called.
+func (m *MockZOSMF) OnReadSpoolFile(jobName, jobID string, spoolID int) *Stub {
+	return m.On(http.MethodGet, fmt.Sprintf("/restjobs/jobs/%s/%s/files/%d/records", jobName, jobID, spoolID)).Return(SpoolContent)
+}
+
+// OnInfo stubs the z/OSMF server information. The stub returns InfoJSON until Return is called.
+func (m *MockZOSMF) OnInfo() *Stub {
+	return m.On(http.MethodGet, profile.ZosmfInfoEndpoint).Return(InfoJSON)
+}
+
+// datasetPath builds the path of a dataset or member
+func datasetPath(datasetName, memberName string) string {
+	if memberName != "" {
+		return fmt.Sprintf("/restfiles/ds/%s(%s)", datasetName, memberName)
+	}
+	return "/restfiles/ds/" + datasetName
+}
+
+// Stub describes how the mock answers matching requests. Its methods return the
+// stub so calls can be chained.
+type Stub struct {
+	mock    *MockZOSMF
+	method  string
+	pattern string
+	query   url.Values
+	match   func(*http.Request, []byte) bool
+
+	status  int
+	body    []byte
+	header  http.Header
+	latency time.Duration
+	drop    bool
+	times   int
+	calls   int
+}
+
+// WithQuery only matches requests with the given query parameter value
+func (s *Stub) WithQuery(key, value string) *Stub {
+	s.mock.mu.Lock()
+	defer s.mock.mu.Unlock()
+	s.query.Set(key, value)
+	return s
+}
+
+// Matching only matches requests for which fn returns true. fn gets the request
+// body, since the request's own body has already been read.
+func (s *Stub) Matching(fn func(r *http.Request, body []byte) bool) *Stub {
+	s.mock.mu.Lock()
+	defer s.mock.mu.Unlock()
+	s.match = fn
+	return s
+}
+
+// Return answers with status 200 and body. Strings and byte slices are sent as they
+// are; any other value is encoded as JSON.
+func (s *Stub) Return(body interface{}) *Stub {
+	return s.ReturnStatus(http.StatusOK, body)
+}
+
+// ReturnStatus answers with status and body, encoded as for Return. A nil body sends
+// no content.
+func (s *Stub) ReturnStatus(status int, body interface{}) *Stub {
+	data, err := encodeBody(body)
+	if err != nil {
+		panic(fmt.Sprintf("zosmftest: cannot encode stub body: %v", err))
+	}
+	s.mock.mu.Lock()
+	defer s.mock.mu.Unlock()
+	s.status = status
+	s.body = data
+	return s
+}
+
+// ReturnError answers with status and a z/OSMF style error body
+func (s *Stub) ReturnError(status int, message string) *Stub {
+	return s.ReturnStatus(status, map[string]interface{}{
+		"rc":       4,
+		"reason":   0,
+		"category": 1,
+		"message":  message,
+	})
+}
+
+// WithHeader adds a response header
+func (s *Stub) WithHeader(key, value string) *Stub {
+	s.mock.mu.Lock()
+	defer s.mock.mu.Unlock()
+	s.header.Add(key, value)
+	return s
+}
+
+// WithLatency delays the response by d. The delay ends early if the client gives up.
+func (s *Stub) WithLatency(d time.Duration) *Stub {
+	s.mock.mu.Lock()
+	defer s.mock.mu.Unlock()
+	s.latency = d
+	return s
+}
+
+// DropConnection closes the connection without sending a response
+func (s *Stub) DropConnection() *Stub {
+	s.mock.mu.Lock()
+	defer s.mock.mu.Unlock()
+	s.drop = true
+	return s
+}
+
+// Times stops matching after n requests; 0 means no limit. Registering the same
+// request again afterwards lets a test answer differently on later calls.
+func (s *Stub) Times(n int) *Stub {
+	s.mock.mu.Lock()
+	defer s.mock.mu.Unlock()
+	s.times = n
+	return s
+}
+
+// Calls returns the number of requests the stub has answered
+func (s *Stub) Calls() int {
+	s.mock.mu.Lock()
+	defer s.mock.mu.Unlock()
+	return s.calls
+}
+
+// matches reports whether the stub answers a request; the caller holds the mock lock
+func (s *Stub) matches(r *http.Request, requestPath string, body []byte) bool {
+	if s.times > 0 && s.calls >= s.times {
+		return false
+	}
+	if s.method != r.Method {
+		return false
+	}
+	if s.pattern != requestPath {
+		if ok, err := path.Match(s.pattern, requestPath); err != nil || !ok {
+			return false
+		}
+	}
+	query := r.URL.Query()
+	for key := range s.query {
+		if query.Get(key) != s.query.Get(key) {
+			return false
+		}
+	}
+	return s.match == nil || s.match(r, body)
+}
+
+func (m *MockZOSMF) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	requestPath := strings.TrimPrefix(r.URL.Path, BasePath)
+
+	m.mu.Lock()
+	m.requests = append(m.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   requestPath,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	var stub *Stub
+	for _, candidate := range m.stubs {
+		if candidate.matches(r, requestPath, body) {
+			stub = candidate
+			stub.calls++
+			break
+		}
+	}
+	latency := m.latency
+	var response Stub
+	if stub != nil {
+		latency += stub.latency
+		response = *stub
+		response.header = stub.header.Clone()
+	}
+	m.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if stub == nil {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"rc":       4,
+			"reason":   0,
+			"category": 1,
+			"message":  fmt.Sprintf("zosmftest: no stub for %s %s", r.Method, requestPath),
+		})
+		return
+	}
+
+	if response.drop {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			panic("zosmftest: connection cannot be dropped")
+		}
+		conn, _, err := hijacker.Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+
+	for key, values := range response.header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	if w.Header().Get("Content-Type") == "" && len(response.body) > 0 {
+		if json.Valid(response.body) {
+			w.Header().Set("Content-Type", "application/json")
+		} else {
+			w.Header().Set("Content-Type", "text/plain")
+		}
+	}
+	w.WriteHeader(response.status)
+	w.Write(response.body)
+}
+
+// encodeBody turns a stub body into bytes
+func encodeBody(body interface{}) ([]byte, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(b), nil
+	case []byte:
+		return append([]byte(nil), b...), nil
+	default:
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
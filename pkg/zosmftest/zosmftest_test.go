@@ -0,0 +1,172 @@
+package zosmftest_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zowe/zowe-client-go-sdk/pkg/datasets"
+	"github.com/zowe/zowe-client-go-sdk/pkg/jobs"
+	"github.com/zowe/zowe-client-go-sdk/pkg/profile"
+	"github.com/zowe/zowe-client-go-sdk/pkg/zosmftest"
+)
+
+func newSession(t *testing.T, mock *zosmftest.MockZOSMF, opts ...profile.SessionOption) *profile.Session {
+	session, err := mock.NewSession(opts...)
+	require.NoError(t, err)
+	return session
+}
+
+func TestMockDatasetFixtures(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListDatasets("IBMUSER.*")
+	mock.OnListMembers("IBMUSER.CNTL")
+	mock.OnReadDataset("IBMUSER.CNTL", "ALLOC")
+
+	dm := datasets.NewDatasetManager(newSession(t, mock))
+
+	list, err := dm.ListDatasets(&datasets.DatasetFilter{Name: "IBMUSER.*"})
+	require.NoError(t, err)
+	require.Len(t, list.Datasets, 4)
+	assert.Equal(t, "IBMUSER.CNTL", list.Datasets[0].Name)
+	assert.True(t, list.Datasets[0].IsPartitioned())
+	assert.True(t, list.Datasets[3].IsMigrated())
+
+	members, err := dm.ListMembers("IBMUSER.CNTL")
+	require.NoError(t, err)
+	require.Len(t, members.Members, 3)
+	assert.Equal(t, "ALLOC", members.Members[0].Name)
+
+	content, err := dm.DownloadTextFromMember("IBMUSER.CNTL", "ALLOC")
+	require.NoError(t, err)
+	assert.Equal(t, zosmftest.DatasetContent, content)
+
+	// Only the stubbed pattern matches
+	_, err = dm.ListDatasets(&datasets.DatasetFilter{Name: "OTHER.*"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestMockJobFixtures(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnListJobs().WithQuery("owner", "*")
+	mock.OnSubmitJob()
+	mock.OnGetJob("IBMUSERA", "JOB12345")
+	mock.OnListSpoolFiles("IBMUSERA", "JOB12345")
+	mock.OnReadSpoolFile("IBMUSERA", "JOB12345", 2)
+
+	jm := jobs.NewJobManager(newSession(t, mock))
+
+	jobList, err := jm.ListJobs(&jobs.JobFilter{Owner: "*"})
+	require.NoError(t, err)
+	require.Len(t, jobList.Jobs, 3)
+	assert.Equal(t, "ABEND S0C4", jobList.Jobs[1].RetCode)
+
+	response, err := jm.SubmitJobStatement("//IBMUSERA JOB\n//STEP1 EXEC PGM=IEFBR14")
+	require.NoError(t, err)
+	assert.Equal(t, "JOB12348", response.JobID)
+
+	job, err := jm.GetJobByNameID("IBMUSERA", "JOB12345")
+	require.NoError(t, err)
+	assert.Equal(t, "CC 0000", job.RetCode)
+
+	files, err := jm.GetSpoolFiles("IBMUSERA", "JOB12345")
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	assert.Equal(t, "JESMSGLG", files[0].DDName)
+
+	content, err := jm.GetSpoolFileContent("IBMUSERA", "JOB12345", 2)
+	require.NoError(t, err)
+	assert.Contains(t, content, "$HASP395 IBMUSERA ENDED - RC=0000")
+}
+
+func TestMockRequestCapture(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnWriteDataset("IBMUSER.CNTL", "NEW")
+
+	dm := datasets.NewDatasetManager(newSession(t, mock))
+	require.NoError(t, dm.UploadTextToMember("IBMUSER.CNTL", "NEW", "HELLO"))
+
+	requests := mock.RequestsTo(http.MethodPut, "/restfiles/ds/IBMUSER.CNTL(NEW)")
+	require.Len(t, requests, 1)
+	assert.Equal(t, "HELLO", string(requests[0].Body))
+	assert.Equal(t, "text/plain;charset=UTF-8", requests[0].Header.Get("Content-Type"))
+
+	last := mock.LastRequest()
+	require.NotNil(t, last)
+	assert.Equal(t, http.MethodPut, last.Method)
+
+	mock.Reset()
+	assert.Empty(t, mock.Requests())
+	assert.Nil(t, mock.LastRequest())
+}
+
+func TestMockStubOrderAndTimes(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	first := mock.OnGetJob("IBMUSERA", "JOB12345").Return(`{"jobname":"IBMUSERA","jobid":"JOB12345","status":"ACTIVE"}`).Times(1)
+	mock.OnGetJob("IBMUSERA", "JOB12345")
+
+	jm := jobs.NewJobManager(newSession(t, mock))
+
+	job, err := jm.GetJobByNameID("IBMUSERA", "JOB12345")
+	require.NoError(t, err)
+	assert.Equal(t, "ACTIVE", job.Status)
+
+	job, err = jm.GetJobByNameID("IBMUSERA", "JOB12345")
+	require.NoError(t, err)
+	assert.Equal(t, "OUTPUT", job.Status)
+	assert.Equal(t, 1, first.Calls())
+
+	// Patterns and typed values
+	mock.On(http.MethodGet, "/restjobs/jobs/*/JOB00001").Return(jobs.Job{JobName: "ANY", JobID: "JOB00001", Status: "INPUT"})
+	job, err = jm.GetJobByNameID("OTHER", "JOB00001")
+	require.NoError(t, err)
+	assert.Equal(t, "INPUT", job.Status)
+}
+
+func TestMockFaultInjection(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnDeleteDataset("IBMUSER.LOCKED").ReturnError(http.StatusInternalServerError, "Data set in use")
+	mock.OnListMembers("IBMUSER.SLOW").WithLatency(200 * time.Millisecond)
+	mock.OnListMembers("IBMUSER.DROP").DropConnection()
+
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	dm := datasets.NewDatasetManager(newSession(t, mock, profile.WithHTTPClient(client)))
+
+	err := dm.DeleteDataset("IBMUSER.LOCKED")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+	assert.Contains(t, err.Error(), "Data set in use")
+
+	_, err = dm.ListMembers("IBMUSER.SLOW")
+	assert.Error(t, err)
+
+	_, err = dm.ListMembers("IBMUSER.DROP")
+	assert.Error(t, err)
+
+	// Mock-wide latency applies to every stub
+	mock.SetLatency(200 * time.Millisecond)
+	err = dm.DeleteDataset("IBMUSER.LOCKED")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "500")
+}
+
+func TestMockInfoFixture(t *testing.T) {
+	mock := zosmftest.NewMockZOSMF()
+	defer mock.Close()
+	mock.OnInfo()
+
+	info, err := newSession(t, mock).GetZosmfInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "28", info.ZosmfVersion)
+	assert.True(t, info.AtLeast(profile.ZosmfVersionV2R5))
+	require.NotNil(t, info.Plugin("workflow"))
+	assert.Equal(t, "ACTIVE", info.Plugin("workflow").Status)
+}
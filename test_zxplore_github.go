@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/zowe/zowe-client-go-sdk/pkg/datasets"
 	"github.com/zowe/zowe-client-go-sdk/pkg/jobs"
@@ -14,31 +13,19 @@ func main() {
 	fmt.Println("=== Zowe Go SDK - zXplore GitHub Integration Test ===")
 	fmt.Println()
 
-	host := os.Getenv("ZXPLORE_HOST")
-	port := os.Getenv("ZXPLORE_PORT")
-	user := os.Getenv("ZXPLORE_USER")
-	password := os.Getenv("ZXPLORE_PASSWORD")
-
-	if host == "" || port == "" || user == "" || password == "" {
-		log.Fatal("Missing required environment variables: ZXPLORE_HOST, ZXPLORE_PORT, ZXPLORE_USER, ZXPLORE_PASSWORD")
-	}
-
+	// ZXPLORE_HOST, ZXPLORE_USER and ZXPLORE_PASSWORD are required; ZXPLORE_PORT overrides the zXplore default
 	cfg := &profile.ZOSMFProfile{
 		Name:               "zxplore",
-		Host:               host,
-		Port:               10443, // Default zXplore port
+		Port:               10443,
 		Protocol:           "https",
-		User:               user,
-		Password:           password,
 		RejectUnauthorized: false,
 		// BasePath defaults to /zosmf via session if omitted
 	}
-
-	// Override port if environment variable is provided
-	if port != "" {
-		if _, err := fmt.Sscanf(port, "%d", &cfg.Port); err != nil {
-			cfg.Port = 10443 // Fallback to default if parsing fails
-		}
+	if err := profile.ApplyEnvOverrides(cfg, "ZXPLORE"); err != nil {
+		log.Fatalf("Invalid environment variables: %v", err)
+	}
+	if err := profile.ValidateProfile(cfg); err != nil {
+		log.Fatalf("Missing required environment variables: %v", err)
 	}
 
 	fmt.Printf("Connecting to %s://%s:%d%s as %s\n", cfg.Protocol, cfg.Host, cfg.Port, "/zosmf", cfg.User)
@@ -64,7 +51,7 @@ func main() {
 
 	// Datasets check: list datasets (best-effort)
 	fmt.Println("Listing datasets (best-effort)...")
-	dl, err := dm.ListDatasets(&datasets.DatasetFilter{Name: user + ".*", Limit: 10})
+	dl, err := dm.ListDatasets(&datasets.DatasetFilter{Name: cfg.User + ".*", Limit: 10})
 	if err != nil {
 		fmt.Printf("Datasets list error: %v\n", err)
 	} else {